@@ -1,6 +1,10 @@
 package main
 
 import (
+	"net"
+	"net/http"
+	"strings"
+
 	"github.com/micro/go-plugins/micro/cors"
 	"github.com/micro/micro/cmd"
 	"github.com/micro/micro/plugin"
@@ -8,6 +12,22 @@ import (
 
 func init() {
 	plugin.Register(cors.NewPlugin())
+	plugin.Register(plugin.NewPlugin(
+		plugin.WithName("forwarded_for"),
+		plugin.WithHandler(forwardedFor),
+	))
+}
+
+// forwardedFor adds the address the request came from to the end of X-Forwarded-For, which is passed on to the
+// services. Anything already in the header was sent by the client, so the services only trust what the gateway adds
+func forwardedFor(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			r.Header.Set("X-Forwarded-For", strings.Join(append(r.Header["X-Forwarded-For"], host), ", "))
+		}
+
+		h.ServeHTTP(w, r)
+	})
 }
 
 func main() {
@@ -0,0 +1,114 @@
+// Package apierrors is the catalogue of errors the services return. Each error has a category, which decides the HTTP
+// status code the API gateway responds with, and a code that's sent in the errors field of responses
+package apierrors
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/micro/go-micro/errors"
+)
+
+// Codes for the categories of error, which are the same as the HTTP status codes they're returned with. Errors can
+// have a more specific code, such as the validation codes, but still use the status of their category
+const (
+	CodeValidation      int32 = http.StatusBadRequest
+	CodeUnauthenticated int32 = http.StatusUnauthorized
+	CodeForbidden       int32 = http.StatusForbidden
+	CodeNotFound        int32 = http.StatusNotFound
+	CodeConflict        int32 = http.StatusConflict
+	CodeRateLimited     int32 = http.StatusTooManyRequests
+	CodeInternal        int32 = http.StatusInternalServerError
+)
+
+// internalDescription is sent instead of the message of errors that aren't in the catalogue, as they could give away
+// details of how the services work
+const internalDescription = "Something went wrong, try again later"
+
+// Error is an error in the catalogue
+type Error struct {
+	// Status is the HTTP status code of the category of the error
+	Status int32
+	// Code is sent in the errors field of the response
+	Code        int32
+	Description string
+}
+
+func (e *Error) Error() string {
+	return e.Description
+}
+
+// Micro converts the error to a go-micro error from a service, which the API gateway returns with the status code
+func (e *Error) Micro(service string) *errors.Error {
+	return &errors.Error{
+		Id:     service,
+		Code:   e.Status,
+		Detail: e.Description,
+		Status: http.StatusText(int(e.Status)),
+	}
+}
+
+func newError(code int32, format string, args ...interface{}) *Error {
+	return &Error{code, code, fmt.Sprintf(format, args...)}
+}
+
+// Validation is an error for a request that isn't valid
+func Validation(format string, args ...interface{}) *Error {
+	return newError(CodeValidation, format, args...)
+}
+
+// Unauthenticated is an error for a request without valid credentials, such as a wrong password or an expired token
+func Unauthenticated(format string, args ...interface{}) *Error {
+	return newError(CodeUnauthenticated, format, args...)
+}
+
+// Forbidden is an error for a request from a user that isn't allowed to do what they asked
+func Forbidden(format string, args ...interface{}) *Error {
+	return newError(CodeForbidden, format, args...)
+}
+
+// NotFound is an error for a request for something that doesn't exist
+func NotFound(format string, args ...interface{}) *Error {
+	return newError(CodeNotFound, format, args...)
+}
+
+// Conflict is an error for a request that can't be done because of the current state of something, such as a task that
+// has been changed since it was read
+func Conflict(format string, args ...interface{}) *Error {
+	return newError(CodeConflict, format, args...)
+}
+
+// RateLimited is an error for a request that has been made too often
+func RateLimited(format string, args ...interface{}) *Error {
+	return newError(CodeRateLimited, format, args...)
+}
+
+// From finds the catalogue error for an error. go-micro errors, such as those returned by calls to other services, keep
+// their status code. Any other error is an internal error
+func From(err error) *Error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *Error:
+		return e
+	case *errors.Error:
+		if e.Code == 0 {
+			return newError(CodeInternal, "%s", e.Detail)
+		}
+
+		return &Error{e.Code, e.Code, e.Detail}
+	}
+
+	return newError(CodeInternal, internalDescription)
+}
+
+// Categorise puts an error that isn't already in the catalogue into a category, keeping its message. It's for errors
+// from other packages that are known to be caused by the request, such as a token that can't be parsed
+func Categorise(err error, code int32) error {
+	switch err.(type) {
+	case nil, *Error, *errors.Error:
+		return err
+	}
+
+	return newError(code, "%s", err.Error())
+}
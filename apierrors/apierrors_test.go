@@ -0,0 +1,141 @@
+package apierrors
+
+import (
+	"context"
+	goerrors "errors"
+	"testing"
+
+	"github.com/micro/go-micro/errors"
+	"github.com/micro/go-micro/server"
+)
+
+type protoError struct {
+	Code        int32
+	Description string
+}
+
+type response struct {
+	Errors []*protoError
+}
+
+type fakeRequest struct {
+	server.Request
+}
+
+func (r *fakeRequest) Service() string {
+	return "go_do.test"
+}
+
+func (r *fakeRequest) Endpoint() string {
+	return "Test.Endpoint"
+}
+
+func TestFrom(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int32
+		wantCode   int32
+	}{
+		{"catalogue error", NotFound("Task not found"), 404, CodeNotFound},
+		{"specific code", &Error{CodeValidation, 1001, "title is required"}, 400, 1001},
+		{"go-micro error", errors.Forbidden("go_do.user", "no"), 403, CodeForbidden},
+		{"go-micro error without a code", &errors.Error{Detail: "broken"}, 500, CodeInternal},
+		{"other error", goerrors.New("connection refused"), 500, CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := From(tt.err)
+
+			if got.Status != tt.wantStatus || got.Code != tt.wantCode {
+				t.Errorf("wanted status %v and code %v but got %v and %v", tt.wantStatus, tt.wantCode, got.Status, got.Code)
+			}
+		})
+	}
+
+	t.Run("other errors don't give away their message", func(t *testing.T) {
+		got := From(goerrors.New("connection refused"))
+
+		if got.Description != internalDescription {
+			t.Errorf("wanted the internal description but got %v", got.Description)
+		}
+	})
+}
+
+func TestCategorise(t *testing.T) {
+
+	t.Run("other errors are put in the category", func(t *testing.T) {
+		got := From(Categorise(goerrors.New("token is expired"), CodeUnauthenticated))
+
+		if got.Code != CodeUnauthenticated || got.Description != "token is expired" {
+			t.Errorf("wanted an unauthenticated error but got %v", got)
+		}
+	})
+
+	t.Run("catalogue errors keep their category", func(t *testing.T) {
+		got := From(Categorise(NotFound("User not found"), CodeUnauthenticated))
+
+		if got.Code != CodeNotFound {
+			t.Errorf("wanted a not found error but got %v", got)
+		}
+	})
+}
+
+func TestHandlerWrapper(t *testing.T) {
+
+	handlerErr := Conflict("There is already a task set as daily do")
+
+	wrapped := HandlerWrapper(func(ctx context.Context, req server.Request, resp interface{}) error {
+		return handlerErr
+	})
+
+	t.Run("errors are returned with the status code", func(t *testing.T) {
+		err := wrapped(context.Background(), &fakeRequest{}, &response{})
+
+		microErr, ok := err.(*errors.Error)
+
+		if !ok || microErr.Code != 409 || microErr.Id != "go_do.test" || microErr.Status != "Conflict" {
+			t.Errorf("wanted a conflict error but got %v", err)
+		}
+	})
+
+	t.Run("errors are put in the response", func(t *testing.T) {
+		resp := response{}
+
+		wrapped(context.Background(), &fakeRequest{}, &resp)
+
+		if len(resp.Errors) != 1 || resp.Errors[0].Code != CodeConflict {
+			t.Errorf("wanted a conflict error in the response but got %v", resp.Errors)
+		}
+	})
+
+	t.Run("errors already in the response are kept", func(t *testing.T) {
+		resp := response{[]*protoError{{1001, "title is required"}}}
+
+		wrapped(context.Background(), &fakeRequest{}, &resp)
+
+		if len(resp.Errors) != 1 || resp.Errors[0].Code != 1001 {
+			t.Errorf("wanted only the existing error in the response but got %v", resp.Errors)
+		}
+	})
+
+	t.Run("responses without errors still get the status code", func(t *testing.T) {
+		err := wrapped(context.Background(), &fakeRequest{}, &struct{}{})
+
+		if microErr, ok := err.(*errors.Error); !ok || microErr.Code != 409 {
+			t.Errorf("wanted a conflict error but got %v", err)
+		}
+	})
+
+	t.Run("no error", func(t *testing.T) {
+		wrapped := HandlerWrapper(func(ctx context.Context, req server.Request, resp interface{}) error {
+			return nil
+		})
+
+		if err := wrapped(context.Background(), &fakeRequest{}, &response{}); err != nil {
+			t.Errorf("wanted no error but got %v", err)
+		}
+	})
+}
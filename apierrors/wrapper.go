@@ -0,0 +1,93 @@
+package apierrors
+
+import (
+	"context"
+	"log"
+	"reflect"
+
+	"github.com/micro/go-micro/server"
+)
+
+// HandlerWrapper converts the errors returned by handlers into go-micro errors with the status code of their category,
+// and adds them to the errors field of the response. Responses that already have errors, such as those added by the
+// validator, are left as they are. Internal errors are logged, as only a generic message is returned for them
+func HandlerWrapper(fn server.HandlerFunc) server.HandlerFunc {
+	return func(ctx context.Context, req server.Request, resp interface{}) error {
+		err := fn(ctx, req, resp)
+
+		if err == nil {
+			return nil
+		}
+
+		apiErr := From(err)
+
+		if apiErr.Code == CodeInternal {
+			log.Printf("Error in %s: %v", req.Endpoint(), err)
+		}
+
+		if !hasErrors(resp) {
+			SetErrors(resp, apiErr)
+		}
+
+		return apiErr.Micro(req.Service())
+	}
+}
+
+// SetErrors adds the errors to the errors field of a response. Each proto has its own Error message, so they're made
+// with reflection. Returns false if the response doesn't have an errors field
+func SetErrors(resp interface{}, errs ...*Error) bool {
+	field, ok := errorsField(resp)
+
+	if !ok {
+		return false
+	}
+
+	errorType := field.Type().Elem().Elem()
+
+	for _, err := range errs {
+		protoErr := reflect.New(errorType)
+		protoErr.Elem().FieldByName("Code").SetInt(int64(err.Code))
+		protoErr.Elem().FieldByName("Description").SetString(err.Description)
+
+		field.Set(reflect.Append(field, protoErr))
+	}
+
+	return true
+}
+
+// hasErrors checks if the errors field of a response has anything in it
+func hasErrors(resp interface{}) bool {
+	field, ok := errorsField(resp)
+
+	return ok && field.Len() > 0
+}
+
+// errorsField gets the errors field of a response, checking that it's a list of messages with a code and a description
+func errorsField(resp interface{}) (reflect.Value, bool) {
+	response := reflect.ValueOf(resp)
+
+	if response.Kind() != reflect.Ptr || response.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	field := response.Elem().FieldByName("Errors")
+
+	if field.Kind() != reflect.Slice || field.Type().Elem().Kind() != reflect.Ptr {
+		return reflect.Value{}, false
+	}
+
+	errorType := field.Type().Elem().Elem()
+
+	if errorType.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	code, hasCode := errorType.FieldByName("Code")
+	description, hasDescription := errorType.FieldByName("Description")
+
+	if !hasCode || code.Type.Kind() != reflect.Int32 || !hasDescription || description.Type.Kind() != reflect.String {
+		return reflect.Value{}, false
+	}
+
+	return field, true
+}
@@ -8,7 +8,6 @@ require (
 	github.com/micro/go-micro v1.8.3
 	github.com/micro/go-plugins v1.2.0
 	github.com/micro/micro v1.8.4
-	github.com/willdot/go-do v0.0.0-20190714212939-35db4330432f
 	golang.org/x/crypto v0.0.0-20190701094942-4def268fd1a4
 	golang.org/x/net v0.0.0-20190813141303-74dc4d7220e7
 )
@@ -0,0 +1,26 @@
+package main
+
+import "time"
+
+// Clock gives the current time. It's used instead of calling time.Now directly so that tests can control the time
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// localDay gets the day that a time falls on in a timezone, in the format used by the daily do history.
+// UTC is used if the timezone is empty or can't be found
+func localDay(now time.Time, timezone string) string {
+	location, err := time.LoadLocation(timezone)
+
+	if err != nil {
+		location = time.UTC
+	}
+
+	return now.In(location).Format(dayLayout)
+}
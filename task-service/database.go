@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gocql/gocql"
@@ -50,10 +51,66 @@ func init() {
 
 	keySpaceMeta, _ := Session.KeyspaceMetadata("go_do")
 
-	if _, exists := keySpaceMeta.Tables["task"]; exists != true {
-		Session.Query("CREATE TABLE task (id UUID, title text, description text, userId text, createdDate timestamp, completedDate timestamp, dailyDo Boolean, PRIMARY KEY(id))").Exec()
-		Session.Query("create index UserIdIndex on task(userId)").Exec()
-		Session.Query("create index DailyDoIndex on task(dailyDo)").Exec()
-		Session.Query("create index CompletedIndex on task(completedDate)").Exec()
+	// Tasks are stored by the queries that read them rather than in one table with secondary indexes. Each user has a
+	// partition of their tasks in the order they were created, task_key finds a tasks row from its id, and each assignee
+	// has a partition of copies of the tasks assigned to them, in the same order. Tasks in the task table from older
+	// versions of the service are copied over by the migrate tool
+	if _, exists := keySpaceMeta.Tables["task_by_user"]; exists != true {
+		Session.Query("CREATE TABLE task_by_user (userId text, createdDate timestamp, id UUID, title text, description text, completedDate timestamp, dailyDo Boolean, deleted Boolean, deletedDate timestamp, dueDate timestamp, priority int, tags set<text>, recurrence text, occurrence int, version int, tenantId text, listId text, assigneeId text, PRIMARY KEY((userId), createdDate, id)) WITH CLUSTERING ORDER BY (createdDate ASC, id ASC)").Exec()
+	}
+
+	if _, exists := keySpaceMeta.Tables["task_key"]; exists != true {
+		Session.Query("CREATE TABLE task_key (id UUID, userId text, createdDate timestamp, PRIMARY KEY(id))").Exec()
+	}
+
+	if _, exists := keySpaceMeta.Tables["task_by_assignee"]; exists != true {
+		Session.Query("CREATE TABLE task_by_assignee (assigneeId text, createdDate timestamp, taskId UUID, userId text, title text, description text, completedDate timestamp, dailyDo Boolean, deleted Boolean, deletedDate timestamp, dueDate timestamp, priority int, tags set<text>, recurrence text, occurrence int, version int, tenantId text, listId text, PRIMARY KEY((assigneeId), createdDate, taskId)) WITH CLUSTERING ORDER BY (createdDate ASC, taskId ASC)").Exec()
+	}
+
+	if _, exists := keySpaceMeta.Tables["checklist_item"]; exists != true {
+		Session.Query("CREATE TABLE checklist_item (taskId UUID, id timeuuid, title text, completed Boolean, position int, PRIMARY KEY(taskId, id))").Exec()
+	}
+
+	if _, exists := keySpaceMeta.Tables["task_list"]; exists != true {
+		Session.Query("CREATE TABLE task_list (id UUID, name text, ownerId text, tenantId text, createdDate timestamp, archived Boolean, PRIMARY KEY(id))").Exec()
+	} else {
+		addColumnIfMissing(keySpaceMeta, "task_list", "archived", "Boolean")
+	}
+
+	// Each list is a partition of copies of the tasks in it, so a page of a list is read without looking up each task.
+	// Every task is in the partition twice, with sortOrder saying which order the row is in: CREATED_ASCENDING rows have
+	// the created date as their sortKey, and LIST_POSITION rows have the tasks position in the list
+	if _, exists := keySpaceMeta.Tables["task_list_item"]; exists != true {
+		Session.Query("CREATE TABLE task_list_item (listId UUID, sortOrder int, sortKey bigint, taskId UUID, position int, userId text, createdDate timestamp, title text, description text, completedDate timestamp, dailyDo Boolean, deleted Boolean, deletedDate timestamp, dueDate timestamp, priority int, tags set<text>, recurrence text, occurrence int, version int, tenantId text, assigneeId text, PRIMARY KEY((listId), sortOrder, sortKey, taskId))").Exec()
+	}
+
+	if _, exists := keySpaceMeta.Tables["task_list_member"]; exists != true {
+		Session.Query("CREATE TABLE task_list_member (listId UUID, userId text, permission int, PRIMARY KEY(listId, userId))").Exec()
+	}
+
+	// Each user has a partition of the lists they are a member of, so their lists are found without an index
+	if _, exists := keySpaceMeta.Tables["task_list_by_member"]; exists != true {
+		Session.Query("CREATE TABLE task_list_by_member (userId text, listId UUID, PRIMARY KEY(userId, listId))").Exec()
+	}
+
+	if _, exists := keySpaceMeta.Tables["daily_do_history"]; exists != true {
+		Session.Query("CREATE TABLE daily_do_history (userId text, day date, taskId UUID, completed Boolean, PRIMARY KEY(userId, day)) WITH CLUSTERING ORDER BY (day DESC)").Exec()
+	}
+
+	if _, exists := keySpaceMeta.Tables["daily_do"]; exists != true {
+		Session.Query("CREATE TABLE daily_do (userId text, taskId UUID, PRIMARY KEY(userId))").Exec()
+	}
+}
+
+// addColumnIfMissing adds a column to an existing table if the table doesn't already have it
+func addColumnIfMissing(keySpaceMeta *gocql.KeyspaceMetadata, table, column, columnType string) {
+	if _, exists := keySpaceMeta.Tables[table].Columns[strings.ToLower(column)]; exists {
+		return
+	}
+
+	err := Session.Query(fmt.Sprintf("ALTER TABLE %s ADD %s %s", table, column, columnType)).Exec()
+
+	if err != nil {
+		fmt.Printf("error adding column %s to %s: %v", column, table, err)
 	}
 }
@@ -1,60 +1,93 @@
 package main
 
 import (
-	"errors"
+	"strings"
 	"time"
 
 	"golang.org/x/net/context"
 
 	"github.com/micro/go-micro/metadata"
-	taskPb "github.com/willdot/go-do/task-service/proto/task"
-	authPb "github.com/willdot/go-do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/apierrors"
+	taskPb "github.com/willdot/Go-Do/task-service/proto/task"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/user-service/verifier"
 )
 
-var errNoMetaData = errors.New("no auth meta data found in request")
-var errDailyDoAlreadyExists = errors.New("There is already a task set as daily do")
+var errNoMetaData = apierrors.Unauthenticated("no auth meta data found in request")
+var errChecklistItemsOpen = apierrors.Conflict("The task still has checklist items that haven't been completed")
+var errUnknownUpdateField = apierrors.Validation("Update mask contains a field that can't be updated")
+var errTitleRequired = apierrors.Validation("title is required")
 
 type taskHandler struct {
 	repo       Repository
 	userClient authPb.AuthClient
+	// purgeAge is how long a task has to have been deleted for before Purge removes it, if the request doesn't say
+	purgeAge time.Duration
+	clock    Clock
+	// serviceToken is sent to the auth service to get the settings of users other than the caller, such as the owner or
+	// assignee of a task the caller has changed
+	serviceToken string
+	// tenantID is the tenant of the caller for a handler from forCaller, whose repo only uses the tasks in it
+	tenantID string
 }
 
-// Get satisfies the Get RPC for the Task proto and gets tasks for a user
+// Get satisfies the Get RPC for the Task proto and gets a page of tasks for a user
 func (t *taskHandler) Get(ctx context.Context, req *taskPb.Request, res *taskPb.Response) error {
 
-	userID, err := t.getUserIDFromTokenInContext(ctx)
+	t, userID, err := t.forCaller(ctx)
 
 	if err != nil {
 		return err
 	}
-	tasks, err := t.repo.Get(userID)
+
+	if req.ListId != "" {
+		if _, err := t.listForUser(userID, req.ListId, taskPb.ListPermission_LIST_PERMISSION_VIEW); err != nil {
+			return err
+		}
+	}
+
+	tasks, nextPageToken, err := t.repo.Get(userID, req, t.clock.Now().Unix())
 
 	if err != nil {
 		return err
 	}
 
 	res.Tasks = tasks
+	res.NextPageToken = nextPageToken
 
 	return nil
 }
 
-// Create satisfies the Create RPC for the Task proto and creates a new task for a user
+// Create satisfies the Create RPC for the Task proto and creates a new task for a user, which can be put in a list they
+// can edit and assigned to a member of their company
 func (t *taskHandler) Create(ctx context.Context, req *taskPb.CreateTask, res *taskPb.Response) error {
 
-	userID, err := t.getUserIDFromTokenInContext(ctx)
+	t, userID, err := t.forCaller(ctx)
 
 	if err != nil {
 		return err
 	}
 
-	existingDailyDo, err := t.repo.GetDailyDoForUser(userID)
+	if req.ListId != "" {
+		if _, err := t.editableList(userID, req.ListId); err != nil {
+			return err
+		}
+	}
+
+	if req.AssigneeId != "" && req.AssigneeId != userID {
+		if req.DailyDo {
+			return errNotDailyDoUser
+		}
 
-	if err != nil {
-		return err
+		if err := t.checkCompanyMember(ctx, req.AssigneeId); err != nil {
+			return err
+		}
 	}
 
-	if existingDailyDo != nil {
-		return errDailyDoAlreadyExists
+	if req.Recurrence != "" {
+		if _, err := parseRecurrence(req.Recurrence); err != nil {
+			return err
+		}
 	}
 
 	task := taskPb.Task{
@@ -62,7 +95,28 @@ func (t *taskHandler) Create(ctx context.Context, req *taskPb.CreateTask, res *t
 		Description: req.Description,
 		DailyDo:     req.DailyDo,
 		UserId:      userID,
-		CreatedDate: int64(time.Now().Unix()),
+		CreatedDate: int64(t.clock.Now().Unix()),
+		DueDate:     req.DueDate,
+		Priority:    req.Priority,
+		Tags:        normaliseTags(req.Tags),
+		Recurrence:  req.Recurrence,
+		ListId:      req.ListId,
+		AssigneeId:  req.AssigneeId,
+	}
+
+	if task.Recurrence != "" {
+		task.Occurrence = 1
+	}
+
+	// The users day is found before anything is stored, so that the task isn't created without its history
+	var today string
+
+	if task.DailyDo {
+		today, err = t.todayForUser(ctx, userID)
+
+		if err != nil {
+			return err
+		}
 	}
 
 	err = t.repo.Create(&task)
@@ -71,6 +125,14 @@ func (t *taskHandler) Create(ctx context.Context, req *taskPb.CreateTask, res *t
 		return err
 	}
 
+	if task.DailyDo {
+		err = t.recordDailyDoChange(userID, today, task.Id, true)
+
+		if err != nil {
+			return err
+		}
+	}
+
 	res.Task = &task
 
 	return nil
@@ -79,17 +141,36 @@ func (t *taskHandler) Create(ctx context.Context, req *taskPb.CreateTask, res *t
 // Update satisfies the Update RPC for the Task proto and updates a task for a user
 func (t *taskHandler) Update(ctx context.Context, req *taskPb.UpdateTask, res *taskPb.Response) error {
 
-	userID, err := t.getUserIDFromTokenInContext(ctx)
+	t, userID, err := t.forCaller(ctx)
 
 	if err != nil {
 		return err
 	}
 
-	task := taskPb.Task{
-		Id:          req.TaskId,
-		Title:       req.Title,
-		Description: req.Description,
-		UserId:      userID,
+	existingTask, err := t.taskForUser(userID, req.TaskId, taskPb.ListPermission_LIST_PERMISSION_EDIT)
+
+	if err != nil {
+		return err
+	}
+
+	task := *existingTask
+	task.Version = expectedVersion(req.Version, existingTask)
+
+	err = applyTaskUpdate(&task, req)
+
+	if err != nil {
+		return err
+	}
+
+	// The validator can't tell if a title in the request clears the title or leaves it unchanged, so check the result
+	if strings.TrimSpace(task.Title) == "" {
+		return errTitleRequired
+	}
+
+	if task.Recurrence != "" {
+		if _, err := parseRecurrence(task.Recurrence); err != nil {
+			return err
+		}
 	}
 
 	err = t.repo.Update(&task)
@@ -98,97 +179,625 @@ func (t *taskHandler) Update(ctx context.Context, req *taskPb.UpdateTask, res *t
 		return err
 	}
 
+	res.Task = &task
+
 	return nil
 }
 
-// ChangeDailyDoStatus satisfies the ChangeDailyDoStatus RPC for the Task proto and sets the daily do status of a task
+// ChangeDailyDoStatus satisfies the ChangeDailyDoStatus RPC for the Task proto and sets the daily do status of a task.
+// Only the user a task is assigned to, or its owner if it isn't assigned, can change it
 func (t *taskHandler) ChangeDailyDoStatus(ctx context.Context, req *taskPb.DailyDoStatusRequest, res *taskPb.Response) error {
 
-	userID, err := t.getUserIDFromTokenInContext(ctx)
+	t, userID, err := t.forCaller(ctx)
 
 	if err != nil {
 		return err
 	}
 
-	// If setting a daily do, check there isn't already a daily do set
-	if req.Status {
-		existingDailyDo, err := t.repo.GetDailyDoForUser(userID)
+	existingTask, err := t.taskForUser(userID, req.TaskId, taskPb.ListPermission_LIST_PERMISSION_EDIT)
 
-		if err != nil {
-			return err
-		}
+	if err != nil {
+		return err
+	}
 
-		if existingDailyDo != nil {
-			return errDailyDoAlreadyExists
-		}
+	if dailyDoUser(existingTask) != userID {
+		return errNotDailyDoUser
 	}
 
-	task := taskPb.Task{
-		Id:      req.TaskId,
-		UserId:  userID,
-		DailyDo: req.Status,
+	today, err := t.todayForUser(ctx, userID)
+
+	if err != nil {
+		return err
 	}
 
+	task := *existingTask
+	task.DailyDo = req.Status
+	task.Version = expectedVersion(req.Version, existingTask)
+
 	err = t.repo.SetDailyDoStatus(&task)
 
 	if err != nil {
 		return err
 	}
 
+	err = t.recordDailyDoChange(userID, today, task.Id, task.DailyDo)
+
+	if err != nil {
+		return err
+	}
+
+	res.Task = &task
+
 	return nil
 }
 
 // CompleteTask sets the task CompletedDate
 func (t *taskHandler) CompleteTask(ctx context.Context, req *taskPb.CompleteTaskRequest, res *taskPb.Response) error {
 
-	userID, err := t.getUserIDFromTokenInContext(ctx)
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	// Get the task before completing it so that it's known whether this completion should create the next occurrence
+	existingTask, err := t.taskForUser(userID, req.TaskId, taskPb.ListPermission_LIST_PERMISSION_EDIT)
+
+	if err != nil {
+		return err
+	}
+
+	// Get the daily do before completing the task, as completing it will stop it being the daily do. The history is
+	// kept for the user whose daily do it is, whoever completes it
+	dailyDo, err := t.repo.GetDailyDoForUser(dailyDoUser(existingTask))
 
 	if err != nil {
 		return err
 	}
 
 	task := taskPb.Task{
-		Id:     req.TaskId,
-		UserId: userID,
+		Id:     existingTask.Id,
+		UserId: existingTask.UserId,
 	}
 
+	wasCompleted := existingTask.CompletedDate != 0
+	task.Version = expectedVersion(req.Version, existingTask)
+
 	if req.Completed {
-		task.CompletedDate = int64(time.Now().Unix())
+		task.CompletedDate = int64(t.clock.Now().Unix())
 		task.DailyDo = false
 	} else {
 		task.CompletedDate = 0
 	}
 
+	wasDailyDo := dailyDo != nil && dailyDo.Id == task.Id
+	createNext := req.Completed && !wasCompleted && existingTask.Recurrence != ""
+
+	// The days and timezones of the users are found before anything is changed, as the caller can be someone other than
+	// the owner or the user whose daily do it is, and a failure part way through would leave the task without its
+	// history or next occurrence
+	var today string
+
+	if wasDailyDo || !req.Completed {
+		today, err = t.todayForUser(ctx, dailyDoUser(existingTask))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	var ownerLocation *time.Location
+
+	if createNext {
+		ownerLocation, err = t.userLocation(ctx, existingTask.UserId)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if req.Completed && req.OpenChecklist != taskPb.OpenChecklist_OPEN_CHECKLIST_IGNORE {
+		err = t.handleOpenChecklistItems(&task, req.OpenChecklist)
+
+		if err != nil {
+			return err
+		}
+	}
+
 	err = t.repo.CompleteTask(&task)
 
 	if err != nil {
 		return err
 	}
 
+	err = t.recordDailyDoCompletion(dailyDoUser(existingTask), today, task.Id, wasDailyDo, req.Completed)
+
+	if err != nil {
+		return err
+	}
+
+	if createNext {
+		nextTask, err := t.createNextOccurrence(existingTask, ownerLocation, task.CompletedDate)
+
+		if err != nil {
+			return err
+		}
+
+		res.Task = nextTask
+	}
+
 	return nil
 }
 
-// so that we can get the user id to use on the functions, we get the supplied token, validate it,
-// and then get the user id. This means not having to send the user id in the request, which
-// limits the chance of random api calls being made with guessed user id
-func (t *taskHandler) getUserIDFromTokenInContext(ctx context.Context) (string, error) {
-	suppliedToken, err := getTokenFromContext(ctx)
+// createNextOccurrence creates the task that follows a completed recurring task. The next due date is worked out from
+// the completed task's due date, or from when it was completed if it didn't have one, in the location of its owner.
+// Returns nil if the series has ended
+func (t *taskHandler) createNextOccurrence(completed *taskPb.Task, location *time.Location, completedDate int64) (*taskPb.Task, error) {
+
+	rule, err := parseRecurrence(completed.Recurrence)
 
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	from := completed.DueDate
+	if from == 0 {
+		from = completedDate
+	}
+
+	occurrence := completed.Occurrence
+	if occurrence == 0 {
+		occurrence = 1
+	}
+
+	nextDue, ok := rule.next(time.Unix(from, 0).In(location), int(occurrence))
+
+	if !ok {
+		return nil, nil
+	}
+
+	nextTask := taskPb.Task{
+		Title:       completed.Title,
+		Description: completed.Description,
+		UserId:      completed.UserId,
+		CreatedDate: int64(t.clock.Now().Unix()),
+		DueDate:     nextDue.Unix(),
+		Priority:    completed.Priority,
+		Tags:        completed.Tags,
+		Recurrence:  completed.Recurrence,
+		Occurrence:  occurrence + 1,
+		ListId:      completed.ListId,
+		AssigneeId:  completed.AssigneeId,
+	}
+
+	err = t.repo.Create(&nextTask)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &nextTask, nil
+}
+
+// Delete satisfies the Delete RPC for the Task proto and soft deletes a task so that it's hidden but can be restored
+func (t *taskHandler) Delete(ctx context.Context, req *taskPb.DeleteTaskRequest, res *taskPb.Response) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	existingTask, err := t.taskForUser(userID, req.TaskId, taskPb.ListPermission_LIST_PERMISSION_EDIT)
+
+	if err != nil {
+		return err
 	}
 
-	token := authPb.Token{
-		Token: suppliedToken,
+	task := taskPb.Task{
+		Id:          existingTask.Id,
+		UserId:      existingTask.UserId,
+		Deleted:     true,
+		DeletedDate: int64(t.clock.Now().Unix()),
 	}
 
-	validationResult, err := t.userClient.ValidateToken(ctx, &token)
+	err = t.repo.Delete(&task)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Restore satisfies the Restore RPC for the Task proto and restores a task that has been deleted
+func (t *taskHandler) Restore(ctx context.Context, req *taskPb.RestoreTaskRequest, res *taskPb.Response) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	existingTask, err := t.taskForUser(userID, req.TaskId, taskPb.ListPermission_LIST_PERMISSION_EDIT)
+
+	if err != nil {
+		return err
+	}
+
+	task := taskPb.Task{
+		Id:     existingTask.Id,
+		UserId: existingTask.UserId,
+	}
+
+	err = t.repo.Restore(&task)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Purge satisfies the Purge RPC for the Task proto and permanently removes a users tasks that were deleted longer
+// ago than the age in the request (in seconds), or the services purge age if one isn't given
+func (t *taskHandler) Purge(ctx context.Context, req *taskPb.PurgeRequest, res *taskPb.Response) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	age := t.purgeAge
+
+	if req.OlderThan > 0 {
+		age = time.Duration(req.OlderThan) * time.Second
+	}
+
+	purged, err := t.repo.Purge(t.tenantID, userID, t.clock.Now().Add(-age).Unix())
+
+	if err != nil {
+		return err
+	}
+
+	res.Tasks = purged
+
+	return nil
+}
+
+// GetDailyDoHistory satisfies the GetDailyDoHistory RPC for the Task proto and gets which task was the daily do on each day
+// between the days in the request (YYYY-MM-DD), newest first
+func (t *taskHandler) GetDailyDoHistory(ctx context.Context, req *taskPb.DailyDoHistoryRequest, res *taskPb.DailyDoHistoryResponse) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for _, day := range []string{req.From, req.To} {
+		if _, err := time.Parse(dayLayout, day); day != "" && err != nil {
+			return errInvalidDay
+		}
+	}
+
+	history, err := t.repo.GetDailyDoHistory(userID, req.From, req.To)
+
+	if err != nil {
+		return err
+	}
+
+	res.History = history
+
+	return nil
+}
+
+// GetStreak satisfies the GetStreak RPC for the Task proto and gets the current and longest number of days in a row
+// that a user has completed their daily do
+func (t *taskHandler) GetStreak(ctx context.Context, req *taskPb.StreakRequest, res *taskPb.StreakResponse) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	history, err := t.repo.GetDailyDoHistory(userID, "", "")
+
+	if err != nil {
+		return err
+	}
+
+	today, err := t.todayForUser(ctx, userID)
+
+	if err != nil {
+		return err
+	}
+
+	res.Current, res.Longest = calculateStreaks(history, today)
+
+	return nil
+}
+
+// AddChecklistItem satisfies the AddChecklistItem RPC for the Task proto and adds an item to the end of a tasks checklist.
+// The response task has the id and checklist of the task
+func (t *taskHandler) AddChecklistItem(ctx context.Context, req *taskPb.AddChecklistItemRequest, res *taskPb.Response) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	existingTask, err := t.taskForUser(userID, req.TaskId, taskPb.ListPermission_LIST_PERMISSION_EDIT)
+
+	if err != nil {
+		return err
+	}
+
+	task := taskPb.Task{
+		Id:     existingTask.Id,
+		UserId: existingTask.UserId,
+	}
+
+	item := taskPb.ChecklistItem{
+		Title: req.Title,
+	}
+
+	err = t.repo.AddChecklistItem(&task, &item)
+
+	if err != nil {
+		return err
+	}
+
+	return t.setChecklistResponse(&task, res)
+}
+
+// ReorderChecklist satisfies the ReorderChecklist RPC for the Task proto and puts a tasks checklist into the order of the
+// item ids in the request. The response task has the id and checklist of the task
+func (t *taskHandler) ReorderChecklist(ctx context.Context, req *taskPb.ReorderChecklistRequest, res *taskPb.Response) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	existingTask, err := t.taskForUser(userID, req.TaskId, taskPb.ListPermission_LIST_PERMISSION_EDIT)
+
+	if err != nil {
+		return err
+	}
+
+	task := taskPb.Task{
+		Id:     existingTask.Id,
+		UserId: existingTask.UserId,
+	}
+
+	err = t.repo.ReorderChecklist(&task, req.ItemIds)
+
+	if err != nil {
+		return err
+	}
+
+	return t.setChecklistResponse(&task, res)
+}
+
+// ToggleChecklistItem satisfies the ToggleChecklistItem RPC for the Task proto and sets if a checklist item is completed.
+// The response task has the id and checklist of the task
+func (t *taskHandler) ToggleChecklistItem(ctx context.Context, req *taskPb.ToggleChecklistItemRequest, res *taskPb.Response) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	existingTask, err := t.taskForUser(userID, req.TaskId, taskPb.ListPermission_LIST_PERMISSION_EDIT)
+
+	if err != nil {
+		return err
+	}
+
+	task := taskPb.Task{
+		Id:     existingTask.Id,
+		UserId: existingTask.UserId,
+	}
+
+	item := taskPb.ChecklistItem{
+		Id:        req.ItemId,
+		Completed: req.Completed,
+	}
+
+	err = t.repo.SetChecklistItemStatus(&task, &item)
+
+	if err != nil {
+		return err
+	}
+
+	return t.setChecklistResponse(&task, res)
+}
+
+// RemoveChecklistItem satisfies the RemoveChecklistItem RPC for the Task proto and removes an item from a tasks checklist.
+// The response task has the id and checklist of the task
+func (t *taskHandler) RemoveChecklistItem(ctx context.Context, req *taskPb.RemoveChecklistItemRequest, res *taskPb.Response) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	existingTask, err := t.taskForUser(userID, req.TaskId, taskPb.ListPermission_LIST_PERMISSION_EDIT)
+
+	if err != nil {
+		return err
+	}
+
+	task := taskPb.Task{
+		Id:     existingTask.Id,
+		UserId: existingTask.UserId,
+	}
+
+	err = t.repo.RemoveChecklistItem(&task, req.ItemId)
+
+	if err != nil {
+		return err
+	}
+
+	return t.setChecklistResponse(&task, res)
+}
+
+// setChecklistResponse sets the response task to the tasks id and its current checklist
+func (t *taskHandler) setChecklistResponse(task *taskPb.Task, res *taskPb.Response) error {
+
+	checklist, err := t.repo.GetChecklist(task)
+
+	if err != nil {
+		return err
+	}
+
+	res.Task = &taskPb.Task{
+		Id:        task.Id,
+		UserId:    task.UserId,
+		Checklist: checklist,
+	}
+
+	return nil
+}
+
+// handleOpenChecklistItems either refuses to complete a task that has checklist items that aren't completed, or
+// completes them along with the task, depending on the option in the CompleteTask request
+func (t *taskHandler) handleOpenChecklistItems(task *taskPb.Task, option taskPb.OpenChecklist) error {
+
+	checklist, err := t.repo.GetChecklist(task)
+
+	if err != nil {
+		return err
+	}
+
+	for _, item := range checklist {
+		if item.Completed {
+			continue
+		}
+
+		if option == taskPb.OpenChecklist_OPEN_CHECKLIST_REFUSE {
+			return errChecklistItemsOpen
+		}
+
+		err = t.repo.SetChecklistItemStatus(task, &taskPb.ChecklistItem{Id: item.Id, Completed: true})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListTags satisfies the ListTags RPC for the Task proto and gets the tags a user has used and how many tasks have each one
+func (t *taskHandler) ListTags(ctx context.Context, req *taskPb.ListTagsRequest, res *taskPb.ListTagsResponse) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	tags, err := t.repo.ListTags(t.tenantID, userID)
+
+	if err != nil {
+		return err
+	}
+
+	res.Tags = tags
+
+	return nil
+}
+
+// recordDailyDoChange keeps the daily do history for the users day in step with a task being set or unset as the
+// daily do
+func (t *taskHandler) recordDailyDoChange(userID, day, taskID string, dailyDo bool) error {
+	if dailyDo {
+		return t.repo.SetDailyDoHistory(&taskPb.DailyDoHistory{
+			UserId: userID,
+			Day:    day,
+			TaskId: taskID,
+		})
+	}
+
+	existing, err := t.repo.GetDailyDoHistoryForDay(userID, day)
+
+	if err != nil {
+		return err
+	}
+
+	// A daily do that has already been completed today still counts, so only forget it if it wasn't done
+	if existing != nil && existing.TaskId == taskID && !existing.Completed {
+		return t.repo.DeleteDailyDoHistory(userID, day)
+	}
+
+	return nil
+}
+
+// recordDailyDoCompletion marks the daily do history for the users day as completed when the daily do is completed,
+// and puts it back if that task is then un completed
+func (t *taskHandler) recordDailyDoCompletion(userID, day, taskID string, wasDailyDo, completed bool) error {
+	if completed && !wasDailyDo {
+		return nil
+	}
+
+	if completed {
+		return t.repo.SetDailyDoHistory(&taskPb.DailyDoHistory{
+			UserId:    userID,
+			Day:       day,
+			TaskId:    taskID,
+			Completed: true,
+		})
+	}
+
+	existing, err := t.repo.GetDailyDoHistoryForDay(userID, day)
+
+	if err != nil {
+		return err
+	}
+
+	if existing != nil && existing.TaskId == taskID && existing.Completed {
+		existing.Completed = false
+		return t.repo.SetDailyDoHistory(existing)
+	}
+
+	return nil
+}
+
+// todayForUser gets the current day in the users own timezone, so that the daily do history follows their days
+func (t *taskHandler) todayForUser(ctx context.Context, userID string) (string, error) {
+	location, err := t.userLocation(ctx, userID)
 
 	if err != nil {
 		return "", err
 	}
 
-	return validationResult.UserId, nil
+	return t.clock.Now().In(location).Format(dayLayout), nil
+}
+
+// userLocation gets the location for the timezone a user has set, falling back to UTC. The user is got with the service
+// token, as the auth service only lets callers get themselves and it can be the owner or assignee of a task
+func (t *taskHandler) userLocation(ctx context.Context, userID string) (*time.Location, error) {
+	userResponse, err := t.userClient.Get(verifier.NewServiceContext(ctx, t.serviceToken), &authPb.User{Id: userID})
+
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := time.LoadLocation(userResponse.GetUser().GetTimezone())
+
+	if err != nil {
+		return time.UTC, nil
+	}
+
+	return location, nil
 }
 
 func getTokenFromContext(ctx context.Context) (string, error) {
@@ -203,3 +812,80 @@ func getTokenFromContext(ctx context.Context) (string, error) {
 
 	return token, nil
 }
+
+// expectedVersion is the version a change to a task expects it to be at. Clients can send the version they last read so
+// that they don't overwrite someone else's change, otherwise the version read by the handler is used
+func expectedVersion(requested int32, existingTask *taskPb.Task) int32 {
+	if requested != 0 {
+		return requested
+	}
+
+	return existingTask.Version
+}
+
+// applyTaskUpdate copies the fields named in the update mask from the request onto the task, so fields can be cleared by
+// listing them in the mask with an empty value. Without a mask, only the fields that are set in the request are copied
+func applyTaskUpdate(task *taskPb.Task, req *taskPb.UpdateTask) error {
+	mask := req.UpdateMask
+
+	if len(mask) == 0 {
+		if req.Title != "" {
+			mask = append(mask, "title")
+		}
+		if req.Description != "" {
+			mask = append(mask, "description")
+		}
+		if req.DueDate != 0 {
+			mask = append(mask, "dueDate")
+		}
+		if req.Priority != taskPb.Priority_PRIORITY_NONE {
+			mask = append(mask, "priority")
+		}
+		if len(req.Tags) > 0 {
+			mask = append(mask, "tags")
+		}
+		if req.Recurrence != "" {
+			mask = append(mask, "recurrence")
+		}
+	}
+
+	for _, field := range mask {
+		switch field {
+		case "title":
+			task.Title = req.Title
+		case "description":
+			task.Description = req.Description
+		case "dueDate":
+			task.DueDate = req.DueDate
+		case "priority":
+			task.Priority = req.Priority
+		case "tags":
+			task.Tags = normaliseTags(req.Tags)
+		case "recurrence":
+			task.Recurrence = req.Recurrence
+		default:
+			return errUnknownUpdateField
+		}
+	}
+
+	return nil
+}
+
+// normaliseTags trims the spaces from tags and removes any that are empty or repeated
+func normaliseTags(tags []string) []string {
+	var normalised []string
+	seen := map[string]bool{}
+
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		normalised = append(normalised, tag)
+	}
+
+	return normalised
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
-	taskPb "github.com/willdot/go-do/task-service/proto/task"
+	"github.com/willdot/Go-Do/apierrors"
+	taskPb "github.com/willdot/Go-Do/task-service/proto/task"
 )
 
 func assertError(got, want error, t *testing.T) {
@@ -14,6 +16,45 @@ func assertError(got, want error, t *testing.T) {
 	}
 }
 
+// assertCode checks the code of an error in the catalogue, for errors that don't have a sentinel to compare with
+func assertCode(got error, want int32, t *testing.T) {
+	t.Helper()
+
+	if code := apierrors.From(got).Code; got == nil || code != want {
+		t.Errorf("got error '%v' but want an error with code %v", got, want)
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+
+	tests := []struct {
+		err  error
+		want int32
+	}{
+		{errNoMetaData, apierrors.CodeUnauthenticated},
+		{errTaskUserIDNotMatched, apierrors.CodeForbidden},
+		{errTaskNotFound, apierrors.CodeNotFound},
+		{errChecklistItemNotFound, apierrors.CodeNotFound},
+		{errTaskVersionConflict, apierrors.CodeConflict},
+		{errDailyDoAlreadyExists, apierrors.CodeConflict},
+		{errChecklistItemsOpen, apierrors.CodeConflict},
+		{errInvalidPageToken, apierrors.CodeValidation},
+		{errFake, apierrors.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			assertCode(tt.err, tt.want, t)
+		})
+	}
+
+	t.Run("invalid recurrence", func(t *testing.T) {
+		_, err := parseRecurrence("FREQ=HOURLY")
+
+		assertCode(err, apierrors.CodeValidation, t)
+	})
+}
+
 func TestGetTasks(t *testing.T) {
 
 	t.Run("get but returns an error in repo", func(t *testing.T) {
@@ -69,6 +110,188 @@ func TestGetTasks(t *testing.T) {
 		}
 	})
 
+	t.Run("get completed tasks for user 1", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		var want []*taskPb.Task
+
+		want = append(want, &fakeTask2)
+
+		request := taskPb.Request{CompletedOnly: true}
+		response := taskPb.Response{}
+
+		err := service.Get(createContext("t", true), &request, &response)
+
+		assertError(err, nil, t)
+
+		if !reflect.DeepEqual(want, response.Tasks) {
+			t.Errorf("want %v got %v", want, response.Tasks)
+		}
+	})
+
+	t.Run("get incomplete tasks for user 1", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		var want []*taskPb.Task
+
+		want = append(want, &fakeTask1, &fakeTask4)
+
+		request := taskPb.Request{IncompleteOnly: true}
+		response := taskPb.Response{}
+
+		err := service.Get(createContext("t", true), &request, &response)
+
+		assertError(err, nil, t)
+
+		if !reflect.DeepEqual(want, response.Tasks) {
+			t.Errorf("want %v got %v", want, response.Tasks)
+		}
+	})
+
+	t.Run("get tasks created in a date range for user 1", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		fakeTask4.CreatedDate = 5
+
+		var want []*taskPb.Task
+
+		want = append(want, &fakeTask4)
+
+		request := taskPb.Request{CreatedFrom: 2, CreatedTo: 10}
+		response := taskPb.Response{}
+
+		err := service.Get(createContext("t", true), &request, &response)
+
+		fakeTask4.CreatedDate = 1
+
+		assertError(err, nil, t)
+
+		if !reflect.DeepEqual(want, response.Tasks) {
+			t.Errorf("want %v got %v", want, response.Tasks)
+		}
+	})
+
+	t.Run("get tasks sorted by newest first for user 1", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		fakeTask4.CreatedDate = 5
+
+		var want []*taskPb.Task
+
+		want = append(want, &fakeTask4, &fakeTask1, &fakeTask2)
+
+		request := taskPb.Request{SortOrder: taskPb.SortOrder_CREATED_DESCENDING}
+		response := taskPb.Response{}
+
+		err := service.Get(createContext("t", true), &request, &response)
+
+		fakeTask4.CreatedDate = 1
+
+		assertError(err, nil, t)
+
+		if !reflect.DeepEqual(want, response.Tasks) {
+			t.Errorf("want %v got %v", want, response.Tasks)
+		}
+	})
+
+	t.Run("get tasks by tag, priority and overdue for user 1", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		fakeTask1.Tags = []string{"home"}
+		fakeTask1.Priority = taskPb.Priority_PRIORITY_HIGH
+		fakeTask1.DueDate = time.Now().Add(-time.Hour).Unix()
+		fakeTask4.Tags = []string{"home", "work"}
+		fakeTask4.Priority = taskPb.Priority_PRIORITY_LOW
+		fakeTask4.DueDate = time.Now().Add(time.Hour).Unix()
+
+		tests := []struct {
+			name    string
+			request taskPb.Request
+			want    []*taskPb.Task
+		}{
+			{"tag", taskPb.Request{Tag: "home"}, []*taskPb.Task{&fakeTask1, &fakeTask4}},
+			{"priority", taskPb.Request{Priority: taskPb.Priority_PRIORITY_LOW}, []*taskPb.Task{&fakeTask4}},
+			{"overdue", taskPb.Request{OverdueOnly: true}, []*taskPb.Task{&fakeTask1}},
+		}
+
+		for _, tt := range tests {
+			response := taskPb.Response{}
+
+			err := service.Get(createContext("t", true), &tt.request, &response)
+
+			assertError(err, nil, t)
+
+			if !reflect.DeepEqual(tt.want, response.Tasks) {
+				t.Errorf("%s: want %v got %v", tt.name, tt.want, response.Tasks)
+			}
+		}
+
+		fakeTask1.Tags = nil
+		fakeTask1.Priority = taskPb.Priority_PRIORITY_NONE
+		fakeTask1.DueDate = 0
+		fakeTask4.Tags = nil
+		fakeTask4.Priority = taskPb.Priority_PRIORITY_NONE
+		fakeTask4.DueDate = 0
+	})
+
+	t.Run("get tasks a page at a time for user 1", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		var want []*taskPb.Task
+
+		want = append(want, &fakeTask1, &fakeTask2)
+
+		request := taskPb.Request{PageSize: 2}
+		response := taskPb.Response{}
+
+		err := service.Get(createContext("t", true), &request, &response)
+
+		assertError(err, nil, t)
+
+		if !reflect.DeepEqual(want, response.Tasks) {
+			t.Errorf("want %v got %v", want, response.Tasks)
+		}
+
+		if response.NextPageToken == "" {
+			t.Fatalf("wanted a next page token but didn't get one")
+		}
+
+		want = []*taskPb.Task{&fakeTask4}
+
+		request = taskPb.Request{PageSize: 2, PageToken: response.NextPageToken}
+		response = taskPb.Response{}
+
+		err = service.Get(createContext("t", true), &request, &response)
+
+		assertError(err, nil, t)
+
+		if !reflect.DeepEqual(want, response.Tasks) {
+			t.Errorf("want %v got %v", want, response.Tasks)
+		}
+
+		if response.NextPageToken != "" {
+			t.Errorf("wanted no next page token but got %v", response.NextPageToken)
+		}
+	})
+
+	t.Run("get with an invalid page token", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		request := taskPb.Request{PageToken: "not a token"}
+		response := taskPb.Response{}
+
+		err := service.Get(createContext("t", true), &request, &response)
+
+		assertError(err, errInvalidPageToken, t)
+	})
+
 }
 
 func TestCreateTasks(t *testing.T) {
@@ -137,6 +360,34 @@ func TestCreateTasks(t *testing.T) {
 
 	})
 
+	t.Run("create task with due date, priority and tags", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		request := taskPb.CreateTask{
+			Title:    "fake",
+			DueDate:  100,
+			Priority: taskPb.Priority_PRIORITY_MEDIUM,
+			Tags:     []string{" work ", "", "home", "work"},
+		}
+
+		response := taskPb.Response{}
+
+		err := service.Create(createContext("t", true), &request, &response)
+
+		assertError(err, nil, t)
+
+		if response.Task.DueDate != 100 || response.Task.Priority != taskPb.Priority_PRIORITY_MEDIUM {
+			t.Errorf("wanted due date 100 and medium priority but got %v %v", response.Task.DueDate, response.Task.Priority)
+		}
+
+		want := []string{"work", "home"}
+
+		if !reflect.DeepEqual(want, response.Task.Tags) {
+			t.Errorf("want %v got %v", want, response.Task.Tags)
+		}
+	})
+
 	t.Run("create daily do task for user 1 with daily do already existing", func(t *testing.T) {
 
 		service := createService(false, false, true)
@@ -245,9 +496,130 @@ func TestUpdateTask(t *testing.T) {
 		if fakeTask1.Description != request.Description {
 			t.Errorf("Description hasn't updated: wanted %v got %v", request.Description, fakeTask1.Description)
 		}
+
+		if response.Task == nil || response.Task.Title != request.Title {
+			t.Errorf("wanted the updated task to be returned but got %v", response.Task)
+		}
+	})
+
+	t.Run("update with a mask clears fields and leaves the rest untouched", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		fakeTask2.Description = "to be cleared"
+		fakeTask2.Priority = taskPb.Priority_PRIORITY_HIGH
+
+		request := taskPb.UpdateTask{
+			TaskId:      "456",
+			Title:       "not in the mask",
+			Description: "",
+			UpdateMask:  []string{"description"},
+		}
+
+		response := taskPb.Response{}
+
+		err := service.Update(createContext("t", true), &request, &response)
+
+		description, title, priority := fakeTask2.Description, fakeTask2.Title, fakeTask2.Priority
+		fakeTask2.Description = "Do something"
+		fakeTask2.Priority = taskPb.Priority_PRIORITY_NONE
+
+		assertError(err, nil, t)
+
+		if description != "" {
+			t.Errorf("wanted description to be cleared but got %v", description)
+		}
+
+		if title != "Test2" || priority != taskPb.Priority_PRIORITY_HIGH {
+			t.Errorf("fields not in the mask were changed: got title %v and priority %v", title, priority)
+		}
+
+		if response.Task.Description != "" || response.Task.Title != "Test2" {
+			t.Errorf("wanted the updated task to be returned but got %v", response.Task)
+		}
+	})
+
+	t.Run("update with an unknown field in the mask", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		request := taskPb.UpdateTask{
+			TaskId:     "456",
+			UpdateMask: []string{"userId"},
+		}
+
+		response := taskPb.Response{}
+
+		err := service.Update(createContext("t", true), &request, &response)
+
+		assertError(err, errUnknownUpdateField, t)
+	})
+
+	t.Run("update can't clear the title", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		request := taskPb.UpdateTask{
+			TaskId:     "456",
+			UpdateMask: []string{"title"},
+		}
+
+		response := taskPb.Response{}
+
+		err := service.Update(createContext("t", true), &request, &response)
+
+		assertError(err, errTitleRequired, t)
+
+		if fakeTask2.Title != "Test2" {
+			t.Errorf("wanted the title to be unchanged but got %v", fakeTask2.Title)
+		}
 	})
 }
 
+func TestCreateDailyDoConcurrently(t *testing.T) {
+
+	service := createService(false, false, true)
+
+	const requests = 50
+
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			request := taskPb.CreateTask{
+				Title:   "fake",
+				DailyDo: true,
+			}
+
+			errs <- service.Create(createContext("t", true), &request, &taskPb.Response{})
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	created := 0
+
+	for err := range errs {
+		switch err {
+		case nil:
+			created++
+		case errDailyDoAlreadyExists:
+		default:
+			t.Errorf("wanted %v or no error but got %v", errDailyDoAlreadyExists, err)
+		}
+	}
+
+	if created != 1 {
+		t.Errorf("wanted only one daily do to be created but %v were", created)
+	}
+}
+
 func TestChangeDailyDoStatus(t *testing.T) {
 
 	t.Run("update but repo returns error", func(t *testing.T) {
@@ -312,7 +684,7 @@ func TestChangeDailyDoStatus(t *testing.T) {
 	})
 
 	t.Run("try to set a task as daily do but a different task is already a daily do", func(t *testing.T) {
-		service := createService(false, false, false)
+		service := createService(false, false, true)
 
 		fakeTask1.DailyDo = true
 
@@ -497,3 +869,818 @@ func TestCompleteTask(t *testing.T) {
 		}
 	})
 }
+
+func TestRecurringTasks(t *testing.T) {
+	t.Run("create task with an invalid recurrence rule", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		request := taskPb.CreateTask{
+			Title:      "fake",
+			Recurrence: "FREQ=YEARLY",
+		}
+
+		response := taskPb.Response{}
+
+		err := service.Create(createContext("t", true), &request, &response)
+
+		if err == nil {
+			t.Errorf("wanted an error for an invalid recurrence rule but didn't get one")
+		}
+	})
+
+	t.Run("create recurring task starts at the first occurrence", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		request := taskPb.CreateTask{
+			Title:      "fake",
+			Recurrence: "FREQ=DAILY",
+		}
+
+		response := taskPb.Response{}
+
+		err := service.Create(createContext("t", true), &request, &response)
+
+		assertError(err, nil, t)
+
+		if response.Task.Recurrence != "FREQ=DAILY" || response.Task.Occurrence != 1 {
+			t.Errorf("wanted recurrence FREQ=DAILY at occurrence 1 but got %v at %v", response.Task.Recurrence, response.Task.Occurrence)
+		}
+	})
+
+	t.Run("completing a recurring task creates the next occurrence", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		// Monday 6th January 2020
+		dueDate := time.Date(2020, time.January, 6, 9, 0, 0, 0, time.UTC)
+
+		fakeTask4.CompletedDate = 0
+		fakeTask4.DueDate = dueDate.Unix()
+		fakeTask4.Priority = taskPb.Priority_PRIORITY_HIGH
+		fakeTask4.Tags = []string{"work"}
+		fakeTask4.Recurrence = "FREQ=WEEKLY;BYDAY=MO,WE"
+		fakeTask4.Occurrence = 1
+
+		request := taskPb.CompleteTaskRequest{
+			TaskId:    "111",
+			Completed: true,
+		}
+
+		response := taskPb.Response{}
+
+		err := service.CompleteTask(createContext("t", true), &request, &response)
+
+		fakeTask4.CompletedDate = 0
+		fakeTask4.DueDate = 0
+		fakeTask4.Priority = taskPb.Priority_PRIORITY_NONE
+		fakeTask4.Tags = nil
+		fakeTask4.Recurrence = ""
+		fakeTask4.Occurrence = 0
+
+		assertError(err, nil, t)
+
+		if response.Task == nil {
+			t.Fatalf("wanted the next occurrence to be returned but got nil")
+		}
+
+		want := time.Date(2020, time.January, 8, 9, 0, 0, 0, time.UTC).Unix()
+
+		if response.Task.DueDate != want {
+			t.Errorf("wanted next due date %v but got %v", time.Unix(want, 0).UTC(), time.Unix(response.Task.DueDate, 0).UTC())
+		}
+
+		if response.Task.Occurrence != 2 || response.Task.Recurrence != "FREQ=WEEKLY;BYDAY=MO,WE" {
+			t.Errorf("wanted occurrence 2 of the same rule but got %v of %v", response.Task.Occurrence, response.Task.Recurrence)
+		}
+
+		if response.Task.Title != "Test4" || response.Task.Priority != taskPb.Priority_PRIORITY_HIGH || !reflect.DeepEqual(response.Task.Tags, []string{"work"}) {
+			t.Errorf("next occurrence didn't keep the task details: %v", response.Task)
+		}
+	})
+
+	t.Run("completing the last occurrence doesn't create another", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		fakeTask4.CompletedDate = 0
+		fakeTask4.Recurrence = "FREQ=DAILY;COUNT=2"
+		fakeTask4.Occurrence = 2
+
+		request := taskPb.CompleteTaskRequest{
+			TaskId:    "111",
+			Completed: true,
+		}
+
+		response := taskPb.Response{}
+
+		err := service.CompleteTask(createContext("t", true), &request, &response)
+
+		fakeTask4.CompletedDate = 0
+		fakeTask4.Recurrence = ""
+		fakeTask4.Occurrence = 0
+
+		assertError(err, nil, t)
+
+		if response.Task != nil {
+			t.Errorf("wanted no next occurrence but got %v", response.Task)
+		}
+	})
+}
+
+func TestTaskVersions(t *testing.T) {
+	t.Run("update with the current version moves the task on a version", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		version := fakeTask2.Version
+
+		request := taskPb.UpdateTask{
+			TaskId:  "456",
+			Title:   "Test2",
+			Version: version,
+		}
+
+		response := taskPb.Response{}
+
+		err := service.Update(createContext("t", true), &request, &response)
+
+		assertError(err, nil, t)
+
+		if response.Task.Version != version+1 || fakeTask2.Version != version+1 {
+			t.Errorf("wanted version %v but got %v in the response and %v stored", version+1, response.Task.Version, fakeTask2.Version)
+		}
+	})
+
+	t.Run("update with a stale version", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		fakeTask2.Version = 5
+
+		request := taskPb.UpdateTask{
+			TaskId:  "456",
+			Title:   "stale",
+			Version: 4,
+		}
+
+		response := taskPb.Response{}
+
+		err := service.Update(createContext("t", true), &request, &response)
+
+		assertError(err, errTaskVersionConflict, t)
+
+		if fakeTask2.Title != "Test2" || fakeTask2.Version != 5 {
+			t.Errorf("a stale update changed the task: got title %v at version %v", fakeTask2.Title, fakeTask2.Version)
+		}
+	})
+
+	t.Run("change daily do status with a stale version", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		fakeTask2.Version = 5
+
+		request := taskPb.DailyDoStatusRequest{
+			TaskId:  "456",
+			Status:  true,
+			Version: 4,
+		}
+
+		response := taskPb.Response{}
+
+		err := service.ChangeDailyDoStatus(createContext("t", true), &request, &response)
+
+		assertError(err, errTaskVersionConflict, t)
+
+		if fakeTask2.DailyDo {
+			t.Errorf("a stale change set the task as the daily do")
+		}
+	})
+
+	t.Run("complete task with a stale version", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		fakeTask4.CompletedDate = 0
+		fakeTask4.Version = 5
+
+		request := taskPb.CompleteTaskRequest{
+			TaskId:    "111",
+			Completed: true,
+			Version:   4,
+		}
+
+		response := taskPb.Response{}
+
+		err := service.CompleteTask(createContext("t", true), &request, &response)
+
+		assertError(err, errTaskVersionConflict, t)
+
+		if fakeTask4.CompletedDate != 0 {
+			t.Errorf("a stale change completed the task")
+		}
+	})
+}
+
+func TestDeleteTask(t *testing.T) {
+	t.Run("delete but repo returns error", func(t *testing.T) {
+
+		service := createService(true, false, true)
+
+		request := taskPb.DeleteTaskRequest{}
+		response := taskPb.Response{}
+
+		err := service.Delete(createContext("t", true), &request, &response)
+
+		assertError(err, errFake, t)
+	})
+
+	t.Run("delete but returns an error for metadata provided", func(t *testing.T) {
+		service := createService(false, true, true)
+
+		request := taskPb.DeleteTaskRequest{}
+		response := taskPb.Response{}
+
+		err := service.Delete(createContext("", false), &request, &response)
+
+		assertError(err, errNoMetaData, t)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		service := createService(false, false, true)
+
+		request := taskPb.DeleteTaskRequest{
+			TaskId: "not found",
+		}
+		response := taskPb.Response{}
+
+		err := service.Delete(createContext("t", true), &request, &response)
+
+		assertError(err, errTaskNotFound, t)
+	})
+
+	t.Run("tasks user id doesn't match id in token", func(t *testing.T) {
+		service := createService(false, false, false)
+
+		request := taskPb.DeleteTaskRequest{
+			TaskId: "123",
+		}
+		response := taskPb.Response{}
+
+		err := service.Delete(createContext("t", true), &request, &response)
+
+		assertError(err, errTaskUserIDNotMatched, t)
+	})
+
+	t.Run("delete fakeTask4 for user 1 and it's hidden from get", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		request := taskPb.DeleteTaskRequest{
+			TaskId: "111",
+		}
+
+		response := taskPb.Response{}
+
+		err := service.Delete(createContext("t", true), &request, &response)
+
+		assertError(err, nil, t)
+
+		if !fakeTask4.Deleted || fakeTask4.DeletedDate == 0 {
+			t.Errorf("Task hasn't been deleted: wanted deleted with a deleted date but got %v %v", fakeTask4.Deleted, fakeTask4.DeletedDate)
+		}
+
+		getResponse := taskPb.Response{}
+
+		err = service.Get(createContext("t", true), &taskPb.Request{}, &getResponse)
+
+		assertError(err, nil, t)
+
+		for _, v := range getResponse.Tasks {
+			if v.Id == fakeTask4.Id {
+				t.Errorf("Deleted task was returned from get")
+			}
+		}
+
+		getResponse = taskPb.Response{}
+
+		err = service.Get(createContext("t", true), &taskPb.Request{IncludeDeleted: true}, &getResponse)
+
+		assertError(err, nil, t)
+
+		if len(getResponse.Tasks) != 3 {
+			t.Errorf("wanted 3 tasks including the deleted task but got %v", len(getResponse.Tasks))
+		}
+
+		fakeTask4.Deleted = false
+		fakeTask4.DeletedDate = 0
+	})
+}
+
+func TestRestoreTask(t *testing.T) {
+	t.Run("restore but repo returns error", func(t *testing.T) {
+
+		service := createService(true, false, true)
+
+		request := taskPb.RestoreTaskRequest{}
+		response := taskPb.Response{}
+
+		err := service.Restore(createContext("t", true), &request, &response)
+
+		assertError(err, errFake, t)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		service := createService(false, false, true)
+
+		request := taskPb.RestoreTaskRequest{
+			TaskId: "not found",
+		}
+		response := taskPb.Response{}
+
+		err := service.Restore(createContext("t", true), &request, &response)
+
+		assertError(err, errTaskNotFound, t)
+	})
+
+	t.Run("tasks user id doesn't match id in token", func(t *testing.T) {
+		service := createService(false, false, false)
+
+		request := taskPb.RestoreTaskRequest{
+			TaskId: "123",
+		}
+		response := taskPb.Response{}
+
+		err := service.Restore(createContext("t", true), &request, &response)
+
+		assertError(err, errTaskUserIDNotMatched, t)
+	})
+
+	t.Run("task hasn't been deleted", func(t *testing.T) {
+		service := createService(false, false, true)
+
+		request := taskPb.RestoreTaskRequest{
+			TaskId: "111",
+		}
+		response := taskPb.Response{}
+
+		err := service.Restore(createContext("t", true), &request, &response)
+
+		assertError(err, errTaskNotDeleted, t)
+	})
+
+	t.Run("restore fakeTask4 for user 1", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		fakeTask4.Deleted = true
+		fakeTask4.DeletedDate = 1
+
+		request := taskPb.RestoreTaskRequest{
+			TaskId: "111",
+		}
+
+		response := taskPb.Response{}
+
+		err := service.Restore(createContext("t", true), &request, &response)
+
+		assertError(err, nil, t)
+
+		if fakeTask4.Deleted || fakeTask4.DeletedDate != 0 {
+			t.Errorf("Task hasn't been restored: wanted not deleted but got %v %v", fakeTask4.Deleted, fakeTask4.DeletedDate)
+		}
+	})
+}
+
+func TestPurge(t *testing.T) {
+	t.Run("purge but repo returns error", func(t *testing.T) {
+
+		service := createService(true, false, true)
+
+		request := taskPb.PurgeRequest{}
+		response := taskPb.Response{}
+
+		err := service.Purge(createContext("t", true), &request, &response)
+
+		assertError(err, errFake, t)
+	})
+
+	t.Run("purge only removes tasks deleted before the purge age", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		fakeTask2.Deleted = true
+		fakeTask2.DeletedDate = time.Now().Add(-time.Hour * 2).Unix()
+		fakeTask4.Deleted = true
+		fakeTask4.DeletedDate = time.Now().Unix()
+
+		var want []*taskPb.Task
+
+		want = append(want, &fakeTask2)
+
+		request := taskPb.PurgeRequest{}
+		response := taskPb.Response{}
+
+		err := service.Purge(createContext("t", true), &request, &response)
+
+		fakeTask2.Deleted = false
+		fakeTask2.DeletedDate = 0
+		fakeTask4.Deleted = false
+		fakeTask4.DeletedDate = 0
+
+		assertError(err, nil, t)
+
+		if !reflect.DeepEqual(want, response.Tasks) {
+			t.Errorf("want %v got %v", want, response.Tasks)
+		}
+	})
+
+	t.Run("purge with an age in the request", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		fakeTask4.Deleted = true
+		fakeTask4.DeletedDate = time.Now().Add(-time.Minute * 2).Unix()
+
+		var want []*taskPb.Task
+
+		want = append(want, &fakeTask4)
+
+		request := taskPb.PurgeRequest{OlderThan: 60}
+		response := taskPb.Response{}
+
+		err := service.Purge(createContext("t", true), &request, &response)
+
+		fakeTask4.Deleted = false
+		fakeTask4.DeletedDate = 0
+
+		assertError(err, nil, t)
+
+		if !reflect.DeepEqual(want, response.Tasks) {
+			t.Errorf("want %v got %v", want, response.Tasks)
+		}
+	})
+}
+
+func TestGetDailyDoHistory(t *testing.T) {
+	t.Run("get but repo returns error", func(t *testing.T) {
+
+		service := createService(true, false, true)
+
+		request := taskPb.DailyDoHistoryRequest{}
+		response := taskPb.DailyDoHistoryResponse{}
+
+		err := service.GetDailyDoHistory(createContext("t", true), &request, &response)
+
+		assertError(err, errFake, t)
+	})
+
+	t.Run("get with an invalid day", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		request := taskPb.DailyDoHistoryRequest{From: "20/08/2019"}
+		response := taskPb.DailyDoHistoryResponse{}
+
+		err := service.GetDailyDoHistory(createContext("t", true), &request, &response)
+
+		assertError(err, errInvalidDay, t)
+	})
+
+	t.Run("completing the daily do is recorded in the history", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		err := service.ChangeDailyDoStatus(createContext("t", true), &taskPb.DailyDoStatusRequest{TaskId: "123", Status: true}, &taskPb.Response{})
+
+		assertError(err, nil, t)
+
+		err = service.CompleteTask(createContext("t", true), &taskPb.CompleteTaskRequest{TaskId: "123", Completed: true}, &taskPb.Response{})
+
+		fakeTask1.CompletedDate = 0
+
+		assertError(err, nil, t)
+
+		today := localDay(time.Now(), "")
+
+		want := []*taskPb.DailyDoHistory{
+			{
+				UserId:    userID1,
+				Day:       today,
+				TaskId:    "123",
+				Completed: true,
+			},
+		}
+
+		request := taskPb.DailyDoHistoryRequest{From: today, To: today}
+		response := taskPb.DailyDoHistoryResponse{}
+
+		err = service.GetDailyDoHistory(createContext("t", true), &request, &response)
+
+		assertError(err, nil, t)
+
+		if !reflect.DeepEqual(want, response.History) {
+			t.Errorf("want %v got %v", want, response.History)
+		}
+	})
+
+	t.Run("unsetting the daily do removes it from the history", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		err := service.ChangeDailyDoStatus(createContext("t", true), &taskPb.DailyDoStatusRequest{TaskId: "123", Status: true}, &taskPb.Response{})
+
+		assertError(err, nil, t)
+
+		err = service.ChangeDailyDoStatus(createContext("t", true), &taskPb.DailyDoStatusRequest{TaskId: "123", Status: false}, &taskPb.Response{})
+
+		assertError(err, nil, t)
+
+		response := taskPb.DailyDoHistoryResponse{}
+
+		err = service.GetDailyDoHistory(createContext("t", true), &taskPb.DailyDoHistoryRequest{}, &response)
+
+		assertError(err, nil, t)
+
+		if len(response.History) != 0 {
+			t.Errorf("wanted no history but got %v", response.History)
+		}
+	})
+}
+
+func TestGetStreak(t *testing.T) {
+	t.Run("get but repo returns error", func(t *testing.T) {
+
+		service := createService(true, false, true)
+
+		request := taskPb.StreakRequest{}
+		response := taskPb.StreakResponse{}
+
+		err := service.GetStreak(createContext("t", true), &request, &response)
+
+		assertError(err, errFake, t)
+	})
+
+	t.Run("get streak for user 1", func(t *testing.T) {
+
+		service := createService(false, false, true)
+
+		day := time.Now().UTC()
+
+		service.repo.(*fakeRepo).history = []*taskPb.DailyDoHistory{
+			{UserId: userID1, Day: day.Format(dayLayout), Completed: true},
+			{UserId: userID1, Day: day.AddDate(0, 0, -1).Format(dayLayout), Completed: true},
+			{UserId: userID1, Day: day.AddDate(0, 0, -2).Format(dayLayout), Completed: false},
+			{UserId: userID1, Day: day.AddDate(0, 0, -3).Format(dayLayout), Completed: true},
+			{UserId: userID1, Day: day.AddDate(0, 0, -4).Format(dayLayout), Completed: true},
+			{UserId: userID1, Day: day.AddDate(0, 0, -5).Format(dayLayout), Completed: true},
+			{UserId: userID2, Day: day.AddDate(0, 0, -6).Format(dayLayout), Completed: true},
+		}
+
+		request := taskPb.StreakRequest{}
+		response := taskPb.StreakResponse{}
+
+		err := service.GetStreak(createContext("t", true), &request, &response)
+
+		assertError(err, nil, t)
+
+		if response.Current != 2 {
+			t.Errorf("current streak: want %v got %v", 2, response.Current)
+		}
+
+		if response.Longest != 3 {
+			t.Errorf("longest streak: want %v got %v", 3, response.Longest)
+		}
+	})
+}
+
+func TestChecklist(t *testing.T) {
+
+	addItems := func(service taskHandler, titles ...string) []*taskPb.ChecklistItem {
+		var response taskPb.Response
+
+		for _, title := range titles {
+			response = taskPb.Response{}
+
+			err := service.AddChecklistItem(createContext("t", true), &taskPb.AddChecklistItemRequest{TaskId: "111", Title: title}, &response)
+
+			assertError(err, nil, t)
+		}
+
+		return response.Task.Checklist
+	}
+
+	t.Run("add but repo returns error", func(t *testing.T) {
+		service := createService(true, false, true)
+
+		request := taskPb.AddChecklistItemRequest{}
+		response := taskPb.Response{}
+
+		err := service.AddChecklistItem(createContext("t", true), &request, &response)
+
+		assertError(err, errFake, t)
+	})
+
+	t.Run("tasks user id doesn't match id in token", func(t *testing.T) {
+		service := createService(false, false, false)
+
+		request := taskPb.AddChecklistItemRequest{TaskId: "111", Title: "step"}
+		response := taskPb.Response{}
+
+		err := service.AddChecklistItem(createContext("t", true), &request, &response)
+
+		assertError(err, errTaskUserIDNotMatched, t)
+	})
+
+	t.Run("add items to fakeTask4 in order", func(t *testing.T) {
+		service := createService(false, false, true)
+
+		checklist := addItems(service, "first", "second")
+
+		fakeTask4.Checklist = nil
+
+		if len(checklist) != 2 || checklist[0].Title != "first" || checklist[1].Title != "second" {
+			t.Errorf("wanted checklist of first then second but got %v", checklist)
+		}
+	})
+
+	t.Run("toggle an item", func(t *testing.T) {
+		service := createService(false, false, true)
+
+		checklist := addItems(service, "first")
+
+		request := taskPb.ToggleChecklistItemRequest{TaskId: "111", ItemId: checklist[0].Id, Completed: true}
+		response := taskPb.Response{}
+
+		err := service.ToggleChecklistItem(createContext("t", true), &request, &response)
+
+		fakeTask4.Checklist = nil
+
+		assertError(err, nil, t)
+
+		if !response.Task.Checklist[0].Completed {
+			t.Errorf("checklist item wasn't completed")
+		}
+	})
+
+	t.Run("toggle an item that doesn't exist", func(t *testing.T) {
+		service := createService(false, false, true)
+
+		request := taskPb.ToggleChecklistItemRequest{TaskId: "111", ItemId: "not found", Completed: true}
+		response := taskPb.Response{}
+
+		err := service.ToggleChecklistItem(createContext("t", true), &request, &response)
+
+		assertError(err, errChecklistItemNotFound, t)
+	})
+
+	t.Run("reorder items", func(t *testing.T) {
+		service := createService(false, false, true)
+
+		checklist := addItems(service, "first", "second", "third")
+
+		request := taskPb.ReorderChecklistRequest{TaskId: "111", ItemIds: []string{checklist[2].Id, checklist[0].Id, checklist[1].Id}}
+		response := taskPb.Response{}
+
+		err := service.ReorderChecklist(createContext("t", true), &request, &response)
+
+		fakeTask4.Checklist = nil
+
+		assertError(err, nil, t)
+
+		var got []string
+		for _, item := range response.Task.Checklist {
+			got = append(got, item.Title)
+		}
+
+		want := []string{"third", "first", "second"}
+
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("want %v got %v", want, got)
+		}
+	})
+
+	t.Run("reorder without every item", func(t *testing.T) {
+		service := createService(false, false, true)
+
+		checklist := addItems(service, "first", "second")
+
+		request := taskPb.ReorderChecklistRequest{TaskId: "111", ItemIds: []string{checklist[1].Id}}
+		response := taskPb.Response{}
+
+		err := service.ReorderChecklist(createContext("t", true), &request, &response)
+
+		fakeTask4.Checklist = nil
+
+		assertError(err, errChecklistItemsNotMatched, t)
+	})
+
+	t.Run("remove an item", func(t *testing.T) {
+		service := createService(false, false, true)
+
+		checklist := addItems(service, "first", "second")
+
+		request := taskPb.RemoveChecklistItemRequest{TaskId: "111", ItemId: checklist[0].Id}
+		response := taskPb.Response{}
+
+		err := service.RemoveChecklistItem(createContext("t", true), &request, &response)
+
+		fakeTask4.Checklist = nil
+
+		assertError(err, nil, t)
+
+		if len(response.Task.Checklist) != 1 || response.Task.Checklist[0].Title != "second" {
+			t.Errorf("wanted only the second item left but got %v", response.Task.Checklist)
+		}
+	})
+
+	t.Run("complete task is refused with open items", func(t *testing.T) {
+		service := createService(false, false, true)
+
+		fakeTask4.CompletedDate = 0
+
+		addItems(service, "first")
+
+		request := taskPb.CompleteTaskRequest{TaskId: "111", Completed: true, OpenChecklist: taskPb.OpenChecklist_OPEN_CHECKLIST_REFUSE}
+		response := taskPb.Response{}
+
+		err := service.CompleteTask(createContext("t", true), &request, &response)
+
+		fakeTask4.Checklist = nil
+
+		assertError(err, errChecklistItemsOpen, t)
+
+		if fakeTask4.CompletedDate != 0 {
+			t.Errorf("task was completed with open checklist items")
+		}
+	})
+
+	t.Run("complete task completes open items", func(t *testing.T) {
+		service := createService(false, false, true)
+
+		checklist := addItems(service, "first", "second")
+
+		request := taskPb.CompleteTaskRequest{TaskId: "111", Completed: true, OpenChecklist: taskPb.OpenChecklist_OPEN_CHECKLIST_COMPLETE}
+		response := taskPb.Response{}
+
+		err := service.CompleteTask(createContext("t", true), &request, &response)
+
+		fakeTask4.Checklist = nil
+		fakeTask4.CompletedDate = 0
+
+		assertError(err, nil, t)
+
+		for _, item := range checklist {
+			if !item.Completed {
+				t.Errorf("checklist item %v wasn't completed", item.Title)
+			}
+		}
+	})
+}
+
+func TestListTags(t *testing.T) {
+	t.Run("list but repo returns error", func(t *testing.T) {
+		service := createService(true, false, true)
+
+		request := taskPb.ListTagsRequest{}
+		response := taskPb.ListTagsResponse{}
+
+		err := service.ListTags(createContext("t", true), &request, &response)
+
+		assertError(err, errFake, t)
+	})
+
+	t.Run("list tags for user 1", func(t *testing.T) {
+		service := createService(false, false, true)
+
+		fakeTask1.Tags = []string{"home", "work"}
+		fakeTask2.Tags = []string{"work"}
+		fakeTask3.Tags = []string{"other user"}
+		fakeTask4.Tags = []string{"garden"}
+		fakeTask4.Deleted = true
+
+		want := []*taskPb.TagCount{
+			{Tag: "work", Count: 2},
+			{Tag: "home", Count: 1},
+		}
+
+		request := taskPb.ListTagsRequest{}
+		response := taskPb.ListTagsResponse{}
+
+		err := service.ListTags(createContext("t", true), &request, &response)
+
+		fakeTask1.Tags = nil
+		fakeTask2.Tags = nil
+		fakeTask3.Tags = nil
+		fakeTask4.Tags = nil
+		fakeTask4.Deleted = false
+
+		assertError(err, nil, t)
+
+		if !reflect.DeepEqual(want, response.Tags) {
+			t.Errorf("want %v got %v", want, response.Tags)
+		}
+	})
+}
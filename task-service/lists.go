@@ -0,0 +1,496 @@
+package main
+
+import (
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/willdot/Go-Do/apierrors"
+	taskPb "github.com/willdot/Go-Do/task-service/proto/task"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+)
+
+var errListsNeedCompany = apierrors.Forbidden("Sharing lists and assigning tasks are only for members of a company")
+var errNotListOwner = apierrors.Forbidden("Only the owner of the list can do that")
+var errListPermissionRequired = apierrors.Validation("permission must be view or edit")
+var errListOwnerPermission = apierrors.Conflict("The owner of a list always has edit permission on it")
+var errNotCompanyMember = apierrors.NotFound("The user isn't a member of your company")
+var errListReadOnly = apierrors.Forbidden("You can only view the list")
+var errListArchived = apierrors.Conflict("The list is archived, unarchive it first")
+var errNotDailyDoUser = apierrors.Forbidden("Only the user a task is assigned to can make it their daily do")
+
+// dailyDoUser is the user whose daily do a task can be, which is who it's assigned to, or its owner if it isn't
+// assigned to anyone. Each user only has one daily do, whoever owns the tasks
+func dailyDoUser(task *taskPb.Task) string {
+	if task.AssigneeId != "" {
+		return task.AssigneeId
+	}
+
+	return task.UserId
+}
+
+// listPermission is the permission a user has on a shared list
+func listPermission(list *taskPb.TaskList, userID string) taskPb.ListPermission {
+	for _, member := range list.Members {
+		if member.UserId == userID {
+			return member.Permission
+		}
+	}
+
+	return taskPb.ListPermission_LIST_PERMISSION_NONE
+}
+
+// taskForUser gets a task that the user can see or change. The owner and the user it's assigned to can do anything
+// with a task, and the members of its list can do what their permission allows. Tasks the user can't use give the same
+// error as tasks of other users always have. The returned task keeps its owner, so changes made with it pass the owner
+// check in the repo
+func (t *taskHandler) taskForUser(userID, taskID string, permission taskPb.ListPermission) (*taskPb.Task, error) {
+	task, err := t.repo.GetTask(&taskPb.Task{Id: taskID, UserId: userID})
+
+	if err != errTaskUserIDNotMatched {
+		return task, err
+	}
+
+	task, err = t.repo.GetTaskByID(taskID)
+
+	if err == errTaskNotFound {
+		return nil, errTaskUserIDNotMatched
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if task.AssigneeId == userID {
+		return task, nil
+	}
+
+	if task.ListId != "" {
+		list, err := t.repo.GetList(task.ListId)
+
+		if err != nil && err != errListNotFound {
+			return nil, err
+		}
+
+		if err == nil && listPermission(list, userID) >= permission {
+			return task, nil
+		}
+	}
+
+	return nil, errTaskUserIDNotMatched
+}
+
+// listForUser gets a list that the user has at least the given permission on. Lists the user isn't a member of
+// aren't found, so that their ids don't leak
+func (t *taskHandler) listForUser(userID, listID string, permission taskPb.ListPermission) (*taskPb.TaskList, error) {
+	list, err := t.repo.GetList(listID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	has := listPermission(list, userID)
+
+	if has == taskPb.ListPermission_LIST_PERMISSION_NONE {
+		return nil, errListNotFound
+	}
+
+	if has < permission {
+		return nil, errListReadOnly
+	}
+
+	return list, nil
+}
+
+// checkCompanyMember checks that a user is in the same company as the caller, as lists can only be shared and tasks
+// only assigned within a company
+func (t *taskHandler) checkCompanyMember(ctx context.Context, userID string) error {
+	if strings.HasPrefix(t.tenantID, personalTenantPrefix) {
+		return errListsNeedCompany
+	}
+
+	members, err := t.userClient.ListMembers(ctx, &authPb.ListMembersRequest{})
+
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members.GetUsers() {
+		if member.Id == userID {
+			return nil
+		}
+	}
+
+	return errNotCompanyMember
+}
+
+// CreateList satisfies the CreateList RPC for the Task proto and creates a list in the callers tenant, which they own
+// and can edit. Lists group tasks into projects, and can be shared with other members of a company
+func (t *taskHandler) CreateList(ctx context.Context, req *taskPb.CreateListRequest, res *taskPb.ListResponse) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	list := taskPb.TaskList{
+		Name:        req.Name,
+		OwnerId:     userID,
+		CreatedDate: t.clock.Now().Unix(),
+	}
+
+	err = t.repo.CreateList(&list)
+
+	if err != nil {
+		return err
+	}
+
+	res.List = &list
+
+	return nil
+}
+
+// GetLists satisfies the GetLists RPC for the Task proto and gets the lists the caller is a member of. Archived lists
+// are left out unless the request asks for them
+func (t *taskHandler) GetLists(ctx context.Context, req *taskPb.GetListsRequest, res *taskPb.ListResponse) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	lists, err := t.repo.GetListsForUser(userID)
+
+	if err != nil {
+		return err
+	}
+
+	for _, list := range lists {
+		if !list.Archived || req.IncludeArchived {
+			res.Lists = append(res.Lists, list)
+		}
+	}
+
+	return nil
+}
+
+// UpdateList satisfies the UpdateList RPC for the Task proto and renames a list the caller can edit
+func (t *taskHandler) UpdateList(ctx context.Context, req *taskPb.UpdateListRequest, res *taskPb.ListResponse) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	list, err := t.listForUser(userID, req.ListId, taskPb.ListPermission_LIST_PERMISSION_EDIT)
+
+	if err != nil {
+		return err
+	}
+
+	err = t.repo.UpdateList(&taskPb.TaskList{Id: list.Id, Name: req.Name})
+
+	if err != nil {
+		return err
+	}
+
+	return t.setListResponse(list.Id, res)
+}
+
+// ArchiveList satisfies the ArchiveList RPC for the Task proto and archives or unarchives a list. Only the owner of the
+// list can archive it
+func (t *taskHandler) ArchiveList(ctx context.Context, req *taskPb.ArchiveListRequest, res *taskPb.ListResponse) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	list, err := t.listForUser(userID, req.ListId, taskPb.ListPermission_LIST_PERMISSION_VIEW)
+
+	if err != nil {
+		return err
+	}
+
+	if list.OwnerId != userID {
+		return errNotListOwner
+	}
+
+	err = t.repo.SetListArchived(list.Id, req.Archived)
+
+	if err != nil {
+		return err
+	}
+
+	return t.setListResponse(list.Id, res)
+}
+
+// DeleteList satisfies the DeleteList RPC for the Task proto and deletes a list. The tasks in it are kept by their
+// owners, outside of any list. Only the owner of the list can delete it
+func (t *taskHandler) DeleteList(ctx context.Context, req *taskPb.DeleteListRequest, res *taskPb.ListResponse) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	list, err := t.listForUser(userID, req.ListId, taskPb.ListPermission_LIST_PERMISSION_VIEW)
+
+	if err != nil {
+		return err
+	}
+
+	if list.OwnerId != userID {
+		return errNotListOwner
+	}
+
+	return t.repo.DeleteList(list.Id)
+}
+
+// MoveTask satisfies the MoveTask RPC for the Task proto and moves a task to the end of another list, or takes it out of
+// its list if the request doesn't have one. The caller has to be able to edit the task and the list it's moved to
+func (t *taskHandler) MoveTask(ctx context.Context, req *taskPb.MoveTaskRequest, res *taskPb.Response) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	existingTask, err := t.taskForUser(userID, req.TaskId, taskPb.ListPermission_LIST_PERMISSION_EDIT)
+
+	if err != nil {
+		return err
+	}
+
+	if existingTask.ListId != "" {
+		list, err := t.repo.GetList(existingTask.ListId)
+
+		if err != nil && err != errListNotFound {
+			return err
+		}
+
+		if err == nil && list.Archived {
+			return errListArchived
+		}
+	}
+
+	if req.ListId != "" {
+		if _, err := t.editableList(userID, req.ListId); err != nil {
+			return err
+		}
+	}
+
+	task := *existingTask
+	task.ListId = req.ListId
+	task.Version = expectedVersion(req.Version, existingTask)
+
+	err = t.repo.MoveTask(&task)
+
+	if err != nil {
+		return err
+	}
+
+	res.Task = &task
+
+	return nil
+}
+
+// ReorderList satisfies the ReorderList RPC for the Task proto and puts the tasks in a list into the order of the task
+// ids in the request. Tasks that aren't in the request keep their order, after the ones that are
+func (t *taskHandler) ReorderList(ctx context.Context, req *taskPb.ReorderListRequest, res *taskPb.Response) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	list, err := t.editableList(userID, req.ListId)
+
+	if err != nil {
+		return err
+	}
+
+	return t.repo.ReorderList(list.Id, req.TaskIds)
+}
+
+// editableList gets a list that the user can edit and that isn't archived, so that tasks can be put in it
+func (t *taskHandler) editableList(userID, listID string) (*taskPb.TaskList, error) {
+	list, err := t.listForUser(userID, listID, taskPb.ListPermission_LIST_PERMISSION_EDIT)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if list.Archived {
+		return nil, errListArchived
+	}
+
+	return list, nil
+}
+
+// ShareList satisfies the ShareList RPC for the Task proto and gives a member of the company view or edit permission on
+// a list, replacing the permission they had. Only the owner of the list can share it
+func (t *taskHandler) ShareList(ctx context.Context, req *taskPb.ShareListRequest, res *taskPb.ListResponse) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if req.Permission == taskPb.ListPermission_LIST_PERMISSION_NONE {
+		return errListPermissionRequired
+	}
+
+	list, err := t.listForUser(userID, req.ListId, taskPb.ListPermission_LIST_PERMISSION_VIEW)
+
+	if err != nil {
+		return err
+	}
+
+	if list.OwnerId != userID {
+		return errNotListOwner
+	}
+
+	if req.UserId == list.OwnerId {
+		return errListOwnerPermission
+	}
+
+	err = t.checkCompanyMember(ctx, req.UserId)
+
+	if err != nil {
+		return err
+	}
+
+	err = t.repo.SetListMember(list.Id, &taskPb.ListMember{UserId: req.UserId, Permission: req.Permission})
+
+	if err != nil {
+		return err
+	}
+
+	return t.setListResponse(list.Id, res)
+}
+
+// UnshareList satisfies the UnshareList RPC for the Task proto and takes away a users permission on a list. The owner
+// can remove anyone else, and members can remove themselves by not giving a user. Tasks in the list that are assigned
+// to the user can still be seen by them
+func (t *taskHandler) UnshareList(ctx context.Context, req *taskPb.UnshareListRequest, res *taskPb.ListResponse) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	list, err := t.listForUser(userID, req.ListId, taskPb.ListPermission_LIST_PERMISSION_VIEW)
+
+	if err != nil {
+		return err
+	}
+
+	removed := req.UserId
+
+	if removed == "" {
+		removed = userID
+	}
+
+	if removed != userID && list.OwnerId != userID {
+		return errNotListOwner
+	}
+
+	if removed == list.OwnerId {
+		return errListOwnerPermission
+	}
+
+	err = t.repo.RemoveListMember(list.Id, removed)
+
+	if err != nil {
+		return err
+	}
+
+	if removed == userID {
+		return nil
+	}
+
+	return t.setListResponse(list.Id, res)
+}
+
+// setListResponse sets the response list to the list as it's now stored
+func (t *taskHandler) setListResponse(listID string, res *taskPb.ListResponse) error {
+
+	list, err := t.repo.GetList(listID)
+
+	if err != nil {
+		return err
+	}
+
+	res.List = list
+
+	return nil
+}
+
+// AssignTask satisfies the AssignTask RPC for the Task proto and assigns a task to a member of the company, or
+// unassigns it if there's no assignee. A task that was the daily do of the user it was assigned to stops being it
+func (t *taskHandler) AssignTask(ctx context.Context, req *taskPb.AssignTaskRequest, res *taskPb.Response) error {
+
+	t, userID, err := t.forCaller(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	existingTask, err := t.taskForUser(userID, req.TaskId, taskPb.ListPermission_LIST_PERMISSION_EDIT)
+
+	if err != nil {
+		return err
+	}
+
+	if req.AssigneeId != "" {
+		err = t.checkCompanyMember(ctx, req.AssigneeId)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// The day of the user whose daily do the task was is found before it's assigned, so a failure doesn't leave it
+	// assigned without its history being updated
+	var today string
+
+	if existingTask.DailyDo {
+		today, err = t.todayForUser(ctx, dailyDoUser(existingTask))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	task := *existingTask
+	task.AssigneeId = req.AssigneeId
+	task.Version = expectedVersion(req.Version, existingTask)
+
+	err = t.repo.AssignTask(&task)
+
+	if err != nil {
+		return err
+	}
+
+	if existingTask.DailyDo {
+		err = t.recordDailyDoChange(dailyDoUser(existingTask), today, task.Id, false)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	res.Task = &task
+
+	return nil
+}
@@ -0,0 +1,585 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	taskPb "github.com/willdot/Go-Do/task-service/proto/task"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+)
+
+const userID3 = "333"
+
+// createListService creates a fake service where the first user owns a list in acme with a task in it, that the
+// second user can view. The third user is in acme but can't see the list
+func createListService() taskHandler {
+	service := createService(false, false, true)
+	service.clock = &fakeClock{time.Now()}
+
+	service.repo = &fakeRepo{
+		tasks: []*taskPb.Task{
+			{Id: "shared", Title: "Shared", UserId: userID1, TenantId: "acme", ListId: "list1", Version: 1},
+		},
+		lists: []*taskPb.TaskList{
+			{Id: "list1", Name: "Team", OwnerId: userID1, TenantId: "acme", Members: []*taskPb.ListMember{
+				{UserId: userID1, Permission: taskPb.ListPermission_LIST_PERMISSION_EDIT},
+				{UserId: userID2, Permission: taskPb.ListPermission_LIST_PERMISSION_VIEW},
+			}},
+		},
+		listOrder: map[string][]string{"list1": {"shared"}},
+	}
+
+	service.userClient = &fakeUserHandler{users: map[string]*authPb.User{
+		userID1: {Id: userID1, CompanyId: "acme"},
+		userID2: {Id: userID2, CompanyId: "acme"},
+		userID3: {Id: userID3, CompanyId: "acme"},
+	}}
+
+	return service
+}
+
+func TestCreateList(t *testing.T) {
+
+	t.Run("the creator owns the list and can edit it", func(t *testing.T) {
+		service := createListService()
+
+		response := taskPb.ListResponse{}
+
+		err := service.CreateList(createCompanyContext(userID3, "acme"), &taskPb.CreateListRequest{Name: "Mine"}, &response)
+
+		assertError(err, nil, t)
+
+		if response.List.TenantId != "acme" || listPermission(response.List, userID3) != taskPb.ListPermission_LIST_PERMISSION_EDIT {
+			t.Errorf("wanted a list in acme that the creator can edit but got %v", response.List)
+		}
+	})
+
+	t.Run("users without a company can have lists but can't share them", func(t *testing.T) {
+		service := createListService()
+		ctx := createCompanyContext(userID1, "")
+
+		response := taskPb.ListResponse{}
+
+		err := service.CreateList(ctx, &taskPb.CreateListRequest{Name: "Mine"}, &response)
+
+		assertError(err, nil, t)
+
+		request := taskPb.ShareListRequest{ListId: response.List.Id, UserId: userID2, Permission: taskPb.ListPermission_LIST_PERMISSION_VIEW}
+
+		err = service.ShareList(ctx, &request, &taskPb.ListResponse{})
+
+		assertError(err, errListsNeedCompany, t)
+	})
+
+	t.Run("users only get the lists they're members of in their company", func(t *testing.T) {
+		service := createListService()
+
+		tests := []struct {
+			userID    string
+			companyID string
+			want      int
+		}{
+			{userID2, "acme", 1},
+			{userID3, "acme", 0},
+			{userID1, "globex", 0},
+		}
+
+		for _, tt := range tests {
+			response := taskPb.ListResponse{}
+
+			err := service.GetLists(createCompanyContext(tt.userID, tt.companyID), &taskPb.GetListsRequest{}, &response)
+
+			assertError(err, nil, t)
+
+			if len(response.Lists) != tt.want {
+				t.Errorf("wanted %v lists for %v in %v but got %v", tt.want, tt.userID, tt.companyID, response.Lists)
+			}
+		}
+	})
+}
+
+func TestShareList(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		caller     string
+		user       string
+		permission taskPb.ListPermission
+		want       error
+	}{
+		{"the owner can share with a member of the company", userID1, userID3, taskPb.ListPermission_LIST_PERMISSION_EDIT, nil},
+		{"the owner can change a permission", userID1, userID2, taskPb.ListPermission_LIST_PERMISSION_EDIT, nil},
+		{"only the owner can share", userID2, userID3, taskPb.ListPermission_LIST_PERMISSION_VIEW, errNotListOwner},
+		{"users outside the company can't be given permission", userID1, "999", taskPb.ListPermission_LIST_PERMISSION_VIEW, errNotCompanyMember},
+		{"the owner always has edit permission", userID1, userID1, taskPb.ListPermission_LIST_PERMISSION_VIEW, errListOwnerPermission},
+		{"a permission is needed", userID1, userID3, taskPb.ListPermission_LIST_PERMISSION_NONE, errListPermissionRequired},
+		{"lists the caller isn't a member of aren't found", userID3, userID3, taskPb.ListPermission_LIST_PERMISSION_VIEW, errListNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := createListService()
+
+			response := taskPb.ListResponse{}
+
+			request := taskPb.ShareListRequest{ListId: "list1", UserId: tt.user, Permission: tt.permission}
+
+			err := service.ShareList(createCompanyContext(tt.caller, "acme"), &request, &response)
+
+			assertError(err, tt.want, t)
+
+			if tt.want == nil && listPermission(response.List, tt.user) != tt.permission {
+				t.Errorf("wanted %v to have %v but got %v", tt.user, tt.permission, response.List)
+			}
+		})
+	}
+}
+
+func TestUnshareList(t *testing.T) {
+
+	t.Run("members can leave a list", func(t *testing.T) {
+		service := createListService()
+		ctx := createCompanyContext(userID2, "acme")
+
+		err := service.UnshareList(ctx, &taskPb.UnshareListRequest{ListId: "list1"}, &taskPb.ListResponse{})
+
+		assertError(err, nil, t)
+
+		err = service.Get(ctx, &taskPb.Request{ListId: "list1"}, &taskPb.Response{})
+
+		assertError(err, errListNotFound, t)
+	})
+
+	t.Run("members can't remove others", func(t *testing.T) {
+		service := createListService()
+
+		err := service.UnshareList(createCompanyContext(userID2, "acme"), &taskPb.UnshareListRequest{ListId: "list1", UserId: userID1}, &taskPb.ListResponse{})
+
+		assertError(err, errNotListOwner, t)
+	})
+
+	t.Run("the owner can't be removed", func(t *testing.T) {
+		service := createListService()
+
+		err := service.UnshareList(createCompanyContext(userID1, "acme"), &taskPb.UnshareListRequest{ListId: "list1"}, &taskPb.ListResponse{})
+
+		assertError(err, errListOwnerPermission, t)
+	})
+}
+
+func TestListPermissions(t *testing.T) {
+
+	t.Run("viewers can get the tasks in the list but not change them", func(t *testing.T) {
+		service := createListService()
+		ctx := createCompanyContext(userID2, "acme")
+
+		response := taskPb.Response{}
+
+		err := service.Get(ctx, &taskPb.Request{ListId: "list1"}, &response)
+
+		assertError(err, nil, t)
+
+		if len(response.Tasks) != 1 || response.Tasks[0].Id != "shared" {
+			t.Errorf("wanted the shared task but got %v", response.Tasks)
+		}
+
+		err = service.Update(ctx, &taskPb.UpdateTask{TaskId: "shared", Title: "Changed"}, &taskPb.Response{})
+
+		assertError(err, errTaskUserIDNotMatched, t)
+
+		err = service.Create(ctx, &taskPb.CreateTask{Title: "New", ListId: "list1"}, &taskPb.Response{})
+
+		assertError(err, errListReadOnly, t)
+	})
+
+	t.Run("editors can change the tasks in the list", func(t *testing.T) {
+		service := createListService()
+		service.repo.SetListMember("list1", &taskPb.ListMember{UserId: userID2, Permission: taskPb.ListPermission_LIST_PERMISSION_EDIT})
+
+		ctx := createCompanyContext(userID2, "acme")
+
+		err := service.Update(ctx, &taskPb.UpdateTask{TaskId: "shared", Title: "Changed"}, &taskPb.Response{})
+
+		assertError(err, nil, t)
+
+		err = service.CompleteTask(ctx, &taskPb.CompleteTaskRequest{TaskId: "shared", Completed: true}, &taskPb.Response{})
+
+		assertError(err, nil, t)
+
+		if task, _ := service.repo.GetTaskByID("shared"); task.Title != "Changed" || task.CompletedDate == 0 || task.UserId != userID1 {
+			t.Errorf("wanted the task to be changed and still owned by the first user but got %v", task)
+		}
+	})
+
+	t.Run("users that aren't members can't see the tasks", func(t *testing.T) {
+		service := createListService()
+		ctx := createCompanyContext(userID3, "acme")
+
+		err := service.Get(ctx, &taskPb.Request{ListId: "list1"}, &taskPb.Response{})
+
+		assertError(err, errListNotFound, t)
+
+		err = service.Delete(ctx, &taskPb.DeleteTaskRequest{TaskId: "shared"}, &taskPb.Response{})
+
+		assertError(err, errTaskUserIDNotMatched, t)
+	})
+}
+
+func TestAssignTask(t *testing.T) {
+
+	assign := func(service taskHandler, assigneeID string, t *testing.T) {
+		t.Helper()
+
+		err := service.AssignTask(createCompanyContext(userID1, "acme"), &taskPb.AssignTaskRequest{TaskId: "shared", AssigneeId: assigneeID}, &taskPb.Response{})
+
+		assertError(err, nil, t)
+	}
+
+	t.Run("assigned tasks can be got and changed by the assignee", func(t *testing.T) {
+		service := createListService()
+		assign(service, userID3, t)
+
+		ctx := createCompanyContext(userID3, "acme")
+
+		response := taskPb.Response{}
+
+		err := service.Get(ctx, &taskPb.Request{AssignedToMe: true}, &response)
+
+		assertError(err, nil, t)
+
+		if len(response.Tasks) != 1 || response.Tasks[0].AssigneeId != userID3 {
+			t.Errorf("wanted the assigned task but got %v", response.Tasks)
+		}
+
+		err = service.Update(ctx, &taskPb.UpdateTask{TaskId: "shared", Title: "Changed"}, &taskPb.Response{})
+
+		assertError(err, nil, t)
+	})
+
+	t.Run("assigned tasks are the daily do of the assignee, not the owner", func(t *testing.T) {
+		service := createListService()
+		assign(service, userID3, t)
+
+		request := taskPb.DailyDoStatusRequest{TaskId: "shared", Status: true}
+
+		err := service.ChangeDailyDoStatus(createCompanyContext(userID1, "acme"), &request, &taskPb.Response{})
+
+		assertError(err, errNotDailyDoUser, t)
+
+		err = service.ChangeDailyDoStatus(createCompanyContext(userID3, "acme"), &request, &taskPb.Response{})
+
+		assertError(err, nil, t)
+
+		if dailyDo, _ := service.repo.GetDailyDoForUser(userID3); dailyDo == nil || dailyDo.Id != "shared" {
+			t.Errorf("wanted the task to be the assignees daily do but got %v", dailyDo)
+		}
+
+		err = service.Create(createCompanyContext(userID1, "acme"), &taskPb.CreateTask{Title: "Own", DailyDo: true}, &taskPb.Response{})
+
+		assertError(err, nil, t)
+	})
+
+	t.Run("reassigning a task stops it being the daily do", func(t *testing.T) {
+		service := createListService()
+		assign(service, userID3, t)
+
+		service.ChangeDailyDoStatus(createCompanyContext(userID3, "acme"), &taskPb.DailyDoStatusRequest{TaskId: "shared", Status: true}, &taskPb.Response{})
+
+		assign(service, userID2, t)
+
+		if dailyDo, _ := service.repo.GetDailyDoForUser(userID3); dailyDo != nil {
+			t.Errorf("wanted the old assignee to have no daily do but got %v", dailyDo)
+		}
+	})
+
+	t.Run("the assignee can complete the owners recurring task", func(t *testing.T) {
+		service := createListService()
+		assign(service, userID3, t)
+
+		shared, _ := service.repo.GetTaskByID("shared")
+		shared.Recurrence = "FREQ=DAILY"
+
+		ctx := createCompanyContext(userID3, "acme")
+
+		service.ChangeDailyDoStatus(ctx, &taskPb.DailyDoStatusRequest{TaskId: "shared", Status: true}, &taskPb.Response{})
+
+		response := taskPb.Response{}
+
+		err := service.CompleteTask(ctx, &taskPb.CompleteTaskRequest{TaskId: "shared", Completed: true}, &response)
+
+		assertError(err, nil, t)
+
+		if response.Task == nil || response.Task.UserId != userID1 || response.Task.AssigneeId != userID3 {
+			t.Errorf("wanted the next occurrence for the owner, assigned to the assignee, but got %v", response.Task)
+		}
+
+		today := service.clock.Now().UTC().Format(dayLayout)
+
+		if history, _ := service.repo.GetDailyDoHistoryForDay(userID3, today); history == nil || !history.Completed {
+			t.Errorf("wanted the assignees daily do to be recorded as completed but got %v", history)
+		}
+	})
+
+	t.Run("nothing is changed if the users can't be got", func(t *testing.T) {
+		service := createListService()
+		assign(service, userID3, t)
+
+		shared, _ := service.repo.GetTaskByID("shared")
+		shared.Recurrence = "FREQ=DAILY"
+
+		service.serviceToken = ""
+
+		err := service.CompleteTask(createCompanyContext(userID3, "acme"), &taskPb.CompleteTaskRequest{TaskId: "shared", Completed: true}, &taskPb.Response{})
+
+		assertError(err, errFakeUnknownUser, t)
+
+		if shared.CompletedDate != 0 {
+			t.Errorf("wanted the task not to be completed when the next occurrence couldn't be worked out")
+		}
+	})
+
+	t.Run("tasks can only be assigned to members of the company", func(t *testing.T) {
+		service := createListService()
+
+		err := service.AssignTask(createCompanyContext(userID1, "acme"), &taskPb.AssignTaskRequest{TaskId: "shared", AssigneeId: "999"}, &taskPb.Response{})
+
+		assertError(err, errNotCompanyMember, t)
+	})
+}
+
+func TestProjects(t *testing.T) {
+
+	ctx := createCompanyContext(userID1, "acme")
+
+	// addTasks creates tasks in the list, each with a different created date so that the orders can be told apart
+	addTasks := func(service taskHandler, t *testing.T, titles ...string) {
+		t.Helper()
+
+		fake := service.repo.(*fakeRepo)
+
+		for i, title := range titles {
+			task := &taskPb.Task{Id: title, Title: title, UserId: userID1, TenantId: "acme", ListId: "list1", CreatedDate: int64(10 - i), Version: 1}
+			fake.tasks = append(fake.tasks, task)
+			fake.addToList("list1", task.Id)
+		}
+	}
+
+	titles := func(tasks []*taskPb.Task) []string {
+		var got []string
+
+		for _, task := range tasks {
+			got = append(got, task.Title)
+		}
+
+		return got
+	}
+
+	t.Run("the tasks in a list can be put in order", func(t *testing.T) {
+		service := createListService()
+		addTasks(service, t, "a", "b")
+
+		err := service.ReorderList(ctx, &taskPb.ReorderListRequest{ListId: "list1", TaskIds: []string{"b", "shared"}}, &taskPb.Response{})
+
+		assertError(err, nil, t)
+
+		response := taskPb.Response{}
+
+		err = service.Get(ctx, &taskPb.Request{ListId: "list1", SortOrder: taskPb.SortOrder_LIST_POSITION}, &response)
+
+		assertError(err, nil, t)
+
+		if got := titles(response.Tasks); len(got) != 3 || got[0] != "b" || got[1] != "Shared" || got[2] != "a" {
+			t.Errorf("wanted b, Shared then a but got %v", got)
+		}
+
+		err = service.ReorderList(ctx, &taskPb.ReorderListRequest{ListId: "list1", TaskIds: []string{"b", "b"}}, &taskPb.Response{})
+
+		assertError(err, errListTasksNotMatched, t)
+	})
+
+	t.Run("tasks can be moved between lists", func(t *testing.T) {
+		service := createListService()
+
+		response := taskPb.ListResponse{}
+		service.CreateList(ctx, &taskPb.CreateListRequest{Name: "Other"}, &response)
+
+		moved := taskPb.Response{}
+
+		err := service.MoveTask(ctx, &taskPb.MoveTaskRequest{TaskId: "shared", ListId: response.List.Id}, &moved)
+
+		assertError(err, nil, t)
+
+		if moved.Task.ListId != response.List.Id {
+			t.Errorf("wanted the task to be in the new list but got %v", moved.Task.ListId)
+		}
+
+		tasks := taskPb.Response{}
+		service.Get(ctx, &taskPb.Request{ListId: "list1"}, &tasks)
+
+		if len(tasks.Tasks) != 0 {
+			t.Errorf("wanted the old list to be empty but got %v", tasks.Tasks)
+		}
+
+		err = service.MoveTask(createCompanyContext(userID2, "acme"), &taskPb.MoveTaskRequest{TaskId: "shared", ListId: "list1"}, &taskPb.Response{})
+
+		assertError(err, errTaskUserIDNotMatched, t)
+	})
+
+	t.Run("archived lists are hidden and closed to new tasks", func(t *testing.T) {
+		service := createListService()
+
+		err := service.ArchiveList(createCompanyContext(userID2, "acme"), &taskPb.ArchiveListRequest{ListId: "list1", Archived: true}, &taskPb.ListResponse{})
+
+		assertError(err, errNotListOwner, t)
+
+		err = service.ArchiveList(ctx, &taskPb.ArchiveListRequest{ListId: "list1", Archived: true}, &taskPb.ListResponse{})
+
+		assertError(err, nil, t)
+
+		response := taskPb.ListResponse{}
+		service.GetLists(ctx, &taskPb.GetListsRequest{}, &response)
+
+		if len(response.Lists) != 0 {
+			t.Errorf("wanted the archived list to be hidden but got %v", response.Lists)
+		}
+
+		service.GetLists(ctx, &taskPb.GetListsRequest{IncludeArchived: true}, &response)
+
+		if len(response.Lists) != 1 {
+			t.Errorf("wanted the archived list when asked for but got %v", response.Lists)
+		}
+
+		err = service.Create(ctx, &taskPb.CreateTask{Title: "New", ListId: "list1"}, &taskPb.Response{})
+
+		assertError(err, errListArchived, t)
+
+		err = service.MoveTask(ctx, &taskPb.MoveTaskRequest{TaskId: "shared"}, &taskPb.Response{})
+
+		assertError(err, errListArchived, t)
+	})
+
+	t.Run("lists can be renamed by editors", func(t *testing.T) {
+		service := createListService()
+
+		response := taskPb.ListResponse{}
+
+		err := service.UpdateList(ctx, &taskPb.UpdateListRequest{ListId: "list1", Name: "Renamed"}, &response)
+
+		assertError(err, nil, t)
+
+		if response.List.Name != "Renamed" {
+			t.Errorf("wanted the list to be renamed but got %v", response.List.Name)
+		}
+
+		err = service.UpdateList(createCompanyContext(userID2, "acme"), &taskPb.UpdateListRequest{ListId: "list1", Name: "Mine"}, &response)
+
+		assertError(err, errListReadOnly, t)
+	})
+
+	t.Run("deleting a list keeps its tasks", func(t *testing.T) {
+		service := createListService()
+
+		err := service.DeleteList(ctx, &taskPb.DeleteListRequest{ListId: "list1"}, &taskPb.ListResponse{})
+
+		assertError(err, nil, t)
+
+		if task, _ := service.repo.GetTaskByID("shared"); task == nil || task.ListId != "" {
+			t.Errorf("wanted the task to be kept outside of a list but got %v", task)
+		}
+
+		err = service.Get(ctx, &taskPb.Request{ListId: "list1"}, &taskPb.Response{})
+
+		assertError(err, errListNotFound, t)
+	})
+}
+
+func TestReorderedList(t *testing.T) {
+
+	positions := map[string]int{"a": 0, "b": 1, "c": 2}
+
+	tests := []struct {
+		name    string
+		taskIDs []string
+		want    []string
+		ok      bool
+	}{
+		{"all of the tasks", []string{"c", "a", "b"}, []string{"c", "a", "b"}, true},
+		{"the rest keep their order", []string{"c"}, []string{"c", "a", "b"}, true},
+		{"tasks not in the list", []string{"d"}, nil, false},
+		{"repeated tasks", []string{"a", "a"}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := reorderedList(positions, tt.taskIDs)
+
+			if ok != tt.ok || strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("wanted %v %v but got %v %v", tt.want, tt.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestListItemRows(t *testing.T) {
+
+	listID, taskID := gocql.TimeUUID(), gocql.TimeUUID()
+	task := &taskPb.Task{Id: taskID.String(), UserId: userID1, CreatedDate: 1560000000, Title: "A task", ListId: listID.String(), Version: 2}
+
+	rows := listItemRows(task, 4)
+
+	if len(rows) != 2 {
+		t.Fatalf("wanted a row in each order but got %v", len(rows))
+	}
+
+	for _, row := range rows {
+		if len(row) != strings.Count(insertListItem, "?") {
+			t.Errorf("wanted a value for every column but got %v", row)
+		}
+	}
+
+	if rows[0][1] != int(taskPb.SortOrder_CREATED_ASCENDING) || rows[0][2] != task.CreatedDate {
+		t.Errorf("wanted the first row to be in the order the tasks were created but got %v", rows[0][:4])
+	}
+
+	if rows[1][1] != int(taskPb.SortOrder_LIST_POSITION) || rows[1][2] != int64(4) {
+		t.Errorf("wanted the second row to be in the order of the list but got %v", rows[1][:4])
+	}
+}
+
+func TestTaskFromCopy(t *testing.T) {
+
+	listID, taskID := gocql.TimeUUID(), gocql.TimeUUID()
+
+	m := map[string]interface{}{
+		"listid":        listID,
+		"sortorder":     int(taskPb.SortOrder_LIST_POSITION),
+		"sortkey":       int64(0),
+		"taskid":        taskID,
+		"position":      0,
+		"userid":        userID1,
+		"createddate":   time.Unix(1560000000, 0),
+		"title":         "A task",
+		"description":   "",
+		"completeddate": time.Time{},
+		"dailydo":       false,
+		"deleted":       false,
+		"deleteddate":   time.Time{},
+		"duedate":       time.Time{},
+		"priority":      0,
+		"tags":          []string(nil),
+		"recurrence":    "",
+		"occurrence":    0,
+		"version":       2,
+		"tenantid":      "acme",
+		"assigneeid":    userID2,
+	}
+
+	task := taskFromCopy(m)
+
+	if task.Id != taskID.String() || task.ListId != listID.String() || task.AssigneeId != userID2 || task.Version != 2 {
+		t.Errorf("wanted the task copied into the list but got %v", task)
+	}
+}
@@ -1,19 +1,26 @@
 package main
 
 import (
-	"errors"
 	"fmt"
 	"log"
+	"os"
+	"time"
 
 	"github.com/micro/go-micro"
-	"github.com/micro/go-micro/client"
-	"github.com/micro/go-micro/metadata"
 	"github.com/micro/go-micro/server"
-	taskPb "github.com/willdot/go-do/task-service/proto/task"
-	authPb "github.com/willdot/go-do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/apierrors"
+	taskPb "github.com/willdot/Go-Do/task-service/proto/task"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/user-service/verifier"
 	"golang.org/x/net/context"
 )
 
+// defaultPurgeAge is how long deleted tasks are kept for before they can be purged
+const defaultPurgeAge = time.Hour * 24 * 30
+
+// defaultRevocationTTL is how long the auth service saying a token hasn't been revoked is trusted for
+const defaultRevocationTTL = time.Second * 30
+
 func main() {
 
 	CassandraSession := Session
@@ -21,45 +28,81 @@ func main() {
 
 	repo := &TaskRepository{CassandraSession}
 
+	purgeAge := defaultPurgeAge
+
+	if purgeAgeString := os.Getenv("TASK_PURGE_AGE"); purgeAgeString != "" {
+		var err error
+		purgeAge, err = time.ParseDuration(purgeAgeString)
+
+		if err != nil {
+			log.Fatalf("invalid TASK_PURGE_AGE: %v", err)
+		}
+	}
+
 	srv := micro.NewService(
 		micro.Name("go_do.task"),
-		micro.WrapHandler(AuthWrapper),
 	)
 
 	authClient := authPb.NewAuthClient("go_do.auth", srv.Client())
 
-	srv.Init()
+	// Tokens are verified locally. JWT_SECRET only needs to be set if the auth service signs tokens with it, and
+	// TOKEN_REVOCATION_TTL is how long a check with the auth service that a token hasn't been revoked is trusted for
+	tokenVerifier := verifier.New(authClient, []byte(os.Getenv("JWT_SECRET")), durationFromEnv("TOKEN_REVOCATION_TTL", defaultRevocationTTL))
+
+	// Errors from every wrapper are converted to the catalogue, and requests are authorised before they're validated, so
+	// that requests without a token don't learn anything
+	srv.Init(micro.WrapHandler(apierrors.HandlerWrapper, AuthWrapper(tokenVerifier), newValidator().HandlerWrapper))
+
+	taskPb.RegisterTaskServiceHandler(srv.Server(), &taskHandler{repo, authClient, purgeAge, realClock{}, os.Getenv("SERVICE_TOKEN"), ""})
+
+	// SERVICE_TOKEN is shared with the auth service, so that the scheduler can get the settings of every user
+	scheduler := DailyDoScheduler{repo, authClient, realClock{}, os.Getenv("SERVICE_TOKEN")}
 
-	taskPb.RegisterTaskServiceHandler(srv.Server(), &taskHandler{repo, authClient})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go scheduler.Start(ctx, rolloverInterval)
 
 	if err := srv.Run(); err != nil {
 		fmt.Println(err)
 	}
 }
 
-// AuthWrapper is a wrapper for authorising using a JWT
-func AuthWrapper(fn server.HandlerFunc) server.HandlerFunc {
-	return func(ctx context.Context, req server.Request, resp interface{}) error {
-		meta, ok := metadata.FromContext(ctx)
+// AuthWrapper verifies the JWT in a request and adds its claims to the context, so that the handlers don't
+// need to ask the auth service who the user is
+func AuthWrapper(tokenVerifier *verifier.Verifier) server.HandlerWrapper {
+	return func(fn server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, resp interface{}) error {
+			token, err := getTokenFromContext(ctx)
+
+			if err != nil {
+				return err
+			}
+
+			claims, err := tokenVerifier.Verify(ctx, token)
 
-		if !ok {
-			return errors.New("no auth meta data found in request")
+			if err != nil {
+				return apierrors.Categorise(err, apierrors.CodeUnauthenticated)
+			}
+
+			return fn(verifier.NewContext(ctx, claims), req, resp)
 		}
+	}
+}
 
-		token := meta["Token"]
-		log.Println("Authenticating token: ", token)
+// durationFromEnv gets a duration from an environment variable, or the default if it isn't set
+func durationFromEnv(name string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(name)
 
-		authClient := authPb.NewAuthClient("go_do.auth", client.DefaultClient)
+	if value == "" {
+		return defaultValue
+	}
 
-		authResp, err := authClient.ValidateToken(ctx, &authPb.Token{
-			Token: token,
-		})
+	duration, err := time.ParseDuration(value)
 
-		log.Println("Auth response: ", authResp)
-		if err != nil {
-			return err
-		}
-		err = fn(ctx, req, resp)
-		return err
+	if err != nil {
+		log.Fatalf("invalid %v: %v", name, err)
 	}
+
+	return duration
 }
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/micro/go-micro/metadata"
+	"github.com/micro/go-micro/server"
+	"github.com/willdot/Go-Do/apierrors"
+	taskPb "github.com/willdot/Go-Do/task-service/proto/task"
+	"github.com/willdot/Go-Do/user-service/verifier"
+	"golang.org/x/net/context"
+)
+
+// createTokenContext creates a context with a signed token in the meta data, as it arrives from the API
+func createTokenContext(t *testing.T, secret string, userID string) context.Context {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, verifier.Claims{
+		UserID: userID,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	})
+
+	signed, err := token.SignedString([]byte(secret))
+
+	if err != nil {
+		t.Fatalf("error signing token: %v", err)
+	}
+
+	return metadata.NewContext(context.Background(), map[string]string{"Token": signed})
+}
+
+func TestAuthWrapper(t *testing.T) {
+
+	// The fake auth client returns errors, so the handlers can only work if the wrapper gives them the user id
+	service := createService(false, true, true)
+	tokenVerifier := verifier.New(service.userClient, []byte("secret"), 0)
+
+	wrapped := AuthWrapper(tokenVerifier)(func(ctx context.Context, req server.Request, resp interface{}) error {
+		return service.Get(ctx, &taskPb.Request{}, resp.(*taskPb.Response))
+	})
+
+	t.Run("handlers get the user id from a verified token", func(t *testing.T) {
+		response := taskPb.Response{}
+
+		err := wrapped(createTokenContext(t, "secret", userID1), nil, &response)
+
+		assertError(err, nil, t)
+
+		if len(response.Tasks) == 0 {
+			t.Errorf("wanted the tasks for user %v but got none", userID1)
+		}
+
+		for _, task := range response.Tasks {
+			if task.UserId != userID1 {
+				t.Errorf("wanted only tasks for user %v but got one for %v", userID1, task.UserId)
+			}
+		}
+	})
+
+	t.Run("token signed with a different secret", func(t *testing.T) {
+		err := wrapped(createTokenContext(t, "not the secret", userID1), nil, &taskPb.Response{})
+
+		assertCode(err, apierrors.CodeUnauthenticated, t)
+	})
+
+	t.Run("no meta data", func(t *testing.T) {
+		err := wrapped(createContext("", false), nil, &taskPb.Response{})
+
+		assertError(err, errNoMetaData, t)
+	})
+}
@@ -0,0 +1,368 @@
+// Command migrate copies the tasks in the task table used by older versions of the task service into the tables it now
+// reads them from. The new tables are created by the service when it starts, so it needs to have been started once
+// first. Rows that have already been copied are left alone, so it is safe to run more than once
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// taskRow is a task read from the old task table
+type taskRow struct {
+	id            gocql.UUID
+	userID        string
+	createdDate   time.Time
+	title         string
+	description   string
+	completedDate time.Time
+	dailyDo       bool
+	deleted       bool
+	deletedDate   time.Time
+	dueDate       time.Time
+	priority      int
+	tags          []string
+	recurrence    string
+	occurrence    int
+	version       int
+	tenantID      string
+	listID        string
+	assigneeID    string
+}
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "count the tasks that would be copied without writing anything")
+	flag.Parse()
+
+	session, err := connect()
+
+	if err != nil {
+		fmt.Printf("error connecting to cassandra: %v\n", err)
+		os.Exit(1)
+	}
+
+	defer session.Close()
+
+	rows, err := readTasks(session)
+
+	if err != nil {
+		fmt.Printf("error reading tasks: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("found %d tasks to copy\n", len(rows))
+
+	if *dryRun {
+		return
+	}
+
+	failed := 0
+
+	for _, row := range rows {
+		if err := copyTask(session, row); err != nil {
+			fmt.Printf("error copying task %v: %v\n", row.id, err)
+			failed++
+		}
+	}
+
+	for listID, items := range listItems(rows) {
+		if err := copyListItems(session, listID, items); err != nil {
+			fmt.Printf("error copying the items in list %v: %v\n", listID, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf("%d tasks or lists weren't copied, run the migration again to retry them\n", failed)
+		os.Exit(1)
+	}
+
+	fmt.Println("done")
+}
+
+// connect connects to the go_do keyspace in the same way as the task service
+func connect() (*gocql.Session, error) {
+
+	host := os.Getenv("DB_HOST")
+
+	if host == "" {
+		host = "127.0.0.1"
+	}
+
+	cluster := gocql.NewCluster(host)
+	cluster.ProtoVersion = 4
+	cluster.Keyspace = "go_do"
+
+	if port, _ := strconv.Atoi(os.Getenv("DB_PORT")); port != 0 {
+		cluster.Port = port
+	}
+
+	cluster.ConnectTimeout = time.Second * 10
+	cluster.DisableInitialHostLookup = true
+
+	return cluster.CreateSession()
+}
+
+// readTasks reads every task in the old task table, skipping any rows that can't be copied
+func readTasks(session *gocql.Session) ([]taskRow, error) {
+	var rows []taskRow
+
+	m := map[string]interface{}{}
+
+	iterable := session.Query("SELECT * FROM task").Iter()
+
+	for iterable.MapScan(m) {
+		if row, ok := rowFromTask(m); ok {
+			rows = append(rows, row)
+		} else {
+			fmt.Printf("skipping task %v as it has no id or user\n", m["id"])
+		}
+
+		m = map[string]interface{}{}
+	}
+
+	if err := iterable.Close(); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// rowFromTask creates a taskRow from a row of the old task table. Columns that were added to the table after it was
+// created can be missing, and are treated as null. Returns false if the row has no id or user, as then there's nowhere
+// to copy it to
+func rowFromTask(m map[string]interface{}) (taskRow, bool) {
+
+	id, ok := m["id"].(gocql.UUID)
+
+	if !ok {
+		return taskRow{}, false
+	}
+
+	row := taskRow{
+		id:     id,
+		userID: stringColumn(m, "userid"),
+		// The service stores created dates to the second and uses them to find the row, so anything finer is dropped
+		createdDate:   timeColumn(m, "createddate").Truncate(time.Second),
+		title:         stringColumn(m, "title"),
+		description:   stringColumn(m, "description"),
+		completedDate: timeColumn(m, "completeddate"),
+		dailyDo:       boolColumn(m, "dailydo"),
+		deleted:       boolColumn(m, "deleted"),
+		deletedDate:   timeColumn(m, "deleteddate"),
+		dueDate:       timeColumn(m, "duedate"),
+		priority:      intColumn(m, "priority"),
+		tags:          tagsColumn(m, "tags"),
+		recurrence:    stringColumn(m, "recurrence"),
+		occurrence:    intColumn(m, "occurrence"),
+		version:       intColumn(m, "version"),
+		tenantID:      stringColumn(m, "tenantid"),
+		listID:        stringColumn(m, "listid"),
+		assigneeID:    stringColumn(m, "assigneeid"),
+	}
+
+	return row, row.userID != ""
+}
+
+// copyTask writes a task into task_by_user, task_key and task_by_assignee, and points its user at it if it's their
+// daily do. A task that is already in task_by_user or task_by_assignee isn't overwritten, as it could have been changed
+// since
+func copyTask(session *gocql.Session, row taskRow) error {
+
+	err := session.Query("INSERT INTO task_key (id, userId, createdDate) VALUES (?,?,?)", row.id, row.userID, row.createdDate).Exec()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = session.Query(`
+	INSERT INTO task_by_user (userId, createdDate, id, title, description, completedDate, dailyDo, deleted, deletedDate, dueDate, priority, tags, recurrence, occurrence, version, tenantId, listId, assigneeId)
+	VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?) IF NOT EXISTS`,
+		row.userID, row.createdDate, row.id, row.title, row.description, timeOrNull(row.completedDate), row.dailyDo,
+		row.deleted, timeOrNull(row.deletedDate), timeOrNull(row.dueDate), row.priority, row.tags, row.recurrence,
+		row.occurrence, intOrNull(row.version), nullIfEmpty(row.tenantID), row.listID, row.assigneeID).
+		MapScanCAS(map[string]interface{}{})
+
+	if err != nil {
+		return err
+	}
+
+	if row.assigneeID != "" {
+		values := append([]interface{}{row.assigneeID, row.createdDate, row.id, row.listID}, copyValues(row)...)
+
+		_, err := session.Query("INSERT INTO task_by_assignee (assigneeId, createdDate, taskId, listId, "+copyColumns+") VALUES (?,?,?,?,"+copyMarkers+") IF NOT EXISTS", values...).
+			MapScanCAS(map[string]interface{}{})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if row.dailyDo && !row.deleted {
+		_, err := session.Query("INSERT INTO daily_do (userId, taskId) VALUES (?,?) IF NOT EXISTS", dailyDoUser(row), row.id).
+			MapScanCAS(map[string]interface{}{})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyListItems puts tasks at the end of a list, in the order given. Each task has a row in the order the tasks were
+// created and a row in the order of the list, like the ones the service writes. Tasks that are already in the list keep
+// their place
+func copyListItems(session *gocql.Session, listID string, rows []taskRow) error {
+
+	id, err := gocql.ParseUUID(listID)
+
+	if err != nil {
+		return err
+	}
+
+	next := 0
+
+	err = session.Query("SELECT position FROM task_list_item WHERE listId = ? AND sortOrder = ? ORDER BY sortOrder DESC, sortKey DESC LIMIT 1",
+		id, listPositionOrder).Scan(&next)
+
+	if err == nil {
+		next++
+	} else if err != gocql.ErrNotFound {
+		return err
+	}
+
+	for _, row := range rows {
+		values := append([]interface{}{id, createdOrder, row.createdDate.Unix(), row.id, next, row.createdDate, row.assigneeID}, copyValues(row)...)
+
+		applied, err := session.Query(insertListItem+" IF NOT EXISTS", values...).MapScanCAS(map[string]interface{}{})
+
+		if err != nil {
+			return err
+		}
+
+		if !applied {
+			continue
+		}
+
+		values = append([]interface{}{id, listPositionOrder, int64(next)}, values[3:]...)
+
+		if err := session.Query(insertListItem, values...).Exec(); err != nil {
+			return err
+		}
+
+		next++
+	}
+
+	return nil
+}
+
+// listItems groups the tasks that are in a list by the list, in the order they were created
+func listItems(rows []taskRow) map[string][]taskRow {
+
+	sorted := make([]taskRow, len(rows))
+	copy(sorted, rows)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].createdDate.Before(sorted[j].createdDate)
+	})
+
+	items := map[string][]taskRow{}
+
+	for _, row := range sorted {
+		if row.listID != "" {
+			items[row.listID] = append(items[row.listID], row)
+		}
+	}
+
+	return items
+}
+
+// The sortOrder of the rows of task_list_item, which are the values of the service's CREATED_ASCENDING and
+// LIST_POSITION sort orders
+const (
+	createdOrder      = 0
+	listPositionOrder = 2
+)
+
+// copyColumns are the columns of a task that the service copies into task_list_item and task_by_assignee, other than
+// the ones in their keys, with copyValues giving the values for them
+const copyColumns = "userId, title, description, completedDate, dailyDo, deleted, deletedDate, dueDate, priority, tags, recurrence, occurrence, version, tenantId"
+
+const copyMarkers = "?,?,?,?,?,?,?,?,?,?,?,?,?,?"
+
+const insertListItem = "INSERT INTO task_list_item (listId, sortOrder, sortKey, taskId, position, createdDate, assigneeId, " +
+	copyColumns + ") VALUES (?,?,?,?,?,?,?," + copyMarkers + ")"
+
+func copyValues(row taskRow) []interface{} {
+	return []interface{}{row.userID, row.title, row.description, timeOrNull(row.completedDate), row.dailyDo, row.deleted,
+		timeOrNull(row.deletedDate), timeOrNull(row.dueDate), row.priority, row.tags, row.recurrence, row.occurrence,
+		intOrNull(row.version), row.tenantID}
+}
+
+// dailyDoUser is the user whose daily do a task is, which is the assignee if it has one
+func dailyDoUser(row taskRow) string {
+	if row.assigneeID != "" {
+		return row.assigneeID
+	}
+
+	return row.userID
+}
+
+func stringColumn(m map[string]interface{}, column string) string {
+	value, _ := m[column].(string)
+	return value
+}
+
+func boolColumn(m map[string]interface{}, column string) bool {
+	value, _ := m[column].(bool)
+	return value
+}
+
+func intColumn(m map[string]interface{}, column string) int {
+	value, _ := m[column].(int)
+	return value
+}
+
+func timeColumn(m map[string]interface{}, column string) time.Time {
+	value, _ := m[column].(time.Time)
+	return value
+}
+
+func tagsColumn(m map[string]interface{}, column string) []string {
+	value, _ := m[column].([]string)
+	return value
+}
+
+// timeOrNull gives null for a timestamp that wasn't set, rather than storing the zero time
+func timeOrNull(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+
+	return t
+}
+
+// intOrNull gives null for 0, as the service treats a null version as a task that hasn't been changed yet
+func intOrNull(i int) interface{} {
+	if i == 0 {
+		return nil
+	}
+
+	return i
+}
+
+// nullIfEmpty keeps tasks from before tenants were added with a null tenant, so the service can still adopt them
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func TestRowFromTask(t *testing.T) {
+
+	id := gocql.TimeUUID()
+	created := time.Date(2019, 6, 1, 9, 30, 0, 500000000, time.UTC)
+
+	t.Run("columns added after the table was created can be missing", func(t *testing.T) {
+		row, ok := rowFromTask(map[string]interface{}{
+			"id":          id,
+			"userid":      "user1",
+			"title":       "a task",
+			"description": "from the first version",
+			"createddate": created,
+			"dailydo":     true,
+		})
+
+		if !ok {
+			t.Fatalf("wanted the task to be copied but it wasn't")
+		}
+
+		want := taskRow{
+			id:          id,
+			userID:      "user1",
+			createdDate: created.Truncate(time.Second),
+			title:       "a task",
+			description: "from the first version",
+			dailyDo:     true,
+		}
+
+		if !reflect.DeepEqual(row, want) {
+			t.Errorf("wanted %+v but got %+v", want, row)
+		}
+	})
+
+	t.Run("the created date is stored to the second", func(t *testing.T) {
+		row, _ := rowFromTask(map[string]interface{}{"id": id, "userid": "user1", "createddate": created})
+
+		if row.createdDate.Nanosecond() != 0 {
+			t.Errorf("wanted the created date to the second but got %v", row.createdDate)
+		}
+	})
+
+	t.Run("tasks without an id or user aren't copied", func(t *testing.T) {
+		if _, ok := rowFromTask(map[string]interface{}{"userid": "user1"}); ok {
+			t.Errorf("wanted a task without an id to be skipped")
+		}
+
+		if _, ok := rowFromTask(map[string]interface{}{"id": id, "userid": ""}); ok {
+			t.Errorf("wanted a task without a user to be skipped")
+		}
+	})
+}
+
+func TestListItems(t *testing.T) {
+
+	first, second, third, other := gocql.TimeUUID(), gocql.TimeUUID(), gocql.TimeUUID(), gocql.TimeUUID()
+	now := time.Now()
+
+	rows := []taskRow{
+		{id: third, listID: "list1", createdDate: now.Add(time.Hour)},
+		{id: first, listID: "list1", createdDate: now.Add(-time.Hour)},
+		{id: other, createdDate: now},
+		{id: second, listID: "list1", createdDate: now},
+	}
+
+	var got []gocql.UUID
+
+	for _, row := range listItems(rows)["list1"] {
+		got = append(got, row.id)
+	}
+
+	want := []gocql.UUID{first, second, third}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wanted %v but got %v", want, got)
+	}
+
+	if len(listItems(rows)) != 1 {
+		t.Errorf("wanted only the tasks in a list to be grouped")
+	}
+
+	if rows[0].id != third {
+		t.Errorf("wanted the tasks given to be left in their order")
+	}
+}
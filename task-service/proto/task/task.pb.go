@@ -26,7 +26,139 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type SortOrder int32
+
+const (
+	SortOrder_CREATED_ASCENDING  SortOrder = 0
+	SortOrder_CREATED_DESCENDING SortOrder = 1
+	// LIST_POSITION is the order the tasks have been put in within a list, for requests with a listId
+	SortOrder_LIST_POSITION SortOrder = 2
+)
+
+var SortOrder_name = map[int32]string{
+	0: "CREATED_ASCENDING",
+	1: "CREATED_DESCENDING",
+	2: "LIST_POSITION",
+}
+
+var SortOrder_value = map[string]int32{
+	"CREATED_ASCENDING":  0,
+	"CREATED_DESCENDING": 1,
+	"LIST_POSITION":      2,
+}
+
+func (x SortOrder) String() string {
+	return proto.EnumName(SortOrder_name, int32(x))
+}
+
+func (SortOrder) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{0}
+}
+
+type Priority int32
+
+const (
+	Priority_PRIORITY_NONE   Priority = 0
+	Priority_PRIORITY_LOW    Priority = 1
+	Priority_PRIORITY_MEDIUM Priority = 2
+	Priority_PRIORITY_HIGH   Priority = 3
+)
+
+var Priority_name = map[int32]string{
+	0: "PRIORITY_NONE",
+	1: "PRIORITY_LOW",
+	2: "PRIORITY_MEDIUM",
+	3: "PRIORITY_HIGH",
+}
+
+var Priority_value = map[string]int32{
+	"PRIORITY_NONE":   0,
+	"PRIORITY_LOW":    1,
+	"PRIORITY_MEDIUM": 2,
+	"PRIORITY_HIGH":   3,
+}
+
+func (x Priority) String() string {
+	return proto.EnumName(Priority_name, int32(x))
+}
+
+func (Priority) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{1}
+}
+
+type OpenChecklist int32
+
+const (
+	OpenChecklist_OPEN_CHECKLIST_IGNORE   OpenChecklist = 0
+	OpenChecklist_OPEN_CHECKLIST_REFUSE   OpenChecklist = 1
+	OpenChecklist_OPEN_CHECKLIST_COMPLETE OpenChecklist = 2
+)
+
+var OpenChecklist_name = map[int32]string{
+	0: "OPEN_CHECKLIST_IGNORE",
+	1: "OPEN_CHECKLIST_REFUSE",
+	2: "OPEN_CHECKLIST_COMPLETE",
+}
+
+var OpenChecklist_value = map[string]int32{
+	"OPEN_CHECKLIST_IGNORE":   0,
+	"OPEN_CHECKLIST_REFUSE":   1,
+	"OPEN_CHECKLIST_COMPLETE": 2,
+}
+
+func (x OpenChecklist) String() string {
+	return proto.EnumName(OpenChecklist_name, int32(x))
+}
+
+func (OpenChecklist) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{2}
+}
+
+type ListPermission int32
+
+const (
+	ListPermission_LIST_PERMISSION_NONE ListPermission = 0
+	ListPermission_LIST_PERMISSION_VIEW ListPermission = 1
+	ListPermission_LIST_PERMISSION_EDIT ListPermission = 2
+)
+
+var ListPermission_name = map[int32]string{
+	0: "LIST_PERMISSION_NONE",
+	1: "LIST_PERMISSION_VIEW",
+	2: "LIST_PERMISSION_EDIT",
+}
+
+var ListPermission_value = map[string]int32{
+	"LIST_PERMISSION_NONE": 0,
+	"LIST_PERMISSION_VIEW": 1,
+	"LIST_PERMISSION_EDIT": 2,
+}
+
+func (x ListPermission) String() string {
+	return proto.EnumName(ListPermission_name, int32(x))
+}
+
+func (ListPermission) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{3}
+}
+
 type Request struct {
+	PageSize       int32     `protobuf:"varint,1,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	PageToken      string    `protobuf:"bytes,2,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+	CompletedOnly  bool      `protobuf:"varint,3,opt,name=completedOnly,proto3" json:"completedOnly,omitempty"`
+	IncompleteOnly bool      `protobuf:"varint,4,opt,name=incompleteOnly,proto3" json:"incompleteOnly,omitempty"`
+	DailyDoOnly    bool      `protobuf:"varint,5,opt,name=dailyDoOnly,proto3" json:"dailyDoOnly,omitempty"`
+	CreatedFrom    int64     `protobuf:"varint,6,opt,name=createdFrom,proto3" json:"createdFrom,omitempty"`
+	CreatedTo      int64     `protobuf:"varint,7,opt,name=createdTo,proto3" json:"createdTo,omitempty"`
+	SortOrder      SortOrder `protobuf:"varint,8,opt,name=sortOrder,proto3,enum=task.SortOrder" json:"sortOrder,omitempty"`
+	IncludeDeleted bool      `protobuf:"varint,9,opt,name=includeDeleted,proto3" json:"includeDeleted,omitempty"`
+	Tag            string    `protobuf:"bytes,10,opt,name=tag,proto3" json:"tag,omitempty"`
+	Priority       Priority  `protobuf:"varint,11,opt,name=priority,proto3,enum=task.Priority" json:"priority,omitempty"`
+	OverdueOnly    bool      `protobuf:"varint,12,opt,name=overdueOnly,proto3" json:"overdueOnly,omitempty"`
+	// listId gets the tasks in a list instead of the callers own tasks
+	ListId string `protobuf:"bytes,13,opt,name=listId,proto3" json:"listId,omitempty"`
+	// assignedToMe gets the tasks assigned to the caller instead of the callers own tasks
+	AssignedToMe         bool     `protobuf:"varint,14,opt,name=assignedToMe,proto3" json:"assignedToMe,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -57,14 +189,127 @@ func (m *Request) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Request proto.InternalMessageInfo
 
+func (m *Request) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *Request) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *Request) GetCompletedOnly() bool {
+	if m != nil {
+		return m.CompletedOnly
+	}
+	return false
+}
+
+func (m *Request) GetIncompleteOnly() bool {
+	if m != nil {
+		return m.IncompleteOnly
+	}
+	return false
+}
+
+func (m *Request) GetDailyDoOnly() bool {
+	if m != nil {
+		return m.DailyDoOnly
+	}
+	return false
+}
+
+func (m *Request) GetCreatedFrom() int64 {
+	if m != nil {
+		return m.CreatedFrom
+	}
+	return 0
+}
+
+func (m *Request) GetCreatedTo() int64 {
+	if m != nil {
+		return m.CreatedTo
+	}
+	return 0
+}
+
+func (m *Request) GetSortOrder() SortOrder {
+	if m != nil {
+		return m.SortOrder
+	}
+	return SortOrder_CREATED_ASCENDING
+}
+
+func (m *Request) GetIncludeDeleted() bool {
+	if m != nil {
+		return m.IncludeDeleted
+	}
+	return false
+}
+
+func (m *Request) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *Request) GetPriority() Priority {
+	if m != nil {
+		return m.Priority
+	}
+	return Priority_PRIORITY_NONE
+}
+
+func (m *Request) GetOverdueOnly() bool {
+	if m != nil {
+		return m.OverdueOnly
+	}
+	return false
+}
+
+func (m *Request) GetListId() string {
+	if m != nil {
+		return m.ListId
+	}
+	return ""
+}
+
+func (m *Request) GetAssignedToMe() bool {
+	if m != nil {
+		return m.AssignedToMe
+	}
+	return false
+}
+
 type Task struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title                string   `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Description          string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	UserId               string   `protobuf:"bytes,4,opt,name=userId,proto3" json:"userId,omitempty"`
-	CreatedDate          int64    `protobuf:"varint,5,opt,name=createdDate,proto3" json:"createdDate,omitempty"`
-	CompletedDate        int64    `protobuf:"varint,6,opt,name=completedDate,proto3" json:"completedDate,omitempty"`
-	DailyDo              bool     `protobuf:"varint,7,opt,name=dailyDo,proto3" json:"dailyDo,omitempty"`
+	Id            string           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string           `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string           `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	UserId        string           `protobuf:"bytes,4,opt,name=userId,proto3" json:"userId,omitempty"`
+	CreatedDate   int64            `protobuf:"varint,5,opt,name=createdDate,proto3" json:"createdDate,omitempty"`
+	CompletedDate int64            `protobuf:"varint,6,opt,name=completedDate,proto3" json:"completedDate,omitempty"`
+	DailyDo       bool             `protobuf:"varint,7,opt,name=dailyDo,proto3" json:"dailyDo,omitempty"`
+	Deleted       bool             `protobuf:"varint,8,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	DeletedDate   int64            `protobuf:"varint,9,opt,name=deletedDate,proto3" json:"deletedDate,omitempty"`
+	Checklist     []*ChecklistItem `protobuf:"bytes,10,rep,name=checklist,proto3" json:"checklist,omitempty"`
+	DueDate       int64            `protobuf:"varint,11,opt,name=dueDate,proto3" json:"dueDate,omitempty"`
+	Priority      Priority         `protobuf:"varint,12,opt,name=priority,proto3,enum=task.Priority" json:"priority,omitempty"`
+	Tags          []string         `protobuf:"bytes,13,rep,name=tags,proto3" json:"tags,omitempty"`
+	Recurrence    string           `protobuf:"bytes,14,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	Occurrence    int32            `protobuf:"varint,15,opt,name=occurrence,proto3" json:"occurrence,omitempty"`
+	Version       int32            `protobuf:"varint,16,opt,name=version,proto3" json:"version,omitempty"`
+	// tenantId is the company the task belongs to, or the user for tasks made outside of a company
+	TenantId string `protobuf:"bytes,17,opt,name=tenantId,proto3" json:"tenantId,omitempty"`
+	// listId is the list the task is in, if it's in one
+	ListId string `protobuf:"bytes,18,opt,name=listId,proto3" json:"listId,omitempty"`
+	// assigneeId is who the task is assigned to. It's their daily do, rather than the owners, when it's set
+	AssigneeId           string   `protobuf:"bytes,19,opt,name=assigneeId,proto3" json:"assigneeId,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -144,10 +389,158 @@ func (m *Task) GetDailyDo() bool {
 	return false
 }
 
+func (m *Task) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
+func (m *Task) GetDeletedDate() int64 {
+	if m != nil {
+		return m.DeletedDate
+	}
+	return 0
+}
+
+func (m *Task) GetChecklist() []*ChecklistItem {
+	if m != nil {
+		return m.Checklist
+	}
+	return nil
+}
+
+func (m *Task) GetDueDate() int64 {
+	if m != nil {
+		return m.DueDate
+	}
+	return 0
+}
+
+func (m *Task) GetPriority() Priority {
+	if m != nil {
+		return m.Priority
+	}
+	return Priority_PRIORITY_NONE
+}
+
+func (m *Task) GetTags() []string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *Task) GetRecurrence() string {
+	if m != nil {
+		return m.Recurrence
+	}
+	return ""
+}
+
+func (m *Task) GetOccurrence() int32 {
+	if m != nil {
+		return m.Occurrence
+	}
+	return 0
+}
+
+func (m *Task) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Task) GetTenantId() string {
+	if m != nil {
+		return m.TenantId
+	}
+	return ""
+}
+
+func (m *Task) GetListId() string {
+	if m != nil {
+		return m.ListId
+	}
+	return ""
+}
+
+func (m *Task) GetAssigneeId() string {
+	if m != nil {
+		return m.AssigneeId
+	}
+	return ""
+}
+
+type ChecklistItem struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title                string   `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Completed            bool     `protobuf:"varint,3,opt,name=completed,proto3" json:"completed,omitempty"`
+	Position             int32    `protobuf:"varint,4,opt,name=position,proto3" json:"position,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChecklistItem) Reset()         { *m = ChecklistItem{} }
+func (m *ChecklistItem) String() string { return proto.CompactTextString(m) }
+func (*ChecklistItem) ProtoMessage()    {}
+func (*ChecklistItem) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{2}
+}
+
+func (m *ChecklistItem) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChecklistItem.Unmarshal(m, b)
+}
+func (m *ChecklistItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChecklistItem.Marshal(b, m, deterministic)
+}
+func (m *ChecklistItem) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChecklistItem.Merge(m, src)
+}
+func (m *ChecklistItem) XXX_Size() int {
+	return xxx_messageInfo_ChecklistItem.Size(m)
+}
+func (m *ChecklistItem) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChecklistItem.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChecklistItem proto.InternalMessageInfo
+
+func (m *ChecklistItem) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ChecklistItem) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+func (m *ChecklistItem) GetCompleted() bool {
+	if m != nil {
+		return m.Completed
+	}
+	return false
+}
+
+func (m *ChecklistItem) GetPosition() int32 {
+	if m != nil {
+		return m.Position
+	}
+	return 0
+}
+
 type Response struct {
 	Task                 *Task    `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
 	Tasks                []*Task  `protobuf:"bytes,2,rep,name=tasks,proto3" json:"tasks,omitempty"`
 	Errors               []*Error `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	NextPageToken        string   `protobuf:"bytes,4,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -157,7 +550,7 @@ func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_152e577c5c92a6d4, []int{2}
+	return fileDescriptor_152e577c5c92a6d4, []int{3}
 }
 
 func (m *Response) XXX_Unmarshal(b []byte) error {
@@ -199,6 +592,13 @@ func (m *Response) GetErrors() []*Error {
 	return nil
 }
 
+func (m *Response) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
 type Error struct {
 	Code                 int32    `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
 	Description          string   `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
@@ -211,7 +611,7 @@ func (m *Error) Reset()         { *m = Error{} }
 func (m *Error) String() string { return proto.CompactTextString(m) }
 func (*Error) ProtoMessage()    {}
 func (*Error) Descriptor() ([]byte, []int) {
-	return fileDescriptor_152e577c5c92a6d4, []int{3}
+	return fileDescriptor_152e577c5c92a6d4, []int{4}
 }
 
 func (m *Error) XXX_Unmarshal(b []byte) error {
@@ -250,6 +650,12 @@ type CreateTask struct {
 	Title                string   `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
 	Description          string   `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
 	DailyDo              bool     `protobuf:"varint,3,opt,name=dailyDo,proto3" json:"dailyDo,omitempty"`
+	DueDate              int64    `protobuf:"varint,4,opt,name=dueDate,proto3" json:"dueDate,omitempty"`
+	Priority             Priority `protobuf:"varint,5,opt,name=priority,proto3,enum=task.Priority" json:"priority,omitempty"`
+	Tags                 []string `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	Recurrence           string   `protobuf:"bytes,7,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	ListId               string   `protobuf:"bytes,8,opt,name=listId,proto3" json:"listId,omitempty"`
+	AssigneeId           string   `protobuf:"bytes,9,opt,name=assigneeId,proto3" json:"assigneeId,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -259,7 +665,7 @@ func (m *CreateTask) Reset()         { *m = CreateTask{} }
 func (m *CreateTask) String() string { return proto.CompactTextString(m) }
 func (*CreateTask) ProtoMessage()    {}
 func (*CreateTask) Descriptor() ([]byte, []int) {
-	return fileDescriptor_152e577c5c92a6d4, []int{4}
+	return fileDescriptor_152e577c5c92a6d4, []int{5}
 }
 
 func (m *CreateTask) XXX_Unmarshal(b []byte) error {
@@ -301,30 +707,78 @@ func (m *CreateTask) GetDailyDo() bool {
 	return false
 }
 
-type UpdateTask struct {
-	TaskId               string   `protobuf:"bytes,1,opt,name=taskId,proto3" json:"taskId,omitempty"`
-	Title                string   `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Description          string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *CreateTask) GetDueDate() int64 {
+	if m != nil {
+		return m.DueDate
+	}
+	return 0
 }
 
-func (m *UpdateTask) Reset()         { *m = UpdateTask{} }
-func (m *UpdateTask) String() string { return proto.CompactTextString(m) }
-func (*UpdateTask) ProtoMessage()    {}
-func (*UpdateTask) Descriptor() ([]byte, []int) {
-	return fileDescriptor_152e577c5c92a6d4, []int{5}
+func (m *CreateTask) GetPriority() Priority {
+	if m != nil {
+		return m.Priority
+	}
+	return Priority_PRIORITY_NONE
 }
 
-func (m *UpdateTask) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UpdateTask.Unmarshal(m, b)
-}
-func (m *UpdateTask) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UpdateTask.Marshal(b, m, deterministic)
+func (m *CreateTask) GetTags() []string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
 }
-func (m *UpdateTask) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UpdateTask.Merge(m, src)
+
+func (m *CreateTask) GetRecurrence() string {
+	if m != nil {
+		return m.Recurrence
+	}
+	return ""
+}
+
+func (m *CreateTask) GetListId() string {
+	if m != nil {
+		return m.ListId
+	}
+	return ""
+}
+
+func (m *CreateTask) GetAssigneeId() string {
+	if m != nil {
+		return m.AssigneeId
+	}
+	return ""
+}
+
+type UpdateTask struct {
+	TaskId               string   `protobuf:"bytes,1,opt,name=taskId,proto3" json:"taskId,omitempty"`
+	Title                string   `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description          string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	DueDate              int64    `protobuf:"varint,4,opt,name=dueDate,proto3" json:"dueDate,omitempty"`
+	Priority             Priority `protobuf:"varint,5,opt,name=priority,proto3,enum=task.Priority" json:"priority,omitempty"`
+	Tags                 []string `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	Recurrence           string   `protobuf:"bytes,7,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	UpdateMask           []string `protobuf:"bytes,8,rep,name=updateMask,proto3" json:"updateMask,omitempty"`
+	Version              int32    `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateTask) Reset()         { *m = UpdateTask{} }
+func (m *UpdateTask) String() string { return proto.CompactTextString(m) }
+func (*UpdateTask) ProtoMessage()    {}
+func (*UpdateTask) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{6}
+}
+
+func (m *UpdateTask) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateTask.Unmarshal(m, b)
+}
+func (m *UpdateTask) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateTask.Marshal(b, m, deterministic)
+}
+func (m *UpdateTask) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateTask.Merge(m, src)
 }
 func (m *UpdateTask) XXX_Size() int {
 	return xxx_messageInfo_UpdateTask.Size(m)
@@ -342,157 +796,1685 @@ func (m *UpdateTask) GetTaskId() string {
 	return ""
 }
 
-func (m *UpdateTask) GetTitle() string {
-	if m != nil {
-		return m.Title
-	}
-	return ""
+func (m *UpdateTask) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+func (m *UpdateTask) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *UpdateTask) GetDueDate() int64 {
+	if m != nil {
+		return m.DueDate
+	}
+	return 0
+}
+
+func (m *UpdateTask) GetPriority() Priority {
+	if m != nil {
+		return m.Priority
+	}
+	return Priority_PRIORITY_NONE
+}
+
+func (m *UpdateTask) GetTags() []string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *UpdateTask) GetRecurrence() string {
+	if m != nil {
+		return m.Recurrence
+	}
+	return ""
+}
+
+func (m *UpdateTask) GetUpdateMask() []string {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+func (m *UpdateTask) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type DailyDoStatusRequest struct {
+	TaskId               string   `protobuf:"bytes,1,opt,name=taskId,proto3" json:"taskId,omitempty"`
+	Status               bool     `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	Version              int32    `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DailyDoStatusRequest) Reset()         { *m = DailyDoStatusRequest{} }
+func (m *DailyDoStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*DailyDoStatusRequest) ProtoMessage()    {}
+func (*DailyDoStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{7}
+}
+
+func (m *DailyDoStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DailyDoStatusRequest.Unmarshal(m, b)
+}
+func (m *DailyDoStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DailyDoStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *DailyDoStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DailyDoStatusRequest.Merge(m, src)
+}
+func (m *DailyDoStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_DailyDoStatusRequest.Size(m)
+}
+func (m *DailyDoStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DailyDoStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DailyDoStatusRequest proto.InternalMessageInfo
+
+func (m *DailyDoStatusRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *DailyDoStatusRequest) GetStatus() bool {
+	if m != nil {
+		return m.Status
+	}
+	return false
+}
+
+func (m *DailyDoStatusRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type CompleteTaskRequest struct {
+	TaskId               string        `protobuf:"bytes,1,opt,name=taskId,proto3" json:"taskId,omitempty"`
+	Completed            bool          `protobuf:"varint,2,opt,name=completed,proto3" json:"completed,omitempty"`
+	OpenChecklist        OpenChecklist `protobuf:"varint,3,opt,name=openChecklist,proto3,enum=task.OpenChecklist" json:"openChecklist,omitempty"`
+	Version              int32         `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *CompleteTaskRequest) Reset()         { *m = CompleteTaskRequest{} }
+func (m *CompleteTaskRequest) String() string { return proto.CompactTextString(m) }
+func (*CompleteTaskRequest) ProtoMessage()    {}
+func (*CompleteTaskRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{8}
+}
+
+func (m *CompleteTaskRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompleteTaskRequest.Unmarshal(m, b)
+}
+func (m *CompleteTaskRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompleteTaskRequest.Marshal(b, m, deterministic)
+}
+func (m *CompleteTaskRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompleteTaskRequest.Merge(m, src)
+}
+func (m *CompleteTaskRequest) XXX_Size() int {
+	return xxx_messageInfo_CompleteTaskRequest.Size(m)
+}
+func (m *CompleteTaskRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompleteTaskRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompleteTaskRequest proto.InternalMessageInfo
+
+func (m *CompleteTaskRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *CompleteTaskRequest) GetCompleted() bool {
+	if m != nil {
+		return m.Completed
+	}
+	return false
+}
+
+func (m *CompleteTaskRequest) GetOpenChecklist() OpenChecklist {
+	if m != nil {
+		return m.OpenChecklist
+	}
+	return OpenChecklist_OPEN_CHECKLIST_IGNORE
+}
+
+func (m *CompleteTaskRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type DeleteTaskRequest struct {
+	TaskId               string   `protobuf:"bytes,1,opt,name=taskId,proto3" json:"taskId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteTaskRequest) Reset()         { *m = DeleteTaskRequest{} }
+func (m *DeleteTaskRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteTaskRequest) ProtoMessage()    {}
+func (*DeleteTaskRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{9}
+}
+
+func (m *DeleteTaskRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteTaskRequest.Unmarshal(m, b)
+}
+func (m *DeleteTaskRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteTaskRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteTaskRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteTaskRequest.Merge(m, src)
+}
+func (m *DeleteTaskRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteTaskRequest.Size(m)
+}
+func (m *DeleteTaskRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteTaskRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteTaskRequest proto.InternalMessageInfo
+
+func (m *DeleteTaskRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+type RestoreTaskRequest struct {
+	TaskId               string   `protobuf:"bytes,1,opt,name=taskId,proto3" json:"taskId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreTaskRequest) Reset()         { *m = RestoreTaskRequest{} }
+func (m *RestoreTaskRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreTaskRequest) ProtoMessage()    {}
+func (*RestoreTaskRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{10}
+}
+
+func (m *RestoreTaskRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreTaskRequest.Unmarshal(m, b)
+}
+func (m *RestoreTaskRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreTaskRequest.Marshal(b, m, deterministic)
+}
+func (m *RestoreTaskRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreTaskRequest.Merge(m, src)
+}
+func (m *RestoreTaskRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreTaskRequest.Size(m)
+}
+func (m *RestoreTaskRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreTaskRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreTaskRequest proto.InternalMessageInfo
+
+func (m *RestoreTaskRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+type PurgeRequest struct {
+	OlderThan            int64    `protobuf:"varint,1,opt,name=olderThan,proto3" json:"olderThan,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PurgeRequest) Reset()         { *m = PurgeRequest{} }
+func (m *PurgeRequest) String() string { return proto.CompactTextString(m) }
+func (*PurgeRequest) ProtoMessage()    {}
+func (*PurgeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{11}
+}
+
+func (m *PurgeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PurgeRequest.Unmarshal(m, b)
+}
+func (m *PurgeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PurgeRequest.Marshal(b, m, deterministic)
+}
+func (m *PurgeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PurgeRequest.Merge(m, src)
+}
+func (m *PurgeRequest) XXX_Size() int {
+	return xxx_messageInfo_PurgeRequest.Size(m)
+}
+func (m *PurgeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PurgeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PurgeRequest proto.InternalMessageInfo
+
+func (m *PurgeRequest) GetOlderThan() int64 {
+	if m != nil {
+		return m.OlderThan
+	}
+	return 0
+}
+
+type DailyDoHistory struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	Day                  string   `protobuf:"bytes,2,opt,name=day,proto3" json:"day,omitempty"`
+	TaskId               string   `protobuf:"bytes,3,opt,name=taskId,proto3" json:"taskId,omitempty"`
+	Completed            bool     `protobuf:"varint,4,opt,name=completed,proto3" json:"completed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DailyDoHistory) Reset()         { *m = DailyDoHistory{} }
+func (m *DailyDoHistory) String() string { return proto.CompactTextString(m) }
+func (*DailyDoHistory) ProtoMessage()    {}
+func (*DailyDoHistory) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{12}
+}
+
+func (m *DailyDoHistory) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DailyDoHistory.Unmarshal(m, b)
+}
+func (m *DailyDoHistory) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DailyDoHistory.Marshal(b, m, deterministic)
+}
+func (m *DailyDoHistory) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DailyDoHistory.Merge(m, src)
+}
+func (m *DailyDoHistory) XXX_Size() int {
+	return xxx_messageInfo_DailyDoHistory.Size(m)
+}
+func (m *DailyDoHistory) XXX_DiscardUnknown() {
+	xxx_messageInfo_DailyDoHistory.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DailyDoHistory proto.InternalMessageInfo
+
+func (m *DailyDoHistory) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *DailyDoHistory) GetDay() string {
+	if m != nil {
+		return m.Day
+	}
+	return ""
+}
+
+func (m *DailyDoHistory) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *DailyDoHistory) GetCompleted() bool {
+	if m != nil {
+		return m.Completed
+	}
+	return false
+}
+
+type DailyDoHistoryRequest struct {
+	From                 string   `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To                   string   `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DailyDoHistoryRequest) Reset()         { *m = DailyDoHistoryRequest{} }
+func (m *DailyDoHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*DailyDoHistoryRequest) ProtoMessage()    {}
+func (*DailyDoHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{13}
+}
+
+func (m *DailyDoHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DailyDoHistoryRequest.Unmarshal(m, b)
+}
+func (m *DailyDoHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DailyDoHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *DailyDoHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DailyDoHistoryRequest.Merge(m, src)
+}
+func (m *DailyDoHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_DailyDoHistoryRequest.Size(m)
+}
+func (m *DailyDoHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DailyDoHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DailyDoHistoryRequest proto.InternalMessageInfo
+
+func (m *DailyDoHistoryRequest) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *DailyDoHistoryRequest) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+type DailyDoHistoryResponse struct {
+	History              []*DailyDoHistory `protobuf:"bytes,1,rep,name=history,proto3" json:"history,omitempty"`
+	Errors               []*Error          `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *DailyDoHistoryResponse) Reset()         { *m = DailyDoHistoryResponse{} }
+func (m *DailyDoHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*DailyDoHistoryResponse) ProtoMessage()    {}
+func (*DailyDoHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{14}
+}
+
+func (m *DailyDoHistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DailyDoHistoryResponse.Unmarshal(m, b)
+}
+func (m *DailyDoHistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DailyDoHistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *DailyDoHistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DailyDoHistoryResponse.Merge(m, src)
+}
+func (m *DailyDoHistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_DailyDoHistoryResponse.Size(m)
+}
+func (m *DailyDoHistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DailyDoHistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DailyDoHistoryResponse proto.InternalMessageInfo
+
+func (m *DailyDoHistoryResponse) GetHistory() []*DailyDoHistory {
+	if m != nil {
+		return m.History
+	}
+	return nil
+}
+
+func (m *DailyDoHistoryResponse) GetErrors() []*Error {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+type StreakRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreakRequest) Reset()         { *m = StreakRequest{} }
+func (m *StreakRequest) String() string { return proto.CompactTextString(m) }
+func (*StreakRequest) ProtoMessage()    {}
+func (*StreakRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{15}
+}
+
+func (m *StreakRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreakRequest.Unmarshal(m, b)
+}
+func (m *StreakRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreakRequest.Marshal(b, m, deterministic)
+}
+func (m *StreakRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreakRequest.Merge(m, src)
+}
+func (m *StreakRequest) XXX_Size() int {
+	return xxx_messageInfo_StreakRequest.Size(m)
+}
+func (m *StreakRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreakRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreakRequest proto.InternalMessageInfo
+
+type StreakResponse struct {
+	Current              int32    `protobuf:"varint,1,opt,name=current,proto3" json:"current,omitempty"`
+	Longest              int32    `protobuf:"varint,2,opt,name=longest,proto3" json:"longest,omitempty"`
+	Errors               []*Error `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreakResponse) Reset()         { *m = StreakResponse{} }
+func (m *StreakResponse) String() string { return proto.CompactTextString(m) }
+func (*StreakResponse) ProtoMessage()    {}
+func (*StreakResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{16}
+}
+
+func (m *StreakResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreakResponse.Unmarshal(m, b)
+}
+func (m *StreakResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreakResponse.Marshal(b, m, deterministic)
+}
+func (m *StreakResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreakResponse.Merge(m, src)
+}
+func (m *StreakResponse) XXX_Size() int {
+	return xxx_messageInfo_StreakResponse.Size(m)
+}
+func (m *StreakResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreakResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreakResponse proto.InternalMessageInfo
+
+func (m *StreakResponse) GetCurrent() int32 {
+	if m != nil {
+		return m.Current
+	}
+	return 0
+}
+
+func (m *StreakResponse) GetLongest() int32 {
+	if m != nil {
+		return m.Longest
+	}
+	return 0
+}
+
+func (m *StreakResponse) GetErrors() []*Error {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+type AddChecklistItemRequest struct {
+	TaskId               string   `protobuf:"bytes,1,opt,name=taskId,proto3" json:"taskId,omitempty"`
+	Title                string   `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddChecklistItemRequest) Reset()         { *m = AddChecklistItemRequest{} }
+func (m *AddChecklistItemRequest) String() string { return proto.CompactTextString(m) }
+func (*AddChecklistItemRequest) ProtoMessage()    {}
+func (*AddChecklistItemRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{17}
+}
+
+func (m *AddChecklistItemRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddChecklistItemRequest.Unmarshal(m, b)
+}
+func (m *AddChecklistItemRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddChecklistItemRequest.Marshal(b, m, deterministic)
+}
+func (m *AddChecklistItemRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddChecklistItemRequest.Merge(m, src)
+}
+func (m *AddChecklistItemRequest) XXX_Size() int {
+	return xxx_messageInfo_AddChecklistItemRequest.Size(m)
+}
+func (m *AddChecklistItemRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddChecklistItemRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddChecklistItemRequest proto.InternalMessageInfo
+
+func (m *AddChecklistItemRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *AddChecklistItemRequest) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+type ReorderChecklistRequest struct {
+	TaskId               string   `protobuf:"bytes,1,opt,name=taskId,proto3" json:"taskId,omitempty"`
+	ItemIds              []string `protobuf:"bytes,2,rep,name=itemIds,proto3" json:"itemIds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReorderChecklistRequest) Reset()         { *m = ReorderChecklistRequest{} }
+func (m *ReorderChecklistRequest) String() string { return proto.CompactTextString(m) }
+func (*ReorderChecklistRequest) ProtoMessage()    {}
+func (*ReorderChecklistRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{18}
+}
+
+func (m *ReorderChecklistRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReorderChecklistRequest.Unmarshal(m, b)
+}
+func (m *ReorderChecklistRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReorderChecklistRequest.Marshal(b, m, deterministic)
+}
+func (m *ReorderChecklistRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReorderChecklistRequest.Merge(m, src)
+}
+func (m *ReorderChecklistRequest) XXX_Size() int {
+	return xxx_messageInfo_ReorderChecklistRequest.Size(m)
+}
+func (m *ReorderChecklistRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReorderChecklistRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReorderChecklistRequest proto.InternalMessageInfo
+
+func (m *ReorderChecklistRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *ReorderChecklistRequest) GetItemIds() []string {
+	if m != nil {
+		return m.ItemIds
+	}
+	return nil
+}
+
+type ToggleChecklistItemRequest struct {
+	TaskId               string   `protobuf:"bytes,1,opt,name=taskId,proto3" json:"taskId,omitempty"`
+	ItemId               string   `protobuf:"bytes,2,opt,name=itemId,proto3" json:"itemId,omitempty"`
+	Completed            bool     `protobuf:"varint,3,opt,name=completed,proto3" json:"completed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ToggleChecklistItemRequest) Reset()         { *m = ToggleChecklistItemRequest{} }
+func (m *ToggleChecklistItemRequest) String() string { return proto.CompactTextString(m) }
+func (*ToggleChecklistItemRequest) ProtoMessage()    {}
+func (*ToggleChecklistItemRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{19}
+}
+
+func (m *ToggleChecklistItemRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ToggleChecklistItemRequest.Unmarshal(m, b)
+}
+func (m *ToggleChecklistItemRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ToggleChecklistItemRequest.Marshal(b, m, deterministic)
+}
+func (m *ToggleChecklistItemRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ToggleChecklistItemRequest.Merge(m, src)
+}
+func (m *ToggleChecklistItemRequest) XXX_Size() int {
+	return xxx_messageInfo_ToggleChecklistItemRequest.Size(m)
+}
+func (m *ToggleChecklistItemRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ToggleChecklistItemRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ToggleChecklistItemRequest proto.InternalMessageInfo
+
+func (m *ToggleChecklistItemRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *ToggleChecklistItemRequest) GetItemId() string {
+	if m != nil {
+		return m.ItemId
+	}
+	return ""
+}
+
+func (m *ToggleChecklistItemRequest) GetCompleted() bool {
+	if m != nil {
+		return m.Completed
+	}
+	return false
+}
+
+type RemoveChecklistItemRequest struct {
+	TaskId               string   `protobuf:"bytes,1,opt,name=taskId,proto3" json:"taskId,omitempty"`
+	ItemId               string   `protobuf:"bytes,2,opt,name=itemId,proto3" json:"itemId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveChecklistItemRequest) Reset()         { *m = RemoveChecklistItemRequest{} }
+func (m *RemoveChecklistItemRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveChecklistItemRequest) ProtoMessage()    {}
+func (*RemoveChecklistItemRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{20}
+}
+
+func (m *RemoveChecklistItemRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveChecklistItemRequest.Unmarshal(m, b)
+}
+func (m *RemoveChecklistItemRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveChecklistItemRequest.Marshal(b, m, deterministic)
+}
+func (m *RemoveChecklistItemRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveChecklistItemRequest.Merge(m, src)
+}
+func (m *RemoveChecklistItemRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveChecklistItemRequest.Size(m)
+}
+func (m *RemoveChecklistItemRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveChecklistItemRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveChecklistItemRequest proto.InternalMessageInfo
+
+func (m *RemoveChecklistItemRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *RemoveChecklistItemRequest) GetItemId() string {
+	if m != nil {
+		return m.ItemId
+	}
+	return ""
+}
+
+type ListTagsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListTagsRequest) Reset()         { *m = ListTagsRequest{} }
+func (m *ListTagsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListTagsRequest) ProtoMessage()    {}
+func (*ListTagsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{21}
+}
+
+func (m *ListTagsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTagsRequest.Unmarshal(m, b)
+}
+func (m *ListTagsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTagsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListTagsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTagsRequest.Merge(m, src)
+}
+func (m *ListTagsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListTagsRequest.Size(m)
+}
+func (m *ListTagsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTagsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTagsRequest proto.InternalMessageInfo
+
+type TagCount struct {
+	Tag                  string   `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Count                int32    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TagCount) Reset()         { *m = TagCount{} }
+func (m *TagCount) String() string { return proto.CompactTextString(m) }
+func (*TagCount) ProtoMessage()    {}
+func (*TagCount) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{22}
+}
+
+func (m *TagCount) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TagCount.Unmarshal(m, b)
+}
+func (m *TagCount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TagCount.Marshal(b, m, deterministic)
+}
+func (m *TagCount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TagCount.Merge(m, src)
+}
+func (m *TagCount) XXX_Size() int {
+	return xxx_messageInfo_TagCount.Size(m)
+}
+func (m *TagCount) XXX_DiscardUnknown() {
+	xxx_messageInfo_TagCount.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TagCount proto.InternalMessageInfo
+
+func (m *TagCount) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *TagCount) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type ListTagsResponse struct {
+	Tags                 []*TagCount `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	Errors               []*Error    `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListTagsResponse) Reset()         { *m = ListTagsResponse{} }
+func (m *ListTagsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListTagsResponse) ProtoMessage()    {}
+func (*ListTagsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{23}
+}
+
+func (m *ListTagsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTagsResponse.Unmarshal(m, b)
+}
+func (m *ListTagsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTagsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListTagsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTagsResponse.Merge(m, src)
+}
+func (m *ListTagsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListTagsResponse.Size(m)
+}
+func (m *ListTagsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTagsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTagsResponse proto.InternalMessageInfo
+
+func (m *ListTagsResponse) GetTags() []*TagCount {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *ListTagsResponse) GetErrors() []*Error {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+// TaskList is a list that is shared with other members of a company. Its tasks can be seen by the members with
+// view permission, and changed by the members with edit permission
+type TaskList struct {
+	Id          string        `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string        `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	OwnerId     string        `protobuf:"bytes,3,opt,name=ownerId,proto3" json:"ownerId,omitempty"`
+	TenantId    string        `protobuf:"bytes,4,opt,name=tenantId,proto3" json:"tenantId,omitempty"`
+	CreatedDate int64         `protobuf:"varint,5,opt,name=createdDate,proto3" json:"createdDate,omitempty"`
+	Members     []*ListMember `protobuf:"bytes,6,rep,name=members,proto3" json:"members,omitempty"`
+	// archived lists are hidden from GetLists and can't have tasks added to them
+	Archived             bool     `protobuf:"varint,7,opt,name=archived,proto3" json:"archived,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TaskList) Reset()         { *m = TaskList{} }
+func (m *TaskList) String() string { return proto.CompactTextString(m) }
+func (*TaskList) ProtoMessage()    {}
+func (*TaskList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{24}
+}
+
+func (m *TaskList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TaskList.Unmarshal(m, b)
+}
+func (m *TaskList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TaskList.Marshal(b, m, deterministic)
+}
+func (m *TaskList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskList.Merge(m, src)
+}
+func (m *TaskList) XXX_Size() int {
+	return xxx_messageInfo_TaskList.Size(m)
+}
+func (m *TaskList) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaskList proto.InternalMessageInfo
+
+func (m *TaskList) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *TaskList) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TaskList) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *TaskList) GetTenantId() string {
+	if m != nil {
+		return m.TenantId
+	}
+	return ""
+}
+
+func (m *TaskList) GetCreatedDate() int64 {
+	if m != nil {
+		return m.CreatedDate
+	}
+	return 0
+}
+
+func (m *TaskList) GetMembers() []*ListMember {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+func (m *TaskList) GetArchived() bool {
+	if m != nil {
+		return m.Archived
+	}
+	return false
+}
+
+type ListMember struct {
+	UserId               string         `protobuf:"bytes,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	Permission           ListPermission `protobuf:"varint,2,opt,name=permission,proto3,enum=task.ListPermission" json:"permission,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ListMember) Reset()         { *m = ListMember{} }
+func (m *ListMember) String() string { return proto.CompactTextString(m) }
+func (*ListMember) ProtoMessage()    {}
+func (*ListMember) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{25}
+}
+
+func (m *ListMember) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListMember.Unmarshal(m, b)
+}
+func (m *ListMember) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListMember.Marshal(b, m, deterministic)
+}
+func (m *ListMember) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListMember.Merge(m, src)
+}
+func (m *ListMember) XXX_Size() int {
+	return xxx_messageInfo_ListMember.Size(m)
+}
+func (m *ListMember) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListMember.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListMember proto.InternalMessageInfo
+
+func (m *ListMember) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *ListMember) GetPermission() ListPermission {
+	if m != nil {
+		return m.Permission
+	}
+	return ListPermission_LIST_PERMISSION_NONE
+}
+
+type CreateListRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateListRequest) Reset()         { *m = CreateListRequest{} }
+func (m *CreateListRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateListRequest) ProtoMessage()    {}
+func (*CreateListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{26}
+}
+
+func (m *CreateListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateListRequest.Unmarshal(m, b)
+}
+func (m *CreateListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateListRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateListRequest.Merge(m, src)
+}
+func (m *CreateListRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateListRequest.Size(m)
+}
+func (m *CreateListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateListRequest proto.InternalMessageInfo
+
+func (m *CreateListRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type GetListsRequest struct {
+	IncludeArchived      bool     `protobuf:"varint,1,opt,name=includeArchived,proto3" json:"includeArchived,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetListsRequest) Reset()         { *m = GetListsRequest{} }
+func (m *GetListsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetListsRequest) ProtoMessage()    {}
+func (*GetListsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{27}
+}
+
+func (m *GetListsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetListsRequest.Unmarshal(m, b)
+}
+func (m *GetListsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetListsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetListsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetListsRequest.Merge(m, src)
+}
+func (m *GetListsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetListsRequest.Size(m)
+}
+func (m *GetListsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetListsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetListsRequest proto.InternalMessageInfo
+
+func (m *GetListsRequest) GetIncludeArchived() bool {
+	if m != nil {
+		return m.IncludeArchived
+	}
+	return false
+}
+
+type ShareListRequest struct {
+	ListId               string         `protobuf:"bytes,1,opt,name=listId,proto3" json:"listId,omitempty"`
+	UserId               string         `protobuf:"bytes,2,opt,name=userId,proto3" json:"userId,omitempty"`
+	Permission           ListPermission `protobuf:"varint,3,opt,name=permission,proto3,enum=task.ListPermission" json:"permission,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ShareListRequest) Reset()         { *m = ShareListRequest{} }
+func (m *ShareListRequest) String() string { return proto.CompactTextString(m) }
+func (*ShareListRequest) ProtoMessage()    {}
+func (*ShareListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{28}
+}
+
+func (m *ShareListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShareListRequest.Unmarshal(m, b)
+}
+func (m *ShareListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShareListRequest.Marshal(b, m, deterministic)
+}
+func (m *ShareListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShareListRequest.Merge(m, src)
+}
+func (m *ShareListRequest) XXX_Size() int {
+	return xxx_messageInfo_ShareListRequest.Size(m)
+}
+func (m *ShareListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShareListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShareListRequest proto.InternalMessageInfo
+
+func (m *ShareListRequest) GetListId() string {
+	if m != nil {
+		return m.ListId
+	}
+	return ""
+}
+
+func (m *ShareListRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *ShareListRequest) GetPermission() ListPermission {
+	if m != nil {
+		return m.Permission
+	}
+	return ListPermission_LIST_PERMISSION_NONE
+}
+
+type UnshareListRequest struct {
+	ListId               string   `protobuf:"bytes,1,opt,name=listId,proto3" json:"listId,omitempty"`
+	UserId               string   `protobuf:"bytes,2,opt,name=userId,proto3" json:"userId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnshareListRequest) Reset()         { *m = UnshareListRequest{} }
+func (m *UnshareListRequest) String() string { return proto.CompactTextString(m) }
+func (*UnshareListRequest) ProtoMessage()    {}
+func (*UnshareListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{29}
+}
+
+func (m *UnshareListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnshareListRequest.Unmarshal(m, b)
+}
+func (m *UnshareListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnshareListRequest.Marshal(b, m, deterministic)
+}
+func (m *UnshareListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnshareListRequest.Merge(m, src)
+}
+func (m *UnshareListRequest) XXX_Size() int {
+	return xxx_messageInfo_UnshareListRequest.Size(m)
+}
+func (m *UnshareListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnshareListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnshareListRequest proto.InternalMessageInfo
+
+func (m *UnshareListRequest) GetListId() string {
+	if m != nil {
+		return m.ListId
+	}
+	return ""
+}
+
+func (m *UnshareListRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+type ListResponse struct {
+	List                 *TaskList   `protobuf:"bytes,1,opt,name=list,proto3" json:"list,omitempty"`
+	Lists                []*TaskList `protobuf:"bytes,2,rep,name=lists,proto3" json:"lists,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListResponse) Reset()         { *m = ListResponse{} }
+func (m *ListResponse) String() string { return proto.CompactTextString(m) }
+func (*ListResponse) ProtoMessage()    {}
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{30}
+}
+
+func (m *ListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListResponse.Unmarshal(m, b)
+}
+func (m *ListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListResponse.Marshal(b, m, deterministic)
+}
+func (m *ListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListResponse.Merge(m, src)
+}
+func (m *ListResponse) XXX_Size() int {
+	return xxx_messageInfo_ListResponse.Size(m)
+}
+func (m *ListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListResponse proto.InternalMessageInfo
+
+func (m *ListResponse) GetList() *TaskList {
+	if m != nil {
+		return m.List
+	}
+	return nil
+}
+
+func (m *ListResponse) GetLists() []*TaskList {
+	if m != nil {
+		return m.Lists
+	}
+	return nil
+}
+
+type AssignTaskRequest struct {
+	TaskId string `protobuf:"bytes,1,opt,name=taskId,proto3" json:"taskId,omitempty"`
+	// assigneeId is who to assign the task to, or empty to unassign it
+	AssigneeId           string   `protobuf:"bytes,2,opt,name=assigneeId,proto3" json:"assigneeId,omitempty"`
+	Version              int32    `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AssignTaskRequest) Reset()         { *m = AssignTaskRequest{} }
+func (m *AssignTaskRequest) String() string { return proto.CompactTextString(m) }
+func (*AssignTaskRequest) ProtoMessage()    {}
+func (*AssignTaskRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{31}
+}
+
+func (m *AssignTaskRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AssignTaskRequest.Unmarshal(m, b)
+}
+func (m *AssignTaskRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AssignTaskRequest.Marshal(b, m, deterministic)
+}
+func (m *AssignTaskRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AssignTaskRequest.Merge(m, src)
+}
+func (m *AssignTaskRequest) XXX_Size() int {
+	return xxx_messageInfo_AssignTaskRequest.Size(m)
+}
+func (m *AssignTaskRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AssignTaskRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AssignTaskRequest proto.InternalMessageInfo
+
+func (m *AssignTaskRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *AssignTaskRequest) GetAssigneeId() string {
+	if m != nil {
+		return m.AssigneeId
+	}
+	return ""
+}
+
+func (m *AssignTaskRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type UpdateListRequest struct {
+	ListId               string   `protobuf:"bytes,1,opt,name=listId,proto3" json:"listId,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateListRequest) Reset()         { *m = UpdateListRequest{} }
+func (m *UpdateListRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateListRequest) ProtoMessage()    {}
+func (*UpdateListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{32}
+}
+
+func (m *UpdateListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateListRequest.Unmarshal(m, b)
+}
+func (m *UpdateListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateListRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateListRequest.Merge(m, src)
+}
+func (m *UpdateListRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateListRequest.Size(m)
+}
+func (m *UpdateListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateListRequest proto.InternalMessageInfo
+
+func (m *UpdateListRequest) GetListId() string {
+	if m != nil {
+		return m.ListId
+	}
+	return ""
+}
+
+func (m *UpdateListRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type ArchiveListRequest struct {
+	ListId               string   `protobuf:"bytes,1,opt,name=listId,proto3" json:"listId,omitempty"`
+	Archived             bool     `protobuf:"varint,2,opt,name=archived,proto3" json:"archived,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ArchiveListRequest) Reset()         { *m = ArchiveListRequest{} }
+func (m *ArchiveListRequest) String() string { return proto.CompactTextString(m) }
+func (*ArchiveListRequest) ProtoMessage()    {}
+func (*ArchiveListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{33}
+}
+
+func (m *ArchiveListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ArchiveListRequest.Unmarshal(m, b)
+}
+func (m *ArchiveListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ArchiveListRequest.Marshal(b, m, deterministic)
+}
+func (m *ArchiveListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ArchiveListRequest.Merge(m, src)
+}
+func (m *ArchiveListRequest) XXX_Size() int {
+	return xxx_messageInfo_ArchiveListRequest.Size(m)
+}
+func (m *ArchiveListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ArchiveListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ArchiveListRequest proto.InternalMessageInfo
+
+func (m *ArchiveListRequest) GetListId() string {
+	if m != nil {
+		return m.ListId
+	}
+	return ""
+}
+
+func (m *ArchiveListRequest) GetArchived() bool {
+	if m != nil {
+		return m.Archived
+	}
+	return false
+}
+
+type DeleteListRequest struct {
+	ListId               string   `protobuf:"bytes,1,opt,name=listId,proto3" json:"listId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteListRequest) Reset()         { *m = DeleteListRequest{} }
+func (m *DeleteListRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteListRequest) ProtoMessage()    {}
+func (*DeleteListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{34}
+}
+
+func (m *DeleteListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteListRequest.Unmarshal(m, b)
+}
+func (m *DeleteListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteListRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteListRequest.Merge(m, src)
+}
+func (m *DeleteListRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteListRequest.Size(m)
+}
+func (m *DeleteListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteListRequest.DiscardUnknown(m)
 }
 
-func (m *UpdateTask) GetDescription() string {
+var xxx_messageInfo_DeleteListRequest proto.InternalMessageInfo
+
+func (m *DeleteListRequest) GetListId() string {
 	if m != nil {
-		return m.Description
+		return m.ListId
 	}
 	return ""
 }
 
-type DailyDoStatusRequest struct {
-	TaskId               string   `protobuf:"bytes,1,opt,name=taskId,proto3" json:"taskId,omitempty"`
-	Status               bool     `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+type MoveTaskRequest struct {
+	TaskId string `protobuf:"bytes,1,opt,name=taskId,proto3" json:"taskId,omitempty"`
+	// listId is the list to move the task to, or empty to take it out of its list
+	ListId               string   `protobuf:"bytes,2,opt,name=listId,proto3" json:"listId,omitempty"`
+	Version              int32    `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DailyDoStatusRequest) Reset()         { *m = DailyDoStatusRequest{} }
-func (m *DailyDoStatusRequest) String() string { return proto.CompactTextString(m) }
-func (*DailyDoStatusRequest) ProtoMessage()    {}
-func (*DailyDoStatusRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_152e577c5c92a6d4, []int{6}
+func (m *MoveTaskRequest) Reset()         { *m = MoveTaskRequest{} }
+func (m *MoveTaskRequest) String() string { return proto.CompactTextString(m) }
+func (*MoveTaskRequest) ProtoMessage()    {}
+func (*MoveTaskRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{35}
 }
 
-func (m *DailyDoStatusRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DailyDoStatusRequest.Unmarshal(m, b)
+func (m *MoveTaskRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MoveTaskRequest.Unmarshal(m, b)
 }
-func (m *DailyDoStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DailyDoStatusRequest.Marshal(b, m, deterministic)
+func (m *MoveTaskRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MoveTaskRequest.Marshal(b, m, deterministic)
 }
-func (m *DailyDoStatusRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DailyDoStatusRequest.Merge(m, src)
+func (m *MoveTaskRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MoveTaskRequest.Merge(m, src)
 }
-func (m *DailyDoStatusRequest) XXX_Size() int {
-	return xxx_messageInfo_DailyDoStatusRequest.Size(m)
+func (m *MoveTaskRequest) XXX_Size() int {
+	return xxx_messageInfo_MoveTaskRequest.Size(m)
 }
-func (m *DailyDoStatusRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_DailyDoStatusRequest.DiscardUnknown(m)
+func (m *MoveTaskRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MoveTaskRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DailyDoStatusRequest proto.InternalMessageInfo
+var xxx_messageInfo_MoveTaskRequest proto.InternalMessageInfo
 
-func (m *DailyDoStatusRequest) GetTaskId() string {
+func (m *MoveTaskRequest) GetTaskId() string {
 	if m != nil {
 		return m.TaskId
 	}
 	return ""
 }
 
-func (m *DailyDoStatusRequest) GetStatus() bool {
+func (m *MoveTaskRequest) GetListId() string {
 	if m != nil {
-		return m.Status
+		return m.ListId
 	}
-	return false
+	return ""
 }
 
-type CompleteTaskRequest struct {
-	TaskId               string   `protobuf:"bytes,1,opt,name=taskId,proto3" json:"taskId,omitempty"`
-	Completed            bool     `protobuf:"varint,2,opt,name=completed,proto3" json:"completed,omitempty"`
+func (m *MoveTaskRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type ReorderListRequest struct {
+	ListId               string   `protobuf:"bytes,1,opt,name=listId,proto3" json:"listId,omitempty"`
+	TaskIds              []string `protobuf:"bytes,2,rep,name=taskIds,proto3" json:"taskIds,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CompleteTaskRequest) Reset()         { *m = CompleteTaskRequest{} }
-func (m *CompleteTaskRequest) String() string { return proto.CompactTextString(m) }
-func (*CompleteTaskRequest) ProtoMessage()    {}
-func (*CompleteTaskRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_152e577c5c92a6d4, []int{7}
+func (m *ReorderListRequest) Reset()         { *m = ReorderListRequest{} }
+func (m *ReorderListRequest) String() string { return proto.CompactTextString(m) }
+func (*ReorderListRequest) ProtoMessage()    {}
+func (*ReorderListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_152e577c5c92a6d4, []int{36}
 }
 
-func (m *CompleteTaskRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CompleteTaskRequest.Unmarshal(m, b)
+func (m *ReorderListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReorderListRequest.Unmarshal(m, b)
 }
-func (m *CompleteTaskRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CompleteTaskRequest.Marshal(b, m, deterministic)
+func (m *ReorderListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReorderListRequest.Marshal(b, m, deterministic)
 }
-func (m *CompleteTaskRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CompleteTaskRequest.Merge(m, src)
+func (m *ReorderListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReorderListRequest.Merge(m, src)
 }
-func (m *CompleteTaskRequest) XXX_Size() int {
-	return xxx_messageInfo_CompleteTaskRequest.Size(m)
+func (m *ReorderListRequest) XXX_Size() int {
+	return xxx_messageInfo_ReorderListRequest.Size(m)
 }
-func (m *CompleteTaskRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_CompleteTaskRequest.DiscardUnknown(m)
+func (m *ReorderListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReorderListRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CompleteTaskRequest proto.InternalMessageInfo
+var xxx_messageInfo_ReorderListRequest proto.InternalMessageInfo
 
-func (m *CompleteTaskRequest) GetTaskId() string {
+func (m *ReorderListRequest) GetListId() string {
 	if m != nil {
-		return m.TaskId
+		return m.ListId
 	}
 	return ""
 }
 
-func (m *CompleteTaskRequest) GetCompleted() bool {
+func (m *ReorderListRequest) GetTaskIds() []string {
 	if m != nil {
-		return m.Completed
+		return m.TaskIds
 	}
-	return false
+	return nil
 }
 
 func init() {
+	proto.RegisterEnum("task.SortOrder", SortOrder_name, SortOrder_value)
+	proto.RegisterEnum("task.Priority", Priority_name, Priority_value)
+	proto.RegisterEnum("task.OpenChecklist", OpenChecklist_name, OpenChecklist_value)
+	proto.RegisterEnum("task.ListPermission", ListPermission_name, ListPermission_value)
 	proto.RegisterType((*Request)(nil), "task.Request")
 	proto.RegisterType((*Task)(nil), "task.Task")
+	proto.RegisterType((*ChecklistItem)(nil), "task.ChecklistItem")
 	proto.RegisterType((*Response)(nil), "task.Response")
 	proto.RegisterType((*Error)(nil), "task.Error")
 	proto.RegisterType((*CreateTask)(nil), "task.CreateTask")
 	proto.RegisterType((*UpdateTask)(nil), "task.UpdateTask")
 	proto.RegisterType((*DailyDoStatusRequest)(nil), "task.DailyDoStatusRequest")
 	proto.RegisterType((*CompleteTaskRequest)(nil), "task.CompleteTaskRequest")
+	proto.RegisterType((*DeleteTaskRequest)(nil), "task.DeleteTaskRequest")
+	proto.RegisterType((*RestoreTaskRequest)(nil), "task.RestoreTaskRequest")
+	proto.RegisterType((*PurgeRequest)(nil), "task.PurgeRequest")
+	proto.RegisterType((*DailyDoHistory)(nil), "task.DailyDoHistory")
+	proto.RegisterType((*DailyDoHistoryRequest)(nil), "task.DailyDoHistoryRequest")
+	proto.RegisterType((*DailyDoHistoryResponse)(nil), "task.DailyDoHistoryResponse")
+	proto.RegisterType((*StreakRequest)(nil), "task.StreakRequest")
+	proto.RegisterType((*StreakResponse)(nil), "task.StreakResponse")
+	proto.RegisterType((*AddChecklistItemRequest)(nil), "task.AddChecklistItemRequest")
+	proto.RegisterType((*ReorderChecklistRequest)(nil), "task.ReorderChecklistRequest")
+	proto.RegisterType((*ToggleChecklistItemRequest)(nil), "task.ToggleChecklistItemRequest")
+	proto.RegisterType((*RemoveChecklistItemRequest)(nil), "task.RemoveChecklistItemRequest")
+	proto.RegisterType((*ListTagsRequest)(nil), "task.ListTagsRequest")
+	proto.RegisterType((*TagCount)(nil), "task.TagCount")
+	proto.RegisterType((*ListTagsResponse)(nil), "task.ListTagsResponse")
+	proto.RegisterType((*TaskList)(nil), "task.TaskList")
+	proto.RegisterType((*ListMember)(nil), "task.ListMember")
+	proto.RegisterType((*CreateListRequest)(nil), "task.CreateListRequest")
+	proto.RegisterType((*GetListsRequest)(nil), "task.GetListsRequest")
+	proto.RegisterType((*ShareListRequest)(nil), "task.ShareListRequest")
+	proto.RegisterType((*UnshareListRequest)(nil), "task.UnshareListRequest")
+	proto.RegisterType((*ListResponse)(nil), "task.ListResponse")
+	proto.RegisterType((*AssignTaskRequest)(nil), "task.AssignTaskRequest")
+	proto.RegisterType((*UpdateListRequest)(nil), "task.UpdateListRequest")
+	proto.RegisterType((*ArchiveListRequest)(nil), "task.ArchiveListRequest")
+	proto.RegisterType((*DeleteListRequest)(nil), "task.DeleteListRequest")
+	proto.RegisterType((*MoveTaskRequest)(nil), "task.MoveTaskRequest")
+	proto.RegisterType((*ReorderListRequest)(nil), "task.ReorderListRequest")
 }
 
 func init() { proto.RegisterFile("proto/task/task.proto", fileDescriptor_152e577c5c92a6d4) }
 
 var fileDescriptor_152e577c5c92a6d4 = []byte{
-	// 446 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x53, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0xc5, 0x76, 0xec, 0x24, 0x13, 0x5a, 0xa1, 0x69, 0x89, 0x96, 0x0a, 0x21, 0xcb, 0x20, 0x94,
-	0x03, 0x2a, 0x52, 0x39, 0x22, 0x4e, 0x09, 0xa0, 0x8a, 0xdb, 0x16, 0x6e, 0x08, 0xc9, 0x78, 0x47,
-	0x60, 0x35, 0x64, 0xdd, 0xdd, 0x0d, 0x12, 0xbf, 0x83, 0x3f, 0xc5, 0xcf, 0x42, 0x9e, 0x5d, 0x37,
-	0x5f, 0x06, 0x24, 0x2e, 0xc9, 0xce, 0x9b, 0xb7, 0x6f, 0x32, 0xef, 0x6d, 0xe0, 0x7e, 0x63, 0xb4,
-	0xd3, 0xcf, 0x5d, 0x69, 0xaf, 0xf9, 0xe3, 0x9c, 0x6b, 0x1c, 0xb4, 0xe7, 0x62, 0x0c, 0x43, 0x49,
-	0x37, 0x6b, 0xb2, 0xae, 0xf8, 0x15, 0xc1, 0xe0, 0x7d, 0x69, 0xaf, 0xf1, 0x18, 0xe2, 0x5a, 0x89,
-	0x28, 0x8f, 0x66, 0x63, 0x19, 0xd7, 0x0a, 0x4f, 0x21, 0x75, 0xb5, 0x5b, 0x92, 0x88, 0x19, 0xf2,
-	0x05, 0xe6, 0x30, 0x51, 0x64, 0x2b, 0x53, 0x37, 0xae, 0xd6, 0x2b, 0x91, 0x70, 0x6f, 0x1b, 0xc2,
-	0x29, 0x64, 0x6b, 0x4b, 0xe6, 0x52, 0x89, 0x01, 0x37, 0x43, 0xd5, 0xde, 0xac, 0x0c, 0x95, 0x8e,
-	0xd4, 0xa2, 0x74, 0x24, 0xd2, 0x3c, 0x9a, 0x25, 0x72, 0x1b, 0xc2, 0x27, 0x70, 0x54, 0xe9, 0x6f,
-	0xcd, 0x92, 0x3a, 0x4e, 0xc6, 0x9c, 0x5d, 0x10, 0x05, 0x0c, 0x55, 0x59, 0x2f, 0x7f, 0x2c, 0xb4,
-	0x18, 0xe6, 0xd1, 0x6c, 0x24, 0xbb, 0xb2, 0xb8, 0x81, 0x91, 0x24, 0xdb, 0xe8, 0x95, 0x25, 0x7c,
-	0x04, 0xbc, 0x29, 0xef, 0x33, 0xb9, 0x80, 0x73, 0xb6, 0xa0, 0xdd, 0x53, 0x32, 0x8e, 0x39, 0xa4,
-	0xed, 0xb7, 0x15, 0x71, 0x9e, 0xec, 0x11, 0x7c, 0x03, 0x1f, 0x43, 0x46, 0xc6, 0x68, 0x63, 0x45,
-	0xc2, 0x94, 0x89, 0xa7, 0xbc, 0x6e, 0x31, 0x19, 0x5a, 0xc5, 0x2b, 0x48, 0x19, 0x40, 0x84, 0x41,
-	0xa5, 0x15, 0xf1, 0xbc, 0x54, 0xf2, 0x79, 0xdf, 0xab, 0xf8, 0xc0, 0xab, 0xe2, 0x13, 0xc0, 0x9c,
-	0x0d, 0xe0, 0x04, 0x6e, 0x1d, 0x8f, 0xfe, 0xe2, 0xf8, 0xa1, 0xca, 0xb6, 0x23, 0xc9, 0xae, 0x23,
-	0x1f, 0x01, 0x3e, 0x34, 0xaa, 0xd3, 0x9f, 0x42, 0xd6, 0xae, 0x70, 0xd9, 0xa5, 0x1c, 0xaa, 0xff,
-	0x4d, 0xba, 0x78, 0x03, 0xa7, 0x0b, 0x3f, 0xe8, 0xca, 0x95, 0x6e, 0x6d, 0xc3, 0x93, 0xfa, 0xe3,
-	0x9c, 0x29, 0x64, 0x96, 0x89, 0x3c, 0x68, 0x24, 0x43, 0x55, 0xbc, 0x83, 0x93, 0x79, 0x88, 0x98,
-	0x03, 0xf8, 0x87, 0xcc, 0x43, 0x18, 0xdf, 0xbe, 0x88, 0xa0, 0xb4, 0x01, 0x2e, 0x7e, 0xc6, 0x30,
-	0x69, 0x55, 0xae, 0xc8, 0x7c, 0xaf, 0x2b, 0xc2, 0xa7, 0x90, 0xbc, 0x25, 0x87, 0x47, 0x3e, 0xbd,
-	0xa0, 0x7d, 0x76, 0xdc, 0x95, 0xfe, 0xb9, 0x14, 0x77, 0xf0, 0x19, 0x64, 0x3e, 0x0a, 0xbc, 0xe7,
-	0x7b, 0x9b, 0x60, 0xfa, 0xd9, 0xde, 0xd8, 0x8e, 0xbd, 0xb1, 0xb9, 0x87, 0x3d, 0x87, 0x93, 0xf9,
-	0xd7, 0x72, 0xf5, 0x85, 0x76, 0xec, 0xc2, 0x33, 0x4f, 0xec, 0xf3, 0xb0, 0x47, 0xe4, 0x25, 0xdc,
-	0xdd, 0x76, 0x09, 0x1f, 0x84, 0x9f, 0x79, 0xe8, 0xdc, 0xe1, 0xe5, 0xcf, 0x19, 0xff, 0xfb, 0x5f,
-	0xfc, 0x0e, 0x00, 0x00, 0xff, 0xff, 0x22, 0xf8, 0xb2, 0x08, 0x16, 0x04, 0x00, 0x00,
+	// 1914 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0xfb, 0x6e, 0xe3, 0x58,
+	0x19, 0x1f, 0x3b, 0x57, 0x7f, 0x69, 0x52, 0xe7, 0xf4, 0x32, 0xde, 0xee, 0x30, 0x8a, 0xcc, 0x6a,
+	0xa9, 0xca, 0xee, 0x20, 0x3a, 0x20, 0x18, 0x8d, 0x56, 0xa8, 0x4a, 0x32, 0xad, 0xb5, 0xcd, 0x45,
+	0x27, 0x19, 0x16, 0x58, 0x89, 0xe2, 0x89, 0x0f, 0xa9, 0x69, 0x62, 0x07, 0xdb, 0x29, 0x53, 0x9e,
+	0x82, 0xff, 0xf8, 0x97, 0x37, 0xe1, 0x0d, 0x78, 0x0c, 0x24, 0xde, 0x02, 0x9d, 0x9b, 0x6f, 0x71,
+	0xda, 0x70, 0x91, 0xf6, 0x9f, 0x1d, 0x7f, 0x97, 0x73, 0xbe, 0xef, 0x7c, 0xd7, 0x5f, 0xba, 0x70,
+	0xb4, 0x0a, 0xfc, 0xc8, 0xff, 0x51, 0x64, 0x87, 0x77, 0xec, 0x3f, 0xaf, 0x18, 0x8d, 0xca, 0xf4,
+	0xdb, 0xfc, 0x67, 0x09, 0x6a, 0x98, 0xfc, 0x71, 0x4d, 0xc2, 0x08, 0x9d, 0x40, 0x7d, 0x65, 0xcf,
+	0xc9, 0xc4, 0xfd, 0x33, 0x31, 0x94, 0x8e, 0x72, 0x5a, 0xc1, 0x31, 0x8d, 0x5e, 0x80, 0x46, 0xbf,
+	0xa7, 0xfe, 0x1d, 0xf1, 0x0c, 0xb5, 0xa3, 0x9c, 0x6a, 0x38, 0x61, 0xa0, 0xcf, 0xa0, 0x39, 0xf3,
+	0x97, 0xab, 0x05, 0x89, 0x88, 0x33, 0xf2, 0x16, 0x0f, 0x46, 0xa9, 0xa3, 0x9c, 0xd6, 0x71, 0x96,
+	0x89, 0x3e, 0x87, 0x96, 0xeb, 0x49, 0x16, 0x53, 0x2b, 0x33, 0xb5, 0x1c, 0x17, 0x75, 0xa0, 0xe1,
+	0xd8, 0xee, 0xe2, 0xa1, 0xe7, 0x33, 0xa5, 0x0a, 0x53, 0x4a, 0xb3, 0xa8, 0xc6, 0x2c, 0x20, 0x76,
+	0x44, 0x9c, 0x77, 0x81, 0xbf, 0x34, 0xaa, 0x1d, 0xe5, 0xb4, 0x84, 0xd3, 0x2c, 0xea, 0xaf, 0x20,
+	0xa7, 0xbe, 0x51, 0x63, 0xf2, 0x84, 0x81, 0xbe, 0x04, 0x2d, 0xf4, 0x83, 0x68, 0x14, 0x38, 0x24,
+	0x30, 0xea, 0x1d, 0xe5, 0xb4, 0x75, 0xbe, 0xff, 0x8a, 0xc5, 0x66, 0x22, 0xd9, 0x38, 0xd1, 0x10,
+	0x8e, 0x2f, 0xd6, 0x0e, 0xe9, 0x11, 0xf6, 0x1c, 0x43, 0x8b, 0x1d, 0x4f, 0x71, 0x91, 0x0e, 0xa5,
+	0xc8, 0x9e, 0x1b, 0xc0, 0xc2, 0x43, 0x3f, 0xd1, 0x19, 0xd4, 0x57, 0x81, 0xeb, 0x07, 0x6e, 0xf4,
+	0x60, 0x34, 0x98, 0x9d, 0x16, 0xb7, 0x33, 0x16, 0x5c, 0x1c, 0xcb, 0xe9, 0xa3, 0xfc, 0x7b, 0x12,
+	0x38, 0x6b, 0x1e, 0x9b, 0x3d, 0xfe, 0xec, 0x14, 0x0b, 0x1d, 0x43, 0x75, 0xe1, 0x86, 0x91, 0xe5,
+	0x18, 0x4d, 0x66, 0x42, 0x50, 0xc8, 0x84, 0x3d, 0x3b, 0x0c, 0xdd, 0xb9, 0x47, 0x1f, 0x37, 0x20,
+	0x46, 0x8b, 0x1d, 0xcd, 0xf0, 0xcc, 0xbf, 0x97, 0xa1, 0x3c, 0xb5, 0xc3, 0x3b, 0xd4, 0x02, 0xd5,
+	0x75, 0x58, 0x7e, 0x35, 0xac, 0xba, 0x0e, 0x3a, 0x84, 0x4a, 0xe4, 0x46, 0x0b, 0x22, 0xb2, 0xca,
+	0x09, 0x96, 0x03, 0x12, 0xce, 0x02, 0x77, 0x15, 0xb9, 0xbe, 0xc7, 0xf2, 0xa9, 0xe1, 0x34, 0x8b,
+	0x3a, 0xb3, 0x0e, 0x49, 0x60, 0x39, 0x2c, 0x8b, 0x1a, 0x16, 0x54, 0x2a, 0x37, 0x3d, 0x3b, 0x22,
+	0x46, 0x25, 0x93, 0x1b, 0xca, 0xca, 0x54, 0x0b, 0xd3, 0xe1, 0xf9, 0xcb, 0x32, 0x91, 0x01, 0x35,
+	0x91, 0x72, 0x96, 0xbf, 0x3a, 0x96, 0x24, 0x93, 0x88, 0x3c, 0xd4, 0x85, 0x84, 0x93, 0xdc, 0xeb,
+	0xe4, 0x5e, 0x8d, 0xdb, 0x4e, 0xb1, 0xd0, 0x8f, 0x41, 0x9b, 0xdd, 0x92, 0xd9, 0x1d, 0x8d, 0x9c,
+	0x01, 0x9d, 0xd2, 0x69, 0xe3, 0xfc, 0x80, 0x67, 0xa4, 0x2b, 0xd9, 0x56, 0x44, 0x96, 0x38, 0xd1,
+	0x62, 0xe6, 0xd6, 0x84, 0x5d, 0xd8, 0x60, 0x17, 0x4a, 0x32, 0x93, 0xdd, 0xbd, 0x27, 0xb2, 0x8b,
+	0xa0, 0x1c, 0xd9, 0xf3, 0xd0, 0x68, 0x76, 0x4a, 0xa7, 0x1a, 0x66, 0xdf, 0xe8, 0x25, 0x40, 0x40,
+	0x66, 0xeb, 0x20, 0x20, 0xde, 0x8c, 0x67, 0x4d, 0xc3, 0x29, 0x0e, 0x95, 0xfb, 0xb3, 0x58, 0xbe,
+	0xcf, 0x5a, 0x32, 0xc5, 0xa1, 0x9e, 0xdd, 0x93, 0x20, 0xa4, 0x09, 0xd2, 0x99, 0x50, 0x92, 0xb4,
+	0x95, 0x23, 0xe2, 0xd9, 0x1e, 0xad, 0x95, 0x36, 0xbb, 0x37, 0xa6, 0x53, 0x55, 0x84, 0x32, 0x55,
+	0xf4, 0x12, 0x40, 0x54, 0x0c, 0xb1, 0x1c, 0xe3, 0x80, 0x7b, 0x93, 0x70, 0x4c, 0x1f, 0x9a, 0x99,
+	0x18, 0xed, 0x58, 0x49, 0xb4, 0x13, 0x65, 0x62, 0xc5, 0x5c, 0x48, 0x18, 0x6c, 0xe6, 0xf8, 0xa1,
+	0xcb, 0x8a, 0xac, 0x2c, 0x66, 0x8e, 0xa0, 0xcd, 0xbf, 0x2a, 0x50, 0xc7, 0x24, 0x5c, 0xf9, 0x5e,
+	0x48, 0x63, 0xc1, 0x06, 0x16, 0x33, 0xd7, 0x38, 0x07, 0x1e, 0x67, 0x5a, 0xd0, 0x98, 0xf1, 0x51,
+	0x07, 0x2a, 0xf4, 0xdf, 0xd0, 0x50, 0x3b, 0xa5, 0x9c, 0x02, 0x17, 0xa0, 0xef, 0x43, 0x95, 0x04,
+	0x81, 0x1f, 0x84, 0x46, 0x89, 0xa9, 0x34, 0xb8, 0x4a, 0x9f, 0xf2, 0xb0, 0x10, 0xd1, 0xda, 0xf4,
+	0xc8, 0xc7, 0x68, 0x1c, 0xcf, 0x3a, 0x5e, 0xdc, 0x59, 0xa6, 0xf9, 0x15, 0x54, 0xd8, 0x31, 0x9a,
+	0xd5, 0x99, 0xef, 0xc8, 0x71, 0xc9, 0xbe, 0xf3, 0xad, 0xa3, 0x6e, 0xb4, 0x8e, 0xf9, 0x17, 0x15,
+	0xa0, 0xcb, 0x1a, 0x82, 0x75, 0x64, 0x1c, 0x37, 0xe5, 0x91, 0x0e, 0xdc, 0xbc, 0x26, 0xdd, 0x21,
+	0xa5, 0xcd, 0x0e, 0x11, 0x25, 0x5b, 0xde, 0x5e, 0xb2, 0x95, 0x1d, 0x4b, 0xb6, 0xba, 0xb5, 0x64,
+	0x6b, 0x1b, 0x25, 0x9b, 0x14, 0x57, 0xfd, 0x91, 0xe2, 0xd2, 0x36, 0x8a, 0x8b, 0x86, 0xe4, 0xfd,
+	0xca, 0x91, 0x21, 0x39, 0x86, 0x2a, 0xf5, 0xca, 0x92, 0xe5, 0x25, 0xa8, 0xff, 0x7a, 0x58, 0x7d,
+	0x77, 0x01, 0x79, 0x09, 0xb0, 0x66, 0xef, 0x1a, 0xd0, 0xea, 0xad, 0xb3, 0x93, 0x29, 0x4e, 0xba,
+	0x87, 0xb5, 0x4c, 0x0f, 0x9b, 0xbf, 0x83, 0xc3, 0x1e, 0xcf, 0xe7, 0x24, 0xb2, 0xa3, 0x75, 0x28,
+	0xd7, 0xf4, 0xb6, 0xd8, 0x1c, 0x43, 0x35, 0x64, 0x8a, 0x2c, 0x38, 0x75, 0x2c, 0xa8, 0xb4, 0x85,
+	0x52, 0xd6, 0xc2, 0xdf, 0x14, 0x38, 0xe8, 0x8a, 0x56, 0x64, 0x9d, 0xf2, 0x84, 0x85, 0x4c, 0x2b,
+	0xab, 0xf9, 0x56, 0x7e, 0x03, 0x4d, 0x7f, 0x45, 0xbc, 0x78, 0x46, 0x30, 0x6b, 0x2d, 0x39, 0x5e,
+	0x47, 0x69, 0x11, 0xce, 0x6a, 0xa6, 0x5d, 0x2c, 0x67, 0x5d, 0xfc, 0x21, 0xb4, 0x7b, 0x64, 0x47,
+	0xff, 0xcc, 0x2f, 0x00, 0x61, 0x12, 0x46, 0x7e, 0xb0, 0xa3, 0xf6, 0xde, 0x78, 0x1d, 0xcc, 0x89,
+	0xd4, 0x7b, 0x01, 0x9a, 0xbf, 0x70, 0x48, 0x30, 0xbd, 0xb5, 0x3d, 0xa6, 0x5a, 0xc2, 0x09, 0xc3,
+	0x5c, 0x41, 0x4b, 0x64, 0xe3, 0xca, 0xa5, 0x26, 0x1e, 0x52, 0x0b, 0x50, 0xc9, 0x2c, 0x40, 0x1d,
+	0x4a, 0x8e, 0xfd, 0x20, 0x2a, 0x94, 0x7e, 0xa6, 0x3c, 0x28, 0x6d, 0x8f, 0x67, 0x39, 0x17, 0x4f,
+	0xf3, 0x2d, 0x1c, 0x65, 0x2d, 0x4a, 0x47, 0x11, 0x94, 0x7f, 0x4f, 0x61, 0x0f, 0x37, 0xcb, 0xbe,
+	0xe9, 0x2c, 0x8e, 0x7c, 0x61, 0x53, 0x8d, 0x7c, 0x73, 0x09, 0xc7, 0xf9, 0xc3, 0x62, 0x90, 0xbe,
+	0x82, 0xda, 0x2d, 0x67, 0x19, 0x0a, 0x9b, 0x83, 0x87, 0x3c, 0x41, 0x39, 0x75, 0xa9, 0x94, 0x1a,
+	0x9b, 0xea, 0xd6, 0xb1, 0x69, 0xee, 0x43, 0x73, 0x12, 0x05, 0xc4, 0x96, 0x41, 0x37, 0x5d, 0x68,
+	0x49, 0x86, 0xb0, 0x6b, 0x40, 0x8d, 0x37, 0x45, 0x24, 0xa6, 0xa5, 0x24, 0xa9, 0x64, 0xe1, 0x7b,
+	0x73, 0x12, 0x46, 0xec, 0x01, 0x15, 0x2c, 0xc9, 0x9d, 0x46, 0xb6, 0x79, 0x09, 0xcf, 0x2f, 0x1c,
+	0x27, 0xbb, 0xbe, 0x9f, 0x28, 0xe4, 0xc2, 0x31, 0x62, 0x7e, 0x0d, 0xcf, 0x31, 0xf1, 0x29, 0xe2,
+	0x4b, 0x0a, 0xf5, 0x89, 0x8b, 0x0c, 0xa8, 0xb9, 0x11, 0x59, 0x5a, 0x0e, 0x8f, 0x8e, 0x86, 0x25,
+	0x69, 0xfe, 0x01, 0x4e, 0xa6, 0xfe, 0x7c, 0xbe, 0x20, 0xff, 0x91, 0x63, 0xc7, 0x50, 0xe5, 0x17,
+	0x08, 0xcf, 0x04, 0xf5, 0xf8, 0x12, 0x35, 0xaf, 0xe1, 0x04, 0x93, 0xa5, 0x7f, 0xff, 0x7f, 0xb1,
+	0x65, 0xb6, 0x61, 0xff, 0xda, 0x0d, 0xa3, 0xa9, 0x3d, 0x97, 0x23, 0xc7, 0x3c, 0x87, 0xfa, 0xd4,
+	0x9e, 0x77, 0xfd, 0xb5, 0x17, 0x49, 0x90, 0xab, 0x24, 0x20, 0xf7, 0x10, 0x2a, 0x33, 0x2a, 0x12,
+	0xd9, 0xe3, 0x84, 0xf9, 0x2d, 0xe8, 0xc9, 0x35, 0xa2, 0x06, 0x4c, 0x31, 0x40, 0x79, 0xe1, 0xb5,
+	0xe4, 0x8e, 0xe6, 0x37, 0x8b, 0x81, 0xba, 0x53, 0xbd, 0xfd, 0x43, 0xa1, 0x1e, 0x85, 0x77, 0xd4,
+	0xc2, 0x06, 0x0e, 0x41, 0x50, 0xf6, 0xec, 0xa5, 0x4c, 0x2e, 0xfb, 0xa6, 0x89, 0xf2, 0xff, 0xe4,
+	0xb1, 0x6e, 0xe5, 0x3d, 0x28, 0xc9, 0x0c, 0x54, 0x2a, 0xe7, 0xa0, 0xd2, 0xd3, 0x58, 0xf6, 0x0c,
+	0x6a, 0x4b, 0xb2, 0xfc, 0x40, 0x02, 0xbe, 0x15, 0x1a, 0xe7, 0x3a, 0x77, 0x97, 0x3a, 0x36, 0x60,
+	0x02, 0x2c, 0x15, 0xa8, 0x25, 0x3b, 0x98, 0xdd, 0xba, 0xf7, 0xc4, 0x11, 0x90, 0x36, 0xa6, 0xcd,
+	0xdf, 0x00, 0x24, 0x47, 0xb6, 0x8e, 0x96, 0x9f, 0x00, 0xac, 0x48, 0xb0, 0x74, 0xc3, 0x50, 0x42,
+	0x82, 0x96, 0x6c, 0x5f, 0x7a, 0x7a, 0x1c, 0xcb, 0x70, 0x4a, 0xcf, 0xfc, 0x01, 0xb4, 0x39, 0xda,
+	0xb8, 0x4e, 0x55, 0xb4, 0x0c, 0x92, 0x92, 0x04, 0xc9, 0x7c, 0x0b, 0xfb, 0x97, 0x24, 0xa2, 0x5a,
+	0xf1, 0xb2, 0x39, 0x85, 0x7d, 0xf1, 0x23, 0xe7, 0x42, 0xba, 0xae, 0x30, 0xd7, 0xf3, 0x6c, 0xf3,
+	0x23, 0xe8, 0x93, 0x5b, 0x3b, 0xc8, 0x18, 0x49, 0xd0, 0x80, 0x92, 0x41, 0x03, 0xc9, 0xfb, 0xd4,
+	0x47, 0xde, 0x57, 0xda, 0xf1, 0x7d, 0x3d, 0x40, 0xef, 0xbd, 0xf0, 0x7f, 0xb4, 0x6d, 0xfe, 0x0a,
+	0xf6, 0xf8, 0xf1, 0xa4, 0x56, 0xe9, 0x09, 0x01, 0x38, 0x5b, 0x09, 0x9e, 0x64, 0x5a, 0x4c, 0x86,
+	0x3e, 0x83, 0x0a, 0xfd, 0x57, 0x96, 0x6a, 0x5e, 0x89, 0x0b, 0x4d, 0x02, 0xed, 0x0b, 0x86, 0x74,
+	0x76, 0xd9, 0xb1, 0x59, 0xa0, 0xa4, 0xe6, 0x81, 0xd2, 0x23, 0xdb, 0xfc, 0x17, 0xd0, 0xe6, 0x08,
+	0x6a, 0x97, 0x28, 0x14, 0xf4, 0x88, 0x79, 0x05, 0x48, 0x64, 0x73, 0x97, 0x1b, 0xd2, 0xd5, 0xac,
+	0xe6, 0xaa, 0x39, 0xde, 0xda, 0x3b, 0x5c, 0x64, 0x7e, 0x0b, 0xfb, 0x03, 0xff, 0x7e, 0x27, 0x00,
+	0x92, 0x5c, 0xa1, 0x66, 0x7c, 0xd9, 0x1e, 0x94, 0x77, 0x80, 0xc4, 0x4c, 0xdf, 0xe5, 0x4d, 0x06,
+	0xd4, 0xb8, 0xa5, 0x78, 0x9c, 0x0b, 0xf2, 0x6c, 0x00, 0x5a, 0xfc, 0xa7, 0x01, 0x74, 0x04, 0xed,
+	0x2e, 0xee, 0x5f, 0x4c, 0xfb, 0xbd, 0x9b, 0x8b, 0x49, 0xb7, 0x3f, 0xec, 0x59, 0xc3, 0x4b, 0xfd,
+	0x19, 0x3a, 0x06, 0x24, 0xd9, 0xbd, 0x7e, 0xcc, 0x57, 0x50, 0x1b, 0x9a, 0xd7, 0xd6, 0x64, 0x7a,
+	0x33, 0x1e, 0x4d, 0xac, 0xa9, 0x35, 0x1a, 0xea, 0xea, 0xd9, 0x37, 0x50, 0x97, 0xf8, 0x92, 0x8a,
+	0xc7, 0xd8, 0x1a, 0x61, 0x6b, 0xfa, 0xeb, 0x9b, 0xe1, 0x68, 0xd8, 0xd7, 0x9f, 0x21, 0x1d, 0xf6,
+	0x62, 0xd6, 0xf5, 0xe8, 0x1b, 0x5d, 0x41, 0x07, 0xb0, 0x1f, 0x73, 0x06, 0xfd, 0x9e, 0xf5, 0x7e,
+	0xa0, 0xab, 0x99, 0x93, 0x57, 0xd6, 0xe5, 0x95, 0x5e, 0x3a, 0xfb, 0x00, 0xcd, 0x0c, 0xd2, 0x42,
+	0x9f, 0xc0, 0xd1, 0x68, 0xdc, 0x1f, 0xde, 0x74, 0xaf, 0xfa, 0xdd, 0xaf, 0x99, 0x1b, 0xd6, 0xe5,
+	0x70, 0x84, 0xa9, 0x95, 0x4d, 0x11, 0xee, 0xbf, 0x7b, 0x3f, 0xe9, 0xeb, 0x0a, 0xfa, 0x14, 0x9e,
+	0xe7, 0x44, 0xdd, 0xd1, 0x60, 0x7c, 0xdd, 0x9f, 0xf6, 0x75, 0xf5, 0xec, 0xb7, 0xd0, 0xca, 0x76,
+	0x23, 0x32, 0xe0, 0x90, 0xbf, 0xb0, 0x8f, 0x07, 0xd6, 0x64, 0x62, 0x8d, 0x86, 0xf2, 0x25, 0x05,
+	0x92, 0x5f, 0x5a, 0x7d, 0xfa, 0xa2, 0x02, 0x49, 0xbf, 0x67, 0x4d, 0x75, 0xf5, 0xfc, 0x5f, 0x0d,
+	0x68, 0xd0, 0x6a, 0x98, 0x90, 0xe0, 0xde, 0x9d, 0x11, 0xf4, 0x39, 0x94, 0x2e, 0x49, 0x84, 0x9a,
+	0xbc, 0xbb, 0x44, 0x0e, 0x4f, 0x5a, 0x92, 0xe4, 0xfd, 0x6a, 0x3e, 0x43, 0x5f, 0x40, 0x95, 0xcf,
+	0x39, 0x24, 0x86, 0x70, 0xf2, 0x1b, 0xab, 0x58, 0x9b, 0xb7, 0x8b, 0xd4, 0x4e, 0x7e, 0x7e, 0x14,
+	0x68, 0x77, 0xe1, 0xa0, 0x7b, 0x6b, 0x7b, 0x73, 0x92, 0x81, 0xe4, 0xe8, 0x24, 0x83, 0x9d, 0x32,
+	0x38, 0xbd, 0xe0, 0x92, 0xb7, 0xb0, 0x97, 0x86, 0xdb, 0xe8, 0x13, 0xe1, 0xe6, 0x26, 0x04, 0x2f,
+	0x38, 0xfc, 0x1a, 0xaa, 0xbc, 0xa7, 0xd0, 0x73, 0x61, 0x94, 0x3c, 0x7d, 0xe8, 0xa7, 0xf4, 0xaf,
+	0x7b, 0x0c, 0x11, 0x23, 0x23, 0x16, 0xe6, 0x00, 0x72, 0xc1, 0xb1, 0x2f, 0xa1, 0xc2, 0xa0, 0x31,
+	0x42, 0xe2, 0xb7, 0x50, 0x0a, 0x27, 0x17, 0xa8, 0x8f, 0xa1, 0x7d, 0x49, 0xa2, 0x1c, 0x3c, 0xfe,
+	0xb4, 0x10, 0x56, 0x8a, 0x3b, 0x5e, 0x14, 0x0b, 0xe3, 0x1b, 0x7f, 0x0e, 0xda, 0x25, 0x89, 0x38,
+	0x82, 0x44, 0xe2, 0x17, 0x44, 0x06, 0x60, 0x9e, 0x1c, 0x66, 0x99, 0xa9, 0x44, 0xe9, 0x79, 0x34,
+	0x88, 0xbe, 0xc7, 0x75, 0xb7, 0xa0, 0xc4, 0xc2, 0x6c, 0xeb, 0x79, 0x24, 0x28, 0x2f, 0xd9, 0x82,
+	0x10, 0x0b, 0x2e, 0xb1, 0xe0, 0xa0, 0x00, 0x01, 0xa2, 0x8e, 0x58, 0x12, 0x5b, 0xc1, 0x61, 0xf1,
+	0x55, 0x05, 0x00, 0x4f, 0x5e, 0xb5, 0x1d, 0xfb, 0x15, 0xd6, 0x60, 0x5d, 0xc2, 0x32, 0x74, 0x94,
+	0xac, 0xd6, 0x14, 0xda, 0x3b, 0x39, 0xce, 0xb3, 0x53, 0x87, 0x21, 0x41, 0x12, 0xb2, 0x0e, 0x37,
+	0xb0, 0xc5, 0x09, 0x4a, 0x2e, 0x48, 0x1d, 0xfe, 0x19, 0xd4, 0x25, 0xba, 0x90, 0x96, 0x73, 0x68,
+	0x63, 0xcb, 0xc1, 0x37, 0xa0, 0xc5, 0xc8, 0x02, 0x09, 0xe7, 0xf2, 0x50, 0x63, 0xcb, 0xd1, 0xaf,
+	0xa0, 0x91, 0x82, 0x06, 0xb2, 0x07, 0x36, 0xd1, 0xc2, 0x56, 0x97, 0x21, 0xd9, 0xdc, 0xf2, 0xbd,
+	0x1b, 0xbb, 0xbc, 0x30, 0xca, 0x90, 0xec, 0x62, 0x79, 0x70, 0x63, 0x3b, 0x6f, 0x77, 0x3a, 0xb5,
+	0x87, 0xa5, 0xd3, 0x9b, 0xab, 0x79, 0xcb, 0xf1, 0xb7, 0x00, 0xc9, 0xf2, 0xcd, 0x0e, 0x8b, 0xa7,
+	0x0f, 0xbf, 0x86, 0xba, 0x5c, 0xc6, 0x32, 0x49, 0xb9, 0xe5, 0x5c, 0xf0, 0xda, 0x37, 0xd0, 0x48,
+	0x2d, 0xd9, 0x64, 0xd2, 0xe4, 0xf7, 0xee, 0xe6, 0xd1, 0x0f, 0x55, 0xf6, 0x3f, 0x23, 0x5e, 0xff,
+	0x7b, 0x00, 0x0c, 0x3c, 0x94, 0x4d, 0xa5, 0x18, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -508,6 +2490,26 @@ type TaskServiceClient interface {
 	Update(ctx context.Context, in *UpdateTask, opts ...client.CallOption) (*Response, error)
 	ChangeDailyDoStatus(ctx context.Context, in *DailyDoStatusRequest, opts ...client.CallOption) (*Response, error)
 	CompleteTask(ctx context.Context, in *CompleteTaskRequest, opts ...client.CallOption) (*Response, error)
+	Delete(ctx context.Context, in *DeleteTaskRequest, opts ...client.CallOption) (*Response, error)
+	Restore(ctx context.Context, in *RestoreTaskRequest, opts ...client.CallOption) (*Response, error)
+	Purge(ctx context.Context, in *PurgeRequest, opts ...client.CallOption) (*Response, error)
+	GetDailyDoHistory(ctx context.Context, in *DailyDoHistoryRequest, opts ...client.CallOption) (*DailyDoHistoryResponse, error)
+	GetStreak(ctx context.Context, in *StreakRequest, opts ...client.CallOption) (*StreakResponse, error)
+	AddChecklistItem(ctx context.Context, in *AddChecklistItemRequest, opts ...client.CallOption) (*Response, error)
+	ReorderChecklist(ctx context.Context, in *ReorderChecklistRequest, opts ...client.CallOption) (*Response, error)
+	ToggleChecklistItem(ctx context.Context, in *ToggleChecklistItemRequest, opts ...client.CallOption) (*Response, error)
+	RemoveChecklistItem(ctx context.Context, in *RemoveChecklistItemRequest, opts ...client.CallOption) (*Response, error)
+	ListTags(ctx context.Context, in *ListTagsRequest, opts ...client.CallOption) (*ListTagsResponse, error)
+	CreateList(ctx context.Context, in *CreateListRequest, opts ...client.CallOption) (*ListResponse, error)
+	GetLists(ctx context.Context, in *GetListsRequest, opts ...client.CallOption) (*ListResponse, error)
+	ShareList(ctx context.Context, in *ShareListRequest, opts ...client.CallOption) (*ListResponse, error)
+	UnshareList(ctx context.Context, in *UnshareListRequest, opts ...client.CallOption) (*ListResponse, error)
+	AssignTask(ctx context.Context, in *AssignTaskRequest, opts ...client.CallOption) (*Response, error)
+	UpdateList(ctx context.Context, in *UpdateListRequest, opts ...client.CallOption) (*ListResponse, error)
+	ArchiveList(ctx context.Context, in *ArchiveListRequest, opts ...client.CallOption) (*ListResponse, error)
+	DeleteList(ctx context.Context, in *DeleteListRequest, opts ...client.CallOption) (*ListResponse, error)
+	MoveTask(ctx context.Context, in *MoveTaskRequest, opts ...client.CallOption) (*Response, error)
+	ReorderList(ctx context.Context, in *ReorderListRequest, opts ...client.CallOption) (*Response, error)
 }
 
 type taskServiceClient struct {
@@ -578,6 +2580,206 @@ func (c *taskServiceClient) CompleteTask(ctx context.Context, in *CompleteTaskRe
 	return out, nil
 }
 
+func (c *taskServiceClient) Delete(ctx context.Context, in *DeleteTaskRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.Delete", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) Restore(ctx context.Context, in *RestoreTaskRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.Restore", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) Purge(ctx context.Context, in *PurgeRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.Purge", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) GetDailyDoHistory(ctx context.Context, in *DailyDoHistoryRequest, opts ...client.CallOption) (*DailyDoHistoryResponse, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.GetDailyDoHistory", in)
+	out := new(DailyDoHistoryResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) GetStreak(ctx context.Context, in *StreakRequest, opts ...client.CallOption) (*StreakResponse, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.GetStreak", in)
+	out := new(StreakResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) AddChecklistItem(ctx context.Context, in *AddChecklistItemRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.AddChecklistItem", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) ReorderChecklist(ctx context.Context, in *ReorderChecklistRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.ReorderChecklist", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) ToggleChecklistItem(ctx context.Context, in *ToggleChecklistItemRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.ToggleChecklistItem", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) RemoveChecklistItem(ctx context.Context, in *RemoveChecklistItemRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.RemoveChecklistItem", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) ListTags(ctx context.Context, in *ListTagsRequest, opts ...client.CallOption) (*ListTagsResponse, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.ListTags", in)
+	out := new(ListTagsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) CreateList(ctx context.Context, in *CreateListRequest, opts ...client.CallOption) (*ListResponse, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.CreateList", in)
+	out := new(ListResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) GetLists(ctx context.Context, in *GetListsRequest, opts ...client.CallOption) (*ListResponse, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.GetLists", in)
+	out := new(ListResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) ShareList(ctx context.Context, in *ShareListRequest, opts ...client.CallOption) (*ListResponse, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.ShareList", in)
+	out := new(ListResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) UnshareList(ctx context.Context, in *UnshareListRequest, opts ...client.CallOption) (*ListResponse, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.UnshareList", in)
+	out := new(ListResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) AssignTask(ctx context.Context, in *AssignTaskRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.AssignTask", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) UpdateList(ctx context.Context, in *UpdateListRequest, opts ...client.CallOption) (*ListResponse, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.UpdateList", in)
+	out := new(ListResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) ArchiveList(ctx context.Context, in *ArchiveListRequest, opts ...client.CallOption) (*ListResponse, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.ArchiveList", in)
+	out := new(ListResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) DeleteList(ctx context.Context, in *DeleteListRequest, opts ...client.CallOption) (*ListResponse, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.DeleteList", in)
+	out := new(ListResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) MoveTask(ctx context.Context, in *MoveTaskRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.MoveTask", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskServiceClient) ReorderList(ctx context.Context, in *ReorderListRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "TaskService.ReorderList", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for TaskService service
 
 type TaskServiceHandler interface {
@@ -586,6 +2788,26 @@ type TaskServiceHandler interface {
 	Update(context.Context, *UpdateTask, *Response) error
 	ChangeDailyDoStatus(context.Context, *DailyDoStatusRequest, *Response) error
 	CompleteTask(context.Context, *CompleteTaskRequest, *Response) error
+	Delete(context.Context, *DeleteTaskRequest, *Response) error
+	Restore(context.Context, *RestoreTaskRequest, *Response) error
+	Purge(context.Context, *PurgeRequest, *Response) error
+	GetDailyDoHistory(context.Context, *DailyDoHistoryRequest, *DailyDoHistoryResponse) error
+	GetStreak(context.Context, *StreakRequest, *StreakResponse) error
+	AddChecklistItem(context.Context, *AddChecklistItemRequest, *Response) error
+	ReorderChecklist(context.Context, *ReorderChecklistRequest, *Response) error
+	ToggleChecklistItem(context.Context, *ToggleChecklistItemRequest, *Response) error
+	RemoveChecklistItem(context.Context, *RemoveChecklistItemRequest, *Response) error
+	ListTags(context.Context, *ListTagsRequest, *ListTagsResponse) error
+	CreateList(context.Context, *CreateListRequest, *ListResponse) error
+	GetLists(context.Context, *GetListsRequest, *ListResponse) error
+	ShareList(context.Context, *ShareListRequest, *ListResponse) error
+	UnshareList(context.Context, *UnshareListRequest, *ListResponse) error
+	AssignTask(context.Context, *AssignTaskRequest, *Response) error
+	UpdateList(context.Context, *UpdateListRequest, *ListResponse) error
+	ArchiveList(context.Context, *ArchiveListRequest, *ListResponse) error
+	DeleteList(context.Context, *DeleteListRequest, *ListResponse) error
+	MoveTask(context.Context, *MoveTaskRequest, *Response) error
+	ReorderList(context.Context, *ReorderListRequest, *Response) error
 }
 
 func RegisterTaskServiceHandler(s server.Server, hdlr TaskServiceHandler, opts ...server.HandlerOption) {
@@ -615,3 +2837,83 @@ func (h *TaskService) ChangeDailyDoStatus(ctx context.Context, in *DailyDoStatus
 func (h *TaskService) CompleteTask(ctx context.Context, in *CompleteTaskRequest, out *Response) error {
 	return h.TaskServiceHandler.CompleteTask(ctx, in, out)
 }
+
+func (h *TaskService) Delete(ctx context.Context, in *DeleteTaskRequest, out *Response) error {
+	return h.TaskServiceHandler.Delete(ctx, in, out)
+}
+
+func (h *TaskService) Restore(ctx context.Context, in *RestoreTaskRequest, out *Response) error {
+	return h.TaskServiceHandler.Restore(ctx, in, out)
+}
+
+func (h *TaskService) Purge(ctx context.Context, in *PurgeRequest, out *Response) error {
+	return h.TaskServiceHandler.Purge(ctx, in, out)
+}
+
+func (h *TaskService) GetDailyDoHistory(ctx context.Context, in *DailyDoHistoryRequest, out *DailyDoHistoryResponse) error {
+	return h.TaskServiceHandler.GetDailyDoHistory(ctx, in, out)
+}
+
+func (h *TaskService) GetStreak(ctx context.Context, in *StreakRequest, out *StreakResponse) error {
+	return h.TaskServiceHandler.GetStreak(ctx, in, out)
+}
+
+func (h *TaskService) AddChecklistItem(ctx context.Context, in *AddChecklistItemRequest, out *Response) error {
+	return h.TaskServiceHandler.AddChecklistItem(ctx, in, out)
+}
+
+func (h *TaskService) ReorderChecklist(ctx context.Context, in *ReorderChecklistRequest, out *Response) error {
+	return h.TaskServiceHandler.ReorderChecklist(ctx, in, out)
+}
+
+func (h *TaskService) ToggleChecklistItem(ctx context.Context, in *ToggleChecklistItemRequest, out *Response) error {
+	return h.TaskServiceHandler.ToggleChecklistItem(ctx, in, out)
+}
+
+func (h *TaskService) RemoveChecklistItem(ctx context.Context, in *RemoveChecklistItemRequest, out *Response) error {
+	return h.TaskServiceHandler.RemoveChecklistItem(ctx, in, out)
+}
+
+func (h *TaskService) ListTags(ctx context.Context, in *ListTagsRequest, out *ListTagsResponse) error {
+	return h.TaskServiceHandler.ListTags(ctx, in, out)
+}
+
+func (h *TaskService) CreateList(ctx context.Context, in *CreateListRequest, out *ListResponse) error {
+	return h.TaskServiceHandler.CreateList(ctx, in, out)
+}
+
+func (h *TaskService) GetLists(ctx context.Context, in *GetListsRequest, out *ListResponse) error {
+	return h.TaskServiceHandler.GetLists(ctx, in, out)
+}
+
+func (h *TaskService) ShareList(ctx context.Context, in *ShareListRequest, out *ListResponse) error {
+	return h.TaskServiceHandler.ShareList(ctx, in, out)
+}
+
+func (h *TaskService) UnshareList(ctx context.Context, in *UnshareListRequest, out *ListResponse) error {
+	return h.TaskServiceHandler.UnshareList(ctx, in, out)
+}
+
+func (h *TaskService) AssignTask(ctx context.Context, in *AssignTaskRequest, out *Response) error {
+	return h.TaskServiceHandler.AssignTask(ctx, in, out)
+}
+
+func (h *TaskService) UpdateList(ctx context.Context, in *UpdateListRequest, out *ListResponse) error {
+	return h.TaskServiceHandler.UpdateList(ctx, in, out)
+}
+
+func (h *TaskService) ArchiveList(ctx context.Context, in *ArchiveListRequest, out *ListResponse) error {
+	return h.TaskServiceHandler.ArchiveList(ctx, in, out)
+}
+
+func (h *TaskService) DeleteList(ctx context.Context, in *DeleteListRequest, out *ListResponse) error {
+	return h.TaskServiceHandler.DeleteList(ctx, in, out)
+}
+
+func (h *TaskService) MoveTask(ctx context.Context, in *MoveTaskRequest, out *Response) error {
+	return h.TaskServiceHandler.MoveTask(ctx, in, out)
+}
+
+func (h *TaskService) ReorderList(ctx context.Context, in *ReorderListRequest, out *Response) error {
+	return h.TaskServiceHandler.ReorderList(ctx, in, out)
+}
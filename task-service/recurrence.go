@@ -0,0 +1,244 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/willdot/Go-Do/apierrors"
+)
+
+type frequency int
+
+const (
+	daily frequency = iota
+	weekly
+	monthly
+)
+
+// untilLayout is the format of the UNTIL part of a recurrence rule
+const untilLayout = "20060102"
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// recurrenceRule is a parsed RRULE style recurrence rule such as "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10". Supported parts are:
+//
+//	FREQ       DAILY, WEEKLY or MONTHLY (required)
+//	INTERVAL   repeat every N days, weeks or months (defaults to 1)
+//	BYDAY      comma separated weekdays (MO,TU,WE,TH,FR,SA,SU) for WEEKLY rules
+//	BYMONTHDAY day of the month for MONTHLY rules, negative to count back from the end of the month (-1 is the last day)
+//	UNTIL      last day an occurrence can fall on, as YYYYMMDD
+//	COUNT      total number of occurrences
+type recurrenceRule struct {
+	frequency frequency
+	interval  int
+	weekdays  []time.Weekday
+	monthDay  int
+	until     time.Time
+	count     int
+}
+
+// parseRecurrence parses an RRULE style recurrence rule
+func parseRecurrence(rule string) (*recurrenceRule, error) {
+
+	r := &recurrenceRule{interval: 1}
+	frequencySet := false
+
+	for _, part := range strings.Split(strings.ToUpper(strings.TrimSpace(rule)), ";") {
+		if part == "" {
+			continue
+		}
+
+		keyValue := strings.SplitN(part, "=", 2)
+
+		if len(keyValue) != 2 || keyValue[1] == "" {
+			return nil, apierrors.Validation("Invalid recurrence rule part '%s'", part)
+		}
+
+		key, value := keyValue[0], keyValue[1]
+
+		switch key {
+		case "FREQ":
+			switch value {
+			case "DAILY":
+				r.frequency = daily
+			case "WEEKLY":
+				r.frequency = weekly
+			case "MONTHLY":
+				r.frequency = monthly
+			default:
+				return nil, apierrors.Validation("Invalid recurrence frequency '%s'", value)
+			}
+			frequencySet = true
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+
+			if err != nil || interval < 1 {
+				return nil, apierrors.Validation("Invalid recurrence interval '%s'", value)
+			}
+			r.interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[day]
+
+				if !ok {
+					return nil, apierrors.Validation("Invalid recurrence weekday '%s'", day)
+				}
+				r.weekdays = append(r.weekdays, weekday)
+			}
+		case "BYMONTHDAY":
+			monthDay, err := strconv.Atoi(value)
+
+			if err != nil || monthDay == 0 || monthDay > 31 || monthDay < -31 {
+				return nil, apierrors.Validation("Invalid recurrence month day '%s'", value)
+			}
+			r.monthDay = monthDay
+		case "UNTIL":
+			until, err := time.Parse(untilLayout, value)
+
+			if err != nil {
+				return nil, apierrors.Validation("Invalid recurrence until date '%s'", value)
+			}
+			r.until = until
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+
+			if err != nil || count < 1 {
+				return nil, apierrors.Validation("Invalid recurrence count '%s'", value)
+			}
+			r.count = count
+		default:
+			return nil, apierrors.Validation("Unsupported recurrence rule part '%s'", key)
+		}
+	}
+
+	if !frequencySet {
+		return nil, apierrors.Validation("Recurrence rule must have a FREQ")
+	}
+
+	if len(r.weekdays) > 0 && r.frequency != weekly {
+		return nil, apierrors.Validation("BYDAY can only be used with FREQ=WEEKLY")
+	}
+
+	if r.monthDay != 0 && r.frequency != monthly {
+		return nil, apierrors.Validation("BYMONTHDAY can only be used with FREQ=MONTHLY")
+	}
+
+	if r.count != 0 && !r.until.IsZero() {
+		return nil, apierrors.Validation("Recurrence rule can't have both UNTIL and COUNT")
+	}
+
+	return r, nil
+}
+
+// next works out when the occurrence after the given one is due. occurrence is the number of the given occurrence
+// in the series, starting at 1. The time of day of the given occurrence is kept. Returns false if the series has ended
+func (r *recurrenceRule) next(after time.Time, occurrence int) (time.Time, bool) {
+
+	if r.count != 0 && occurrence >= r.count {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+
+	switch r.frequency {
+	case daily:
+		next = after.AddDate(0, 0, r.interval)
+	case weekly:
+		next = r.nextWeekly(after)
+	case monthly:
+		next = r.nextMonthly(after)
+	}
+
+	if !r.until.IsZero() {
+		untilDay := time.Date(r.until.Year(), r.until.Month(), r.until.Day(), 0, 0, 0, 0, after.Location())
+
+		if !next.Before(untilDay.AddDate(0, 0, 1)) {
+			return time.Time{}, false
+		}
+	}
+
+	return next, true
+}
+
+func (r *recurrenceRule) nextWeekly(after time.Time) time.Time {
+
+	if len(r.weekdays) == 0 {
+		return after.AddDate(0, 0, 7*r.interval)
+	}
+
+	// weeks start on a Monday, the same as RRULE
+	daysIntoWeek := (int(after.Weekday()) + 6) % 7
+
+	// a later day in the same week
+	for day := daysIntoWeek + 1; day < 7; day++ {
+		if r.hasWeekday(time.Weekday((day + 1) % 7)) {
+			return after.AddDate(0, 0, day-daysIntoWeek)
+		}
+	}
+
+	// otherwise the first day in the next week of the interval
+	startOfWeek := after.AddDate(0, 0, -daysIntoWeek+7*r.interval)
+
+	for day := 0; ; day++ {
+		if r.hasWeekday(time.Weekday((day + 1) % 7)) {
+			return startOfWeek.AddDate(0, 0, day)
+		}
+	}
+}
+
+func (r *recurrenceRule) hasWeekday(weekday time.Weekday) bool {
+	for _, v := range r.weekdays {
+		if v == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *recurrenceRule) nextMonthly(after time.Time) time.Time {
+
+	monthDay := r.monthDay
+	if monthDay == 0 {
+		monthDay = after.Day()
+	}
+
+	// the day later on in the same month comes first, as long as it isn't the day being moved on from
+	if day, ok := dayInMonth(after.Year(), after.Month(), monthDay); ok && day > after.Day() && r.monthDay != 0 {
+		return time.Date(after.Year(), after.Month(), day, after.Hour(), after.Minute(), after.Second(), 0, after.Location())
+	}
+
+	// months without the day (such as the 31st in April) are skipped
+	for months := r.interval; ; months += r.interval {
+		firstOfMonth := time.Date(after.Year(), after.Month()+time.Month(months), 1, 0, 0, 0, 0, after.Location())
+
+		if day, ok := dayInMonth(firstOfMonth.Year(), firstOfMonth.Month(), monthDay); ok {
+			return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day, after.Hour(), after.Minute(), after.Second(), 0, after.Location())
+		}
+	}
+}
+
+// dayInMonth turns a month day from a rule into the actual day in a month. Negative days count back from the end
+// of the month. Returns false if the month doesn't have that day
+func dayInMonth(year int, month time.Month, monthDay int) (int, bool) {
+	daysInMonth := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	if monthDay < 0 {
+		monthDay = daysInMonth + monthDay + 1
+	}
+
+	if monthDay < 1 || monthDay > daysInMonth {
+		return 0, false
+	}
+
+	return monthDay, true
+}
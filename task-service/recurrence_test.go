@@ -0,0 +1,106 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+
+	tests := []struct {
+		rule    string
+		wantErr bool
+	}{
+		{"FREQ=DAILY", false},
+		{"freq=daily;interval=3", false},
+		{"FREQ=WEEKLY;BYDAY=MO,WE,FR", false},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=12", false},
+		{"FREQ=DAILY;UNTIL=20191231", false},
+		{"", true},
+		{"INTERVAL=2", true},
+		{"FREQ=YEARLY", true},
+		{"FREQ=DAILY;INTERVAL=0", true},
+		{"FREQ=DAILY;INTERVAL=two", true},
+		{"FREQ=WEEKLY;BYDAY=MO,XX", true},
+		{"FREQ=DAILY;BYDAY=MO", true},
+		{"FREQ=MONTHLY;BYMONTHDAY=32", true},
+		{"FREQ=MONTHLY;BYMONTHDAY=0", true},
+		{"FREQ=WEEKLY;BYMONTHDAY=1", true},
+		{"FREQ=DAILY;UNTIL=31-12-2019", true},
+		{"FREQ=DAILY;COUNT=0", true},
+		{"FREQ=DAILY;COUNT=2;UNTIL=20191231", true},
+		{"FREQ=DAILY;BYHOUR=9", true},
+		{"FREQ", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			_, err := parseRecurrence(tt.rule)
+
+			if tt.wantErr && err == nil {
+				t.Errorf("wanted an error but didn't get one")
+			}
+
+			if !tt.wantErr && err != nil {
+				t.Errorf("didn't want an error but got %v", err)
+			}
+		})
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		rule       string
+		after      time.Time
+		occurrence int
+		want       time.Time
+		wantEnded  bool
+	}{
+		{"daily", "FREQ=DAILY", date(2019, 8, 20), 1, date(2019, 8, 21), false},
+		{"every 3 days", "FREQ=DAILY;INTERVAL=3", date(2019, 8, 30), 1, date(2019, 9, 2), false},
+		{"weekly on the same day", "FREQ=WEEKLY", date(2019, 8, 20), 1, date(2019, 8, 27), false},
+		{"every 2 weeks", "FREQ=WEEKLY;INTERVAL=2", date(2019, 8, 20), 1, date(2019, 9, 3), false},
+		// 20th August 2019 is a Tuesday
+		{"weekly later in the same week", "FREQ=WEEKLY;BYDAY=MO,TH", date(2019, 8, 20), 1, date(2019, 8, 22), false},
+		{"weekly into the next week", "FREQ=WEEKLY;BYDAY=MO,TU", date(2019, 8, 20), 1, date(2019, 8, 26), false},
+		{"weekly on sunday is the end of the week", "FREQ=WEEKLY;BYDAY=SU", date(2019, 8, 20), 1, date(2019, 8, 25), false},
+		{"every 2 weeks into a later week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", date(2019, 8, 20), 1, date(2019, 9, 2), false},
+		{"monthly on the same day", "FREQ=MONTHLY", date(2019, 8, 20), 1, date(2019, 9, 20), false},
+		{"monthly on a later day in the same month", "FREQ=MONTHLY;BYMONTHDAY=25", date(2019, 8, 20), 1, date(2019, 8, 25), false},
+		{"monthly on an earlier day", "FREQ=MONTHLY;BYMONTHDAY=1", date(2019, 8, 20), 1, date(2019, 9, 1), false},
+		{"monthly on the 31st skips short months", "FREQ=MONTHLY;BYMONTHDAY=31", date(2019, 8, 31), 1, date(2019, 10, 31), false},
+		{"monthly on the last day", "FREQ=MONTHLY;BYMONTHDAY=-1", date(2019, 1, 31), 1, date(2019, 2, 28), false},
+		{"monthly on the last day of a leap year february", "FREQ=MONTHLY;BYMONTHDAY=-1", date(2020, 1, 31), 1, date(2020, 2, 29), false},
+		{"every 3 months across a year", "FREQ=MONTHLY;INTERVAL=3", date(2019, 11, 15), 1, date(2020, 2, 15), false},
+		{"count not reached", "FREQ=DAILY;COUNT=3", date(2019, 8, 20), 2, date(2019, 8, 21), false},
+		{"count reached", "FREQ=DAILY;COUNT=3", date(2019, 8, 20), 3, time.Time{}, true},
+		{"on the until day", "FREQ=DAILY;UNTIL=20190821", date(2019, 8, 20), 1, date(2019, 8, 21), false},
+		{"after the until day", "FREQ=DAILY;UNTIL=20190820", date(2019, 8, 20), 1, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := parseRecurrence(tt.rule)
+
+			if err != nil {
+				t.Fatalf("failed to parse rule: %v", err)
+			}
+
+			got, ok := rule.next(tt.after, tt.occurrence)
+
+			if ok == tt.wantEnded {
+				t.Fatalf("wanted series ended %v but got %v", tt.wantEnded, !ok)
+			}
+
+			if !got.Equal(tt.want) {
+				t.Errorf("want %v got %v", tt.want, got)
+			}
+		})
+	}
+}
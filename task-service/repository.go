@@ -1,25 +1,67 @@
 package main
 
 import (
-	"errors"
-	"strconv"
+	"encoding/base64"
+	"sort"
 	"time"
 
 	"github.com/gocql/gocql"
-	taskPb "github.com/willdot/go-do/task-service/proto/task"
+	"github.com/willdot/Go-Do/apierrors"
+	taskPb "github.com/willdot/Go-Do/task-service/proto/task"
 )
 
-var errTaskNotFound = errors.New("Task not found")
-var errTaskUserIDNotMatched = errors.New("The user id for the task provided doesn't match user id from token")
+var errTaskNotFound = apierrors.NotFound("Task not found")
+var errTaskUserIDNotMatched = apierrors.Forbidden("The user id for the task provided doesn't match user id from token")
+var errTaskNotDeleted = apierrors.Conflict("The task provided hasn't been deleted")
+var errChecklistItemNotFound = apierrors.NotFound("Checklist item not found")
+var errChecklistItemsNotMatched = apierrors.Conflict("The checklist items provided don't match the items on the task")
+var errInvalidPageToken = apierrors.Validation("The page token provided is not valid")
+var errDailyDoAlreadyExists = apierrors.Conflict("There is already a task set as daily do")
+var errTaskVersionConflict = apierrors.Conflict("The task has been changed since it was read, get the latest version and try again")
+var errListNotFound = apierrors.NotFound("List not found")
+var errListTasksNotMatched = apierrors.Conflict("The tasks provided aren't all in the list, or are repeated")
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
 
 // Repository ..
 type Repository interface {
-	Get(string) ([]*taskPb.Task, error)
+	Get(userID string, req *taskPb.Request, now int64) ([]*taskPb.Task, string, error)
 	Create(*taskPb.Task) error
 	Update(*taskPb.Task) error
 	SetDailyDoStatus(*taskPb.Task) error
+	GetTask(*taskPb.Task) (*taskPb.Task, error)
 	GetDailyDoForUser(string) (*taskPb.Task, error)
+	GetDailyDos() ([]*taskPb.Task, error)
 	CompleteTask(*taskPb.Task) error
+	Delete(*taskPb.Task) error
+	Restore(*taskPb.Task) error
+	Purge(tenantID, userID string, deletedBefore int64) ([]*taskPb.Task, error)
+	SetDailyDoHistory(*taskPb.DailyDoHistory) error
+	GetDailyDoHistoryForDay(userID, day string) (*taskPb.DailyDoHistory, error)
+	DeleteDailyDoHistory(userID, day string) error
+	GetDailyDoHistory(userID, from, to string) ([]*taskPb.DailyDoHistory, error)
+	GetChecklist(*taskPb.Task) ([]*taskPb.ChecklistItem, error)
+	AddChecklistItem(*taskPb.Task, *taskPb.ChecklistItem) error
+	SetChecklistItemStatus(*taskPb.Task, *taskPb.ChecklistItem) error
+	ReorderChecklist(task *taskPb.Task, itemIDs []string) error
+	RemoveChecklistItem(task *taskPb.Task, itemID string) error
+	ListTags(tenantID, userID string) ([]*taskPb.TagCount, error)
+	AdoptTask(taskID, tenantID string) (bool, error)
+	MoveTask(*taskPb.Task) error
+	UpdateList(*taskPb.TaskList) error
+	SetListArchived(listID string, archived bool) error
+	DeleteList(listID string) error
+	ReorderList(listID string, taskIDs []string) error
+	GetTaskByID(id string) (*taskPb.Task, error)
+	AssignTask(*taskPb.Task) error
+	CreateList(*taskPb.TaskList) error
+	GetList(id string) (*taskPb.TaskList, error)
+	GetListsForUser(userID string) ([]*taskPb.TaskList, error)
+	SetListMember(listID string, member *taskPb.ListMember) error
+	RemoveListMember(listID, userID string) error
 }
 
 // TaskRepository is a datastore
@@ -27,187 +69,1702 @@ type TaskRepository struct {
 	Session *gocql.Session
 }
 
-// Get will get tasks for a user
-func (repo *TaskRepository) Get(userID string) ([]*taskPb.Task, error) {
+// Get will get a page of tasks for a user that match the filters in the request, with now used to work out which tasks
+// are overdue. The tasks are the users own, or the ones assigned to them or in the list in the request, which the
+// caller has to check the user can see. The returned page token can be sent back in the next request to carry on where
+// this page finished and will be empty when there are no more tasks
+func (repo *TaskRepository) Get(userID string, req *taskPb.Request, now int64) ([]*taskPb.Task, string, error) {
+
+	if req.ListId != "" {
+		return repo.getListTasks(req, now)
+	}
+
+	if req.AssignedToMe {
+		return repo.getAssignedTasks(userID, req, now)
+	}
+
+	pageState, err := decodePageToken(req.PageToken)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	// A users tasks are one partition, clustered by when they were created, so the created date range and the order are
+	// done by Cassandra and the pages follow on from each other in order
+	created, values := createdRange(req, "createdDate", func(unix int64) interface{} { return time.Unix(unix, 0) })
+
+	queryString := "SELECT * FROM task_by_user WHERE userId = ?" + created
+	parameters := append([]interface{}{userID}, values...)
+
+	if req.SortOrder == taskPb.SortOrder_CREATED_DESCENDING {
+		queryString += " ORDER BY createdDate DESC"
+	}
+
+	return repo.getPage(repo.Session.Query(queryString, parameters...), pageState, req, now, taskFromRow)
+}
+
+// getPage reads a page of tasks from a query, carrying on from the paging state of the page token in the request.
+// fromRow creates a task from a row of the table that is queried
+func (repo *TaskRepository) getPage(query *gocql.Query, pageState []byte, req *taskPb.Request, now int64, fromRow func(map[string]interface{}) *taskPb.Task) ([]*taskPb.Task, string, error) {
 	var tasks []*taskPb.Task
 
 	m := map[string]interface{}{}
 
-	query := repo.Session.Query("SELECT * FROM task WHERE userId =?", userID)
-	iterable := query.Iter()
+	iterable := query.PageSize(pageSizeForRequest(req)).PageState(pageState).Iter()
 
-	for iterable.MapScan(m) {
+	// Only read the rows from this page, otherwise gocql will carry on fetching the next pages
+	for rows := iterable.NumRows(); rows > 0 && iterable.MapScan(m); rows-- {
 
-		tasks = append(tasks, &taskPb.Task{
-			Id:            m["id"].(gocql.UUID).String(),
-			Title:         m["title"].(string),
-			Description:   m["description"].(string),
-			UserId:        m["userid"].(string),
-			DailyDo:       m["dailydo"].(bool),
-			CompletedDate: m["completeddate"].(time.Time).Unix(),
-			CreatedDate:   m["createddate"].(time.Time).Unix(),
-		})
+		task := fromRow(m)
+
+		// The other filters aren't on the key, so they are done here rather than with an index or ALLOW FILTERING. This
+		// can mean a page has fewer tasks than the page size
+		if taskMatchesRequest(task, req, now) {
+			tasks = append(tasks, task)
+		}
 
 		m = map[string]interface{}{}
 	}
 
-	return tasks, nil
+	nextPageState := iterable.PageState()
+
+	if err := iterable.Close(); err != nil {
+		return nil, "", err
+	}
+
+	if err := repo.addChecklists(tasks); err != nil {
+		return nil, "", err
+	}
+
+	return tasks, encodePageToken(nextPageState), nil
+}
+
+// createdRange gives the conditions and values for the created date range in a request, for a query of a partition
+// clustered by when the tasks were created. toKey turns a unix time into the value of the clustering column
+func createdRange(req *taskPb.Request, column string, toKey func(int64) interface{}) (string, []interface{}) {
+	var conditions string
+	var values []interface{}
+
+	if req.CreatedFrom != 0 {
+		conditions += " AND " + column + " >= ?"
+		values = append(values, toKey(req.CreatedFrom))
+	}
+
+	if req.CreatedTo != 0 {
+		conditions += " AND " + column + " <= ?"
+		values = append(values, toKey(req.CreatedTo))
+	}
+
+	return conditions, values
 }
 
 // Create will create a new task
 func (repo *TaskRepository) Create(task *taskPb.Task) error {
 	gocqlUUID := gocql.TimeUUID()
 
-	err := repo.Session.Query(`
-	INSERT INTO task (id, title, description, userId, createdDate, dailyDo) VALUES (?,?,?,?,?,?)`,
-		gocqlUUID, task.Title, task.Description, task.UserId, task.CreatedDate, task.DailyDo).Exec()
+	if task.DailyDo {
+		if err := repo.claimDailyDo(dailyDoUser(task), gocqlUUID.String()); err != nil {
+			return err
+		}
+	}
+
+	// The key and the row are written in a logged batch, so that a task is never in a users partition without a way to
+	// find it by id, or the other way round
+	batch := repo.Session.NewBatch(gocql.LoggedBatch)
+
+	batch.Query("INSERT INTO task_key (id, userId, createdDate) VALUES (?,?,?)", gocqlUUID, task.UserId, time.Unix(task.CreatedDate, 0))
+	batch.Query(`
+	INSERT INTO task_by_user (userId, createdDate, id, title, description, dailyDo, dueDate, priority, tags, recurrence, occurrence, version, tenantId, listId, assigneeId)
+	VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+		task.UserId, time.Unix(task.CreatedDate, 0), gocqlUUID, task.Title, task.Description, task.DailyDo,
+		timestampOrNull(task.DueDate), int(task.Priority), task.Tags, task.Recurrence, int(task.Occurrence), 1, task.TenantId,
+		task.ListId, task.AssigneeId)
+
+	if err := repo.Session.ExecuteBatch(batch); err != nil {
+		if task.DailyDo {
+			repo.releaseDailyDo(dailyDoUser(task), gocqlUUID.String())
+		}
+
+		return err
+	}
+
+	// The task has been created by now, so it stays the daily do even if it can't be copied
+	if task.AssigneeId != "" || task.ListId != "" {
+		err := repo.copyTask(&taskPb.Task{Id: gocqlUUID.String(), UserId: task.UserId, CreatedDate: task.CreatedDate})
+
+		if err != nil {
+			return err
+		}
+	}
 
 	task.Id = gocqlUUID.String()
+	task.Version = 1
 
-	return err
+	return nil
 }
 
-// Update will update a task
+// Update will update the title, description, due date, priority, tags and recurrence of a task
 func (repo *TaskRepository) Update(task *taskPb.Task) error {
 
-	var found = false
+	existingTask, err := repo.getExistingTask(task.Id)
 
-	var existingTask taskPb.Task
-	m := map[string]interface{}{}
+	if err != nil {
+		return err
+	}
 
-	query := repo.Session.Query("SELECT * FROM task WHERE taskId=? LIMIT 1", task.Id)
-	iterable := query.Consistency(gocql.One).Iter()
+	if existingTask.UserId != task.UserId {
+		return errTaskUserIDNotMatched
+	}
 
-	for iterable.MapScan(m) {
-		found = true
-		existingTask = taskPb.Task{
-			UserId: m["userId"].(string),
-		}
+	err = repo.updateIfVersion(existingTask, task, "title = ?, description = ?, dueDate = ?, priority = ?, tags = ?, recurrence = ?",
+		task.Title, task.Description, timestampOrNull(task.DueDate), int(task.Priority), task.Tags, task.Recurrence)
+
+	if err != nil {
+		return err
 	}
 
-	if !found {
-		return errTaskNotFound
+	return repo.copyTask(existingTask)
+}
+
+// updateIfVersion sets fields on the stored task using a lightweight transaction that only applies if the task is still
+// at the version given in the task. The task is moved on to the next version, or errTaskVersionConflict is returned if
+// something else has changed the task since that version was read
+func (repo *TaskRepository) updateIfVersion(existingTask, task *taskPb.Task, set string, values ...interface{}) error {
+
+	values = append(values, int(task.Version+1))
+	values = append(values, rowKey(existingTask)...)
+	values = append(values, versionOrNull(task.Version))
+
+	applied, err := repo.Session.Query("UPDATE task_by_user SET "+set+", version = ?"+whereRowKey+" IF version = ?", values...).
+		MapScanCAS(map[string]interface{}{})
+
+	if err != nil {
+		return err
 	}
 
-	if existingTask.UserId != task.UserId {
-		return errTaskUserIDNotMatched
+	if !applied {
+		return errTaskVersionConflict
 	}
 
-	if task.Title == "" {
-		task.Title = existingTask.Title
+	task.Version++
+
+	return nil
+}
+
+// GetTask gets a single task, checking that it belongs to the user of the task given
+func (repo *TaskRepository) GetTask(task *taskPb.Task) (*taskPb.Task, error) {
+
+	existingTask, err := repo.getExistingTask(task.Id)
+
+	if err != nil {
+		return nil, err
 	}
 
-	if task.Description == "" {
-		task.Description = existingTask.Description
+	if existingTask.UserId != task.UserId {
+		return nil, errTaskUserIDNotMatched
 	}
 
-	err := repo.Session.Query("UPDATE task SET title =?, description = ? where id = ?", task.Title, task.Description, task.Id).Exec()
+	if err := repo.addChecklists([]*taskPb.Task{existingTask}); err != nil {
+		return nil, err
+	}
 
-	return err
+	return existingTask, nil
 }
 
 // SetDailyDoStatus will set a task as a daily do
 func (repo *TaskRepository) SetDailyDoStatus(task *taskPb.Task) error {
 
-	var found = false
+	existingTask, err := repo.getExistingTask(task.Id)
 
-	var existingTask taskPb.Task
-	m := map[string]interface{}{}
+	if err != nil {
+		return err
+	}
 
-	query := repo.Session.Query("SELECT * FROM task WHERE id = ?", task.Id)
-	iterable := query.Consistency(gocql.One).Iter()
+	if existingTask.UserId != task.UserId {
+		return errTaskUserIDNotMatched
+	}
 
-	for iterable.MapScan(m) {
-		found = true
-		existingTask = taskPb.Task{
-			UserId: m["userid"].(string),
+	if task.DailyDo && !existingTask.DailyDo {
+		if err := repo.claimDailyDo(dailyDoUser(existingTask), task.Id); err != nil {
+			return err
 		}
 	}
 
-	if !found {
-		return errTaskNotFound
+	err = repo.updateIfVersion(existingTask, task, "dailyDo = ?", task.DailyDo)
+
+	if err != nil {
+		if task.DailyDo && !existingTask.DailyDo {
+			repo.releaseDailyDo(dailyDoUser(existingTask), task.Id)
+		}
+
+		return err
 	}
 
-	if existingTask.UserId != task.UserId {
-		return errTaskUserIDNotMatched
+	if !task.DailyDo {
+		if err := repo.releaseDailyDo(dailyDoUser(existingTask), task.Id); err != nil {
+			return err
+		}
+	}
+
+	return repo.copyTask(existingTask)
+}
+
+// claimDailyDo makes a task the users daily do, as long as they don't already have a different one. A lightweight
+// transaction is used so that two requests can't both set a daily do for the same user
+func (repo *TaskRepository) claimDailyDo(userID, taskID string) error {
+	existing := map[string]interface{}{}
+
+	applied, err := repo.Session.Query("INSERT INTO daily_do (userId, taskId) VALUES (?, ?) IF NOT EXISTS", userID, taskID).
+		MapScanCAS(existing)
+
+	if err != nil {
+		return err
+	}
+
+	if !applied && existing["taskid"].(gocql.UUID).String() != taskID {
+		return errDailyDoAlreadyExists
 	}
 
-	err := repo.Session.Query("UPDATE task SET dailyDo =? where id = ?", task.DailyDo, task.Id).Exec()
+	return nil
+}
+
+// releaseDailyDo stops a task being the users daily do. Nothing happens if a different task is the daily do
+func (repo *TaskRepository) releaseDailyDo(userID, taskID string) error {
+	_, err := repo.Session.Query("DELETE FROM daily_do WHERE userId = ? IF taskId = ?", userID, taskID).
+		MapScanCAS(map[string]interface{}{})
 
 	return err
 }
 
-// GetDailyDoForUser will get a daily do task for a user
+// GetDailyDoForUser will get the daily do task of a user, which can be a task they own or one assigned to them
 func (repo *TaskRepository) GetDailyDoForUser(userID string) (*taskPb.Task, error) {
 
-	var dailyDo *taskPb.Task
-	m := map[string]interface{}{}
+	var taskID gocql.UUID
 
-	query := repo.Session.Query("SELECT * FROM task WHERE dailyDo = true and userId =? ALLOW FILTERING", userID)
-	iterable := query.Consistency(gocql.One).Iter()
+	err := repo.Session.Query("SELECT taskId FROM daily_do WHERE userId = ?", userID).Consistency(gocql.One).Scan(&taskID)
 
-	for iterable.MapScan(m) {
-		dailyDo = &taskPb.Task{
-			Id:            m["id"].(gocql.UUID).String(),
-			Title:         m["title"].(string),
-			Description:   m["description"].(string),
-			UserId:        m["userid"].(string),
-			CreatedDate:   m["createddate"].(time.Time).Unix(),
-			CompletedDate: m["completeddate"].(time.Time).Unix(),
-			DailyDo:       m["dailydo"].(bool),
+	if err == gocql.ErrNotFound {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	dailyDo, err := repo.getExistingTask(taskID.String())
+
+	if err == errTaskNotFound || (err == nil && !dailyDo.DailyDo) {
+		return nil, nil
+	}
+
+	return dailyDo, err
+}
+
+// GetDailyDos gets the daily do tasks for every user. The daily_do table has a row for each user with a daily do, so
+// it's read in full rather than looking through every task
+func (repo *TaskRepository) GetDailyDos() ([]*taskPb.Task, error) {
+	var taskIDs []string
+	var taskID gocql.UUID
+
+	iterable := repo.Session.Query("SELECT taskId FROM daily_do").Iter()
+
+	for iterable.Scan(&taskID) {
+		taskIDs = append(taskIDs, taskID.String())
+	}
+
+	if err := iterable.Close(); err != nil {
+		return nil, err
+	}
+
+	tasks, err := repo.getTasksByID(taskIDs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var dailyDos []*taskPb.Task
+
+	for _, task := range tasks {
+		if task.DailyDo {
+			dailyDos = append(dailyDos, task)
 		}
 	}
 
-	return dailyDo, nil
+	return dailyDos, nil
 }
 
 // CompleteTask sets the completed date time of the task. Sets to 0 if it's being un completed
 func (repo *TaskRepository) CompleteTask(task *taskPb.Task) error {
 
-	var found = false
+	existingTask, err := repo.getExistingTask(task.Id)
 
-	var existingTask taskPb.Task
-	m := map[string]interface{}{}
+	if err != nil {
+		return err
+	}
 
-	query := repo.Session.Query("SELECT * FROM task WHERE id=?", task.Id)
-	iterable := query.Consistency(gocql.One).Iter()
+	if existingTask.UserId != task.UserId {
+		return errTaskUserIDNotMatched
+	}
 
-	for iterable.MapScan(m) {
-		found = true
-		existingTask = taskPb.Task{
-			UserId:  m["userid"].(string),
-			DailyDo: m["dailydo"].(bool),
+	// A completed task can't stay as the daily do, but un completing a task leaves its daily do status alone
+	if task.CompletedDate == 0 {
+		err = repo.updateIfVersion(existingTask, task, "completedDate = null")
+	} else {
+		err = repo.updateIfVersion(existingTask, task, "completedDate = ?, dailyDo = ?", time.Unix(task.CompletedDate, 0), task.DailyDo)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if existingTask.DailyDo && !task.DailyDo {
+		if err := repo.releaseDailyDo(dailyDoUser(existingTask), task.Id); err != nil {
+			return err
 		}
 	}
 
-	if !found {
-		return errTaskNotFound
+	return repo.copyTask(existingTask)
+}
+
+// Delete soft deletes a task by marking it as deleted. It can be restored until it gets purged
+func (repo *TaskRepository) Delete(task *taskPb.Task) error {
+
+	existingTask, err := repo.getExistingTask(task.Id)
+
+	if err != nil {
+		return err
 	}
 
 	if existingTask.UserId != task.UserId {
 		return errTaskUserIDNotMatched
 	}
 
-	parameters := []string{strconv.FormatInt(task.CompletedDate, 10)}
+	// A deleted task can't be the daily do, otherwise the user wouldn't be able to pick a new one
+	values := append([]interface{}{true, time.Unix(task.DeletedDate, 0), false}, rowKey(existingTask)...)
+
+	err = repo.Session.Query("UPDATE task_by_user SET deleted = ?, deletedDate = ?, dailyDo = ?"+whereRowKey, values...).Exec()
+
+	if err != nil {
+		return err
+	}
+
+	if existingTask.DailyDo {
+		if err := repo.releaseDailyDo(dailyDoUser(existingTask), task.Id); err != nil {
+			return err
+		}
+	}
+
+	return repo.copyTask(existingTask)
+}
 
-	// THIS needs fixing to use correct syntax for multiple where clauses
-	queryString := "UPDATE task SET completedDate =?"
+// Restore un deletes a task that has been soft deleted
+func (repo *TaskRepository) Restore(task *taskPb.Task) error {
+
+	existingTask, err := repo.getExistingTask(task.Id)
+
+	if err != nil {
+		return err
+	}
 
-	if existingTask.DailyDo != task.DailyDo {
-		queryString += ", dailyDo =?"
-		parameters = append(parameters, strconv.FormatBool(task.DailyDo))
+	if existingTask.UserId != task.UserId {
+		return errTaskUserIDNotMatched
 	}
 
-	parameters = append(parameters, task.Id)
+	if !existingTask.Deleted {
+		return errTaskNotDeleted
+	}
 
-	queryString += " where id = ?"
+	values := append([]interface{}{false}, rowKey(existingTask)...)
 
-	err := repo.Session.Query(queryString, parameters).Exec()
+	err = repo.Session.Query("UPDATE task_by_user SET deleted = ?, deletedDate = null"+whereRowKey, values...).Exec()
 
 	if err != nil {
 		return err
 	}
 
+	return repo.copyTask(existingTask)
+}
+
+// Purge permanently deletes a users tasks in a tenant that were soft deleted before the given time and returns the tasks
+// that were removed. Tasks from before tenants were added are purged from whichever tenant the user is in
+func (repo *TaskRepository) Purge(tenantID, userID string, deletedBefore int64) ([]*taskPb.Task, error) {
+	var purged []*taskPb.Task
+
+	m := map[string]interface{}{}
+
+	query := repo.Session.Query("SELECT * FROM task_by_user WHERE userId = ?", userID)
+	iterable := query.Iter()
+
+	for iterable.MapScan(m) {
+		task := taskFromRow(m)
+
+		if task.Deleted && task.DeletedDate < deletedBefore && inTenant(task, tenantID) {
+			purged = append(purged, task)
+		}
+
+		m = map[string]interface{}{}
+	}
+
+	if err := iterable.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, task := range purged {
+		err := repo.Session.Query("DELETE FROM task_by_user"+whereRowKey, rowKey(task)...).Exec()
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = repo.Session.Query("DELETE FROM task_key WHERE id = ?", task.Id).Exec()
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = repo.Session.Query("DELETE FROM checklist_item WHERE taskId = ?", task.Id).Exec()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := repo.copyTask(task); err != nil {
+			return nil, err
+		}
+	}
+
+	return purged, nil
+}
+
+// SetDailyDoHistory records which task is the daily do for a user on a day, and whether it has been completed
+func (repo *TaskRepository) SetDailyDoHistory(history *taskPb.DailyDoHistory) error {
+
+	day, err := time.Parse(dayLayout, history.Day)
+
+	if err != nil {
+		return errInvalidDay
+	}
+
+	err = repo.Session.Query("INSERT INTO daily_do_history (userId, day, taskId, completed) VALUES (?,?,?,?)",
+		history.UserId, day, history.TaskId, history.Completed).Exec()
+
+	return err
+}
+
+// GetDailyDoHistoryForDay gets the daily do history for a user on a single day. Returns nil if there wasn't a daily do that day
+func (repo *TaskRepository) GetDailyDoHistoryForDay(userID, day string) (*taskPb.DailyDoHistory, error) {
+
+	history, err := repo.GetDailyDoHistory(userID, day, day)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(history) == 0 {
+		return nil, nil
+	}
+
+	return history[0], nil
+}
+
+// DeleteDailyDoHistory removes the daily do history for a user on a day
+func (repo *TaskRepository) DeleteDailyDoHistory(userID, day string) error {
+
+	parsedDay, err := time.Parse(dayLayout, day)
+
+	if err != nil {
+		return errInvalidDay
+	}
+
+	err = repo.Session.Query("DELETE FROM daily_do_history WHERE userId = ? AND day = ?", userID, parsedDay).Exec()
+
 	return err
+}
+
+// GetDailyDoHistory gets the daily do history for a user between two days (inclusive), newest first.
+// Either day can be empty to leave that end of the range open
+func (repo *TaskRepository) GetDailyDoHistory(userID, from, to string) ([]*taskPb.DailyDoHistory, error) {
+	var history []*taskPb.DailyDoHistory
+
+	queryString := "SELECT * FROM daily_do_history WHERE userId = ?"
+	parameters := []interface{}{userID}
+
+	if from != "" {
+		fromDay, err := time.Parse(dayLayout, from)
+
+		if err != nil {
+			return nil, errInvalidDay
+		}
+
+		queryString += " AND day >= ?"
+		parameters = append(parameters, fromDay)
+	}
+
+	if to != "" {
+		toDay, err := time.Parse(dayLayout, to)
+
+		if err != nil {
+			return nil, errInvalidDay
+		}
+
+		queryString += " AND day <= ?"
+		parameters = append(parameters, toDay)
+	}
+
+	m := map[string]interface{}{}
+
+	iterable := repo.Session.Query(queryString, parameters...).Iter()
+
+	for iterable.MapScan(m) {
+		history = append(history, &taskPb.DailyDoHistory{
+			UserId:    m["userid"].(string),
+			Day:       m["day"].(time.Time).Format(dayLayout),
+			TaskId:    m["taskid"].(gocql.UUID).String(),
+			Completed: m["completed"].(bool),
+		})
+
+		m = map[string]interface{}{}
+	}
+
+	if err := iterable.Close(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// GetChecklist gets the checklist items of a task in order
+func (repo *TaskRepository) GetChecklist(task *taskPb.Task) ([]*taskPb.ChecklistItem, error) {
+
+	existingTask, err := repo.getExistingTask(task.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if existingTask.UserId != task.UserId {
+		return nil, errTaskUserIDNotMatched
+	}
+
+	checklists, err := repo.getChecklists([]string{task.Id})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return checklists[task.Id], nil
+}
+
+// AddChecklistItem adds an item to the end of a tasks checklist
+func (repo *TaskRepository) AddChecklistItem(task *taskPb.Task, item *taskPb.ChecklistItem) error {
+
+	checklist, err := repo.GetChecklist(task)
+
+	if err != nil {
+		return err
+	}
+
+	item.Position = 0
+	if len(checklist) > 0 {
+		item.Position = checklist[len(checklist)-1].Position + 1
+	}
+
+	gocqlUUID := gocql.TimeUUID()
+
+	err = repo.Session.Query("INSERT INTO checklist_item (taskId, id, title, completed, position) VALUES (?,?,?,?,?)",
+		task.Id, gocqlUUID, item.Title, item.Completed, item.Position).Exec()
+
+	item.Id = gocqlUUID.String()
+
+	return err
+}
+
+// SetChecklistItemStatus sets if a checklist item is completed
+func (repo *TaskRepository) SetChecklistItemStatus(task *taskPb.Task, item *taskPb.ChecklistItem) error {
+
+	checklist, err := repo.GetChecklist(task)
+
+	if err != nil {
+		return err
+	}
+
+	if findChecklistItem(checklist, item.Id) == nil {
+		return errChecklistItemNotFound
+	}
+
+	err = repo.Session.Query("UPDATE checklist_item SET completed = ? WHERE taskId = ? AND id = ?", item.Completed, task.Id, item.Id).Exec()
+
+	return err
+}
+
+// ReorderChecklist puts a tasks checklist items into the order of the ids given. Every item on the checklist has to be given
+func (repo *TaskRepository) ReorderChecklist(task *taskPb.Task, itemIDs []string) error {
+
+	checklist, err := repo.GetChecklist(task)
+
+	if err != nil {
+		return err
+	}
+
+	if !checklistMatchesIDs(checklist, itemIDs) {
+		return errChecklistItemsNotMatched
+	}
+
+	batch := repo.Session.NewBatch(gocql.LoggedBatch)
+
+	for position, id := range itemIDs {
+		batch.Query("UPDATE checklist_item SET position = ? WHERE taskId = ? AND id = ?", position, task.Id, id)
+	}
+
+	return repo.Session.ExecuteBatch(batch)
+}
+
+// RemoveChecklistItem removes an item from a tasks checklist
+func (repo *TaskRepository) RemoveChecklistItem(task *taskPb.Task, itemID string) error {
+
+	checklist, err := repo.GetChecklist(task)
+
+	if err != nil {
+		return err
+	}
+
+	if findChecklistItem(checklist, itemID) == nil {
+		return errChecklistItemNotFound
+	}
+
+	err = repo.Session.Query("DELETE FROM checklist_item WHERE taskId = ? AND id = ?", task.Id, itemID).Exec()
+
+	return err
+}
+
+// addChecklists fills in the checklists of tasks
+func (repo *TaskRepository) addChecklists(tasks []*taskPb.Task) error {
+
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	var taskIDs []string
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.Id)
+	}
+
+	checklists, err := repo.getChecklists(taskIDs)
+
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		task.Checklist = checklists[task.Id]
+	}
+
+	return nil
+}
+
+// getChecklists gets the ordered checklists for tasks, keyed by task id
+func (repo *TaskRepository) getChecklists(taskIDs []string) (map[string][]*taskPb.ChecklistItem, error) {
+
+	checklists := map[string][]*taskPb.ChecklistItem{}
+
+	m := map[string]interface{}{}
+
+	iterable := repo.Session.Query("SELECT * FROM checklist_item WHERE taskId IN ?", taskIDs).Iter()
+
+	for iterable.MapScan(m) {
+		taskID := m["taskid"].(gocql.UUID).String()
+
+		checklists[taskID] = append(checklists[taskID], &taskPb.ChecklistItem{
+			Id:        m["id"].(gocql.UUID).String(),
+			Title:     m["title"].(string),
+			Completed: m["completed"].(bool),
+			Position:  int32(m["position"].(int)),
+		})
+
+		m = map[string]interface{}{}
+	}
+
+	if err := iterable.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, checklist := range checklists {
+		sortChecklist(checklist)
+	}
+
+	return checklists, nil
+}
+
+// getExistingTask gets the stored task so that a change to it can be checked. The task is found from its id with the
+// task_key table, which gives the key of its row in its users partition
+func (repo *TaskRepository) getExistingTask(id string) (*taskPb.Task, error) {
+
+	taskID, err := gocql.ParseUUID(id)
+
+	if err != nil {
+		return nil, errTaskNotFound
+	}
+
+	var userID string
+	var createdDate time.Time
+
+	err = repo.Session.Query("SELECT userId, createdDate FROM task_key WHERE id = ?", taskID).Consistency(gocql.One).
+		Scan(&userID, &createdDate)
+
+	if err == gocql.ErrNotFound {
+		return nil, errTaskNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var existingTask *taskPb.Task
+	m := map[string]interface{}{}
+
+	query := repo.Session.Query("SELECT * FROM task_by_user"+whereRowKey, userID, createdDate, taskID)
+	iterable := query.Consistency(gocql.One).Iter()
+
+	for iterable.MapScan(m) {
+		existingTask = taskFromRow(m)
+	}
+
+	if err := iterable.Close(); err != nil {
+		return nil, err
+	}
+
+	if existingTask == nil {
+		return nil, errTaskNotFound
+	}
+
+	return existingTask, nil
+}
+
+// ListTags counts how many of a users tasks in a tenant have each tag, with the most used tags first. Deleted tasks aren't
+// counted
+func (repo *TaskRepository) ListTags(tenantID, userID string) ([]*taskPb.TagCount, error) {
+	var tasks []*taskPb.Task
+
+	m := map[string]interface{}{}
+
+	iterable := repo.Session.Query("SELECT tags, deleted, tenantId FROM task_by_user WHERE userId = ?", userID).Iter()
+
+	for iterable.MapScan(m) {
+		task := &taskPb.Task{
+			Tags:     m["tags"].([]string),
+			Deleted:  m["deleted"].(bool),
+			TenantId: m["tenantid"].(string),
+		}
+
+		if inTenant(task, tenantID) {
+			tasks = append(tasks, task)
+		}
+
+		m = map[string]interface{}{}
+	}
+
+	if err := iterable.Close(); err != nil {
+		return nil, err
+	}
+
+	return countTags(tasks), nil
+}
+
+// GetTaskByID gets a task whoever it belongs to, so that the caller can check if the user can see it
+func (repo *TaskRepository) GetTaskByID(id string) (*taskPb.Task, error) {
+
+	existingTask, err := repo.getExistingTask(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repo.addChecklists([]*taskPb.Task{existingTask}); err != nil {
+		return nil, err
+	}
+
+	return existingTask, nil
+}
+
+// AssignTask assigns a task to the user in the task, or unassigns it if there isn't one. The task stops being the daily
+// do of whoever it was assigned to before
+func (repo *TaskRepository) AssignTask(task *taskPb.Task) error {
+
+	existingTask, err := repo.getExistingTask(task.Id)
+
+	if err != nil {
+		return err
+	}
+
+	if existingTask.UserId != task.UserId {
+		return errTaskUserIDNotMatched
+	}
+
+	err = repo.updateIfVersion(existingTask, task, "assigneeId = ?, dailyDo = ?", task.AssigneeId, false)
+
+	if err != nil {
+		return err
+	}
+
+	task.DailyDo = false
+
+	if existingTask.DailyDo {
+		if err := repo.releaseDailyDo(dailyDoUser(existingTask), task.Id); err != nil {
+			return err
+		}
+	}
+
+	return repo.copyTask(existingTask)
+}
+
+// CreateList creates a list, with its owner as a member that can edit it
+func (repo *TaskRepository) CreateList(list *taskPb.TaskList) error {
+	gocqlUUID := gocql.TimeUUID()
+
+	err := repo.Session.Query("INSERT INTO task_list (id, name, ownerId, tenantId, createdDate, archived) VALUES (?,?,?,?,?,?)",
+		gocqlUUID, list.Name, list.OwnerId, list.TenantId, time.Unix(list.CreatedDate, 0), false).Exec()
+
+	if err != nil {
+		return err
+	}
+
+	list.Id = gocqlUUID.String()
+
+	owner := taskPb.ListMember{UserId: list.OwnerId, Permission: taskPb.ListPermission_LIST_PERMISSION_EDIT}
+
+	if err := repo.SetListMember(list.Id, &owner); err != nil {
+		return err
+	}
+
+	list.Members = []*taskPb.ListMember{&owner}
+
+	return nil
+}
+
+// GetList gets a shared list and its members
+func (repo *TaskRepository) GetList(id string) (*taskPb.TaskList, error) {
+
+	var list *taskPb.TaskList
+	m := map[string]interface{}{}
+
+	listID, err := gocql.ParseUUID(id)
+
+	if err != nil {
+		return nil, errListNotFound
+	}
+
+	iterable := repo.Session.Query("SELECT * FROM task_list WHERE id = ?", listID).Consistency(gocql.One).Iter()
+
+	for iterable.MapScan(m) {
+		list = &taskPb.TaskList{
+			Id:          m["id"].(gocql.UUID).String(),
+			Name:        m["name"].(string),
+			OwnerId:     m["ownerid"].(string),
+			TenantId:    m["tenantid"].(string),
+			CreatedDate: unixOrZero(m["createddate"].(time.Time)),
+			Archived:    m["archived"].(bool),
+		}
+	}
+
+	if err := iterable.Close(); err != nil {
+		return nil, err
+	}
+
+	if list == nil {
+		return nil, errListNotFound
+	}
+
+	var userID string
+	var permission int
+
+	members := repo.Session.Query("SELECT userId, permission FROM task_list_member WHERE listId = ?", listID).Iter()
+
+	for members.Scan(&userID, &permission) {
+		list.Members = append(list.Members, &taskPb.ListMember{UserId: userID, Permission: taskPb.ListPermission(permission)})
+	}
+
+	if err := members.Close(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetListsForUser gets the shared lists that a user is a member of, from their partition of task_list_by_member
+func (repo *TaskRepository) GetListsForUser(userID string) ([]*taskPb.TaskList, error) {
+
+	var listIDs []gocql.UUID
+	var listID gocql.UUID
+
+	iterable := repo.Session.Query("SELECT listId FROM task_list_by_member WHERE userId = ?", userID).Iter()
+
+	for iterable.Scan(&listID) {
+		listIDs = append(listIDs, listID)
+	}
+
+	if err := iterable.Close(); err != nil {
+		return nil, err
+	}
+
+	var lists []*taskPb.TaskList
+
+	for _, id := range listIDs {
+		list, err := repo.GetList(id.String())
+
+		if err == errListNotFound {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		lists = append(lists, list)
+	}
+
+	return lists, nil
+}
+
+// SetListMember gives a user permission on a shared list, replacing any permission they already had. The member is
+// written to both the lists partition of task_list_member and the users partition of task_list_by_member in a logged
+// batch, so the two always agree
+func (repo *TaskRepository) SetListMember(listID string, member *taskPb.ListMember) error {
+
+	id, err := gocql.ParseUUID(listID)
+
+	if err != nil {
+		return errListNotFound
+	}
+
+	batch := repo.Session.NewBatch(gocql.LoggedBatch)
+
+	batch.Query("INSERT INTO task_list_member (listId, userId, permission) VALUES (?,?,?)", id, member.UserId, int(member.Permission))
+	batch.Query("INSERT INTO task_list_by_member (userId, listId) VALUES (?,?)", member.UserId, id)
+
+	return repo.Session.ExecuteBatch(batch)
+}
+
+// RemoveListMember takes away a users permission on a shared list
+func (repo *TaskRepository) RemoveListMember(listID, userID string) error {
+
+	id, err := gocql.ParseUUID(listID)
+
+	if err != nil {
+		return errListNotFound
+	}
+
+	batch := repo.Session.NewBatch(gocql.LoggedBatch)
+
+	batch.Query("DELETE FROM task_list_member WHERE listId = ? AND userId = ?", id, userID)
+	batch.Query("DELETE FROM task_list_by_member WHERE userId = ? AND listId = ?", userID, id)
+
+	return repo.Session.ExecuteBatch(batch)
+}
+
+// UpdateList renames a list
+func (repo *TaskRepository) UpdateList(list *taskPb.TaskList) error {
+
+	id, err := gocql.ParseUUID(list.Id)
+
+	if err != nil {
+		return errListNotFound
+	}
+
+	return repo.Session.Query("UPDATE task_list SET name = ? WHERE id = ?", list.Name, id).Exec()
+}
+
+// SetListArchived archives a list, or brings it back from the archive
+func (repo *TaskRepository) SetListArchived(listID string, archived bool) error {
+
+	id, err := gocql.ParseUUID(listID)
+
+	if err != nil {
+		return errListNotFound
+	}
+
+	return repo.Session.Query("UPDATE task_list SET archived = ? WHERE id = ?", archived, id).Exec()
+}
+
+// DeleteList deletes a list and its members. The tasks in it aren't deleted, they're taken out of the list. The version
+// of the tasks isn't moved on, as it's the list that has changed rather than the tasks, but a task is only taken out if
+// it's still in the list
+func (repo *TaskRepository) DeleteList(listID string) error {
+
+	id, err := gocql.ParseUUID(listID)
+
+	if err != nil {
+		return errListNotFound
+	}
+
+	tasks, _, err := repo.getListItems(id)
+
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		values := append(append([]interface{}{""}, rowKey(task)...), listID)
+
+		_, err = repo.Session.Query("UPDATE task_by_user SET listId = ?"+whereRowKey+" IF listId = ?", values...).
+			MapScanCAS(map[string]interface{}{})
+
+		if err != nil {
+			return err
+		}
+
+		if err := repo.copyTask(task); err != nil {
+			return err
+		}
+	}
+
+	list, err := repo.GetList(listID)
+
+	if err != nil {
+		return err
+	}
+
+	for _, member := range list.Members {
+		if err := repo.RemoveListMember(listID, member.UserId); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range []string{"task_list_item", "task_list_member"} {
+		if err := repo.Session.Query("DELETE FROM "+table+" WHERE listId = ?", id).Exec(); err != nil {
+			return err
+		}
+	}
+
+	return repo.Session.Query("DELETE FROM task_list WHERE id = ?", id).Exec()
+}
+
+// MoveTask moves a task to the list in the task, putting it at the end of that list, or takes it out of its list if
+// there isn't one
+func (repo *TaskRepository) MoveTask(task *taskPb.Task) error {
+
+	existingTask, err := repo.getExistingTask(task.Id)
+
+	if err != nil {
+		return err
+	}
+
+	if existingTask.UserId != task.UserId {
+		return errTaskUserIDNotMatched
+	}
+
+	err = repo.updateIfVersion(existingTask, task, "listId = ?", task.ListId)
+
+	if err != nil {
+		return err
+	}
+
+	return repo.copyTask(existingTask)
+}
+
+// ReorderList puts the tasks in a list into the order of the task ids given. Tasks in the list that aren't given stay
+// in the order they were in, after the ones that are. The whole list is one partition, so the new order is written in
+// one batch. A task that is changed while its list is reordered can keep its old copy in the list until it's next changed
+func (repo *TaskRepository) ReorderList(listID string, taskIDs []string) error {
+
+	id, err := gocql.ParseUUID(listID)
+
+	if err != nil {
+		return errListNotFound
+	}
+
+	tasks, positions, err := repo.getListItems(id)
+
+	if err != nil {
+		return err
+	}
+
+	order, ok := reorderedList(positions, taskIDs)
+
+	if !ok {
+		return errListTasksNotMatched
+	}
+
+	byID := map[string]*taskPb.Task{}
+
+	for _, task := range tasks {
+		byID[task.Id] = task
+	}
+
+	batch := repo.Session.NewBatch(gocql.UnloggedBatch)
+
+	for position, taskID := range order {
+		if positions[taskID] == position {
+			continue
+		}
+
+		task := byID[taskID]
+
+		batch.Query("DELETE FROM task_list_item WHERE listId = ? AND sortOrder = ? AND sortKey = ? AND taskId = ?",
+			id, int(taskPb.SortOrder_LIST_POSITION), int64(positions[taskID]), taskUUID(task))
+
+		for _, item := range listItemRows(task, position) {
+			batch.Query(insertListItem, item...)
+		}
+	}
+
+	if batch.Size() == 0 {
+		return nil
+	}
+
+	return repo.Session.ExecuteBatch(batch)
+}
+
+// getListTasks gets a page of the tasks in a list from the copies of them in task_list_item. A list is one partition
+// with each task in it twice, once in the order the tasks were created and once in the order of the list, so whichever
+// order is asked for the pages follow on from each other
+func (repo *TaskRepository) getListTasks(req *taskPb.Request, now int64) ([]*taskPb.Task, string, error) {
+
+	pageState, err := decodePageToken(req.PageToken)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	listID, err := gocql.ParseUUID(req.ListId)
+
+	if err != nil {
+		return nil, "", errListNotFound
+	}
+
+	queryString := "SELECT * FROM task_list_item WHERE listId = ? AND sortOrder = ?"
+	parameters := []interface{}{listID}
+
+	if req.SortOrder == taskPb.SortOrder_LIST_POSITION {
+		parameters = append(parameters, int(taskPb.SortOrder_LIST_POSITION))
+	} else {
+		created, values := createdRange(req, "sortKey", func(unix int64) interface{} { return unix })
+
+		queryString += created
+		parameters = append(append(parameters, int(taskPb.SortOrder_CREATED_ASCENDING)), values...)
+
+		if req.SortOrder == taskPb.SortOrder_CREATED_DESCENDING {
+			queryString += " ORDER BY sortOrder DESC, sortKey DESC"
+		}
+	}
+
+	return repo.getPage(repo.Session.Query(queryString, parameters...), pageState, req, now, taskFromCopy)
+}
+
+// getListItems reads the copies of the tasks in a list in the order of the list, giving the position of each task too
+func (repo *TaskRepository) getListItems(listID gocql.UUID) ([]*taskPb.Task, map[string]int, error) {
+
+	var tasks []*taskPb.Task
+	positions := map[string]int{}
+
+	m := map[string]interface{}{}
+
+	query := repo.Session.Query("SELECT * FROM task_list_item WHERE listId = ? AND sortOrder = ?", listID, int(taskPb.SortOrder_LIST_POSITION))
+	iterable := query.Iter()
+
+	for iterable.MapScan(m) {
+		task := taskFromCopy(m)
+
+		tasks = append(tasks, task)
+		positions[task.Id] = m["position"].(int)
+
+		m = map[string]interface{}{}
+	}
+
+	if err := iterable.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	return tasks, positions, nil
+}
+
+// listPosition gets the position of a task in its list, or the position at the end of the list if it isn't in it yet
+func (repo *TaskRepository) listPosition(listID gocql.UUID, task *taskPb.Task) (int, error) {
+
+	var position int
+
+	err := repo.Session.Query("SELECT position FROM task_list_item WHERE listId = ? AND sortOrder = ? AND sortKey = ? AND taskId = ?",
+		listID, int(taskPb.SortOrder_CREATED_ASCENDING), task.CreatedDate, taskUUID(task)).Scan(&position)
+
+	if err != gocql.ErrNotFound {
+		return position, err
+	}
+
+	err = repo.Session.Query("SELECT position FROM task_list_item WHERE listId = ? AND sortOrder = ? ORDER BY sortOrder DESC, sortKey DESC LIMIT 1",
+		listID, int(taskPb.SortOrder_LIST_POSITION)).Scan(&position)
+
+	if err == gocql.ErrNotFound {
+		return 0, nil
+	}
+
+	return position + 1, err
+}
+
+// copyTask brings the copies of a task in task_list_item and task_by_assignee up to date with its row in task_by_user.
+// It's given the task from before it was changed, so that it can be taken out of a list or assignee it has left. The
+// row is read again rather than trusting the change, and the copies are written with the time it was read, so if the
+// task is changed twice at once the copy of the later change wins whichever is written last
+func (repo *TaskRepository) copyTask(before *taskPb.Task) error {
+
+	readAt := time.Now().UnixNano() / int64(time.Microsecond)
+
+	var task *taskPb.Task
+	m := map[string]interface{}{}
+
+	iterable := repo.Session.Query("SELECT * FROM task_by_user"+whereRowKey, rowKey(before)...).Iter()
+
+	for iterable.MapScan(m) {
+		task = taskFromRow(m)
+	}
+
+	if err := iterable.Close(); err != nil {
+		return err
+	}
+
+	batch := repo.Session.NewBatch(gocql.LoggedBatch)
+
+	if before.ListId != "" && (task == nil || task.ListId != before.ListId) {
+		listID, _ := gocql.ParseUUID(before.ListId)
+
+		position, err := repo.listPosition(listID, before)
+
+		if err != nil {
+			return err
+		}
+
+		for _, key := range listItemKeys(before, position) {
+			batch.Query("DELETE FROM task_list_item USING TIMESTAMP ? WHERE listId = ? AND sortOrder = ? AND sortKey = ? AND taskId = ?",
+				append([]interface{}{readAt}, key...)...)
+		}
+	}
+
+	if before.AssigneeId != "" && (task == nil || task.AssigneeId != before.AssigneeId) {
+		batch.Query("DELETE FROM task_by_assignee USING TIMESTAMP ? WHERE assigneeId = ? AND createdDate = ? AND taskId = ?",
+			readAt, before.AssigneeId, time.Unix(before.CreatedDate, 0), taskUUID(before))
+	}
+
+	if task != nil && task.ListId != "" {
+		listID, err := gocql.ParseUUID(task.ListId)
+
+		if err != nil {
+			return errListNotFound
+		}
+
+		position, err := repo.listPosition(listID, task)
+
+		if err != nil {
+			return err
+		}
+
+		for _, item := range listItemRows(task, position) {
+			batch.Query(insertListItem+" USING TIMESTAMP ?", append(item, readAt)...)
+		}
+	}
+
+	if task != nil && task.AssigneeId != "" {
+		values := append([]interface{}{task.AssigneeId, time.Unix(task.CreatedDate, 0), taskUUID(task), task.ListId}, copyValues(task)...)
+
+		batch.Query("INSERT INTO task_by_assignee (assigneeId, createdDate, taskId, listId, "+copyColumns+") VALUES (?,?,?,?,"+copyMarkers+") USING TIMESTAMP ?",
+			append(values, readAt)...)
+	}
+
+	if batch.Size() == 0 {
+		return nil
+	}
+
+	return repo.Session.ExecuteBatch(batch)
+}
+
+// copyColumns are the columns of task_by_user that are copied into task_list_item and task_by_assignee, other than the
+// ones in their keys, with copyValues giving the values for them from a task
+const copyColumns = "userId, title, description, completedDate, dailyDo, deleted, deletedDate, dueDate, priority, tags, recurrence, occurrence, version, tenantId"
+
+const copyMarkers = "?,?,?,?,?,?,?,?,?,?,?,?,?,?"
+
+func copyValues(task *taskPb.Task) []interface{} {
+	return []interface{}{task.UserId, task.Title, task.Description, timestampOrNull(task.CompletedDate), task.DailyDo,
+		task.Deleted, timestampOrNull(task.DeletedDate), timestampOrNull(task.DueDate), int(task.Priority), task.Tags,
+		task.Recurrence, int(task.Occurrence), versionOrNull(task.Version), task.TenantId}
+}
+
+// insertListItem writes one of the rows of a task in task_list_item, with the values given by listItemRows
+const insertListItem = "INSERT INTO task_list_item (listId, sortOrder, sortKey, taskId, position, createdDate, assigneeId, " +
+	copyColumns + ") VALUES (?,?,?,?,?,?,?," + copyMarkers + ")"
+
+// listItemKeys gives the keys of the two rows of a task in its list, the first in the order the tasks were created and
+// the second in the order of the list
+func listItemKeys(task *taskPb.Task, position int) [][]interface{} {
+	listID, _ := gocql.ParseUUID(task.ListId)
+	taskID := taskUUID(task)
+
+	return [][]interface{}{
+		{listID, int(taskPb.SortOrder_CREATED_ASCENDING), task.CreatedDate, taskID},
+		{listID, int(taskPb.SortOrder_LIST_POSITION), int64(position), taskID},
+	}
+}
+
+// listItemRows gives the values for insertListItem of the two rows of a task in its list
+func listItemRows(task *taskPb.Task, position int) [][]interface{} {
+	var rows [][]interface{}
+
+	for _, key := range listItemKeys(task, position) {
+		row := append(key, position, time.Unix(task.CreatedDate, 0), task.AssigneeId)
+		rows = append(rows, append(row, copyValues(task)...))
+	}
+
+	return rows
+}
+
+// AdoptTask puts a task from before tenants were added into a tenant. Returns false if it's already in one
+func (repo *TaskRepository) AdoptTask(taskID, tenantID string) (bool, error) {
+
+	existingTask, err := repo.getExistingTask(taskID)
+
+	if err != nil {
+		return false, err
+	}
+
+	values := append([]interface{}{tenantID}, rowKey(existingTask)...)
+
+	adopted, err := repo.Session.Query("UPDATE task_by_user SET tenantId = ?"+whereRowKey+" IF tenantId = null", values...).
+		MapScanCAS(map[string]interface{}{})
+
+	if err != nil || !adopted {
+		return adopted, err
+	}
+
+	return true, repo.copyTask(existingTask)
+}
+
+// whereRowKey is the where clause for a single row of task_by_user, with the values for it given by rowKey
+const whereRowKey = " WHERE userId = ? AND createdDate = ? AND id = ?"
+
+// rowKey gives the key of a tasks row in task_by_user
+func rowKey(task *taskPb.Task) []interface{} {
+	return []interface{}{task.UserId, time.Unix(task.CreatedDate, 0), taskUUID(task)}
+}
+
+// taskUUID gives the id of a task as it's stored
+func taskUUID(task *taskPb.Task) gocql.UUID {
+	id, _ := gocql.ParseUUID(task.Id)
+
+	return id
+}
+
+// getTasksByID gets the tasks with the given ids, skipping any that no longer exist. Each task is read on its own,
+// as the ids can be from any number of users partitions
+func (repo *TaskRepository) getTasksByID(ids []string) ([]*taskPb.Task, error) {
+	var tasks []*taskPb.Task
+
+	for _, id := range ids {
+		task, err := repo.getExistingTask(id)
+
+		if err == errTaskNotFound {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// getAssignedTasks gets a page of the tasks assigned to a user from the copies of them in task_by_assignee. Each
+// assignee is a partition clustered by when the tasks were created, like the users own tasks in task_by_user
+func (repo *TaskRepository) getAssignedTasks(userID string, req *taskPb.Request, now int64) ([]*taskPb.Task, string, error) {
+
+	pageState, err := decodePageToken(req.PageToken)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	created, values := createdRange(req, "createdDate", func(unix int64) interface{} { return time.Unix(unix, 0) })
+
+	queryString := "SELECT * FROM task_by_assignee WHERE assigneeId = ?" + created
+	parameters := append([]interface{}{userID}, values...)
+
+	if req.SortOrder == taskPb.SortOrder_CREATED_DESCENDING {
+		queryString += " ORDER BY createdDate DESC"
+	}
+
+	return repo.getPage(repo.Session.Query(queryString, parameters...), pageState, req, now, taskFromCopy)
+}
+
+// taskFromRow creates a task from a row of the task_by_user table
+func taskFromRow(m map[string]interface{}) *taskPb.Task {
+	return &taskPb.Task{
+		Id:            m["id"].(gocql.UUID).String(),
+		Title:         m["title"].(string),
+		Description:   m["description"].(string),
+		UserId:        m["userid"].(string),
+		DailyDo:       m["dailydo"].(bool),
+		CompletedDate: unixOrZero(m["completeddate"].(time.Time)),
+		CreatedDate:   unixOrZero(m["createddate"].(time.Time)),
+		Deleted:       m["deleted"].(bool),
+		DeletedDate:   unixOrZero(m["deleteddate"].(time.Time)),
+		DueDate:       unixOrZero(m["duedate"].(time.Time)),
+		Priority:      taskPb.Priority(m["priority"].(int)),
+		Tags:          m["tags"].([]string),
+		Recurrence:    m["recurrence"].(string),
+		Occurrence:    int32(m["occurrence"].(int)),
+		Version:       int32(m["version"].(int)),
+		TenantId:      m["tenantid"].(string),
+		ListId:        m["listid"].(string),
+		AssigneeId:    m["assigneeid"].(string),
+	}
+}
+
+// taskFromCopy creates a task from its copy in task_list_item or task_by_assignee, which have its id in taskId and, for
+// a list, the list id as a uuid
+func taskFromCopy(m map[string]interface{}) *taskPb.Task {
+	m["id"] = m["taskid"]
+
+	if listID, ok := m["listid"].(gocql.UUID); ok {
+		m["listid"] = listID.String()
+	}
+
+	return taskFromRow(m)
+}
+
+// versionOrNull turns a task version into the value stored for it. Tasks created before versions were added have a
+// null version, which is read as 0
+func versionOrNull(version int32) interface{} {
+	if version == 0 {
+		return nil
+	}
+
+	return int(version)
+}
+
+// timestampOrNull turns a unix time into a timestamp to store, or null if it's not set
+func timestampOrNull(unix int64) interface{} {
+	if unix == 0 {
+		return nil
+	}
+
+	return time.Unix(unix, 0)
+}
+
+// unixOrZero turns a stored timestamp into a unix time, or 0 if it was null
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
+
+// pageSizeForRequest gets the page size to use for a request, using the default if one hasn't been given
+// and capping it so that a single request can't fetch every task
+func pageSizeForRequest(req *taskPb.Request) int {
+	if req.PageSize <= 0 {
+		return defaultPageSize
+	}
+
+	if req.PageSize > maxPageSize {
+		return maxPageSize
+	}
+
+	return int(req.PageSize)
+}
+
+// taskMatchesRequest checks if a task passes the filters in a request, with now used to work out if the task is overdue
+func taskMatchesRequest(task *taskPb.Task, req *taskPb.Request, now int64) bool {
+
+	if task.Deleted && !req.IncludeDeleted {
+		return false
+	}
+
+	completed := task.CompletedDate > 0
+
+	if req.CompletedOnly && !completed {
+		return false
+	}
+
+	if req.IncompleteOnly && completed {
+		return false
+	}
+
+	if req.DailyDoOnly && !task.DailyDo {
+		return false
+	}
+
+	if req.CreatedFrom != 0 && task.CreatedDate < req.CreatedFrom {
+		return false
+	}
+
+	if req.CreatedTo != 0 && task.CreatedDate > req.CreatedTo {
+		return false
+	}
+
+	if req.Tag != "" && !hasTag(task, req.Tag) {
+		return false
+	}
+
+	if req.Priority != taskPb.Priority_PRIORITY_NONE && task.Priority != req.Priority {
+		return false
+	}
+
+	if req.OverdueOnly && (completed || task.DueDate == 0 || task.DueDate >= now) {
+		return false
+	}
+
+	return true
+}
+
+// hasTag checks if a task has been given a tag
+func hasTag(task *taskPb.Task, tag string) bool {
+	for _, v := range task.Tags {
+		if v == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// countTags counts how many tasks have each tag, with the most used tags first. Deleted tasks aren't counted
+func countTags(tasks []*taskPb.Task) []*taskPb.TagCount {
+	counts := map[string]int32{}
+
+	for _, task := range tasks {
+		if task.Deleted {
+			continue
+		}
+
+		for _, tag := range task.Tags {
+			counts[tag]++
+		}
+	}
+
+	var tags []*taskPb.TagCount
+
+	for tag, count := range counts {
+		tags = append(tags, &taskPb.TagCount{Tag: tag, Count: count})
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+
+		return tags[i].Tag < tags[j].Tag
+	})
+
+	return tags
+}
+
+// sortTasks sorts tasks by their created date in the order requested
+func sortTasks(tasks []*taskPb.Task, order taskPb.SortOrder) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if order == taskPb.SortOrder_CREATED_DESCENDING {
+			return tasks[i].CreatedDate > tasks[j].CreatedDate
+		}
+
+		return tasks[i].CreatedDate < tasks[j].CreatedDate
+	})
+}
+
+// encodePageToken turns Cassandra paging state into an opaque token that can be given to a client
+func encodePageToken(pageState []byte) string {
+	if len(pageState) == 0 {
+		return ""
+	}
+
+	return base64.URLEncoding.EncodeToString(pageState)
+}
+
+// sortByListPosition sorts tasks into the order of a list
+func sortByListPosition(tasks []*taskPb.Task, positions map[string]int) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return positions[tasks[i].Id] < positions[tasks[j].Id]
+	})
+}
+
+// reorderedList works out the new order of a list from the task ids given, followed by the rest of the tasks in their
+// current order. It's not ok if an id isn't in the list or is repeated
+func reorderedList(positions map[string]int, taskIDs []string) ([]string, bool) {
+	seen := map[string]bool{}
+
+	for _, id := range taskIDs {
+		if _, ok := positions[id]; !ok || seen[id] {
+			return nil, false
+		}
+
+		seen[id] = true
+	}
+
+	var rest []string
+
+	for id := range positions {
+		if !seen[id] {
+			rest = append(rest, id)
+		}
+	}
+
+	sort.SliceStable(rest, func(i, j int) bool {
+		if positions[rest[i]] != positions[rest[j]] {
+			return positions[rest[i]] < positions[rest[j]]
+		}
+
+		return rest[i] < rest[j]
+	})
+
+	return append(append([]string{}, taskIDs...), rest...), true
+}
+
+// decodePageToken turns a token given to a client back into Cassandra paging state
+func decodePageToken(token string) ([]byte, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	pageState, err := base64.URLEncoding.DecodeString(token)
+
+	if err != nil {
+		return nil, errInvalidPageToken
+	}
+
+	return pageState, nil
+}
+
+// sortChecklist puts checklist items in order of their position
+func sortChecklist(checklist []*taskPb.ChecklistItem) {
+	sort.SliceStable(checklist, func(i, j int) bool {
+		return checklist[i].Position < checklist[j].Position
+	})
+}
+
+// findChecklistItem finds an item in a checklist by id. Returns nil if it isn't in the checklist
+func findChecklistItem(checklist []*taskPb.ChecklistItem, itemID string) *taskPb.ChecklistItem {
+	for _, item := range checklist {
+		if item.Id == itemID {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// checklistMatchesIDs checks that the ids given are exactly the ids of the items in a checklist
+func checklistMatchesIDs(checklist []*taskPb.ChecklistItem, itemIDs []string) bool {
+	if len(checklist) != len(itemIDs) {
+		return false
+	}
+
+	seen := map[string]bool{}
+
+	for _, id := range itemIDs {
+		if seen[id] || findChecklistItem(checklist, id) == nil {
+			return false
+		}
+
+		seen[id] = true
+	}
 
+	return true
 }
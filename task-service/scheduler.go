@@ -0,0 +1,126 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	taskPb "github.com/willdot/Go-Do/task-service/proto/task"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/user-service/verifier"
+	"golang.org/x/net/context"
+)
+
+// rolloverInterval is how often the scheduler checks if it's gone midnight for any users
+const rolloverInterval = time.Minute
+
+// DailyDoScheduler rolls each users daily do over when it gets to midnight in their timezone. The daily do from the
+// day that has finished is recorded as missed and is no longer the daily do, unless the user has chosen to carry
+// an uncompleted daily do over to the next day
+type DailyDoScheduler struct {
+	repo       Repository
+	userClient authPb.AuthClient
+	clock      Clock
+	// serviceToken is sent to the auth service to get the settings of users, as the scheduler doesn't have a caller
+	serviceToken string
+}
+
+// Start runs the rollover every interval until the context is cancelled
+func (s *DailyDoScheduler) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Rollover(ctx); err != nil {
+				log.Println("Daily do rollover failed: ", err)
+			}
+		}
+	}
+}
+
+// Rollover closes out any daily do that was chosen on a day that has now finished for its user
+func (s *DailyDoScheduler) Rollover(ctx context.Context) error {
+
+	dailyDos, err := s.repo.GetDailyDos()
+
+	if err != nil {
+		return err
+	}
+
+	for _, dailyDo := range dailyDos {
+		if err := s.rolloverTask(ctx, dailyDo); err != nil {
+			log.Printf("Daily do rollover failed for task %s: %v", dailyDo.Id, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *DailyDoScheduler) rolloverTask(ctx context.Context, dailyDo *taskPb.Task) error {
+
+	// The task can be assigned to someone other than its owner, and it's their daily do and day that count
+	userID := dailyDoUser(dailyDo)
+
+	userResponse, err := s.userClient.Get(verifier.NewServiceContext(ctx, s.serviceToken), &authPb.User{Id: userID})
+
+	if err != nil {
+		return err
+	}
+
+	user := userResponse.GetUser()
+	today := localDay(s.clock.Now(), user.GetTimezone())
+
+	history, err := s.repo.GetDailyDoHistory(userID, "", "")
+
+	if err != nil {
+		return err
+	}
+
+	// history is newest first, so the first entry for the task is the day it was last the daily do
+	var latest *taskPb.DailyDoHistory
+	for _, v := range history {
+		if v.TaskId == dailyDo.Id {
+			latest = v
+			break
+		}
+	}
+
+	// Tasks that were the daily do before the history was recorded start being tracked from today
+	if latest == nil {
+		return s.repo.SetDailyDoHistory(&taskPb.DailyDoHistory{
+			UserId: userID,
+			Day:    today,
+			TaskId: dailyDo.Id,
+		})
+	}
+
+	if latest.Day >= today {
+		return nil
+	}
+
+	// The task is still the daily do, so it wasn't completed on the day it was chosen for
+	latest.Completed = false
+	err = s.repo.SetDailyDoHistory(latest)
+
+	if err != nil {
+		return err
+	}
+
+	if user.GetCarryOverDailyDo() {
+		return s.repo.SetDailyDoHistory(&taskPb.DailyDoHistory{
+			UserId: userID,
+			Day:    today,
+			TaskId: dailyDo.Id,
+		})
+	}
+
+	return s.repo.SetDailyDoStatus(&taskPb.Task{
+		Id:      dailyDo.Id,
+		UserId:  dailyDo.UserId,
+		DailyDo: false,
+		Version: dailyDo.Version,
+	})
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	taskPb "github.com/willdot/Go-Do/task-service/proto/task"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"golang.org/x/net/context"
+)
+
+// createScheduler creates a scheduler with a single daily do task for user 1 that was chosen on the day given
+func createScheduler(now time.Time, chosenOn string, user *authPb.User) (*DailyDoScheduler, *fakeRepo, *taskPb.Task) {
+
+	dailyDo := &taskPb.Task{
+		Id:      "999",
+		UserId:  userID1,
+		DailyDo: true,
+	}
+
+	repo := &fakeRepo{tasks: []*taskPb.Task{dailyDo}}
+
+	if chosenOn != "" {
+		repo.history = []*taskPb.DailyDoHistory{
+			{UserId: userID1, Day: chosenOn, TaskId: dailyDo.Id},
+		}
+	}
+
+	users := map[string]*authPb.User{userID1: user}
+
+	scheduler := &DailyDoScheduler{repo, &fakeUserHandler{false, true, users}, &fakeClock{now}, fakeServiceToken}
+
+	return scheduler, repo, dailyDo
+}
+
+func TestRollover(t *testing.T) {
+
+	t.Run("the scheduler needs the service token to get users", func(t *testing.T) {
+		now := time.Date(2019, 8, 21, 0, 0, 30, 0, time.UTC)
+
+		scheduler, _, dailyDo := createScheduler(now, "2019-08-20", &authPb.User{Id: userID1})
+		scheduler.serviceToken = ""
+
+		err := scheduler.rolloverTask(context.Background(), dailyDo)
+
+		assertError(err, errFakeNoToken, t)
+	})
+
+	t.Run("daily do isn't rolled over before midnight", func(t *testing.T) {
+		now := time.Date(2019, 8, 20, 23, 59, 0, 0, time.UTC)
+
+		scheduler, repo, dailyDo := createScheduler(now, "2019-08-20", &authPb.User{Id: userID1})
+
+		err := scheduler.Rollover(context.Background())
+
+		assertError(err, nil, t)
+
+		if !dailyDo.DailyDo {
+			t.Errorf("daily do was cleared before midnight")
+		}
+
+		if len(repo.history) != 1 {
+			t.Errorf("wanted 1 history entry but got %v", len(repo.history))
+		}
+	})
+
+	t.Run("daily do is cleared and recorded as missed after midnight", func(t *testing.T) {
+		now := time.Date(2019, 8, 21, 0, 0, 30, 0, time.UTC)
+
+		scheduler, repo, dailyDo := createScheduler(now, "2019-08-20", &authPb.User{Id: userID1})
+
+		err := scheduler.Rollover(context.Background())
+
+		assertError(err, nil, t)
+
+		if dailyDo.DailyDo {
+			t.Errorf("daily do wasn't cleared after midnight")
+		}
+
+		history, _ := repo.GetDailyDoHistoryForDay(userID1, "2019-08-20")
+
+		if history == nil || history.Completed {
+			t.Errorf("wanted the daily do to be recorded as missed but got %v", history)
+		}
+	})
+
+	t.Run("midnight is in the users timezone", func(t *testing.T) {
+		// 23:30 in UTC is 09:30 the next day in Sydney
+		now := time.Date(2019, 8, 20, 23, 30, 0, 0, time.UTC)
+
+		scheduler, _, dailyDo := createScheduler(now, "2019-08-20", &authPb.User{Id: userID1, Timezone: "Australia/Sydney"})
+
+		err := scheduler.Rollover(context.Background())
+
+		assertError(err, nil, t)
+
+		if dailyDo.DailyDo {
+			t.Errorf("daily do wasn't cleared after midnight in the users timezone")
+		}
+	})
+
+	t.Run("uncompleted daily do is carried over", func(t *testing.T) {
+		now := time.Date(2019, 8, 21, 0, 0, 30, 0, time.UTC)
+
+		scheduler, repo, dailyDo := createScheduler(now, "2019-08-20", &authPb.User{Id: userID1, CarryOverDailyDo: true})
+
+		err := scheduler.Rollover(context.Background())
+
+		assertError(err, nil, t)
+
+		if !dailyDo.DailyDo {
+			t.Errorf("daily do was cleared but should have been carried over")
+		}
+
+		missed, _ := repo.GetDailyDoHistoryForDay(userID1, "2019-08-20")
+
+		if missed == nil || missed.Completed {
+			t.Errorf("wanted the daily do to be recorded as missed but got %v", missed)
+		}
+
+		carried, _ := repo.GetDailyDoHistoryForDay(userID1, "2019-08-21")
+
+		if carried == nil || carried.TaskId != dailyDo.Id {
+			t.Errorf("wanted the daily do to be carried over to the new day but got %v", carried)
+		}
+	})
+
+	t.Run("daily do without any history starts being tracked", func(t *testing.T) {
+		now := time.Date(2019, 8, 21, 12, 0, 0, 0, time.UTC)
+
+		scheduler, repo, dailyDo := createScheduler(now, "", &authPb.User{Id: userID1})
+
+		err := scheduler.Rollover(context.Background())
+
+		assertError(err, nil, t)
+
+		if !dailyDo.DailyDo {
+			t.Errorf("daily do was cleared but had only just started being tracked")
+		}
+
+		history, _ := repo.GetDailyDoHistoryForDay(userID1, "2019-08-21")
+
+		if history == nil || history.TaskId != dailyDo.Id {
+			t.Errorf("wanted the daily do to be tracked from today but got %v", history)
+		}
+	})
+
+	t.Run("rollover but repo returns error", func(t *testing.T) {
+		scheduler, repo, _ := createScheduler(time.Now(), "", &authPb.User{Id: userID1})
+		repo.returnError = true
+
+		err := scheduler.Rollover(context.Background())
+
+		assertError(err, errFake, t)
+	})
+}
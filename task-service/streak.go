@@ -0,0 +1,66 @@
+package main
+
+import (
+	"time"
+
+	"github.com/willdot/Go-Do/apierrors"
+	taskPb "github.com/willdot/Go-Do/task-service/proto/task"
+)
+
+// dayLayout is the format of the days used in the daily do history
+const dayLayout = "2006-01-02"
+
+var errInvalidDay = apierrors.Validation("Day must be in the format YYYY-MM-DD")
+
+// calculateStreaks works out the current and longest number of consecutive days that a user completed their daily do.
+// The current streak isn't broken until the end of today, so a streak that ran until yesterday is still current
+func calculateStreaks(history []*taskPb.DailyDoHistory, currentDay string) (current, longest int32) {
+
+	completedDays := map[string]bool{}
+
+	for _, v := range history {
+		if v.Completed {
+			completedDays[v.Day] = true
+		}
+	}
+
+	for day := range completedDays {
+		parsedDay, err := time.Parse(dayLayout, day)
+
+		if err != nil {
+			continue
+		}
+
+		// only count from the start of each run so that each run is only counted once
+		if completedDays[parsedDay.AddDate(0, 0, -1).Format(dayLayout)] {
+			continue
+		}
+
+		var length int32
+		for completedDays[parsedDay.Format(dayLayout)] {
+			length++
+			parsedDay = parsedDay.AddDate(0, 0, 1)
+		}
+
+		if length > longest {
+			longest = length
+		}
+	}
+
+	day, err := time.Parse(dayLayout, currentDay)
+
+	if err != nil {
+		return 0, longest
+	}
+
+	if !completedDays[currentDay] {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	for completedDays[day.Format(dayLayout)] {
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	return current, longest
+}
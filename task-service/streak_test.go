@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+
+	taskPb "github.com/willdot/Go-Do/task-service/proto/task"
+)
+
+func TestCalculateStreaks(t *testing.T) {
+
+	history := func(days map[string]bool) []*taskPb.DailyDoHistory {
+		var history []*taskPb.DailyDoHistory
+
+		for day, completed := range days {
+			history = append(history, &taskPb.DailyDoHistory{Day: day, Completed: completed})
+		}
+
+		return history
+	}
+
+	tests := []struct {
+		name        string
+		history     []*taskPb.DailyDoHistory
+		today       string
+		wantCurrent int32
+		wantLongest int32
+	}{
+		{
+			name:        "no history",
+			today:       "2019-08-20",
+			wantCurrent: 0,
+			wantLongest: 0,
+		},
+		{
+			name:        "completed today only",
+			history:     history(map[string]bool{"2019-08-20": true}),
+			today:       "2019-08-20",
+			wantCurrent: 1,
+			wantLongest: 1,
+		},
+		{
+			name:        "streak up to yesterday is still current",
+			history:     history(map[string]bool{"2019-08-18": true, "2019-08-19": true, "2019-08-20": false}),
+			today:       "2019-08-20",
+			wantCurrent: 2,
+			wantLongest: 2,
+		},
+		{
+			name:        "missed yesterday breaks the streak",
+			history:     history(map[string]bool{"2019-08-17": true, "2019-08-18": true, "2019-08-19": false}),
+			today:       "2019-08-20",
+			wantCurrent: 0,
+			wantLongest: 2,
+		},
+		{
+			name:        "day without a daily do breaks the streak",
+			history:     history(map[string]bool{"2019-08-15": true, "2019-08-16": true, "2019-08-17": true, "2019-08-19": true, "2019-08-20": true}),
+			today:       "2019-08-20",
+			wantCurrent: 2,
+			wantLongest: 3,
+		},
+		{
+			name:        "streak across the end of a month",
+			history:     history(map[string]bool{"2019-07-30": true, "2019-07-31": true, "2019-08-01": true}),
+			today:       "2019-08-01",
+			wantCurrent: 3,
+			wantLongest: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := calculateStreaks(tt.history, tt.today)
+
+			if current != tt.wantCurrent {
+				t.Errorf("current streak: want %v got %v", tt.wantCurrent, current)
+			}
+
+			if longest != tt.wantLongest {
+				t.Errorf("longest streak: want %v got %v", tt.wantLongest, longest)
+			}
+		})
+	}
+}
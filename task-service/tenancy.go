@@ -0,0 +1,438 @@
+package main
+
+import (
+	"golang.org/x/net/context"
+
+	taskPb "github.com/willdot/Go-Do/task-service/proto/task"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/user-service/verifier"
+)
+
+// personalTenantPrefix is put before the id of a user to make the tenant for the tasks they make outside of a company
+const personalTenantPrefix = "user:"
+
+// tenantFor is the tenant a user's tasks go in, which is their company, or just them if they aren't in one
+func tenantFor(userID, companyID string) string {
+	if companyID != "" {
+		return companyID
+	}
+
+	return personalTenantPrefix + userID
+}
+
+// inTenant reports if a task is in a tenant. Tasks from before tenants were added aren't in one yet, and go to
+// whichever tenant their user is in
+func inTenant(task *taskPb.Task, tenantID string) bool {
+	return task.TenantId == tenantID || task.TenantId == ""
+}
+
+// tenantRepository only lets the tasks in one tenant be seen or changed, so that a handler that gets the wrong task
+// can't see or change tasks from another company. Tasks from another tenant aren't found, rather than forbidden, so
+// that their ids don't leak
+type tenantRepository struct {
+	Repository
+	tenantID string
+}
+
+// owns reports if a task is in the tenant, putting tasks from before tenants were added into it
+func (r *tenantRepository) owns(task *taskPb.Task) (bool, error) {
+	if task.TenantId != "" {
+		return task.TenantId == r.tenantID, nil
+	}
+
+	adopted, err := r.Repository.AdoptTask(task.Id, r.tenantID)
+
+	if err != nil {
+		return false, err
+	}
+
+	if adopted {
+		task.TenantId = r.tenantID
+	}
+
+	return adopted, nil
+}
+
+// check gets the stored task, returning errTaskNotFound if it's in another tenant
+func (r *tenantRepository) check(task *taskPb.Task) error {
+	_, err := r.GetTask(task)
+
+	return err
+}
+
+// Get gets a page of the users tasks in the tenant. Tasks in other tenants are left out, so a page can have fewer tasks
+// than the page size
+func (r *tenantRepository) Get(userID string, req *taskPb.Request, now int64) ([]*taskPb.Task, string, error) {
+	tasks, nextPageToken, err := r.Repository.Get(userID, req, now)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	var owned []*taskPb.Task
+
+	for _, task := range tasks {
+		ok, err := r.owns(task)
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		if ok {
+			owned = append(owned, task)
+		}
+	}
+
+	return owned, nextPageToken, nil
+}
+
+// Create creates a task in the tenant
+func (r *tenantRepository) Create(task *taskPb.Task) error {
+	task.TenantId = r.tenantID
+
+	if task.DailyDo {
+		if err := r.releaseOtherDailyDo(dailyDoUser(task)); err != nil {
+			return err
+		}
+	}
+
+	return r.Repository.Create(task)
+}
+
+// GetTask gets a task in the tenant
+func (r *tenantRepository) GetTask(task *taskPb.Task) (*taskPb.Task, error) {
+	existingTask, err := r.Repository.GetTask(task)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := r.owns(existingTask)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, errTaskNotFound
+	}
+
+	return existingTask, nil
+}
+
+// GetDailyDoForUser gets the users daily do if it's in the tenant
+func (r *tenantRepository) GetDailyDoForUser(userID string) (*taskPb.Task, error) {
+	dailyDo, err := r.Repository.GetDailyDoForUser(userID)
+
+	if err != nil || dailyDo == nil {
+		return nil, err
+	}
+
+	ok, err := r.owns(dailyDo)
+
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return dailyDo, nil
+}
+
+// releaseOtherDailyDo stops a daily do left in another tenant from being the users daily do. Users only have one daily
+// do, and one left behind in a company they've left can't be seen to be changed
+func (r *tenantRepository) releaseOtherDailyDo(userID string) error {
+	dailyDo, err := r.Repository.GetDailyDoForUser(userID)
+
+	if err != nil || dailyDo == nil || inTenant(dailyDo, r.tenantID) {
+		return err
+	}
+
+	dailyDo.DailyDo = false
+
+	return r.Repository.SetDailyDoStatus(dailyDo)
+}
+
+// Update updates a task in the tenant
+func (r *tenantRepository) Update(task *taskPb.Task) error {
+	if err := r.check(task); err != nil {
+		return err
+	}
+
+	return r.Repository.Update(task)
+}
+
+// SetDailyDoStatus sets the daily do status of a task in the tenant
+func (r *tenantRepository) SetDailyDoStatus(task *taskPb.Task) error {
+	if err := r.check(task); err != nil {
+		return err
+	}
+
+	if task.DailyDo {
+		if err := r.releaseOtherDailyDo(dailyDoUser(task)); err != nil {
+			return err
+		}
+	}
+
+	return r.Repository.SetDailyDoStatus(task)
+}
+
+// CompleteTask completes a task in the tenant
+func (r *tenantRepository) CompleteTask(task *taskPb.Task) error {
+	if err := r.check(task); err != nil {
+		return err
+	}
+
+	return r.Repository.CompleteTask(task)
+}
+
+// Delete deletes a task in the tenant
+func (r *tenantRepository) Delete(task *taskPb.Task) error {
+	if err := r.check(task); err != nil {
+		return err
+	}
+
+	return r.Repository.Delete(task)
+}
+
+// Restore restores a task in the tenant
+func (r *tenantRepository) Restore(task *taskPb.Task) error {
+	if err := r.check(task); err != nil {
+		return err
+	}
+
+	return r.Repository.Restore(task)
+}
+
+// Purge purges the users tasks in the tenant, whichever tenant is asked for
+func (r *tenantRepository) Purge(tenantID, userID string, deletedBefore int64) ([]*taskPb.Task, error) {
+	return r.Repository.Purge(r.tenantID, userID, deletedBefore)
+}
+
+// GetChecklist gets the checklist of a task in the tenant
+func (r *tenantRepository) GetChecklist(task *taskPb.Task) ([]*taskPb.ChecklistItem, error) {
+	if err := r.check(task); err != nil {
+		return nil, err
+	}
+
+	return r.Repository.GetChecklist(task)
+}
+
+// AddChecklistItem adds an item to the checklist of a task in the tenant
+func (r *tenantRepository) AddChecklistItem(task *taskPb.Task, item *taskPb.ChecklistItem) error {
+	if err := r.check(task); err != nil {
+		return err
+	}
+
+	return r.Repository.AddChecklistItem(task, item)
+}
+
+// SetChecklistItemStatus completes or un completes a checklist item of a task in the tenant
+func (r *tenantRepository) SetChecklistItemStatus(task *taskPb.Task, item *taskPb.ChecklistItem) error {
+	if err := r.check(task); err != nil {
+		return err
+	}
+
+	return r.Repository.SetChecklistItemStatus(task, item)
+}
+
+// ReorderChecklist reorders the checklist of a task in the tenant
+func (r *tenantRepository) ReorderChecklist(task *taskPb.Task, itemIDs []string) error {
+	if err := r.check(task); err != nil {
+		return err
+	}
+
+	return r.Repository.ReorderChecklist(task, itemIDs)
+}
+
+// RemoveChecklistItem removes an item from the checklist of a task in the tenant
+func (r *tenantRepository) RemoveChecklistItem(task *taskPb.Task, itemID string) error {
+	if err := r.check(task); err != nil {
+		return err
+	}
+
+	return r.Repository.RemoveChecklistItem(task, itemID)
+}
+
+// ListTags counts the tags of the users tasks in the tenant, whichever tenant is asked for
+func (r *tenantRepository) ListTags(tenantID, userID string) ([]*taskPb.TagCount, error) {
+	return r.Repository.ListTags(r.tenantID, userID)
+}
+
+// GetTaskByID gets a task in the tenant, whoever it belongs to. Tasks from before tenants were added aren't adopted, as
+// the caller may not be their user
+func (r *tenantRepository) GetTaskByID(id string) (*taskPb.Task, error) {
+	existingTask, err := r.Repository.GetTaskByID(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !inTenant(existingTask, r.tenantID) {
+		return nil, errTaskNotFound
+	}
+
+	return existingTask, nil
+}
+
+// AssignTask assigns a task in the tenant
+func (r *tenantRepository) AssignTask(task *taskPb.Task) error {
+	if err := r.check(task); err != nil {
+		return err
+	}
+
+	return r.Repository.AssignTask(task)
+}
+
+// CreateList creates a shared list in the tenant
+func (r *tenantRepository) CreateList(list *taskPb.TaskList) error {
+	list.TenantId = r.tenantID
+
+	return r.Repository.CreateList(list)
+}
+
+// GetList gets a shared list in the tenant
+func (r *tenantRepository) GetList(id string) (*taskPb.TaskList, error) {
+	list, err := r.Repository.GetList(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if list.TenantId != r.tenantID {
+		return nil, errListNotFound
+	}
+
+	return list, nil
+}
+
+// GetListsForUser gets the shared lists in the tenant that a user is a member of
+func (r *tenantRepository) GetListsForUser(userID string) ([]*taskPb.TaskList, error) {
+	lists, err := r.Repository.GetListsForUser(userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var inTenant []*taskPb.TaskList
+
+	for _, list := range lists {
+		if list.TenantId == r.tenantID {
+			inTenant = append(inTenant, list)
+		}
+	}
+
+	return inTenant, nil
+}
+
+// SetListMember gives a user permission on a shared list in the tenant
+func (r *tenantRepository) SetListMember(listID string, member *taskPb.ListMember) error {
+	if _, err := r.GetList(listID); err != nil {
+		return err
+	}
+
+	return r.Repository.SetListMember(listID, member)
+}
+
+// RemoveListMember takes away a users permission on a shared list in the tenant
+func (r *tenantRepository) RemoveListMember(listID, userID string) error {
+	if _, err := r.GetList(listID); err != nil {
+		return err
+	}
+
+	return r.Repository.RemoveListMember(listID, userID)
+}
+
+// UpdateList renames a list in the tenant
+func (r *tenantRepository) UpdateList(list *taskPb.TaskList) error {
+	if _, err := r.GetList(list.Id); err != nil {
+		return err
+	}
+
+	return r.Repository.UpdateList(list)
+}
+
+// SetListArchived archives a list in the tenant
+func (r *tenantRepository) SetListArchived(listID string, archived bool) error {
+	if _, err := r.GetList(listID); err != nil {
+		return err
+	}
+
+	return r.Repository.SetListArchived(listID, archived)
+}
+
+// DeleteList deletes a list in the tenant
+func (r *tenantRepository) DeleteList(listID string) error {
+	if _, err := r.GetList(listID); err != nil {
+		return err
+	}
+
+	return r.Repository.DeleteList(listID)
+}
+
+// ReorderList reorders a list in the tenant
+func (r *tenantRepository) ReorderList(listID string, taskIDs []string) error {
+	if _, err := r.GetList(listID); err != nil {
+		return err
+	}
+
+	return r.Repository.ReorderList(listID, taskIDs)
+}
+
+// MoveTask moves a task in the tenant to a list in the tenant
+func (r *tenantRepository) MoveTask(task *taskPb.Task) error {
+	if err := r.check(task); err != nil {
+		return err
+	}
+
+	if task.ListId != "" {
+		if _, err := r.GetList(task.ListId); err != nil {
+			return err
+		}
+	}
+
+	return r.Repository.MoveTask(task)
+}
+
+// forCaller gets the id of the user that made the request, and a copy of the handler that can only use the tasks in
+// their tenant. Handlers use the copy for everything, so they don't each have to remember to check the tenant
+func (t *taskHandler) forCaller(ctx context.Context) (*taskHandler, string, error) {
+	userID, companyID, err := t.callerFromContext(ctx)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	scoped := *t
+	scoped.tenantID = tenantFor(userID, companyID)
+	scoped.repo = &tenantRepository{t.repo, scoped.tenantID}
+
+	return &scoped, userID, nil
+}
+
+// so that we can get the user id to use on the functions, we get the supplied token, validate it,
+// and then get the user id and company. This means not having to send the user id in the request, which
+// limits the chance of random api calls being made with guessed user id. The AuthWrapper has usually
+// already verified the token and put its claims in the context, and the auth service is only asked
+// when it hasn't
+func (t *taskHandler) callerFromContext(ctx context.Context) (string, string, error) {
+	if claims, ok := verifier.ClaimsFromContext(ctx); ok {
+		return claims.UserID, claims.CompanyID, nil
+	}
+
+	suppliedToken, err := getTokenFromContext(ctx)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	token := authPb.Token{
+		Token: suppliedToken,
+	}
+
+	validationResult, err := t.userClient.ValidateToken(ctx, &token)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return validationResult.UserId, validationResult.CompanyId, nil
+}
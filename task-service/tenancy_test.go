@@ -0,0 +1,186 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	taskPb "github.com/willdot/Go-Do/task-service/proto/task"
+	"github.com/willdot/Go-Do/user-service/verifier"
+)
+
+// createTenantService creates a fake service where the first user has tasks in two companies, on their own and from
+// before tenants were added
+func createTenantService() taskHandler {
+	service := createService(false, false, true)
+
+	service.repo = &fakeRepo{tasks: []*taskPb.Task{
+		{Id: "acme", Title: "Acme", UserId: userID1, TenantId: "acme", Tags: []string{"work"}},
+		{Id: "globex", Title: "Globex", UserId: userID1, TenantId: "globex", Tags: []string{"work"}, Deleted: true, DeletedDate: 1},
+		{Id: "personal", Title: "Personal", UserId: userID1, TenantId: tenantFor(userID1, ""), Tags: []string{"home"}},
+		{Id: "legacy", Title: "Legacy", UserId: userID1, Tags: []string{"work"}},
+	}}
+
+	return service
+}
+
+// createCompanyContext creates a context with the claims of a user in a company, as the AuthWrapper does
+func createCompanyContext(userID, companyID string) context.Context {
+	return verifier.NewContext(context.Background(), &verifier.Claims{UserID: userID, CompanyID: companyID})
+}
+
+func TestTenants(t *testing.T) {
+
+	ids := func(tasks []*taskPb.Task) map[string]bool {
+		got := map[string]bool{}
+
+		for _, task := range tasks {
+			got[task.Id] = true
+		}
+
+		return got
+	}
+
+	t.Run("only the tasks in the callers company are got", func(t *testing.T) {
+		service := createTenantService()
+
+		response := taskPb.Response{}
+
+		err := service.Get(createCompanyContext(userID1, "acme"), &taskPb.Request{}, &response)
+
+		assertError(err, nil, t)
+
+		if got := ids(response.Tasks); len(got) != 2 || !got["acme"] || !got["legacy"] {
+			t.Errorf("wanted the acme and legacy tasks but got %v", got)
+		}
+	})
+
+	t.Run("tasks from before tenants are adopted by the first tenant to see them", func(t *testing.T) {
+		service := createTenantService()
+
+		service.Get(createCompanyContext(userID1, "acme"), &taskPb.Request{}, &taskPb.Response{})
+
+		response := taskPb.Response{}
+
+		service.Get(createCompanyContext(userID1, ""), &taskPb.Request{}, &response)
+
+		if got := ids(response.Tasks); len(got) != 1 || !got["personal"] {
+			t.Errorf("wanted only the personal task but got %v", got)
+		}
+	})
+
+	t.Run("tasks in other tenants can't be changed", func(t *testing.T) {
+		service := createTenantService()
+		ctx := createCompanyContext(userID1, "acme")
+
+		err := service.Update(ctx, &taskPb.UpdateTask{TaskId: "personal", Title: "Changed"}, &taskPb.Response{})
+		assertError(err, errTaskNotFound, t)
+
+		err = service.ChangeDailyDoStatus(ctx, &taskPb.DailyDoStatusRequest{TaskId: "personal", Status: true}, &taskPb.Response{})
+		assertError(err, errTaskNotFound, t)
+
+		err = service.CompleteTask(ctx, &taskPb.CompleteTaskRequest{TaskId: "personal"}, &taskPb.Response{})
+		assertError(err, errTaskNotFound, t)
+
+		err = service.Delete(ctx, &taskPb.DeleteTaskRequest{TaskId: "personal"}, &taskPb.Response{})
+		assertError(err, errTaskNotFound, t)
+
+		err = service.Restore(ctx, &taskPb.RestoreTaskRequest{TaskId: "globex"}, &taskPb.Response{})
+		assertError(err, errTaskNotFound, t)
+
+		err = service.AddChecklistItem(ctx, &taskPb.AddChecklistItemRequest{TaskId: "personal", Title: "Item"}, &taskPb.Response{})
+		assertError(err, errTaskNotFound, t)
+
+		if task, _ := service.repo.GetTask(&taskPb.Task{Id: "personal", UserId: userID1}); task.Title != "Personal" || task.Deleted {
+			t.Errorf("wanted the personal task to be unchanged but got %v", task)
+		}
+	})
+
+	t.Run("new tasks are in the callers tenant", func(t *testing.T) {
+		service := createTenantService()
+
+		response := taskPb.Response{}
+
+		err := service.Create(createCompanyContext(userID1, "acme"), &taskPb.CreateTask{Title: "New"}, &response)
+
+		assertError(err, nil, t)
+
+		if response.Task.TenantId != "acme" {
+			t.Errorf("wanted the task to be in acme but got %v", response.Task.TenantId)
+		}
+	})
+
+	t.Run("purging only purges the callers tenant", func(t *testing.T) {
+		service := createTenantService()
+
+		response := taskPb.Response{}
+
+		err := service.Purge(createCompanyContext(userID1, "acme"), &taskPb.PurgeRequest{}, &response)
+
+		assertError(err, nil, t)
+
+		if len(response.Tasks) != 0 {
+			t.Errorf("wanted the globex task to be kept but got %v", response.Tasks)
+		}
+
+		err = service.Purge(createCompanyContext(userID1, "globex"), &taskPb.PurgeRequest{}, &response)
+
+		assertError(err, nil, t)
+
+		if got := ids(response.Tasks); len(got) != 1 || !got["globex"] {
+			t.Errorf("wanted the globex task to be purged but got %v", got)
+		}
+	})
+
+	t.Run("only the tags in the callers tenant are counted", func(t *testing.T) {
+		service := createTenantService()
+
+		response := taskPb.ListTagsResponse{}
+
+		err := service.ListTags(createCompanyContext(userID1, ""), &taskPb.ListTagsRequest{}, &response)
+
+		assertError(err, nil, t)
+
+		if len(response.Tags) != 2 {
+			t.Errorf("wanted the home and legacy work tags but got %v", response.Tags)
+		}
+	})
+
+	t.Run("the repo sticks to its own tenant whichever is asked for", func(t *testing.T) {
+		service := createTenantService()
+
+		repo := &tenantRepository{service.repo, "globex"}
+
+		tags, err := repo.ListTags(tenantFor(userID1, ""), userID1)
+
+		assertError(err, nil, t)
+
+		for _, tag := range tags {
+			if tag.Tag == "home" {
+				t.Errorf("wanted only the globex tags but got %v", tags)
+			}
+		}
+	})
+
+	t.Run("a daily do left in another tenant is released for a new one", func(t *testing.T) {
+		service := createTenantService()
+		service.clock = &fakeClock{time.Now()}
+
+		fake := service.repo.(*fakeRepo)
+		fake.tasks[0].DailyDo = true
+		fake.dailyDos = map[string]string{userID1: "acme"}
+
+		ctx := createCompanyContext(userID1, "")
+
+		response := taskPb.Response{}
+
+		err := service.ChangeDailyDoStatus(ctx, &taskPb.DailyDoStatusRequest{TaskId: "personal", Status: true}, &response)
+
+		assertError(err, nil, t)
+
+		if fake.tasks[0].DailyDo || !response.Task.DailyDo {
+			t.Errorf("wanted the personal task to replace the acme daily do")
+		}
+	})
+}
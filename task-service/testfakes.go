@@ -3,12 +3,17 @@ package main
 import (
 	"errors"
 	"net/http"
+	"sort"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/micro/go-micro/client"
 	"github.com/micro/go-micro/metadata"
-	taskPb "github.com/willdot/go-do/task-service/proto/task"
-	authPb "github.com/willdot/go-do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/apierrors"
+	taskPb "github.com/willdot/Go-Do/task-service/proto/task"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/user-service/verifier"
 	"golang.org/x/net/context"
 )
 
@@ -19,27 +24,69 @@ const (
 
 var errFake = errors.New("This is a fake error message")
 
+var errFakeNoToken = apierrors.Unauthenticated("No token was found in the request metadata")
+
+var errFakeUnknownUser = apierrors.NotFound("User not found")
+
+// fakeServiceToken is the service token the fake auth service shares with the task service
+const fakeServiceToken = "fake service token"
+
 type fakeRepo struct {
 	// returnError is used as a flag to return a fake error
 	returnError bool
 	tasks       []*taskPb.Task
+	history     []*taskPb.DailyDoHistory
+	// dailyDos stands in for the daily_do table, mapping user ids to the id of their daily do task. mu guards it in
+	// place of the lightweight transactions used by the real repo
+	dailyDos map[string]string
+	mu       sync.Mutex
+	lists    []*taskPb.TaskList
+	// listOrder stands in for the task_list_item table, giving the ids of the tasks in each list in order
+	listOrder map[string][]string
 }
 
-func (f *fakeRepo) Get(userID string) ([]*taskPb.Task, error) {
+func (f *fakeRepo) Get(userID string, req *taskPb.Request, now int64) ([]*taskPb.Task, string, error) {
 
 	if f.returnError {
-		return nil, errFake
+		return nil, "", errFake
+	}
+
+	// the page token is just the index of the first task of the page
+	start := 0
+	if req.PageToken != "" {
+		var err error
+		start, err = strconv.Atoi(req.PageToken)
+
+		if err != nil {
+			return nil, "", errInvalidPageToken
+		}
 	}
 
 	var tasks []*taskPb.Task
 
 	for _, v := range f.tasks {
-		if v.UserId == userID {
+		if fakeQueryMatches(v, userID, req) && taskMatchesRequest(v, req, now) {
 			tasks = append(tasks, v)
 		}
 	}
 
-	return tasks, nil
+	if req.ListId != "" && req.SortOrder == taskPb.SortOrder_LIST_POSITION {
+		sortByListPosition(tasks, f.listPositions(req.ListId))
+	} else {
+		sortTasks(tasks, req.SortOrder)
+	}
+
+	if start >= len(tasks) {
+		return nil, "", nil
+	}
+
+	end := start + pageSizeForRequest(req)
+
+	if end >= len(tasks) {
+		return tasks[start:], "", nil
+	}
+
+	return tasks[start:end], strconv.Itoa(end), nil
 }
 
 func (f *fakeRepo) Create(task *taskPb.Task) error {
@@ -47,7 +94,23 @@ func (f *fakeRepo) Create(task *taskPb.Task) error {
 		return errFake
 	}
 
+	if task.DailyDo {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if f.hasOtherDailyDo(dailyDoUser(task), "") {
+			return errDailyDoAlreadyExists
+		}
+
+		f.setDailyDo(dailyDoUser(task), "123")
+	}
+
 	task.Id = "123"
+	task.Version = 1
+
+	if task.ListId != "" {
+		f.addToList(task.ListId, task.Id)
+	}
 
 	return nil
 }
@@ -74,12 +137,24 @@ func (f *fakeRepo) Update(task *taskPb.Task) error {
 		return errTaskUserIDNotMatched
 	}
 
+	if err := checkVersion(taskToUpdate, task); err != nil {
+		return err
+	}
+
 	taskToUpdate.Title = task.Title
 	taskToUpdate.Description = task.Description
+	taskToUpdate.DueDate = task.DueDate
+	taskToUpdate.Priority = task.Priority
+	taskToUpdate.Tags = task.Tags
+	taskToUpdate.Recurrence = task.Recurrence
 
 	return nil
 }
 
+func (f *fakeRepo) GetTask(task *taskPb.Task) (*taskPb.Task, error) {
+	return f.getOwnedTask(task)
+}
+
 func (f *fakeRepo) CompleteTask(task *taskPb.Task) error {
 	if f.returnError {
 		return errFake
@@ -102,8 +177,16 @@ func (f *fakeRepo) CompleteTask(task *taskPb.Task) error {
 		return errTaskUserIDNotMatched
 	}
 
+	if err := checkVersion(taskToComplete, task); err != nil {
+		return err
+	}
+
 	taskToComplete.CompletedDate = task.CompletedDate
-	taskToComplete.DailyDo = task.DailyDo
+
+	if task.CompletedDate != 0 {
+		taskToComplete.DailyDo = task.DailyDo
+		f.releaseDailyDo(dailyDoUser(taskToComplete), task.Id)
+	}
 
 	return nil
 }
@@ -131,8 +214,73 @@ func (f *fakeRepo) SetDailyDoStatus(task *taskPb.Task) error {
 		return errTaskUserIDNotMatched
 	}
 
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	userID := dailyDoUser(taskToUpdate)
+
+	if task.DailyDo && f.hasOtherDailyDo(userID, task.Id) {
+		return errDailyDoAlreadyExists
+	}
+
+	if err := checkVersion(taskToUpdate, task); err != nil {
+		return err
+	}
+
 	taskToUpdate.DailyDo = task.DailyDo
 
+	if task.DailyDo {
+		f.setDailyDo(userID, task.Id)
+	} else if f.dailyDos[userID] == task.Id {
+		delete(f.dailyDos, userID)
+	}
+
+	return nil
+}
+
+// hasOtherDailyDo checks if a user has a daily do other than the given task, either claimed through the fake or set
+// on one of the tasks the fake was created with. mu must be held
+func (f *fakeRepo) hasOtherDailyDo(userID, taskID string) bool {
+	if claimed, ok := f.dailyDos[userID]; ok && claimed != taskID {
+		return true
+	}
+
+	for _, v := range f.tasks {
+		if dailyDoUser(v) == userID && v.DailyDo && v.Id != taskID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setDailyDo claims the daily do for a user. mu must be held
+func (f *fakeRepo) setDailyDo(userID, taskID string) {
+	if f.dailyDos == nil {
+		f.dailyDos = map[string]string{}
+	}
+
+	f.dailyDos[userID] = taskID
+}
+
+func (f *fakeRepo) releaseDailyDo(userID, taskID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.dailyDos[userID] == taskID {
+		delete(f.dailyDos, userID)
+	}
+}
+
+// checkVersion does the same check as the lightweight transaction in the real repo, moving both tasks on a version
+func checkVersion(stored, task *taskPb.Task) error {
+	if stored.Version != task.Version {
+		return errTaskVersionConflict
+	}
+
+	stored.Version++
+	task.Version = stored.Version
+
 	return nil
 }
 
@@ -140,7 +288,7 @@ func (f *fakeRepo) GetDailyDoForUser(userID string) (*taskPb.Task, error) {
 
 	var dailyDo *taskPb.Task
 	for _, v := range f.tasks {
-		if v.DailyDo {
+		if dailyDoUser(v) == userID && v.DailyDo {
 			dailyDo = v
 			break
 		}
@@ -149,6 +297,563 @@ func (f *fakeRepo) GetDailyDoForUser(userID string) (*taskPb.Task, error) {
 	return dailyDo, nil
 }
 
+func (f *fakeRepo) Delete(task *taskPb.Task) error {
+	if f.returnError {
+		return errFake
+	}
+
+	var taskToDelete *taskPb.Task
+
+	for _, v := range f.tasks {
+		if v.Id == task.Id {
+			taskToDelete = v
+			break
+		}
+	}
+
+	if taskToDelete == nil {
+		return errTaskNotFound
+	}
+
+	if taskToDelete.UserId != task.UserId {
+		return errTaskUserIDNotMatched
+	}
+
+	taskToDelete.Deleted = true
+	taskToDelete.DeletedDate = task.DeletedDate
+	taskToDelete.DailyDo = false
+	f.releaseDailyDo(dailyDoUser(taskToDelete), task.Id)
+
+	return nil
+}
+
+func (f *fakeRepo) Restore(task *taskPb.Task) error {
+	if f.returnError {
+		return errFake
+	}
+
+	var taskToRestore *taskPb.Task
+
+	for _, v := range f.tasks {
+		if v.Id == task.Id {
+			taskToRestore = v
+			break
+		}
+	}
+
+	if taskToRestore == nil {
+		return errTaskNotFound
+	}
+
+	if taskToRestore.UserId != task.UserId {
+		return errTaskUserIDNotMatched
+	}
+
+	if !taskToRestore.Deleted {
+		return errTaskNotDeleted
+	}
+
+	taskToRestore.Deleted = false
+	taskToRestore.DeletedDate = 0
+
+	return nil
+}
+
+func (f *fakeRepo) Purge(tenantID, userID string, deletedBefore int64) ([]*taskPb.Task, error) {
+	if f.returnError {
+		return nil, errFake
+	}
+
+	var purged []*taskPb.Task
+	var remaining []*taskPb.Task
+
+	for _, v := range f.tasks {
+		if v.UserId == userID && inTenant(v, tenantID) && v.Deleted && v.DeletedDate < deletedBefore {
+			purged = append(purged, v)
+			continue
+		}
+
+		remaining = append(remaining, v)
+	}
+
+	f.tasks = remaining
+
+	return purged, nil
+}
+
+func (f *fakeRepo) SetDailyDoHistory(history *taskPb.DailyDoHistory) error {
+	if f.returnError {
+		return errFake
+	}
+
+	for i, v := range f.history {
+		if v.UserId == history.UserId && v.Day == history.Day {
+			f.history[i] = history
+			return nil
+		}
+	}
+
+	f.history = append(f.history, history)
+
+	return nil
+}
+
+func (f *fakeRepo) GetDailyDoHistoryForDay(userID, day string) (*taskPb.DailyDoHistory, error) {
+	if f.returnError {
+		return nil, errFake
+	}
+
+	for _, v := range f.history {
+		if v.UserId == userID && v.Day == day {
+			return v, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (f *fakeRepo) DeleteDailyDoHistory(userID, day string) error {
+	if f.returnError {
+		return errFake
+	}
+
+	for i, v := range f.history {
+		if v.UserId == userID && v.Day == day {
+			f.history = append(f.history[:i], f.history[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeRepo) GetDailyDoHistory(userID, from, to string) ([]*taskPb.DailyDoHistory, error) {
+	if f.returnError {
+		return nil, errFake
+	}
+
+	var history []*taskPb.DailyDoHistory
+
+	for _, v := range f.history {
+		if v.UserId != userID || (from != "" && v.Day < from) || (to != "" && v.Day > to) {
+			continue
+		}
+
+		history = append(history, v)
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Day > history[j].Day
+	})
+
+	return history, nil
+}
+
+func (f *fakeRepo) GetDailyDos() ([]*taskPb.Task, error) {
+	if f.returnError {
+		return nil, errFake
+	}
+
+	var dailyDos []*taskPb.Task
+
+	for _, v := range f.tasks {
+		if v.DailyDo {
+			dailyDos = append(dailyDos, v)
+		}
+	}
+
+	return dailyDos, nil
+}
+
+func (f *fakeRepo) GetChecklist(task *taskPb.Task) ([]*taskPb.ChecklistItem, error) {
+	existingTask, err := f.getOwnedTask(task)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return existingTask.Checklist, nil
+}
+
+func (f *fakeRepo) AddChecklistItem(task *taskPb.Task, item *taskPb.ChecklistItem) error {
+	existingTask, err := f.getOwnedTask(task)
+
+	if err != nil {
+		return err
+	}
+
+	item.Position = 0
+	if len(existingTask.Checklist) > 0 {
+		item.Position = existingTask.Checklist[len(existingTask.Checklist)-1].Position + 1
+	}
+
+	// positions only ever go up, so they can be used as unique ids
+	item.Id = strconv.Itoa(int(item.Position))
+
+	existingTask.Checklist = append(existingTask.Checklist, item)
+
+	return nil
+}
+
+func (f *fakeRepo) SetChecklistItemStatus(task *taskPb.Task, item *taskPb.ChecklistItem) error {
+	existingTask, err := f.getOwnedTask(task)
+
+	if err != nil {
+		return err
+	}
+
+	existingItem := findChecklistItem(existingTask.Checklist, item.Id)
+
+	if existingItem == nil {
+		return errChecklistItemNotFound
+	}
+
+	existingItem.Completed = item.Completed
+
+	return nil
+}
+
+func (f *fakeRepo) ReorderChecklist(task *taskPb.Task, itemIDs []string) error {
+	existingTask, err := f.getOwnedTask(task)
+
+	if err != nil {
+		return err
+	}
+
+	if !checklistMatchesIDs(existingTask.Checklist, itemIDs) {
+		return errChecklistItemsNotMatched
+	}
+
+	for position, id := range itemIDs {
+		findChecklistItem(existingTask.Checklist, id).Position = int32(position)
+	}
+
+	sortChecklist(existingTask.Checklist)
+
+	return nil
+}
+
+func (f *fakeRepo) RemoveChecklistItem(task *taskPb.Task, itemID string) error {
+	existingTask, err := f.getOwnedTask(task)
+
+	if err != nil {
+		return err
+	}
+
+	for i, item := range existingTask.Checklist {
+		if item.Id == itemID {
+			existingTask.Checklist = append(existingTask.Checklist[:i], existingTask.Checklist[i+1:]...)
+			return nil
+		}
+	}
+
+	return errChecklistItemNotFound
+}
+
+func (f *fakeRepo) ListTags(tenantID, userID string) ([]*taskPb.TagCount, error) {
+	if f.returnError {
+		return nil, errFake
+	}
+
+	var tasks []*taskPb.Task
+
+	for _, v := range f.tasks {
+		if v.UserId == userID && inTenant(v, tenantID) {
+			tasks = append(tasks, v)
+		}
+	}
+
+	return countTags(tasks), nil
+}
+
+func (f *fakeRepo) AdoptTask(taskID, tenantID string) (bool, error) {
+	if f.returnError {
+		return false, errFake
+	}
+
+	for _, v := range f.tasks {
+		if v.Id == taskID {
+			if v.TenantId != "" {
+				return false, nil
+			}
+
+			v.TenantId = tenantID
+			return true, nil
+		}
+	}
+
+	return false, errTaskNotFound
+}
+
+func (f *fakeRepo) GetTaskByID(id string) (*taskPb.Task, error) {
+	if f.returnError {
+		return nil, errFake
+	}
+
+	for _, v := range f.tasks {
+		if v.Id == id {
+			return v, nil
+		}
+	}
+
+	return nil, errTaskNotFound
+}
+
+func (f *fakeRepo) AssignTask(task *taskPb.Task) error {
+	existingTask, err := f.getOwnedTask(task)
+
+	if err != nil {
+		return err
+	}
+
+	previous := dailyDoUser(existingTask)
+
+	if err := checkVersion(existingTask, task); err != nil {
+		return err
+	}
+
+	existingTask.AssigneeId = task.AssigneeId
+
+	if existingTask.DailyDo {
+		existingTask.DailyDo = false
+		task.DailyDo = false
+		f.releaseDailyDo(previous, task.Id)
+	}
+
+	return nil
+}
+
+func (f *fakeRepo) CreateList(list *taskPb.TaskList) error {
+	if f.returnError {
+		return errFake
+	}
+
+	list.Id = "list" + strconv.Itoa(len(f.lists)+1)
+	list.Members = []*taskPb.ListMember{{UserId: list.OwnerId, Permission: taskPb.ListPermission_LIST_PERMISSION_EDIT}}
+
+	stored := *list
+	stored.Members = append([]*taskPb.ListMember{}, list.Members...)
+	f.lists = append(f.lists, &stored)
+
+	return nil
+}
+
+func (f *fakeRepo) GetList(id string) (*taskPb.TaskList, error) {
+	if f.returnError {
+		return nil, errFake
+	}
+
+	for _, v := range f.lists {
+		if v.Id == id {
+			return v, nil
+		}
+	}
+
+	return nil, errListNotFound
+}
+
+func (f *fakeRepo) GetListsForUser(userID string) ([]*taskPb.TaskList, error) {
+	if f.returnError {
+		return nil, errFake
+	}
+
+	var lists []*taskPb.TaskList
+
+	for _, v := range f.lists {
+		if listPermission(v, userID) != taskPb.ListPermission_LIST_PERMISSION_NONE {
+			lists = append(lists, v)
+		}
+	}
+
+	return lists, nil
+}
+
+func (f *fakeRepo) SetListMember(listID string, member *taskPb.ListMember) error {
+	list, err := f.GetList(listID)
+
+	if err != nil {
+		return err
+	}
+
+	for _, v := range list.Members {
+		if v.UserId == member.UserId {
+			v.Permission = member.Permission
+			return nil
+		}
+	}
+
+	list.Members = append(list.Members, member)
+
+	return nil
+}
+
+func (f *fakeRepo) RemoveListMember(listID, userID string) error {
+	list, err := f.GetList(listID)
+
+	if err != nil {
+		return err
+	}
+
+	for i, v := range list.Members {
+		if v.UserId == userID {
+			list.Members = append(list.Members[:i], list.Members[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeRepo) MoveTask(task *taskPb.Task) error {
+	existingTask, err := f.getOwnedTask(task)
+
+	if err != nil {
+		return err
+	}
+
+	previous := existingTask.ListId
+
+	if err := checkVersion(existingTask, task); err != nil {
+		return err
+	}
+
+	existingTask.ListId = task.ListId
+
+	if previous != task.ListId {
+		f.removeFromList(previous, task.Id)
+		f.addToList(task.ListId, task.Id)
+	}
+
+	return nil
+}
+
+func (f *fakeRepo) UpdateList(list *taskPb.TaskList) error {
+	existing, err := f.GetList(list.Id)
+
+	if err != nil {
+		return err
+	}
+
+	existing.Name = list.Name
+
+	return nil
+}
+
+func (f *fakeRepo) SetListArchived(listID string, archived bool) error {
+	list, err := f.GetList(listID)
+
+	if err != nil {
+		return err
+	}
+
+	list.Archived = archived
+
+	return nil
+}
+
+func (f *fakeRepo) DeleteList(listID string) error {
+	if _, err := f.GetList(listID); err != nil {
+		return err
+	}
+
+	for _, v := range f.tasks {
+		if v.ListId == listID {
+			v.ListId = ""
+		}
+	}
+
+	for i, v := range f.lists {
+		if v.Id == listID {
+			f.lists = append(f.lists[:i], f.lists[i+1:]...)
+			break
+		}
+	}
+
+	delete(f.listOrder, listID)
+
+	return nil
+}
+
+func (f *fakeRepo) ReorderList(listID string, taskIDs []string) error {
+	if _, err := f.GetList(listID); err != nil {
+		return err
+	}
+
+	order, ok := reorderedList(f.listPositions(listID), taskIDs)
+
+	if !ok {
+		return errListTasksNotMatched
+	}
+
+	f.listOrder[listID] = order
+
+	return nil
+}
+
+// listPositions gives the position of each task in a list, like the partition of the list in the real repo
+func (f *fakeRepo) listPositions(listID string) map[string]int {
+	positions := map[string]int{}
+
+	for position, id := range f.listOrder[listID] {
+		positions[id] = position
+	}
+
+	return positions
+}
+
+func (f *fakeRepo) addToList(listID, taskID string) {
+	if listID == "" {
+		return
+	}
+
+	if f.listOrder == nil {
+		f.listOrder = map[string][]string{}
+	}
+
+	f.listOrder[listID] = append(f.listOrder[listID], taskID)
+}
+
+func (f *fakeRepo) removeFromList(listID, taskID string) {
+	for i, id := range f.listOrder[listID] {
+		if id == taskID {
+			f.listOrder[listID] = append(f.listOrder[listID][:i], f.listOrder[listID][i+1:]...)
+			return
+		}
+	}
+}
+
+// fakeQueryMatches does the same as the where clause the real repo picks for a request
+func fakeQueryMatches(task *taskPb.Task, userID string, req *taskPb.Request) bool {
+	if req.ListId != "" {
+		return task.ListId == req.ListId
+	}
+
+	if req.AssignedToMe {
+		return task.AssigneeId == userID
+	}
+
+	return task.UserId == userID
+}
+
+// getOwnedTask finds a task and checks it belongs to the user of the task given
+func (f *fakeRepo) getOwnedTask(task *taskPb.Task) (*taskPb.Task, error) {
+	if f.returnError {
+		return nil, errFake
+	}
+
+	for _, v := range f.tasks {
+		if v.Id == task.Id {
+			if v.UserId != task.UserId {
+				return nil, errTaskUserIDNotMatched
+			}
+
+			return v, nil
+		}
+	}
+
+	return nil, errTaskNotFound
+}
+
 func setTaskAsDailyDo() {
 
 	fakeTask1.DailyDo = true
@@ -200,11 +905,11 @@ func createService(repoReturnError, userHandlerReturnError, userIDInTokenMatches
 
 	tasks = append(tasks, &fakeTask1, &fakeTask2, &fakeTask3, &fakeTask4)
 
-	fakeRepo := &fakeRepo{repoReturnError, tasks}
+	fakeRepo := &fakeRepo{returnError: repoReturnError, tasks: tasks}
 
-	fakeAuthClient := &fakeUserHandler{userHandlerReturnError, userIDInTokenMatchesTask}
+	fakeAuthClient := &fakeUserHandler{userHandlerReturnError, userIDInTokenMatchesTask, nil}
 
-	service := taskHandler{fakeRepo, fakeAuthClient}
+	service := taskHandler{fakeRepo, fakeAuthClient, time.Hour, realClock{}, fakeServiceToken, ""}
 
 	return service
 }
@@ -237,14 +942,59 @@ type fakeUserHandler struct {
 	// UseIdMatches is a flag to set the user id found inside the JWT. Then in the repo when a comparison between the user id
 	// of the task that has been sent in the request doesn't match the user id in the JWT, an error can be returned
 	userIDMatches bool
+	// users are returned from Get so that tests can give users settings such as a timezone
+	users map[string]*authPb.User
+}
+
+// fakeClock is a clock that is always at the time it's been set to
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
 }
 
 func (u *fakeUserHandler) Create(ctx context.Context, req *authPb.User, opts ...client.CallOption) (*authPb.Response, error) {
 	return nil, nil
 }
 
+// Get needs the service token or a callers token, like the auth service
 func (u *fakeUserHandler) Get(ctx context.Context, req *authPb.User, opts ...client.CallOption) (*authPb.Response, error) {
-	return nil, nil
+
+	meta, _ := metadata.FromContext(ctx)
+	_, hasClaims := verifier.ClaimsFromContext(ctx)
+
+	if serviceToken := meta[verifier.ServiceTokenKey]; serviceToken != "" {
+		if serviceToken != fakeServiceToken {
+			return nil, errFake
+		}
+	} else if meta["Token"] == "" && meta["token"] == "" && !hasClaims {
+		return nil, errFakeNoToken
+	} else if caller := u.caller(ctx); req.Id != caller {
+		// Users can only get themselves
+		return nil, errFakeUnknownUser
+	}
+
+	if user, ok := u.users[req.Id]; ok {
+		return &authPb.Response{User: user}, nil
+	}
+
+	return &authPb.Response{User: &authPb.User{Id: req.Id}}, nil
+}
+
+// caller is the user whose token is in the context, which is the user the fake gives from ValidateToken if the
+// claims haven't been verified
+func (u *fakeUserHandler) caller(ctx context.Context) string {
+	if claims, ok := verifier.ClaimsFromContext(ctx); ok {
+		return claims.UserID
+	}
+
+	if !u.userIDMatches {
+		return userID2
+	}
+
+	return userID1
 }
 
 func (u *fakeUserHandler) GetAll(ctx context.Context, req *authPb.Request, opts ...client.CallOption) (*authPb.Response, error) {
@@ -252,7 +1002,7 @@ func (u *fakeUserHandler) GetAll(ctx context.Context, req *authPb.Request, opts
 
 }
 
-func (u *fakeUserHandler) Update(ctx context.Context, req *authPb.User, opts ...client.CallOption) (*authPb.Response, error) {
+func (u *fakeUserHandler) Update(ctx context.Context, req *authPb.UpdateUserRequest, opts ...client.CallOption) (*authPb.Response, error) {
 	return nil, nil
 }
 
@@ -283,3 +1033,94 @@ func (u *fakeUserHandler) ValidateToken(ctx context.Context, req *authPb.Token,
 func (u *fakeUserHandler) ChangePassword(ctx context.Context, req *authPb.PasswordChange, opts ...client.CallOption) (*authPb.Token, error) {
 	return nil, nil
 }
+
+func (u *fakeUserHandler) Refresh(ctx context.Context, req *authPb.Token, opts ...client.CallOption) (*authPb.Token, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) Logout(ctx context.Context, req *authPb.Token, opts ...client.CallOption) (*authPb.Token, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) RevokeAllSessions(ctx context.Context, req *authPb.Token, opts ...client.CallOption) (*authPb.Token, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) GetJWKS(ctx context.Context, req *authPb.JWKSRequest, opts ...client.CallOption) (*authPb.JWKS, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) RequestPasswordReset(ctx context.Context, req *authPb.PasswordResetRequest, opts ...client.CallOption) (*authPb.Response, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) ResetPassword(ctx context.Context, req *authPb.PasswordReset, opts ...client.CallOption) (*authPb.Token, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) VerifyEmail(ctx context.Context, req *authPb.VerifyEmailRequest, opts ...client.CallOption) (*authPb.Response, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) ResendVerification(ctx context.Context, req *authPb.ResendVerificationRequest, opts ...client.CallOption) (*authPb.Response, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) UnlockUser(ctx context.Context, req *authPb.UnlockUserRequest, opts ...client.CallOption) (*authPb.Response, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) SetRole(ctx context.Context, req *authPb.SetRoleRequest, opts ...client.CallOption) (*authPb.Response, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) CreateCompany(ctx context.Context, req *authPb.CreateCompanyRequest, opts ...client.CallOption) (*authPb.Response, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) InviteMember(ctx context.Context, req *authPb.InviteMemberRequest, opts ...client.CallOption) (*authPb.Response, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) AcceptInvite(ctx context.Context, req *authPb.AcceptInviteRequest, opts ...client.CallOption) (*authPb.Response, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) RemoveMember(ctx context.Context, req *authPb.RemoveMemberRequest, opts ...client.CallOption) (*authPb.Response, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) ListMembers(ctx context.Context, req *authPb.ListMembersRequest, opts ...client.CallOption) (*authPb.Response, error) {
+
+	if u.returnError {
+		return nil, errFake
+	}
+
+	var members []*authPb.User
+
+	for _, user := range u.users {
+		members = append(members, user)
+	}
+
+	return &authPb.Response{Users: members}, nil
+}
+
+func (u *fakeUserHandler) TransferOwnership(ctx context.Context, req *authPb.TransferOwnershipRequest, opts ...client.CallOption) (*authPb.Response, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) EnrollTOTP(ctx context.Context, req *authPb.EnrollTOTPRequest, opts ...client.CallOption) (*authPb.TOTPEnrollment, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) ConfirmTOTP(ctx context.Context, req *authPb.TOTPCode, opts ...client.CallOption) (*authPb.RecoveryCodes, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) GenerateRecoveryCodes(ctx context.Context, req *authPb.TOTPCode, opts ...client.CallOption) (*authPb.RecoveryCodes, error) {
+	return nil, nil
+}
+
+func (u *fakeUserHandler) VerifyMFA(ctx context.Context, req *authPb.MFAVerification, opts ...client.CallOption) (*authPb.Token, error) {
+	return nil, nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import "github.com/willdot/Go-Do/validation"
+
+const (
+	maxTitleLength       = 200
+	maxDescriptionLength = 5000
+	maxTags              = 20
+	maxTagLength         = 50
+	maxRecurrenceLength  = 200
+)
+
+// newValidator creates the validator with the rules for the requests to each endpoint, which run before the handlers
+func newValidator() *validation.Validator {
+	v := validation.New()
+
+	taskID := validation.Field("taskId", validation.Required())
+	tags := validation.Field("tags", validation.MaxItems(maxTags), validation.Each(validation.MaxLength(maxTagLength)))
+
+	v.Register("TaskService.Create",
+		validation.Field("title", validation.Required(), validation.MaxLength(maxTitleLength)),
+		validation.Field("description", validation.MaxLength(maxDescriptionLength)),
+		tags,
+		validation.Field("recurrence", validation.MaxLength(maxRecurrenceLength)),
+	)
+
+	v.Register("TaskService.Update",
+		taskID,
+		validation.Field("title", validation.MaxLength(maxTitleLength)),
+		validation.Field("description", validation.MaxLength(maxDescriptionLength)),
+		tags,
+		validation.Field("recurrence", validation.MaxLength(maxRecurrenceLength)),
+	)
+
+	v.Register("TaskService.ChangeDailyDoStatus", taskID)
+	v.Register("TaskService.CompleteTask", taskID)
+	v.Register("TaskService.Delete", taskID)
+	v.Register("TaskService.Restore", taskID)
+
+	v.Register("TaskService.AddChecklistItem",
+		taskID,
+		validation.Field("title", validation.Required(), validation.MaxLength(maxTitleLength)),
+	)
+
+	v.Register("TaskService.ReorderChecklist", taskID)
+	v.Register("TaskService.ToggleChecklistItem", taskID, validation.Field("itemId", validation.Required()))
+	v.Register("TaskService.RemoveChecklistItem", taskID, validation.Field("itemId", validation.Required()))
+
+	listID := validation.Field("listId", validation.Required())
+
+	v.Register("TaskService.CreateList", validation.Field("name", validation.Required(), validation.MaxLength(maxTitleLength)))
+	v.Register("TaskService.ShareList", listID, validation.Field("userId", validation.Required()))
+	v.Register("TaskService.UnshareList", listID)
+	v.Register("TaskService.AssignTask", taskID)
+	v.Register("TaskService.UpdateList", listID, validation.Field("name", validation.Required(), validation.MaxLength(maxTitleLength)))
+	v.Register("TaskService.ArchiveList", listID)
+	v.Register("TaskService.DeleteList", listID)
+	v.Register("TaskService.MoveTask", taskID)
+	v.Register("TaskService.ReorderList", listID)
+
+	return v
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	taskPb "github.com/willdot/Go-Do/task-service/proto/task"
+	"github.com/willdot/Go-Do/validation"
+)
+
+func TestValidationRules(t *testing.T) {
+	v := newValidator()
+
+	manyTags := make([]string, maxTags+1)
+
+	for i := range manyTags {
+		manyTags[i] = "tag"
+	}
+
+	tests := []struct {
+		name     string
+		endpoint string
+		req      interface{}
+		want     []int32
+	}{
+		{"valid task", "TaskService.Create", &taskPb.CreateTask{Title: "title", Tags: []string{"work"}}, nil},
+		{"empty title", "TaskService.Create", &taskPb.CreateTask{Title: " "}, []int32{validation.CodeRequired}},
+		{"long description", "TaskService.Create", &taskPb.CreateTask{Title: "title", Description: strings.Repeat("a", maxDescriptionLength+1)}, []int32{validation.CodeTooLong}},
+		{"too many tags", "TaskService.Create", &taskPb.CreateTask{Title: "title", Tags: manyTags}, []int32{validation.CodeTooMany}},
+		{"long tag", "TaskService.Create", &taskPb.CreateTask{Title: "title", Tags: []string{strings.Repeat("a", maxTagLength+1)}}, []int32{validation.CodeTooLong}},
+		{"update without a title", "TaskService.Update", &taskPb.UpdateTask{TaskId: "1"}, nil},
+		{"update without a task", "TaskService.Update", &taskPb.UpdateTask{Title: "title"}, []int32{validation.CodeRequired}},
+		{"checklist item without a title", "TaskService.AddChecklistItem", &taskPb.AddChecklistItemRequest{TaskId: "1"}, []int32{validation.CodeRequired}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := v.Validate(tt.endpoint, tt.req)
+
+			if len(errs) != len(tt.want) {
+				t.Fatalf("wanted %v errors but got %v", len(tt.want), errs)
+			}
+
+			for i, err := range errs {
+				if err.Code != tt.want[i] {
+					t.Errorf("wanted code %v but got %v", tt.want[i], err)
+				}
+			}
+		})
+	}
+}
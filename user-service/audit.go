@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// Audit events and their outcomes
+const (
+	eventLogin          = "login"
+	eventChangePassword = "change_password"
+	eventUnlockUser     = "unlock_user"
+	eventVerifyMFA      = "verify_mfa"
+	eventEnableMFA      = "enable_mfa"
+	eventRecoveryCodes  = "generate_recovery_codes"
+	eventSetRole        = "set_role"
+
+	eventCreateCompany     = "create_company"
+	eventInviteMember      = "invite_member"
+	eventJoinCompany       = "join_company"
+	eventRemoveMember      = "remove_member"
+	eventTransferOwnership = "transfer_ownership"
+
+	outcomeSuccess          = "success"
+	outcomeWrongPassword    = "wrong_password"
+	outcomeUnknownUser      = "unknown_user"
+	outcomeThrottled        = "throttled"
+	outcomeEmailNotVerified = "email_not_verified"
+	outcomeDenied           = "denied"
+	outcomeMFARequired      = "mfa_required"
+	outcomeWrongCode        = "wrong_code"
+)
+
+// AuditEvent is something that happened to an account that should be kept track of, such as an attempt to log in. It
+// never has passwords, tokens or any other credentials in it
+type AuditEvent struct {
+	Time    time.Time `json:"time"`
+	Event   string    `json:"event"`
+	Outcome string    `json:"outcome"`
+	Email   string    `json:"email,omitempty"`
+	UserID  string    `json:"userId,omitempty"`
+	IP      string    `json:"ip,omitempty"`
+	// ActorID is the user that did something to another users account, such as the admin that unlocked it
+	ActorID string `json:"actorId,omitempty"`
+	// CompanyID is the company that a user joined or left, or that was changed
+	CompanyID string `json:"companyId,omitempty"`
+}
+
+// AuditLog writes audit events as lines of JSON, so that they can be searched
+type AuditLog struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewAuditLog creates an audit log that writes to out
+func NewAuditLog(out io.Writer) *AuditLog {
+	return &AuditLog{out: out}
+}
+
+// Log writes an event, using the current time if the event doesn't have one
+func (a *AuditLog) Log(event AuditEvent) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	line, err := json.Marshal(event)
+
+	if err != nil {
+		log.Println("Error writing audit event: ", err)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, err := a.out.Write(append(line, '\n')); err != nil {
+		log.Println("Error writing audit event: ", err)
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"crypto/subtle"
+	"log"
+	"strings"
+
+	"github.com/micro/go-micro/metadata"
+	"github.com/micro/go-micro/server"
+	"github.com/willdot/Go-Do/apierrors"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/user-service/verifier"
+	"golang.org/x/net/context"
+)
+
+var errNotAdmin = apierrors.Forbidden("Only admins can do that")
+
+var errNoToken = apierrors.Unauthenticated("No token was found in the request metadata")
+
+var errRoleNotAllowed = apierrors.Forbidden("Company admins can only give users the user or company-admin role")
+
+var errInvalidServiceToken = apierrors.Unauthenticated("Service token is not valid")
+
+var (
+	// allRoles can call any endpoint that only needs a token
+	allRoles = []string{verifier.RoleUser, verifier.RoleCompanyAdmin, verifier.RoleSystemAdmin}
+	// adminRoles can call the endpoints that manage other users
+	adminRoles = []string{verifier.RoleCompanyAdmin, verifier.RoleSystemAdmin}
+)
+
+// endpointRoles are the roles that can call each endpoint that needs a token in the Token metadata. Endpoints that
+// aren't listed don't need one, such as logging in. The handlers still check which users the caller can see
+var endpointRoles = map[string][]string{
+	"Auth.Get":                   allRoles,
+	"Auth.GetAll":                adminRoles,
+	"Auth.Update":                allRoles,
+	"Auth.UnlockUser":            adminRoles,
+	"Auth.SetRole":               adminRoles,
+	"Auth.EnrollTOTP":            allRoles,
+	"Auth.ConfirmTOTP":           allRoles,
+	"Auth.GenerateRecoveryCodes": allRoles,
+	"Auth.CreateCompany":         allRoles,
+	"Auth.InviteMember":          adminRoles,
+	"Auth.AcceptInvite":          allRoles,
+	"Auth.RemoveMember":          allRoles,
+	"Auth.ListMembers":           allRoles,
+	"Auth.TransferOwnership":     adminRoles,
+}
+
+// serviceEndpoints can also be called by other services with the SERVICE_TOKEN in the Service-Token metadata, for any
+// user. The task service uses them to get the settings of users when there isn't a caller, or the caller is someone else
+var serviceEndpoints = map[string]bool{
+	"Auth.Get": true,
+}
+
+type serviceCallKey struct{}
+
+// AuthorisationWrapper checks that the token in a request has a role that can call the endpoint, and adds its claims
+// to the context so that the handlers don't need to check it again
+func (u *userHandler) AuthorisationWrapper(fn server.HandlerFunc) server.HandlerFunc {
+	return func(ctx context.Context, req server.Request, resp interface{}) error {
+		if serviceToken, ok := serviceTokenFromContext(ctx); ok && serviceEndpoints[req.Endpoint()] {
+			if !u.validServiceToken(serviceToken) {
+				return errInvalidServiceToken
+			}
+
+			return fn(context.WithValue(ctx, serviceCallKey{}, true), req, resp)
+		}
+
+		roles, ok := endpointRoles[req.Endpoint()]
+
+		if !ok {
+			return fn(ctx, req, resp)
+		}
+
+		claims, err := u.claimsFromContext(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		if !hasRole(roles, claims.Role) {
+			return errNotAdmin
+		}
+
+		return fn(verifier.NewContext(ctx, claims), req, resp)
+	}
+}
+
+// claimsFromContext gets the claims of the token in the request metadata, which the AuthorisationWrapper has usually
+// already verified
+func (u *userHandler) claimsFromContext(ctx context.Context) (*CustomClaims, error) {
+
+	if claims, ok := verifier.ClaimsFromContext(ctx); ok {
+		return claims, nil
+	}
+
+	meta, _ := metadata.FromContext(ctx)
+
+	if meta["Token"] == "" {
+		return nil, errNoToken
+	}
+
+	return u.verifyToken(meta["Token"])
+}
+
+// serviceTokenFromContext gets the service token in the request metadata, if there is one
+func serviceTokenFromContext(ctx context.Context) (string, bool) {
+	meta, _ := metadata.FromContext(ctx)
+
+	return meta[verifier.ServiceTokenKey], meta[verifier.ServiceTokenKey] != ""
+}
+
+// validServiceToken reports if a service token is the one the auth service was given. No service can call it if it
+// wasn't given one
+func (u *userHandler) validServiceToken(serviceToken string) bool {
+	return u.serviceToken != "" && subtle.ConstantTimeCompare([]byte(u.serviceToken), []byte(serviceToken)) == 1
+}
+
+// isServiceCall reports if the AuthorisationWrapper let the request through because it came from another service
+func isServiceCall(ctx context.Context) bool {
+	serviceCall, _ := ctx.Value(serviceCallKey{}).(bool)
+
+	return serviceCall
+}
+
+// userFromContext gets the user that the token in the request metadata belongs to
+func (u *userHandler) userFromContext(ctx context.Context) (*authPb.User, error) {
+
+	claims, err := u.claimsFromContext(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return u.repo.Get(claims.UserID)
+}
+
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// roleOf is the role of a user, which is a user for users from before roles were added
+func roleOf(user *authPb.User) string {
+	if user.Role == "" {
+		return verifier.RoleUser
+	}
+
+	return user.Role
+}
+
+// isAdmin reports if a user can manage other users
+func isAdmin(user *authPb.User) bool {
+	return hasRole(adminRoles, roleOf(user))
+}
+
+// canManage reports if the caller can see and change a user. Users can manage themselves, company admins can manage
+// the users in their company other than system admins, and system admins can manage everyone
+func canManage(caller, user *authPb.User) bool {
+	if caller.Id == user.Id {
+		return true
+	}
+
+	switch roleOf(caller) {
+	case verifier.RoleSystemAdmin:
+		return true
+	case verifier.RoleCompanyAdmin:
+		return caller.CompanyId != "" && caller.CompanyId == user.CompanyId && roleOf(user) != verifier.RoleSystemAdmin
+	}
+
+	return false
+}
+
+// withoutPassword copies a user without their password hash, so that it can be put in a response
+func withoutPassword(user *authPb.User) *authPb.User {
+	copied := *user
+	copied.Password = ""
+
+	return &copied
+}
+
+// withoutPasswords copies users without their password hashes, so that they can be put in a response
+func withoutPasswords(users []*authPb.User) []*authPb.User {
+	copied := make([]*authPb.User, len(users))
+
+	for i, user := range users {
+		copied[i] = withoutPassword(user)
+	}
+
+	return copied
+}
+
+// parseEmails parses a comma separated list of emails
+func parseEmails(list string) []string {
+	var emails []string
+
+	for _, email := range strings.Split(list, ",") {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			emails = append(emails, email)
+		}
+	}
+
+	return emails
+}
+
+// promoteAdmins makes the users with the emails system admins, so that there's someone to give other users roles.
+// Emails without a user are skipped
+func promoteAdmins(repo Repository, emails []string) {
+	for _, email := range emails {
+		user, err := repo.GetByEmail(email)
+
+		if err != nil {
+			log.Printf("Error making %s a system admin: %v", email, err)
+			continue
+		}
+
+		if roleOf(user) == verifier.RoleSystemAdmin {
+			continue
+		}
+
+		if err := repo.SetRole(user.Id, verifier.RoleSystemAdmin); err != nil {
+			log.Printf("Error making %s a system admin: %v", email, err)
+		}
+	}
+}
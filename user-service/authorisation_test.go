@@ -0,0 +1,464 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/micro/go-micro/server"
+	"golang.org/x/net/context"
+
+	"github.com/willdot/Go-Do/apierrors"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/user-service/verifier"
+)
+
+type fakeRequest struct {
+	server.Request
+	endpoint string
+}
+
+func (r *fakeRequest) Endpoint() string {
+	return r.endpoint
+}
+
+// createCompanyService creates a fake service with a system admin, and an admin and a user in each of two companies
+func createCompanyService() userHandler {
+	service := createService(false)
+
+	repo := service.repo.(*fakeRepo)
+	repo.users = []*authPb.User{
+		{Id: "1", Email: "system@fake.com", Role: verifier.RoleSystemAdmin},
+		{Id: "2", Email: "admin@acme.com", Company: "acme", CompanyId: "acme", Role: verifier.RoleCompanyAdmin},
+		{Id: "3", Email: "user@acme.com", Company: "acme", CompanyId: "acme", Role: verifier.RoleUser, Password: "hash"},
+		{Id: "4", Email: "admin@globex.com", Company: "globex", CompanyId: "globex", Role: verifier.RoleCompanyAdmin},
+		{Id: "5", Email: "user@globex.com", Company: "globex", CompanyId: "globex"},
+	}
+	repo.companies = map[string]*authPb.Company{
+		"acme":   {Id: "acme", Name: "Acme", OwnerId: "2"},
+		"globex": {Id: "globex", Name: "Globex", OwnerId: "4"},
+	}
+
+	return service
+}
+
+// createSessionContext logs a user in and creates a context with their access token in the metadata
+func createSessionContext(service userHandler, userID string, t *testing.T) context.Context {
+	user, _ := service.repo.Get(userID)
+
+	token, err := service.tokenService.NewSession(user)
+
+	if err != nil {
+		t.Fatalf("error creating session: %v", err)
+	}
+
+	return createTokenContext(token.Token)
+}
+
+func TestAuthorisationWrapper(t *testing.T) {
+
+	service := createCompanyService()
+
+	var handlerClaims *CustomClaims
+
+	wrapped := service.AuthorisationWrapper(func(ctx context.Context, req server.Request, resp interface{}) error {
+		handlerClaims, _ = verifier.ClaimsFromContext(ctx)
+		return nil
+	})
+
+	t.Run("endpoints that don't need a token are let through", func(t *testing.T) {
+		err := wrapped(createContext(), &fakeRequest{endpoint: "Auth.Auth"}, &authPb.Token{})
+
+		assertError(err, nil, t)
+	})
+
+	t.Run("a token is needed", func(t *testing.T) {
+		err := wrapped(createContext(), &fakeRequest{endpoint: "Auth.Get"}, &authPb.Response{})
+
+		assertError(err, errNoToken, t)
+	})
+
+	t.Run("an invalid token isn't let through", func(t *testing.T) {
+		err := wrapped(createTokenContext("not a token"), &fakeRequest{endpoint: "Auth.Get"}, &authPb.Response{})
+
+		assertCode(err, apierrors.CodeUnauthenticated, t)
+	})
+
+	t.Run("users can't call admin endpoints", func(t *testing.T) {
+		handlerClaims = nil
+
+		err := wrapped(createSessionContext(service, "3", t), &fakeRequest{endpoint: "Auth.GetAll"}, &authPb.Response{})
+
+		assertError(err, errNotAdmin, t)
+
+		if handlerClaims != nil {
+			t.Errorf("wanted the handler not to be called")
+		}
+	})
+
+	t.Run("the claims are given to the handler", func(t *testing.T) {
+		err := wrapped(createSessionContext(service, "2", t), &fakeRequest{endpoint: "Auth.GetAll"}, &authPb.Response{})
+
+		assertError(err, nil, t)
+
+		if handlerClaims == nil || handlerClaims.UserID != "2" || handlerClaims.Role != verifier.RoleCompanyAdmin {
+			t.Errorf("wanted the claims of the company admin but got %+v", handlerClaims)
+		}
+	})
+}
+
+func TestServiceToken(t *testing.T) {
+
+	service := createCompanyService()
+
+	wrapped := service.AuthorisationWrapper(func(ctx context.Context, req server.Request, resp interface{}) error {
+		if req.Endpoint() == "Auth.Get" {
+			return service.Get(ctx, &authPb.User{Id: "3"}, resp.(*authPb.Response))
+		}
+
+		return service.GetAll(ctx, &authPb.Request{}, resp.(*authPb.Response))
+	})
+
+	t.Run("services can get any user without a token", func(t *testing.T) {
+		response := authPb.Response{}
+
+		err := wrapped(verifier.NewServiceContext(createContext(), fakeServiceToken), &fakeRequest{endpoint: "Auth.Get"}, &response)
+
+		assertError(err, nil, t)
+
+		if response.User == nil || response.User.Id != "3" || response.User.Password != "" {
+			t.Errorf("wanted user 3 without their password but got %v", response.User)
+		}
+	})
+
+	t.Run("the service token has to match", func(t *testing.T) {
+		err := wrapped(verifier.NewServiceContext(createContext(), "guessed"), &fakeRequest{endpoint: "Auth.Get"}, &authPb.Response{})
+
+		assertError(err, errInvalidServiceToken, t)
+	})
+
+	t.Run("the service token only works on service endpoints", func(t *testing.T) {
+		err := wrapped(verifier.NewServiceContext(createContext(), fakeServiceToken), &fakeRequest{endpoint: "Auth.GetAll"}, &authPb.Response{})
+
+		assertError(err, errNoToken, t)
+	})
+
+	t.Run("services can't call the auth service if it doesn't have a service token", func(t *testing.T) {
+		service := createCompanyService()
+		service.serviceToken = ""
+
+		wrapped := service.AuthorisationWrapper(func(ctx context.Context, req server.Request, resp interface{}) error {
+			return service.Get(ctx, &authPb.User{Id: "3"}, resp.(*authPb.Response))
+		})
+
+		err := wrapped(verifier.NewServiceContext(createContext(), ""), &fakeRequest{endpoint: "Auth.Get"}, &authPb.Response{})
+
+		assertError(err, errNoToken, t)
+
+		err = wrapped(verifier.NewServiceContext(createContext(), fakeServiceToken), &fakeRequest{endpoint: "Auth.Get"}, &authPb.Response{})
+
+		assertError(err, errInvalidServiceToken, t)
+	})
+}
+
+func TestGetPermissions(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		caller string
+		user   string
+		want   error
+	}{
+		{"users can get themselves", "3", "3", nil},
+		{"users can't get other users", "3", "2", errUnknownUser},
+		{"company admins can get users in their company", "2", "3", nil},
+		{"company admins can't get users in other companies", "2", "5", errUnknownUser},
+		{"company admins can't get system admins", "2", "1", errUnknownUser},
+		{"system admins can get anyone", "1", "5", nil},
+		{"unknown users aren't found", "1", "6", errUnknownUser},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := createCompanyService()
+
+			response := authPb.Response{}
+
+			err := service.Get(createCallerContext(tt.caller), &authPb.User{Id: tt.user}, &response)
+
+			assertError(err, tt.want, t)
+
+			if tt.want == nil && response.User.Id != tt.user {
+				t.Errorf("wanted user %v but got %v", tt.user, response.User)
+			}
+		})
+	}
+}
+
+func TestGetAllPermissions(t *testing.T) {
+
+	ids := func(users []*authPb.User) []string {
+		var got []string
+
+		for _, user := range users {
+			got = append(got, user.Id)
+		}
+
+		return got
+	}
+
+	tests := []struct {
+		name    string
+		caller  string
+		company string
+		want    []string
+	}{
+		{"system admins get everyone", "1", "", []string{"1", "2", "3", "4", "5"}},
+		{"company admins get their company", "2", "acme", []string{"2", "3"}},
+		{"company admins without a company only get themselves", "2", "", []string{"2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := createCompanyService()
+
+			caller, _ := service.repo.Get(tt.caller)
+			caller.CompanyId = tt.company
+
+			response := authPb.Response{}
+
+			err := service.GetAll(createCallerContext(tt.caller), &authPb.Request{}, &response)
+
+			assertError(err, nil, t)
+
+			if got := ids(response.Users); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("wanted users %v but got %v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("users can't get everyone", func(t *testing.T) {
+		service := createCompanyService()
+
+		err := service.GetAll(createCallerContext("3"), &authPb.Request{}, &authPb.Response{})
+
+		assertError(err, errNotAdmin, t)
+	})
+}
+
+func TestUpdatePermissions(t *testing.T) {
+
+	t.Run("company admins can update users in their company", func(t *testing.T) {
+		service := createCompanyService()
+
+		err := service.Update(createCallerContext("2"), &authPb.UpdateUserRequest{User: &authPb.User{Id: "3", Name: "New name"}, UpdateMask: []string{"name"}}, &authPb.Response{})
+
+		assertError(err, nil, t)
+	})
+
+	t.Run("users can't update other users", func(t *testing.T) {
+		service := createCompanyService()
+
+		err := service.Update(createCallerContext("3"), &authPb.UpdateUserRequest{User: &authPb.User{Id: "2", Name: "New name"}, UpdateMask: []string{"name"}}, &authPb.Response{})
+
+		assertError(err, errUnknownUser, t)
+	})
+
+	t.Run("changing the company name doesn't move users to another company", func(t *testing.T) {
+		service := createCompanyService()
+
+		response := authPb.Response{}
+
+		err := service.Update(createCallerContext("3"), &authPb.UpdateUserRequest{User: &authPb.User{Id: "3", Company: "globex"}, UpdateMask: []string{"company"}}, &response)
+
+		assertError(err, nil, t)
+
+		if response.User.Company != "globex" || response.User.CompanyId != "acme" {
+			t.Errorf("wanted only the company name to change but got %v", response.User)
+		}
+	})
+
+	t.Run("company admins can't reach another company by taking its name", func(t *testing.T) {
+		service := createCompanyService()
+
+		err := service.Update(createCallerContext("2"), &authPb.UpdateUserRequest{User: &authPb.User{Id: "2", Company: "globex"}, UpdateMask: []string{"company"}}, &authPb.Response{})
+
+		assertError(err, nil, t)
+
+		err = service.Get(createCallerContext("2"), &authPb.User{Id: "5"}, &authPb.Response{})
+
+		assertError(err, errUnknownUser, t)
+
+		err = service.Update(createCallerContext("2"), &authPb.UpdateUserRequest{User: &authPb.User{Id: "5", Name: "New name"}, UpdateMask: []string{"name"}}, &authPb.Response{})
+
+		assertError(err, errUnknownUser, t)
+
+		response := authPb.Response{}
+
+		err = service.GetAll(createCallerContext("2"), &authPb.Request{}, &response)
+
+		assertError(err, nil, t)
+
+		for _, user := range response.Users {
+			if user.CompanyId != "acme" {
+				t.Errorf("wanted only the users in acme but got %v", user)
+			}
+		}
+	})
+
+	t.Run("the company a user is in can't be updated", func(t *testing.T) {
+		service := createCompanyService()
+
+		err := service.Update(createCallerContext("2"), &authPb.UpdateUserRequest{User: &authPb.User{Id: "2", CompanyId: "globex"}, UpdateMask: []string{"companyId"}}, &authPb.Response{})
+
+		assertError(err, errUnknownUpdateField, t)
+
+		if user, _ := service.repo.Get("2"); user.CompanyId != "acme" {
+			t.Errorf("wanted the user to still be in acme but got %v", user.CompanyId)
+		}
+	})
+}
+
+func TestPasswordsAreNotReturned(t *testing.T) {
+
+	service := createCompanyService()
+
+	assertNoPassword := func(users []*authPb.User, t *testing.T) {
+		t.Helper()
+
+		for _, user := range users {
+			if user == nil || user.Password != "" {
+				t.Errorf("wanted a user without a password but got %v", user)
+			}
+		}
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		response := authPb.Response{}
+
+		err := service.Create(createContext(), &authPb.User{Email: "new@acme.com", Password: "password1"}, &response)
+
+		assertError(err, nil, t)
+		assertNoPassword([]*authPb.User{response.User}, t)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		response := authPb.Response{}
+
+		service.Get(createCallerContext("3"), &authPb.User{Id: "3"}, &response)
+
+		assertNoPassword([]*authPb.User{response.User}, t)
+	})
+
+	t.Run("GetAll", func(t *testing.T) {
+		response := authPb.Response{}
+
+		service.GetAll(createCallerContext("1"), &authPb.Request{}, &response)
+
+		assertNoPassword(response.Users, t)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		response := authPb.Response{}
+
+		service.Update(createCallerContext("3"), &authPb.UpdateUserRequest{User: &authPb.User{Id: "3"}, UpdateMask: []string{"name"}}, &response)
+
+		assertNoPassword([]*authPb.User{response.User}, t)
+	})
+
+	t.Run("the stored user keeps their password", func(t *testing.T) {
+		if user, _ := service.repo.Get("3"); user.Password != "hash" {
+			t.Errorf("wanted the stored password to be kept but got %v", user.Password)
+		}
+	})
+}
+
+func TestSetRole(t *testing.T) {
+
+	t.Run("new users are users", func(t *testing.T) {
+		service := createCompanyService()
+
+		response := authPb.Response{}
+
+		service.Create(createContext(), &authPb.User{Email: "new@acme.com", Password: "password1", Role: verifier.RoleSystemAdmin}, &response)
+
+		if response.User.Role != verifier.RoleUser {
+			t.Errorf("wanted a new user to be a user but got %v", response.User.Role)
+		}
+	})
+
+	t.Run("company admins can make users in their company admins", func(t *testing.T) {
+		service := createCompanyService()
+
+		response := authPb.Response{}
+
+		err := service.SetRole(createCallerContext("2"), &authPb.SetRoleRequest{UserId: "3", Role: verifier.RoleCompanyAdmin}, &response)
+
+		assertError(err, nil, t)
+
+		if user, _ := service.repo.Get("3"); user.Role != verifier.RoleCompanyAdmin || response.User.Role != verifier.RoleCompanyAdmin {
+			t.Errorf("wanted the user to be a company admin but got %v", user.Role)
+		}
+	})
+
+	t.Run("company admins can't make system admins", func(t *testing.T) {
+		service := createCompanyService()
+
+		err := service.SetRole(createCallerContext("2"), &authPb.SetRoleRequest{UserId: "3", Role: verifier.RoleSystemAdmin}, &authPb.Response{})
+
+		assertError(err, errRoleNotAllowed, t)
+	})
+
+	t.Run("company admins can't change users in other companies", func(t *testing.T) {
+		service := createCompanyService()
+
+		err := service.SetRole(createCallerContext("2"), &authPb.SetRoleRequest{UserId: "5", Role: verifier.RoleCompanyAdmin}, &authPb.Response{})
+
+		assertError(err, errUnknownUser, t)
+	})
+
+	t.Run("users can't change roles", func(t *testing.T) {
+		service := createCompanyService()
+
+		err := service.SetRole(createCallerContext("3"), &authPb.SetRoleRequest{UserId: "3", Role: verifier.RoleSystemAdmin}, &authPb.Response{})
+
+		assertError(err, errNotAdmin, t)
+	})
+
+	t.Run("tokens with the old role can't be used but refreshing gets the new one", func(t *testing.T) {
+		service := createCompanyService()
+
+		user, _ := service.repo.Get("3")
+		tokens, _ := service.tokenService.NewSession(user)
+
+		err := service.SetRole(createCallerContext("1"), &authPb.SetRoleRequest{UserId: "3", Role: verifier.RoleCompanyAdmin}, &authPb.Response{})
+
+		assertError(err, nil, t)
+
+		err = service.ValidateToken(createContext(), &authPb.Token{Token: tokens.Token}, &authPb.Token{})
+
+		assertError(err, errTokenPasswordNotValid, t)
+
+		refreshed := authPb.Token{}
+
+		err = service.Refresh(createContext(), &authPb.Token{RefreshToken: tokens.RefreshToken}, &refreshed)
+
+		assertError(err, nil, t)
+
+		claims, _ := service.tokenService.Decode(refreshed.Token)
+
+		if claims.Role != verifier.RoleCompanyAdmin {
+			t.Errorf("wanted the new role in the token but got %v", claims.Role)
+		}
+	})
+}
+
+func TestPromoteAdmins(t *testing.T) {
+
+	service := createCompanyService()
+
+	promoteAdmins(service.repo, parseEmails(" user@acme.com, nobody@fake.com,, "))
+
+	if user, _ := service.repo.Get("3"); user.Role != verifier.RoleSystemAdmin {
+		t.Errorf("wanted the user to be a system admin but got %v", user.Role)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/willdot/Go-Do/apierrors"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/user-service/verifier"
+)
+
+var errCompanyNotFound = apierrors.NotFound("Company not found")
+
+var errAlreadyInCompany = apierrors.Conflict("You're already a member of a company, leave it first")
+
+var errAlreadyMember = apierrors.Conflict("The user is already a member of the company")
+
+var errNotMember = apierrors.NotFound("The user isn't a member of the company")
+
+var errInviteNotFound = apierrors.NotFound("There's no invite to the company for your email, or it has expired")
+
+var errOwnerCantLeave = apierrors.Conflict("The owner of a company can't leave it, transfer ownership first")
+
+var errNotCompanyAdmin = apierrors.Forbidden("Only the admins of the company can do that")
+
+var errNotOwner = apierrors.Forbidden("Only the owner of the company can do that")
+
+var errOwnershipContended = apierrors.Conflict("The owner of the company changed at the same time, try again")
+
+// CompanyInvite is an invite for an email to join a company. It's accepted by the user with that email once they've
+// logged in, so it doesn't need a secret
+type CompanyInvite struct {
+	CompanyID string
+	Email     string
+	InvitedBy string
+}
+
+// CompanyService manages companies and their members. Changing the company of a user moves them on to a new token
+// generation, as their tokens carry their company, so they need to refresh their token afterwards
+type CompanyService struct {
+	repo   Repository
+	mailer Mailer
+	// inviteLifetime is how long an invite can be accepted for
+	inviteLifetime time.Duration
+}
+
+// Create creates a company owned by the user, who becomes its first admin
+func (s *CompanyService) Create(owner *authPb.User, name string) (*authPb.Company, error) {
+
+	if owner.CompanyId != "" {
+		return nil, errAlreadyInCompany
+	}
+
+	company := authPb.Company{Name: name, OwnerId: owner.Id, CreatedAt: time.Now().Unix()}
+
+	err := s.repo.CreateCompany(&company)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.join(owner, company.Id, verifier.RoleCompanyAdmin)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &company, nil
+}
+
+// Get gets a company that the caller is a member of, or any company for system admins. The caller's own company is
+// used if there's no id
+func (s *CompanyService) Get(caller *authPb.User, id string) (*authPb.Company, error) {
+
+	if id == "" {
+		id = caller.CompanyId
+	}
+
+	if id == "" || (id != caller.CompanyId && roleOf(caller) != verifier.RoleSystemAdmin) {
+		return nil, errCompanyNotFound
+	}
+
+	return s.repo.GetCompany(id)
+}
+
+// Administered gets a company that the caller is an admin of
+func (s *CompanyService) Administered(caller *authPb.User, id string) (*authPb.Company, error) {
+
+	company, err := s.Get(caller, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !isAdmin(caller) {
+		return nil, errNotCompanyAdmin
+	}
+
+	return company, nil
+}
+
+// Invite emails an invite to join a company
+func (s *CompanyService) Invite(company *authPb.Company, inviter *authPb.User, email string) error {
+
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	existing, err := s.repo.GetByEmail(email)
+
+	if err == nil && existing.CompanyId == company.Id {
+		return errAlreadyMember
+	}
+
+	if err != nil && err != errUnknownUser {
+		return err
+	}
+
+	invite := CompanyInvite{company.Id, email, inviter.Id}
+
+	err = s.repo.CreateInvite(&invite, s.inviteLifetime)
+
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(Email{
+		To:      email,
+		Subject: fmt.Sprintf("You've been invited to join %s on Go-Do", company.Name),
+		Body: fmt.Sprintf("%s has invited you to join %s on Go-Do. Log in with this email address, or sign up if you "+
+			"don't have an account, and accept the invite to company %s.\n\nThe invite can be accepted in the next %v.\n",
+			inviter.Email, company.Name, company.Id, s.inviteLifetime),
+	})
+}
+
+// Accept makes the user a member of a company they've been invited to. Their email has to be verified, as the invite
+// was sent to it
+func (s *CompanyService) Accept(user *authPb.User, companyID string) (*authPb.Company, error) {
+
+	if !user.EmailVerified {
+		return nil, errEmailNotVerified
+	}
+
+	if user.CompanyId != "" {
+		return nil, errAlreadyInCompany
+	}
+
+	_, err := s.repo.GetInvite(companyID, strings.ToLower(user.Email))
+
+	if err != nil {
+		return nil, err
+	}
+
+	company, err := s.repo.GetCompany(companyID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.join(user, company.Id, verifier.RoleUser)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return company, s.repo.DeleteInvite(companyID, strings.ToLower(user.Email))
+}
+
+// Remove takes a user out of a company, making them a user again. The owner can't be removed
+func (s *CompanyService) Remove(company *authPb.Company, user *authPb.User) error {
+
+	if user.CompanyId != company.Id {
+		return errNotMember
+	}
+
+	if user.Id == company.OwnerId {
+		return errOwnerCantLeave
+	}
+
+	return s.join(user, "", verifier.RoleUser)
+}
+
+// Members gets the users in a company
+func (s *CompanyService) Members(company *authPb.Company) ([]*authPb.User, error) {
+	return s.repo.GetByCompanyID(company.Id)
+}
+
+// TransferOwnership makes another member the owner of a company, and an admin of it if they weren't already
+func (s *CompanyService) TransferOwnership(company *authPb.Company, user *authPb.User) error {
+
+	if user.CompanyId != company.Id {
+		return errNotMember
+	}
+
+	transferred, err := s.repo.TransferOwnership(company.Id, company.OwnerId, user.Id)
+
+	if err != nil {
+		return err
+	}
+
+	if !transferred {
+		return errOwnershipContended
+	}
+
+	company.OwnerId = user.Id
+
+	if roleOf(user) == verifier.RoleUser {
+		return s.join(user, company.Id, verifier.RoleCompanyAdmin)
+	}
+
+	return nil
+}
+
+// join sets the company of a user and their role in it. System admins stay system admins whatever company they're in
+func (s *CompanyService) join(user *authPb.User, companyID, role string) error {
+
+	if roleOf(user) == verifier.RoleSystemAdmin {
+		role = verifier.RoleSystemAdmin
+	}
+
+	err := s.repo.SetCompany(user.Id, companyID, role)
+
+	if err != nil {
+		return err
+	}
+
+	user.CompanyId = companyID
+	user.Role = role
+
+	return nil
+}
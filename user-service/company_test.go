@@ -0,0 +1,307 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/user-service/verifier"
+)
+
+func TestCreateCompany(t *testing.T) {
+
+	t.Run("the creator owns the company and is an admin of it", func(t *testing.T) {
+		service := createCompanyService()
+
+		response := authPb.Response{}
+
+		err := service.CreateCompany(createCallerContext("1"), &authPb.CreateCompanyRequest{Name: "Initech"}, &response)
+
+		assertError(err, nil, t)
+
+		if response.Company == nil || response.Company.OwnerId != "1" || response.Company.Name != "Initech" {
+			t.Fatalf("wanted a company owned by the caller but got %v", response.Company)
+		}
+
+		if user, _ := service.repo.Get("1"); user.CompanyId != response.Company.Id || user.Role != verifier.RoleSystemAdmin {
+			t.Errorf("wanted the system admin to join the company and keep their role but got %v", user)
+		}
+	})
+
+	t.Run("users become company admins", func(t *testing.T) {
+		service := createCompanyService()
+		service.repo.(*fakeRepo).users = append(service.repo.(*fakeRepo).users, &authPb.User{Id: "6", Email: "new@fake.com", Role: verifier.RoleUser})
+
+		err := service.CreateCompany(createCallerContext("6"), &authPb.CreateCompanyRequest{Name: "Initech"}, &authPb.Response{})
+
+		assertError(err, nil, t)
+
+		if user, _ := service.repo.Get("6"); user.Role != verifier.RoleCompanyAdmin {
+			t.Errorf("wanted the creator to be a company admin but got %v", user.Role)
+		}
+	})
+
+	t.Run("members of a company can't create another", func(t *testing.T) {
+		service := createCompanyService()
+
+		err := service.CreateCompany(createCallerContext("3"), &authPb.CreateCompanyRequest{Name: "Initech"}, &authPb.Response{})
+
+		assertError(err, errAlreadyInCompany, t)
+	})
+}
+
+func TestInvites(t *testing.T) {
+
+	// invite creates a user without a company and invites them to acme
+	invite := func(service userHandler, t *testing.T) {
+		t.Helper()
+
+		repo := service.repo.(*fakeRepo)
+		repo.users = append(repo.users, &authPb.User{Id: "6", Email: "new@fake.com", Role: verifier.RoleUser, EmailVerified: true})
+
+		err := service.InviteMember(createCallerContext("2"), &authPb.InviteMemberRequest{Email: "new@fake.com"}, &authPb.Response{})
+
+		assertError(err, nil, t)
+	}
+
+	t.Run("invites are emailed", func(t *testing.T) {
+		service := createCompanyService()
+
+		invite(service, t)
+
+		sent := service.companyService.mailer.(*MemoryMailer).Sent()
+
+		if len(sent) != 1 || sent[0].To != "new@fake.com" || !strings.Contains(sent[0].Body, "Acme") {
+			t.Errorf("wanted an invite to Acme to be emailed but got %v", sent)
+		}
+	})
+
+	t.Run("invited users can join the company", func(t *testing.T) {
+		service := createCompanyService()
+
+		invite(service, t)
+
+		response := authPb.Response{}
+
+		err := service.AcceptInvite(createCallerContext("6"), &authPb.AcceptInviteRequest{CompanyId: "acme"}, &response)
+
+		assertError(err, nil, t)
+
+		if user, _ := service.repo.Get("6"); user.CompanyId != "acme" || user.Role != verifier.RoleUser {
+			t.Errorf("wanted the user to be a member of acme but got %v", user)
+		}
+
+		err = service.AcceptInvite(createCallerContext("6"), &authPb.AcceptInviteRequest{CompanyId: "acme"}, &response)
+
+		assertError(err, errAlreadyInCompany, t)
+	})
+
+	t.Run("users can't join companies they weren't invited to", func(t *testing.T) {
+		service := createCompanyService()
+
+		invite(service, t)
+
+		err := service.AcceptInvite(createCallerContext("6"), &authPb.AcceptInviteRequest{CompanyId: "globex"}, &authPb.Response{})
+
+		assertError(err, errInviteNotFound, t)
+	})
+
+	t.Run("the email has to be verified to accept an invite", func(t *testing.T) {
+		service := createCompanyService()
+
+		invite(service, t)
+
+		user, _ := service.repo.Get("6")
+		user.EmailVerified = false
+
+		err := service.AcceptInvite(createCallerContext("6"), &authPb.AcceptInviteRequest{CompanyId: "acme"}, &authPb.Response{})
+
+		assertError(err, errEmailNotVerified, t)
+	})
+
+	t.Run("only admins can invite", func(t *testing.T) {
+		service := createCompanyService()
+
+		err := service.InviteMember(createCallerContext("3"), &authPb.InviteMemberRequest{Email: "new@fake.com"}, &authPb.Response{})
+
+		assertError(err, errNotCompanyAdmin, t)
+	})
+
+	t.Run("company admins can't invite to other companies", func(t *testing.T) {
+		service := createCompanyService()
+
+		err := service.InviteMember(createCallerContext("2"), &authPb.InviteMemberRequest{CompanyId: "globex", Email: "new@fake.com"}, &authPb.Response{})
+
+		assertError(err, errCompanyNotFound, t)
+	})
+
+	t.Run("members can't be invited again", func(t *testing.T) {
+		service := createCompanyService()
+
+		err := service.InviteMember(createCallerContext("2"), &authPb.InviteMemberRequest{Email: "user@acme.com"}, &authPb.Response{})
+
+		assertError(err, errAlreadyMember, t)
+	})
+
+	t.Run("members can't be invited again with their email in another case", func(t *testing.T) {
+		service := createCompanyService()
+
+		err := service.InviteMember(createCallerContext("2"), &authPb.InviteMemberRequest{Email: " User@Acme.com"}, &authPb.Response{})
+
+		assertError(err, errAlreadyMember, t)
+	})
+}
+
+func TestRemoveMember(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		caller string
+		user   string
+		want   error
+	}{
+		{"members can leave", "3", "", nil},
+		{"admins can remove members", "2", "3", nil},
+		{"system admins can remove members of any company", "1", "5", nil},
+		{"users can't remove other members", "3", "2", errNotCompanyAdmin},
+		{"admins can't remove members of other companies", "2", "5", errNotMember},
+		{"the owner can't leave", "2", "", errOwnerCantLeave},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := createCompanyService()
+
+			request := authPb.RemoveMemberRequest{UserId: tt.user}
+
+			if tt.caller == "1" {
+				request.CompanyId = "globex"
+			}
+
+			err := service.RemoveMember(createCallerContext(tt.caller), &request, &authPb.Response{})
+
+			assertError(err, tt.want, t)
+
+			removed := tt.user
+
+			if removed == "" {
+				removed = tt.caller
+			}
+
+			user, _ := service.repo.Get(removed)
+
+			if tt.want == nil && (user.CompanyId != "" || user.Role != verifier.RoleUser) {
+				t.Errorf("wanted the user to be removed from the company but got %v", user)
+			}
+
+			if tt.want != nil && user.CompanyId == "" {
+				t.Errorf("wanted the user to stay in the company")
+			}
+		})
+	}
+}
+
+func TestListMembers(t *testing.T) {
+
+	t.Run("members can list their company", func(t *testing.T) {
+		service := createCompanyService()
+
+		response := authPb.Response{}
+
+		err := service.ListMembers(createCallerContext("3"), &authPb.ListMembersRequest{}, &response)
+
+		assertError(err, nil, t)
+
+		if len(response.Users) != 2 || response.Company.Id != "acme" {
+			t.Errorf("wanted the 2 members of acme but got %v", response.Users)
+		}
+	})
+
+	t.Run("members can't list other companies", func(t *testing.T) {
+		service := createCompanyService()
+
+		err := service.ListMembers(createCallerContext("3"), &authPb.ListMembersRequest{CompanyId: "globex"}, &authPb.Response{})
+
+		assertError(err, errCompanyNotFound, t)
+	})
+
+	t.Run("users without a company have nothing to list", func(t *testing.T) {
+		service := createCompanyService()
+
+		err := service.ListMembers(createCallerContext("1"), &authPb.ListMembersRequest{}, &authPb.Response{})
+
+		assertError(err, errCompanyNotFound, t)
+	})
+}
+
+func TestTransferOwnership(t *testing.T) {
+
+	t.Run("the owner can transfer the company to a member", func(t *testing.T) {
+		service := createCompanyService()
+
+		response := authPb.Response{}
+
+		err := service.TransferOwnership(createCallerContext("2"), &authPb.TransferOwnershipRequest{UserId: "3"}, &response)
+
+		assertError(err, nil, t)
+
+		if response.Company.OwnerId != "3" {
+			t.Errorf("wanted the new owner to be 3 but got %v", response.Company.OwnerId)
+		}
+
+		if user, _ := service.repo.Get("3"); user.Role != verifier.RoleCompanyAdmin {
+			t.Errorf("wanted the new owner to be a company admin but got %v", user.Role)
+		}
+
+		err = service.RemoveMember(createCallerContext("3"), &authPb.RemoveMemberRequest{UserId: "2"}, &authPb.Response{})
+
+		assertError(err, nil, t)
+	})
+
+	t.Run("only the owner can transfer the company", func(t *testing.T) {
+		service := createCompanyService()
+
+		user, _ := service.repo.Get("3")
+		user.Role = verifier.RoleCompanyAdmin
+
+		err := service.TransferOwnership(createCallerContext("3"), &authPb.TransferOwnershipRequest{UserId: "3"}, &authPb.Response{})
+
+		assertError(err, errNotOwner, t)
+	})
+
+	t.Run("the company can only be transferred to a member", func(t *testing.T) {
+		service := createCompanyService()
+
+		err := service.TransferOwnership(createCallerContext("2"), &authPb.TransferOwnershipRequest{UserId: "5"}, &authPb.Response{})
+
+		assertError(err, errNotMember, t)
+	})
+}
+
+func TestCompanyInTokens(t *testing.T) {
+
+	service := createCompanyService()
+
+	user, _ := service.repo.Get("3")
+	tokens, _ := service.tokenService.NewSession(user)
+
+	response := authPb.Token{}
+
+	err := service.ValidateToken(createContext(), &authPb.Token{Token: tokens.Token}, &response)
+
+	assertError(err, nil, t)
+
+	if response.CompanyId != "acme" {
+		t.Errorf("wanted the company of the user but got %v", response.CompanyId)
+	}
+
+	t.Run("leaving a company invalidates the tokens for it", func(t *testing.T) {
+		err := service.RemoveMember(createCallerContext("3"), &authPb.RemoveMemberRequest{}, &authPb.Response{})
+
+		assertError(err, nil, t)
+
+		err = service.ValidateToken(createContext(), &authPb.Token{Token: tokens.Token}, &authPb.Token{})
+
+		assertError(err, errTokenPasswordNotValid, t)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gocql/gocql"
@@ -50,8 +51,100 @@ func init() {
 
 	keySpaceMeta, _ := Session.KeyspaceMetadata("go_do")
 
+	// Companies are created first, as existing users are linked to them when the user table is updated
+	if _, exists := keySpaceMeta.Tables["company"]; exists != true {
+		Session.Query("CREATE TABLE company (id UUID, name text, ownerId text, createdAt timestamp, PRIMARY KEY(id))").Exec()
+	}
+
+	if _, exists := keySpaceMeta.Tables["company_invite"]; exists != true {
+		Session.Query("CREATE TABLE company_invite (email text, companyId text, invitedBy text, createdAt timestamp, PRIMARY KEY(email, companyId))").Exec()
+	}
+
 	if _, exists := keySpaceMeta.Tables["user"]; exists != true {
-		Session.Query("CREATE TABLE user (id UUID, name text, email text, password text, company text, PRIMARY KEY(id))").Exec()
+		Session.Query("CREATE TABLE user (id UUID, name text, email text, password text, company text, timezone text, carryOverDailyDo Boolean, tokenGeneration int, emailVerified Boolean, mfaEnabled Boolean, totpSecret text, totpLastStep bigint, role text, companyId text, PRIMARY KEY(id))").Exec()
 		Session.Query("create index UserEmailIndex on user(email)").Exec()
+		Session.Query("create index UserCompanyIdIndex on user(companyId)").Exec()
+	} else {
+		// The table was created by an older version of the service, so add any columns that have been added since
+		addColumnIfMissing(keySpaceMeta, "user", "timezone", "text")
+		addColumnIfMissing(keySpaceMeta, "user", "carryOverDailyDo", "Boolean")
+		addColumnIfMissing(keySpaceMeta, "user", "tokenGeneration", "int")
+
+		// Users created before emails were verified are trusted, rather than stopped from logging in
+		if addColumnIfMissing(keySpaceMeta, "user", "emailVerified", "Boolean") {
+			verifyExistingUsers()
+		}
+
+		addColumnIfMissing(keySpaceMeta, "user", "mfaEnabled", "Boolean")
+		addColumnIfMissing(keySpaceMeta, "user", "totpSecret", "text")
+		addColumnIfMissing(keySpaceMeta, "user", "totpLastStep", "bigint")
+
+		// Users from before roles were added are users, as an empty role is read as one
+		addColumnIfMissing(keySpaceMeta, "user", "role", "text")
+
+		// Users are no longer looked up by the company name they gave, as anyone can give any name
+		Session.Query("drop index if exists UserCompanyIndex").Exec()
+
+		// Users from before companies were added aren't in one, as the company name they gave doesn't show that users with
+		// the same name work together. They join a company by creating it or being invited to it
+		addColumnIfMissing(keySpaceMeta, "user", "companyId", "text")
+		Session.Query("create index if not exists UserCompanyIdIndex on user(companyId)").Exec()
+	}
+
+	if _, exists := keySpaceMeta.Tables["session"]; exists != true {
+		Session.Query("CREATE TABLE session (id UUID, userId text, refreshTokenHash text, expiresAt timestamp, revoked Boolean, PRIMARY KEY(id))").Exec()
+		Session.Query("create index SessionUserIdIndex on session(userId)").Exec()
+	}
+
+	for _, purpose := range []tokenPurpose{purposePasswordReset, purposeEmailVerification, purposeMFAChallenge} {
+		if _, exists := keySpaceMeta.Tables[string(purpose)]; exists != true {
+			Session.Query(fmt.Sprintf("CREATE TABLE %s (id UUID, userId text, tokenHash text, expiresAt timestamp, PRIMARY KEY(id))", purpose)).Exec()
+		}
+	}
+
+	if _, exists := keySpaceMeta.Tables["verification_email"]; exists != true {
+		Session.Query("CREATE TABLE verification_email (userId text, sentAt timestamp, PRIMARY KEY(userId))").Exec()
+	}
+
+	if _, exists := keySpaceMeta.Tables["recovery_code"]; exists != true {
+		Session.Query("CREATE TABLE recovery_code (userId text, codeHash text, PRIMARY KEY(userId, codeHash))").Exec()
+	}
+
+	if _, exists := keySpaceMeta.Tables["login_attempts"]; exists != true {
+		Session.Query("CREATE TABLE login_attempts (id text, failures int, lastFailure timestamp, PRIMARY KEY(id))").Exec()
+	}
+}
+
+// addColumnIfMissing adds a column to an existing table if the table doesn't already have it, returning true if it was
+// added
+func addColumnIfMissing(keySpaceMeta *gocql.KeyspaceMetadata, table, column, columnType string) bool {
+	if _, exists := keySpaceMeta.Tables[table].Columns[strings.ToLower(column)]; exists {
+		return false
+	}
+
+	err := Session.Query(fmt.Sprintf("ALTER TABLE %s ADD %s %s", table, column, columnType)).Exec()
+
+	if err != nil {
+		fmt.Printf("error adding column %s to %s: %v", column, table, err)
+		return false
+	}
+
+	return true
+}
+
+// verifyExistingUsers marks the emails of all users as verified
+func verifyExistingUsers() {
+	var id gocql.UUID
+
+	iterable := Session.Query("SELECT id FROM user").Iter()
+
+	for iterable.Scan(&id) {
+		if err := Session.Query("UPDATE user SET emailVerified = ? WHERE id = ?", true, id).Exec(); err != nil {
+			fmt.Printf("error verifying email of user %v: %v", id, err)
+		}
+	}
+
+	if err := iterable.Close(); err != nil {
+		fmt.Printf("error verifying emails of existing users: %v", err)
 	}
 }
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/willdot/Go-Do/apierrors"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+)
+
+var errInvalidVerificationToken = apierrors.Validation("Email verification token is not valid")
+
+var errVerificationTokenExpired = apierrors.Validation("Email verification token has expired, request a new one")
+
+var errVerificationRateLimited = apierrors.RateLimited("A verification email was sent recently, try again later")
+
+var errEmailNotVerified = apierrors.Forbidden("Email address hasn't been verified yet, check your email for the verification link")
+
+// EmailVerificationService emails new users a token to prove that they own their email address
+type EmailVerificationService struct {
+	repo   Repository
+	mailer Mailer
+	// lifetime is how long a verification token can be used for
+	lifetime time.Duration
+	// url is the page where users verify their email, which the token is added to as a query parameter. Without it
+	// the token is sent on its own
+	url string
+	// resendInterval is how long a user has to wait between verification emails
+	resendInterval time.Duration
+	// required stops users logging in until they have verified their email
+	required bool
+}
+
+func (s *EmailVerificationService) tokens() oneTimeTokens {
+	return oneTimeTokens{s.repo, purposeEmailVerification, s.lifetime, errInvalidVerificationToken, errVerificationTokenExpired}
+}
+
+// Send creates a verification token for a user and emails it to them, unless they were sent one within the resend
+// interval
+func (s *EmailVerificationService) Send(user *authPb.User) error {
+
+	allowed, err := s.repo.ClaimVerificationEmail(user.Id, s.resendInterval)
+
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return errVerificationRateLimited
+	}
+
+	token, err := s.tokens().issue(user.Id)
+
+	if err != nil {
+		return err
+	}
+
+	link, err := tokenLink(s.url, token)
+
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(Email{
+		To:      user.Email,
+		Subject: "Verify your Go-Do email address",
+		Body: fmt.Sprintf("Welcome to Go-Do. Verify your email address with:\n\n%s\n\n"+
+			"It can be used once in the next %v. If you didn't sign up, you can ignore this email.\n", link, s.lifetime),
+	})
+}
+
+// Verify checks a verification token and marks the email of the user it was issued to as verified
+func (s *EmailVerificationService) Verify(token string) error {
+
+	userID, err := s.tokens().use(token)
+
+	if err != nil {
+		return err
+	}
+
+	return s.repo.SetEmailVerified(userID)
+}
@@ -1,33 +1,74 @@
 package main
 
 import (
-	"errors"
 	"fmt"
 	"log"
+	"net"
+	"net/mail"
+	"time"
 
-	authPb "github.com/willdot/go-do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/apierrors"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/user-service/verifier"
 
 	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/net/context"
 )
 
-var errUnknownUser = errors.New("User not found")
+var errUnknownUser = apierrors.NotFound("User not found")
 
-var errTokenPasswordNotValid = errors.New("Token password no longer valid")
+var errTokenPasswordNotValid = apierrors.Unauthenticated("Token password no longer valid")
+
+var errInvalidTimezone = apierrors.Validation("Timezone must be an IANA time zone name such as Europe/London")
+
+var errUnknownUpdateField = apierrors.Validation("Update mask contains a field that can't be updated")
+
+var errEmptyPassword = apierrors.Validation("Password can't be empty")
+
+var errInvalidCredentials = apierrors.Unauthenticated("Email or password is incorrect")
+
+var errInvalidEmail = apierrors.Validation("Email must be an email address such as will@email.com")
 
 type userHandler struct {
-	repo         Repository
-	tokenService TokenService
+	repo                 Repository
+	tokenService         TokenService
+	passwordResetService PasswordResetService
+	verificationService  EmailVerificationService
+	loginThrottle        LoginThrottle
+	auditLog             *AuditLog
+	mfaService           MFAService
+	companyService       CompanyService
+	// serviceToken is shared with the other services so that they can call the serviceEndpoints
+	serviceToken string
+	// trustedProxies are the proxies, such as the API gateway, whose X-Forwarded-For header is believed
+	trustedProxies []*net.IPNet
+}
+
+// clientIP gets the IP that a request came from, through the trusted proxies
+func (u *userHandler) clientIP(ctx context.Context) string {
+	return clientIP(ctx, u.trustedProxies)
 }
 
+// Create creates a user with an unverified email, and emails them a token to verify it. New users are always given the
+// user role
 func (u *userHandler) Create(ctx context.Context, req *authPb.User, res *authPb.Response) error {
 
+	if !validEmail(req.Email) {
+		return errInvalidEmail
+	}
+
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return errInvalidTimezone
+	}
+
 	hashedPass, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return fmt.Errorf("error hashing password: %v", err)
 	}
 
 	req.Password = string(hashedPass)
+	req.EmailVerified = false
+	req.Role = verifier.RoleUser
 
 	err = u.repo.Create(req)
 
@@ -35,108 +76,421 @@ func (u *userHandler) Create(ctx context.Context, req *authPb.User, res *authPb.
 		return err
 	}
 
-	res.User = req
+	// The user has been created, so if the email can't be sent they can ask for it again rather than sign up again
+	if err := u.verificationService.Send(req); err != nil {
+		log.Println("Error sending verification email: ", err)
+	}
+
+	res.User = withoutPassword(req)
 
 	return nil
 }
 
+// validEmail checks that an email is a plain email address, without a display name
+func validEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+
+	return err == nil && address.Address == email
+}
+
+// Get gets a user by id. Users can get themselves and admins can get the users they manage. Other users aren't found,
+// so that which ids exist isn't given away
 func (u *userHandler) Get(ctx context.Context, req *authPb.User, res *authPb.Response) error {
 
-	user, err := u.repo.Get(req.Id)
+	var user *authPb.User
+	var err error
+
+	// Other services can get any user, as they aren't acting for a caller
+	if isServiceCall(ctx) {
+		user, err = u.repo.Get(req.Id)
+	} else {
+		user, err = u.managedUser(ctx, req.Id)
+	}
 
 	if err != nil {
 		return err
 	}
 
-	res.User = user
+	res.User = withoutPassword(user)
 	return nil
 }
 
+// managedUser gets a user that the caller can manage, for an id in a request
+func (u *userHandler) managedUser(ctx context.Context, id string) (*authPb.User, error) {
+
+	caller, err := u.userFromContext(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := u.repo.Get(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !canManage(caller, user) {
+		return nil, errUnknownUser
+	}
+
+	return user, nil
+}
+
+// GetAll gets every user for system admins, and the members of their company for company admins
 func (u *userHandler) GetAll(ctx context.Context, req *authPb.Request, res *authPb.Response) error {
 
-	users, err := u.repo.GetAll()
+	caller, err := u.userFromContext(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	var users []*authPb.User
+
+	switch {
+	case roleOf(caller) == verifier.RoleSystemAdmin:
+		users, err = u.repo.GetAll()
+	case roleOf(caller) == verifier.RoleCompanyAdmin && caller.CompanyId != "":
+		users, err = u.repo.GetByCompanyID(caller.CompanyId)
+	case roleOf(caller) == verifier.RoleCompanyAdmin:
+		// Without a company there's nobody else to manage
+		users = []*authPb.User{caller}
+	default:
+		return errNotAdmin
+	}
 
 	if err != nil {
 		return err
 	}
 
-	res.Users = users
+	res.Users = withoutPasswords(users)
 
 	return nil
 }
 
-func (u *userHandler) Update(ctx context.Context, req *authPb.User, res *authPb.Response) error {
-	err := u.repo.Update(req)
+// Update updates the fields of a user in the update mask. Users can update themselves and admins can update the users
+// they manage. The company field is only a name, the company a user is a member of is changed with invites
+func (u *userHandler) Update(ctx context.Context, req *authPb.UpdateUserRequest, res *authPb.Response) error {
+
+	existingUser, err := u.managedUser(ctx, req.GetUser().GetId())
 
 	if err != nil {
 		return err
 	}
 
-	res.User = req
+	user := *existingUser
+
+	err = applyUserUpdate(&user, req)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := time.LoadLocation(user.Timezone); err != nil {
+		return errInvalidTimezone
+	}
+
+	err = u.repo.Update(&user)
+
+	if err != nil {
+		return err
+	}
+
+	res.User = withoutPassword(&user)
+
+	return nil
+}
+
+// applyUserUpdate copies the fields named in the update mask from the request onto the user. Without a mask, all of
+// the fields that can be updated are copied
+func applyUserUpdate(user *authPb.User, req *authPb.UpdateUserRequest) error {
+	mask := req.UpdateMask
+
+	if len(mask) == 0 {
+		mask = []string{"name", "company", "timezone", "carryOverDailyDo"}
+	}
+
+	changes := req.GetUser()
+
+	if changes == nil {
+		changes = &authPb.User{}
+	}
+
+	for _, field := range mask {
+		switch field {
+		case "name":
+			user.Name = changes.Name
+		case "company":
+			user.Company = changes.Company
+		case "timezone":
+			user.Timezone = changes.Timezone
+		case "carryOverDailyDo":
+			user.CarryOverDailyDo = changes.CarryOverDailyDo
+		default:
+			return errUnknownUpdateField
+		}
+	}
 
 	return nil
 }
 
 func (u *userHandler) Auth(ctx context.Context, req *authPb.User, res *authPb.Token) error {
 
-	log.Println("Logging in with: ", req.Email, req.Password)
+	user, err := u.checkPassword(ctx, eventLogin, req.Email, req.Password)
 
-	user, err := u.repo.GetByEmail(req.Email)
 	if err != nil {
 		return err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return err
+	audit := AuditEvent{Event: eventLogin, Email: user.Email, UserID: user.Id, IP: u.clientIP(ctx)}
+
+	if u.verificationService.required && !user.EmailVerified {
+		audit.Outcome = outcomeEmailNotVerified
+		u.auditLog.Log(audit)
+
+		return errEmailNotVerified
 	}
 
-	token, err := u.tokenService.Encode(user)
+	err = u.newSession(user, res)
 
 	if err != nil {
 		return err
 	}
 
-	res.Token = token
+	audit.Outcome = outcomeSuccess
+
+	if res.MfaRequired {
+		audit.Outcome = outcomeMFARequired
+	}
+
+	u.auditLog.Log(audit)
+
 	return nil
 }
 
-func (u *userHandler) ValidateToken(ctx context.Context, req *authPb.Token, res *authPb.Token) error {
+// newSession logs in a user who has proven who they are with their password. Users with two factor authentication get
+// an mfa token instead, which has to be sent to VerifyMFA with a code to log in, and their failed logins aren't
+// forgotten until they have
+func (u *userHandler) newSession(user *authPb.User, res *authPb.Token) error {
+
+	if user.MfaEnabled {
+		mfaToken, err := u.mfaService.Challenge(user.Id)
+
+		if err != nil {
+			return err
+		}
+
+		res.MfaRequired = true
+		res.MfaToken = mfaToken
+
+		return nil
+	}
+
+	return u.startSession(user, res)
+}
+
+// startSession creates a session for a user who has fully logged in, forgetting their failed logins
+func (u *userHandler) startSession(user *authPb.User, res *authPb.Token) error {
+
+	if err := u.loginThrottle.Reset(user.Email); err != nil {
+		return err
+	}
+
+	token, err := u.tokenService.NewSession(user)
 
-	claims, err := u.tokenService.Decode(req.Token)
 	if err != nil {
 		return err
 	}
 
-	if claims.User.Id == "" {
-		return errUnknownUser
+	setToken(res, token)
+	return nil
+}
+
+// checkPassword checks the password of the user with the email, for an event such as logging in. Every failure is
+// audited and counted by the login throttle, so that passwords can't be guessed over and over
+func (u *userHandler) checkPassword(ctx context.Context, event, email, password string) (*authPb.User, error) {
+	ip := u.clientIP(ctx)
+
+	if err := u.loginThrottle.Check(email, ip); err != nil {
+		u.auditLog.Log(AuditEvent{Event: event, Outcome: outcomeThrottled, Email: email, IP: ip})
+		return nil, err
 	}
 
-	// Get the user from the repo so that we can compare the password in the token with the users actual password.
-	// Otherwise they may be using a token that was assigned before a password change
-	user, err := u.repo.Get(claims.User.Id)
+	user, err := u.repo.GetByEmail(email)
+
+	// Don't let on whether it was the email or the password that was wrong
+	if err == errUnknownUser {
+		return nil, u.attemptFailed(AuditEvent{Event: event, Outcome: outcomeUnknownUser, Email: email, IP: ip}, errInvalidCredentials)
+	}
 
 	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, u.attemptFailed(AuditEvent{Event: event, Outcome: outcomeWrongPassword, Email: email, UserID: user.Id, IP: ip}, errInvalidCredentials)
+	}
+
+	return user, nil
+}
+
+// attemptFailed audits a wrong password or code and counts it in the login throttle, returning the failure
+func (u *userHandler) attemptFailed(event AuditEvent, failure error) error {
+	u.auditLog.Log(event)
+
+	if err := u.loginThrottle.Failed(event.Email, event.IP); err != nil {
 		return err
 	}
 
-	if user.Password != claims.User.Password {
-		return errTokenPasswordNotValid
+	return failure
+}
+
+func (u *userHandler) ValidateToken(ctx context.Context, req *authPb.Token, res *authPb.Token) error {
+
+	claims, err := u.verifyToken(req.Token)
+
+	if err != nil {
+		return err
 	}
 
 	res.Valid = true
-	res.UserId = claims.User.Id
+	res.UserId = claims.UserID
+	res.CompanyId = claims.CompanyID
 
 	return nil
 }
 
+// verifyToken checks that a token is valid and hasn't been revoked, returning its claims with the user id set even for
+// tokens issued before the claims were reduced to ids
+func (u *userHandler) verifyToken(token string) (*CustomClaims, error) {
+
+	claims, err := u.tokenService.Decode(token)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := claims.UserID
+
+	if claims.User != nil {
+		userID = claims.User.Id
+	}
+
+	if userID == "" {
+		return nil, errUnknownUser
+	}
+
+	if claims.User != nil {
+		// The token was issued before the claims were reduced to ids, so compare the password in the token with the users
+		// actual password to check it wasn't assigned before a password change
+		user, err := u.repo.Get(userID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if user.Password != claims.User.Password {
+			return nil, errTokenPasswordNotValid
+		}
+	} else {
+		// The token generation goes up each time the password changes, so tokens from an older generation were issued
+		// before a password change
+		tokenGeneration, err := u.repo.GetTokenGeneration(userID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if tokenGeneration != claims.TokenGeneration {
+			return nil, errTokenPasswordNotValid
+		}
+	}
+
+	// Tokens issued before sessions were added don't have a session, so they can't be revoked but will still expire
+	if claims.SessionID != "" {
+		session, err := u.repo.GetSession(claims.SessionID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if session.Revoked {
+			return nil, errSessionRevoked
+		}
+	}
+
+	claims.UserID = userID
+
+	if claims.Role == "" {
+		claims.Role = verifier.RoleUser
+	}
+
+	return claims, nil
+}
+
 func (u *userHandler) ChangePassword(ctx context.Context, req *authPb.PasswordChange, res *authPb.Token) error {
 
+	user, err := u.checkPassword(ctx, eventChangePassword, req.Email, req.OldPassword)
+
+	if err != nil {
+		return err
+	}
+
+	hashedPass, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %v", err)
+	}
+
+	user.Password = string(hashedPass)
+
+	err = u.repo.UpdatePassword(user.Id, user.Password)
+
+	if err != nil {
+		return err
+	}
+
+	// Log out everywhere else, as the password may have been changed because someone else knew it
+	err = u.repo.RevokeSessions(user.Id)
+
+	if err != nil {
+		return err
+	}
+
+	u.auditLog.Log(AuditEvent{Event: eventChangePassword, Outcome: outcomeSuccess, Email: user.Email, UserID: user.Id, IP: u.clientIP(ctx)})
+
+	return u.newSession(user, res)
+}
+
+// RequestPasswordReset emails a password reset token to the user with the email in the request. It doesn't return an
+// error if there's no user with the email, so that it can't be used to find out who has an account
+func (u *userHandler) RequestPasswordReset(ctx context.Context, req *authPb.PasswordResetRequest, res *authPb.Response) error {
+
 	user, err := u.repo.GetByEmail(req.Email)
+
+	if err != nil {
+		log.Println("Password reset requested for unknown email: ", err)
+		return nil
+	}
+
+	return u.passwordResetService.Send(user)
+}
+
+// ResetPassword sets a new password using a password reset token, and logs the user out everywhere else
+func (u *userHandler) ResetPassword(ctx context.Context, req *authPb.PasswordReset, res *authPb.Token) error {
+
+	if req.NewPassword == "" {
+		return errEmptyPassword
+	}
+
+	userID, err := u.passwordResetService.Use(req.Token)
+
 	if err != nil {
 		return err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.OldPassword)); err != nil {
+	user, err := u.repo.Get(userID)
+
+	if err != nil {
 		return err
 	}
 
@@ -153,12 +507,515 @@ func (u *userHandler) ChangePassword(ctx context.Context, req *authPb.PasswordCh
 		return err
 	}
 
-	token, err := u.tokenService.Encode(user)
+	err = u.repo.RevokeSessions(user.Id)
+
+	if err != nil {
+		return err
+	}
+
+	// Knowing the reset token only proves access to the email, so users with two factor authentication still need a code
+	return u.newSession(user, res)
+}
+
+// VerifyEmail marks the email of a user as verified using the token emailed to them
+func (u *userHandler) VerifyEmail(ctx context.Context, req *authPb.VerifyEmailRequest, res *authPb.Response) error {
+
+	return u.verificationService.Verify(req.Token)
+}
+
+// ResendVerification emails a new verification token to the user with the email in the request. Like
+// RequestPasswordReset, it doesn't return an error if there's no user with the email
+func (u *userHandler) ResendVerification(ctx context.Context, req *authPb.ResendVerificationRequest, res *authPb.Response) error {
+
+	user, err := u.repo.GetByEmail(req.Email)
+
+	if err != nil {
+		log.Println("Verification email requested for unknown email: ", err)
+		return nil
+	}
+
+	if user.EmailVerified {
+		return nil
+	}
+
+	return u.verificationService.Send(user)
+}
+
+// UnlockUser forgets the failed logins for an email, so that a user who has been locked out can log in straight away.
+// System admins can unlock any email, and company admins can unlock the users in their company
+func (u *userHandler) UnlockUser(ctx context.Context, req *authPb.UnlockUserRequest, res *authPb.Response) error {
+
+	admin, err := u.userFromContext(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	audit := AuditEvent{Event: eventUnlockUser, Email: req.Email, IP: u.clientIP(ctx), ActorID: admin.Id}
+
+	if !u.canUnlock(admin, req.Email) {
+		audit.Outcome = outcomeDenied
+		u.auditLog.Log(audit)
+
+		return errNotAdmin
+	}
+
+	if err := u.loginThrottle.Reset(req.Email); err != nil {
+		return err
+	}
+
+	audit.Outcome = outcomeSuccess
+	u.auditLog.Log(audit)
+
+	return nil
+}
+
+// canUnlock reports if an admin can unlock an email. Emails without a user can only be unlocked by system admins, as
+// they aren't in a company
+func (u *userHandler) canUnlock(admin *authPb.User, email string) bool {
+
+	if roleOf(admin) == verifier.RoleSystemAdmin {
+		return true
+	}
+
+	if !isAdmin(admin) {
+		return false
+	}
+
+	user, err := u.repo.GetByEmail(email)
+
+	return err == nil && canManage(admin, user)
+}
+
+// SetRole gives a user a role. Company admins can make the users in their company users or company admins, and system
+// admins can give anyone any role. Tokens with the old role can't be used once it has changed
+func (u *userHandler) SetRole(ctx context.Context, req *authPb.SetRoleRequest, res *authPb.Response) error {
+
+	admin, err := u.userFromContext(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if !isAdmin(admin) {
+		return errNotAdmin
+	}
+
+	user, err := u.managedUser(ctx, req.UserId)
+
+	if err != nil {
+		return err
+	}
+
+	audit := AuditEvent{Event: eventSetRole, Email: user.Email, UserID: user.Id, IP: u.clientIP(ctx), ActorID: admin.Id}
+
+	if roleOf(admin) != verifier.RoleSystemAdmin && req.Role == verifier.RoleSystemAdmin {
+		audit.Outcome = outcomeDenied
+		u.auditLog.Log(audit)
+
+		return errRoleNotAllowed
+	}
+
+	err = u.repo.SetRole(user.Id, req.Role)
+
+	if err != nil {
+		return err
+	}
+
+	audit.Outcome = outcomeSuccess
+	u.auditLog.Log(audit)
+
+	user.Role = req.Role
+	res.User = withoutPassword(user)
+
+	return nil
+}
+
+// CreateCompany creates a company owned by the caller, who becomes an admin of it. The caller can't already be in a
+// company, and needs to refresh their token to get one for the company
+func (u *userHandler) CreateCompany(ctx context.Context, req *authPb.CreateCompanyRequest, res *authPb.Response) error {
+
+	user, err := u.userFromContext(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	company, err := u.companyService.Create(user, req.Name)
+
+	if err != nil {
+		return err
+	}
+
+	u.auditLog.Log(AuditEvent{Event: eventCreateCompany, Outcome: outcomeSuccess, Email: user.Email, UserID: user.Id, IP: u.clientIP(ctx), CompanyID: company.Id})
+
+	res.Company = company
+
+	return nil
+}
+
+// InviteMember emails an invite to join a company. Company admins can invite people to their company, and system
+// admins to any company
+func (u *userHandler) InviteMember(ctx context.Context, req *authPb.InviteMemberRequest, res *authPb.Response) error {
+
+	admin, err := u.userFromContext(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	company, err := u.companyService.Administered(admin, req.CompanyId)
+
+	if err != nil {
+		return err
+	}
+
+	err = u.companyService.Invite(company, admin, req.Email)
+
+	if err != nil {
+		return err
+	}
+
+	u.auditLog.Log(AuditEvent{Event: eventInviteMember, Outcome: outcomeSuccess, Email: req.Email, IP: u.clientIP(ctx), ActorID: admin.Id, CompanyID: company.Id})
+
+	res.Company = company
+
+	return nil
+}
+
+// AcceptInvite makes the caller a member of a company that their email has been invited to. They need to refresh their
+// token to get one for the company
+func (u *userHandler) AcceptInvite(ctx context.Context, req *authPb.AcceptInviteRequest, res *authPb.Response) error {
+
+	user, err := u.userFromContext(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	company, err := u.companyService.Accept(user, req.CompanyId)
+
+	if err != nil {
+		return err
+	}
+
+	u.auditLog.Log(AuditEvent{Event: eventJoinCompany, Outcome: outcomeSuccess, Email: user.Email, UserID: user.Id, IP: u.clientIP(ctx), CompanyID: company.Id})
+
+	res.Company = company
+
+	return nil
+}
+
+// RemoveMember takes a user out of a company. Members can leave their company by removing themselves, and admins can
+// remove the members they manage. The owner can't be removed until they've transferred ownership
+func (u *userHandler) RemoveMember(ctx context.Context, req *authPb.RemoveMemberRequest, res *authPb.Response) error {
+
+	caller, err := u.userFromContext(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	company, err := u.companyService.Get(caller, req.CompanyId)
+
+	if err != nil {
+		return err
+	}
+
+	user := caller
+
+	if req.UserId != "" && req.UserId != caller.Id {
+		if !isAdmin(caller) {
+			return errNotCompanyAdmin
+		}
+
+		user, err = u.repo.Get(req.UserId)
+
+		if err != nil || !canManage(caller, user) {
+			return errNotMember
+		}
+	}
+
+	err = u.companyService.Remove(company, user)
+
+	if err != nil {
+		return err
+	}
+
+	u.auditLog.Log(AuditEvent{Event: eventRemoveMember, Outcome: outcomeSuccess, Email: user.Email, UserID: user.Id, IP: u.clientIP(ctx), ActorID: caller.Id, CompanyID: company.Id})
+
+	res.Company = company
+
+	return nil
+}
+
+// ListMembers gets the members of the callers company, or of any company for system admins
+func (u *userHandler) ListMembers(ctx context.Context, req *authPb.ListMembersRequest, res *authPb.Response) error {
+
+	caller, err := u.userFromContext(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	company, err := u.companyService.Get(caller, req.CompanyId)
+
+	if err != nil {
+		return err
+	}
+
+	members, err := u.companyService.Members(company)
+
+	if err != nil {
+		return err
+	}
+
+	res.Company = company
+	res.Users = withoutPasswords(members)
+
+	return nil
+}
+
+// TransferOwnership makes another member the owner of a company. Only the owner and system admins can transfer it,
+// and the old owner stays an admin of the company
+func (u *userHandler) TransferOwnership(ctx context.Context, req *authPb.TransferOwnershipRequest, res *authPb.Response) error {
+
+	caller, err := u.userFromContext(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	company, err := u.companyService.Administered(caller, req.CompanyId)
+
+	if err != nil {
+		return err
+	}
+
+	if company.OwnerId != caller.Id && roleOf(caller) != verifier.RoleSystemAdmin {
+		return errNotOwner
+	}
+
+	user, err := u.repo.Get(req.UserId)
+
+	if err != nil {
+		return errNotMember
+	}
+
+	err = u.companyService.TransferOwnership(company, user)
+
+	if err != nil {
+		return err
+	}
+
+	u.auditLog.Log(AuditEvent{Event: eventTransferOwnership, Outcome: outcomeSuccess, Email: user.Email, UserID: user.Id, IP: u.clientIP(ctx), ActorID: caller.Id, CompanyID: company.Id})
+
+	res.Company = company
+
+	return nil
+}
+
+// EnrollTOTP creates a new authenticator app secret for the user the token in the request metadata belongs to. The
+// response has the secret and an otpauth URI for it, which can be shown as a QR code
+func (u *userHandler) EnrollTOTP(ctx context.Context, req *authPb.EnrollTOTPRequest, res *authPb.TOTPEnrollment) error {
+
+	user, err := u.userFromContext(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	secret, uri, err := u.mfaService.Enroll(user)
+
+	if err != nil {
+		return err
+	}
+
+	res.Secret = secret
+	res.Uri = uri
+
+	return nil
+}
+
+// ConfirmTOTP turns on two factor authentication with a code from the authenticator app that was enrolled, returning
+// the recovery codes that can be used instead of a code if the app is lost
+func (u *userHandler) ConfirmTOTP(ctx context.Context, req *authPb.TOTPCode, res *authPb.RecoveryCodes) error {
+
+	user, err := u.userFromContext(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	codes, err := u.mfaService.Confirm(user, req.Code)
+
+	if err != nil {
+		return err
+	}
+
+	u.auditLog.Log(AuditEvent{Event: eventEnableMFA, Outcome: outcomeSuccess, Email: user.Email, UserID: user.Id, IP: u.clientIP(ctx)})
+
+	res.Codes = codes
+
+	return nil
+}
+
+// GenerateRecoveryCodes replaces the recovery codes of the user the token in the request metadata belongs to, which
+// needs a code from their authenticator app
+func (u *userHandler) GenerateRecoveryCodes(ctx context.Context, req *authPb.TOTPCode, res *authPb.RecoveryCodes) error {
+
+	user, err := u.userFromContext(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	codes, err := u.mfaService.RecoveryCodes(user, req.Code)
+
+	if err != nil {
+		return err
+	}
+
+	u.auditLog.Log(AuditEvent{Event: eventRecoveryCodes, Outcome: outcomeSuccess, Email: user.Email, UserID: user.Id, IP: u.clientIP(ctx)})
+
+	res.Codes = codes
+
+	return nil
+}
+
+// VerifyMFA finishes logging in a user with two factor authentication, swapping the mfa token from Auth and a code
+// from their authenticator app or a recovery code for a token
+func (u *userHandler) VerifyMFA(ctx context.Context, req *authPb.MFAVerification, res *authPb.Token) error {
+
+	userID, err := u.mfaService.UseChallenge(req.MfaToken)
+
+	if err != nil {
+		return err
+	}
+
+	user, err := u.repo.Get(userID)
 
 	if err != nil {
 		return err
 	}
 
-	res.Token = token
+	ip := u.clientIP(ctx)
+	audit := AuditEvent{Event: eventVerifyMFA, Email: user.Email, UserID: user.Id, IP: ip}
+
+	if err := u.loginThrottle.Check(user.Email, ip); err != nil {
+		audit.Outcome = outcomeThrottled
+		u.auditLog.Log(audit)
+
+		return err
+	}
+
+	err = u.mfaService.Verify(user.Id, req.Code, req.RecoveryCode)
+
+	if err == errInvalidMFACode {
+		audit.Outcome = outcomeWrongCode
+		return u.attemptFailed(audit, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = u.startSession(user, res)
+
+	if err != nil {
+		return err
+	}
+
+	audit.Outcome = outcomeSuccess
+	u.auditLog.Log(audit)
+
 	return nil
 }
+
+// Refresh swaps the refresh token in the request for a new access token and refresh token
+func (u *userHandler) Refresh(ctx context.Context, req *authPb.Token, res *authPb.Token) error {
+
+	token, err := u.tokenService.Refresh(req.RefreshToken)
+
+	if err != nil {
+		return err
+	}
+
+	setToken(res, token)
+	return nil
+}
+
+// Logout revokes the session of the access token in the request, or of the refresh token if there's no access token
+func (u *userHandler) Logout(ctx context.Context, req *authPb.Token, res *authPb.Token) error {
+
+	var sessionID string
+
+	if req.Token != "" {
+		claims, err := u.tokenService.Decode(req.Token)
+
+		if err != nil {
+			return err
+		}
+
+		sessionID = claims.SessionID
+	} else {
+		id, secret, err := splitRefreshToken(req.RefreshToken)
+
+		if err != nil {
+			return err
+		}
+
+		session, err := u.repo.GetSession(id)
+
+		if err == errSessionNotFound {
+			return errInvalidRefreshToken
+		}
+
+		if err != nil {
+			return err
+		}
+
+		// Only the holder of the refresh token can end the session with it, not anyone who knows the session id
+		if session.RefreshTokenHash != hashTokenSecret(secret) {
+			return errInvalidRefreshToken
+		}
+
+		sessionID = id
+	}
+
+	if sessionID == "" {
+		return errSessionNotFound
+	}
+
+	return u.repo.RevokeSession(sessionID)
+}
+
+// RevokeAllSessions revokes every session of the user the access token in the request belongs to
+func (u *userHandler) RevokeAllSessions(ctx context.Context, req *authPb.Token, res *authPb.Token) error {
+
+	validated := authPb.Token{}
+
+	err := u.ValidateToken(ctx, req, &validated)
+
+	if err != nil {
+		return err
+	}
+
+	return u.repo.RevokeSessions(validated.UserId)
+}
+
+// GetJWKS gets the public keys that tokens are signed with, so that tokens can be verified without calling ValidateToken
+func (u *userHandler) GetJWKS(ctx context.Context, req *authPb.JWKSRequest, res *authPb.JWKS) error {
+
+	res.Keys = u.tokenService.keys.JWKS().Keys
+
+	return nil
+}
+
+// setToken copies the tokens that have been issued into a response
+func setToken(res *authPb.Token, token *authPb.Token) {
+	res.Token = token.Token
+	res.UserId = token.UserId
+	res.RefreshToken = token.RefreshToken
+	res.ExpiresAt = token.ExpiresAt
+}
@@ -1,13 +1,17 @@
 package main
 
 import (
-	"fmt"
+	"crypto/sha1"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/dgrijalva/jwt-go"
 	"golang.org/x/crypto/bcrypt"
 
-	authPb "github.com/willdot/go-do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/apierrors"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/user-service/verifier"
 )
 
 func assertError(got, want error, t *testing.T) {
@@ -25,6 +29,15 @@ func assertError(got, want error, t *testing.T) {
 	}
 }
 
+// assertCode checks the code of an error in the catalogue, for errors that don't have a sentinel to compare with
+func assertCode(got error, want int32, t *testing.T) {
+	t.Helper()
+
+	if code := apierrors.From(got).Code; got == nil || code != want {
+		t.Errorf("got error '%v' but want an error with code %v", got, want)
+	}
+}
+
 func TestCreate(t *testing.T) {
 
 	t.Run("returns a user", func(t *testing.T) {
@@ -58,7 +71,22 @@ func TestCreate(t *testing.T) {
 
 		err := service.Create(createContext(), &existingUser, &response)
 
-		assertError(err, fmt.Errorf(errUserAlreadyExists, fakeUser.Email), t)
+		assertCode(err, apierrors.CodeConflict, t)
+	})
+
+	t.Run("returns invalid timezone error", func(t *testing.T) {
+		service := createService(false)
+
+		response := authPb.Response{}
+
+		user := authPb.User{
+			Email:    "timezone@fake.com",
+			Timezone: "Not/A_Timezone",
+		}
+
+		err := service.Create(createContext(), &user, &response)
+
+		assertError(err, errInvalidTimezone, t)
 	})
 
 }
@@ -70,7 +98,7 @@ func TestGet(t *testing.T) {
 
 		response := authPb.Response{}
 
-		err := service.Get(createContext(), &fakeUser, &response)
+		err := service.Get(createCallerContext(fakeUser.Id), &fakeUser, &response)
 
 		assertError(err, nil, t)
 	})
@@ -80,7 +108,7 @@ func TestGet(t *testing.T) {
 
 		response := authPb.Response{}
 
-		err := service.Get(createContext(), &fakeUser, &response)
+		err := service.Get(createCallerContext(fakeUser.Id), &fakeUser, &response)
 
 		assertError(err, errFake, t)
 	})
@@ -89,16 +117,23 @@ func TestGet(t *testing.T) {
 
 func TestGetAll(t *testing.T) {
 
+	defer func() { fakeUser.Role = "" }()
+
 	t.Run("returns a user", func(t *testing.T) {
 		service := createService(false)
+		fakeUser.Role = verifier.RoleSystemAdmin
 
 		request := authPb.Request{}
 
 		response := authPb.Response{}
 
-		err := service.GetAll(createContext(), &request, &response)
+		err := service.GetAll(createCallerContext(fakeUser.Id), &request, &response)
 
 		assertError(err, nil, t)
+
+		if len(response.Users) != 1 {
+			t.Errorf("wanted 1 user but got %v", response.Users)
+		}
 	})
 
 	t.Run("returns an error", func(t *testing.T) {
@@ -108,7 +143,7 @@ func TestGetAll(t *testing.T) {
 
 		response := authPb.Response{}
 
-		err := service.GetAll(createContext(), &request, &response)
+		err := service.GetAll(createCallerContext(fakeUser.Id), &request, &response)
 
 		assertError(err, errFake, t)
 	})
@@ -122,9 +157,13 @@ func TestUpdate(t *testing.T) {
 
 		response := authPb.Response{}
 
-		err := service.Update(createContext(), &fakeUser, &response)
+		err := service.Update(createCallerContext(fakeUser.Id), &authPb.UpdateUserRequest{User: &fakeUser}, &response)
 
 		assertError(err, nil, t)
+
+		if response.User == nil || response.User.Name != fakeUser.Name {
+			t.Errorf("wanted the updated user to be returned but got %v", response.User)
+		}
 	})
 
 	t.Run("returns an error", func(t *testing.T) {
@@ -132,11 +171,58 @@ func TestUpdate(t *testing.T) {
 
 		response := authPb.Response{}
 
-		err := service.Update(createContext(), &fakeUser, &response)
+		err := service.Update(createCallerContext(fakeUser.Id), &authPb.UpdateUserRequest{User: &fakeUser}, &response)
 
 		assertError(err, errFake, t)
 	})
 
+	t.Run("only updates the fields in the mask", func(t *testing.T) {
+		service := createService(false)
+
+		// Only system admins can move users to another company
+		fakeUser.Role = verifier.RoleSystemAdmin
+		defer func() { fakeUser.Role = "" }()
+
+		request := authPb.UpdateUserRequest{
+			User: &authPb.User{
+				Id:       fakeUser.Id,
+				Name:     "New name",
+				Company:  "",
+				Timezone: "Europe/London",
+			},
+			UpdateMask: []string{"company", "timezone"},
+		}
+
+		response := authPb.Response{}
+
+		err := service.Update(createCallerContext(fakeUser.Id), &request, &response)
+
+		assertError(err, nil, t)
+
+		if response.User.Name != fakeUser.Name {
+			t.Errorf("name isn't in the mask so wanted %v but got %v", fakeUser.Name, response.User.Name)
+		}
+
+		if response.User.Company != "" || response.User.Timezone != "Europe/London" {
+			t.Errorf("wanted company cleared and timezone Europe/London but got %v and %v", response.User.Company, response.User.Timezone)
+		}
+	})
+
+	t.Run("returns an error for an unknown field in the mask", func(t *testing.T) {
+		service := createService(false)
+
+		request := authPb.UpdateUserRequest{
+			User:       &authPb.User{Id: fakeUser.Id, Email: "new@fake.com"},
+			UpdateMask: []string{"email"},
+		}
+
+		response := authPb.Response{}
+
+		err := service.Update(createCallerContext(fakeUser.Id), &request, &response)
+
+		assertError(err, errUnknownUpdateField, t)
+	})
+
 }
 
 func TestValidateToken(t *testing.T) {
@@ -147,7 +233,7 @@ func TestValidateToken(t *testing.T) {
 		user := &fakeUser
 		user.Id = "123"
 
-		token, _ := service.tokenService.Encode(user)
+		token, _, _ := service.tokenService.Encode(user, "", 0)
 		request := authPb.Token{Token: token}
 		response := authPb.Token{}
 
@@ -162,14 +248,52 @@ func TestValidateToken(t *testing.T) {
 		user := &fakeUser
 		user.Id = "123"
 
-		token, _ := service.tokenService.Encode(user)
+		token, _, _ := service.tokenService.Encode(user, "", 0)
+		request := authPb.Token{Token: token}
+		response := authPb.Token{}
+
+		err := service.ValidateToken(createContext(), &request, &response)
+
+		assertCode(err, apierrors.CodeUnauthenticated, t)
+	})
+
+	t.Run("token issued before a password change is no longer valid", func(t *testing.T) {
+		service := createService(false)
+
+		tokens := login(service, t)
+		password := fakeUser.Password
+
+		err := service.ChangePassword(createContext(), &authPb.PasswordChange{
+			Email:       fakeUser.Email,
+			OldPassword: "test",
+			NewPassword: "new",
+		}, &authPb.Token{})
+
+		fakeUser.Password = password
+
+		assertError(err, nil, t)
+
+		err = service.ValidateToken(createContext(), &authPb.Token{Token: tokens.Token}, &authPb.Token{})
+
+		assertError(err, errTokenPasswordNotValid, t)
+	})
+
+	t.Run("token issued with the user in the claims is still valid", func(t *testing.T) {
+		service := createService(false)
+
+		user := &fakeUser
+		user.Id = "123"
+
+		token := legacyToken(user, t)
 		request := authPb.Token{Token: token}
 		response := authPb.Token{}
 
 		err := service.ValidateToken(createContext(), &request, &response)
 
-		if !strings.Contains(err.Error(), "token is expired by") {
-			t.Errorf("wanted 'token is expired by......' but got %v", err)
+		assertError(err, nil, t)
+
+		if response.UserId != "123" {
+			t.Errorf("wanted user id 123 but got %v", response.UserId)
 		}
 	})
 
@@ -185,7 +309,7 @@ func TestValidateToken(t *testing.T) {
 			Company:  "fake",
 		}
 
-		token, _ := service.tokenService.Encode(&user)
+		token := legacyToken(&user, t)
 		request := authPb.Token{Token: token}
 		response := authPb.Token{}
 
@@ -200,7 +324,7 @@ func TestValidateToken(t *testing.T) {
 		user := &fakeUser
 		user.Id = ""
 
-		token, _ := service.tokenService.Encode(user)
+		token, _, _ := service.tokenService.Encode(user, "", 0)
 		request := authPb.Token{Token: token}
 		response := authPb.Token{}
 
@@ -211,6 +335,23 @@ func TestValidateToken(t *testing.T) {
 
 }
 
+// legacyToken creates a token in the format used before the claims were reduced to ids, with the whole user in it
+func legacyToken(user *authPb.User, t *testing.T) string {
+	claims := CustomClaims{
+		User: user,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+			Issuer:    "go-do.user",
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(fakeSecret)
+
+	assertError(err, nil, t)
+
+	return token
+}
+
 func TestAuth(t *testing.T) {
 
 	t.Run("auth is fine", func(t *testing.T) {
@@ -219,6 +360,7 @@ func TestAuth(t *testing.T) {
 		response := authPb.Token{}
 
 		user := authPb.User{
+			Email:    "fake@fake.com",
 			Password: "test",
 		}
 
@@ -243,14 +385,13 @@ func TestAuth(t *testing.T) {
 		response := authPb.Token{}
 
 		user := authPb.User{
+			Email:    "fake@fake.com",
 			Password: "wrong",
 		}
 
 		err := service.Auth(createContext(), &user, &response)
 
-		if !strings.Contains(err.Error(), "hashedPassword is not the hash of the given password") {
-			t.Errorf("wanted 'hashedPassword is not the hash of the given password......' but got %v", err)
-		}
+		assertCode(err, apierrors.CodeUnauthenticated, t)
 	})
 }
 func TestPasswordChange(t *testing.T) {
@@ -293,16 +434,627 @@ func TestPasswordChange(t *testing.T) {
 		response := authPb.Token{}
 
 		request := authPb.PasswordChange{
-			Email:       "notreal",
+			Email:       "fake@fake.com",
 			OldPassword: "wrong",
 			NewPassword: "new",
 		}
 
 		err := service.ChangePassword(createContext(), &request, &response)
 
-		if !strings.Contains(err.Error(), "hashedPassword is not the hash of the given password") {
-			t.Errorf("wanted 'hashedPassword is not the hash of the given password......' but got %v", err)
+		assertCode(err, apierrors.CodeUnauthenticated, t)
+	})
+
+}
+
+// login logs in as the fake user and returns the tokens issued
+func login(service userHandler, t *testing.T) authPb.Token {
+	// Other tests change the fake users password, so put it back to "test" if needed
+	if bcrypt.CompareHashAndPassword([]byte(fakeUser.Password), []byte("test")) != nil {
+		hashedPass, _ := bcrypt.GenerateFromPassword([]byte("test"), bcrypt.MinCost)
+		fakeUser.Password = string(hashedPass)
+	}
+
+	fakeUser.Id = "123"
+
+	tokens := authPb.Token{}
+
+	err := service.Auth(createContext(), &authPb.User{Email: fakeUser.Email, Password: "test"}, &tokens)
+
+	assertError(err, nil, t)
+
+	return tokens
+}
+
+func TestRefresh(t *testing.T) {
+
+	t.Run("refresh token is swapped for new tokens", func(t *testing.T) {
+		service := createService(false)
+
+		tokens := login(service, t)
+
+		if tokens.RefreshToken == "" || tokens.ExpiresAt == 0 {
+			t.Fatalf("wanted a refresh token and expiry from logging in but got %v", tokens)
 		}
+
+		response := authPb.Token{}
+
+		err := service.Refresh(createContext(), &authPb.Token{RefreshToken: tokens.RefreshToken}, &response)
+
+		assertError(err, nil, t)
+
+		if response.Token == "" || response.RefreshToken == "" || response.RefreshToken == tokens.RefreshToken {
+			t.Errorf("wanted a new access token and refresh token but got %v", response)
+		}
+
+		err = service.ValidateToken(createContext(), &authPb.Token{Token: response.Token}, &authPb.Token{})
+
+		assertError(err, nil, t)
+	})
+
+	t.Run("reusing a refresh token revokes the session", func(t *testing.T) {
+		service := createService(false)
+
+		tokens := login(service, t)
+
+		err := service.Refresh(createContext(), &authPb.Token{RefreshToken: tokens.RefreshToken}, &authPb.Token{})
+
+		assertError(err, nil, t)
+
+		err = service.Refresh(createContext(), &authPb.Token{RefreshToken: tokens.RefreshToken}, &authPb.Token{})
+
+		assertError(err, errInvalidRefreshToken, t)
+
+		err = service.ValidateToken(createContext(), &authPb.Token{Token: tokens.Token}, &authPb.Token{})
+
+		assertError(err, errSessionRevoked, t)
 	})
 
+	t.Run("refresh token isn't valid", func(t *testing.T) {
+		service := createService(false)
+
+		err := service.Refresh(createContext(), &authPb.Token{RefreshToken: "not a token"}, &authPb.Token{})
+
+		assertError(err, errInvalidRefreshToken, t)
+
+		err = service.Refresh(createContext(), &authPb.Token{RefreshToken: "404.secret"}, &authPb.Token{})
+
+		assertError(err, errInvalidRefreshToken, t)
+	})
+}
+
+func TestLogout(t *testing.T) {
+
+	t.Run("access token can't be used after logging out", func(t *testing.T) {
+		service := createService(false)
+
+		tokens := login(service, t)
+
+		err := service.Logout(createContext(), &authPb.Token{Token: tokens.Token}, &authPb.Token{})
+
+		assertError(err, nil, t)
+
+		err = service.ValidateToken(createContext(), &authPb.Token{Token: tokens.Token}, &authPb.Token{})
+
+		assertError(err, errSessionRevoked, t)
+
+		err = service.Refresh(createContext(), &authPb.Token{RefreshToken: tokens.RefreshToken}, &authPb.Token{})
+
+		assertError(err, errSessionRevoked, t)
+	})
+
+	t.Run("log out with a refresh token", func(t *testing.T) {
+		service := createService(false)
+
+		tokens := login(service, t)
+
+		err := service.Logout(createContext(), &authPb.Token{RefreshToken: tokens.RefreshToken}, &authPb.Token{})
+
+		assertError(err, nil, t)
+
+		err = service.ValidateToken(createContext(), &authPb.Token{Token: tokens.Token}, &authPb.Token{})
+
+		assertError(err, errSessionRevoked, t)
+	})
+
+	t.Run("a refresh token with the wrong secret doesn't log out of the session", func(t *testing.T) {
+		service := createService(false)
+
+		tokens := login(service, t)
+
+		sessionID := strings.SplitN(tokens.RefreshToken, ".", 2)[0]
+
+		err := service.Logout(createContext(), &authPb.Token{RefreshToken: sessionID + ".guessed"}, &authPb.Token{})
+
+		assertError(err, errInvalidRefreshToken, t)
+
+		err = service.ValidateToken(createContext(), &authPb.Token{Token: tokens.Token}, &authPb.Token{})
+
+		assertError(err, nil, t)
+	})
+}
+
+func TestRevokeAllSessions(t *testing.T) {
+
+	service := createService(false)
+
+	first := login(service, t)
+	second := login(service, t)
+
+	err := service.RevokeAllSessions(createContext(), &authPb.Token{Token: first.Token}, &authPb.Token{})
+
+	assertError(err, nil, t)
+
+	for _, tokens := range []authPb.Token{first, second} {
+		err = service.ValidateToken(createContext(), &authPb.Token{Token: tokens.Token}, &authPb.Token{})
+
+		assertError(err, errSessionRevoked, t)
+	}
+}
+
+// sentToken gets the token from the last email sent by the service
+func sentToken(service userHandler, t *testing.T) string {
+	sent := service.passwordResetService.mailer.(*MemoryMailer).Sent()
+
+	if len(sent) == 0 {
+		t.Fatalf("wanted an email with a token but none were sent")
+	}
+
+	// The token is on its own line after the first paragraph
+	return strings.Split(sent[len(sent)-1].Body, "\n")[2]
+}
+
+func TestPasswordReset(t *testing.T) {
+
+	t.Run("reset email is sent to the user", func(t *testing.T) {
+		service := createService(false)
+
+		err := service.RequestPasswordReset(createContext(), &authPb.PasswordResetRequest{Email: fakeUser.Email}, &authPb.Response{})
+
+		assertError(err, nil, t)
+
+		sent := service.passwordResetService.mailer.(*MemoryMailer).Sent()
+
+		if len(sent) != 1 || sent[0].To != fakeUser.Email {
+			t.Errorf("wanted 1 email to %v but got %v", fakeUser.Email, sent)
+		}
+	})
+
+	t.Run("unknown email doesn't return an error or send an email", func(t *testing.T) {
+		service := createService(true)
+
+		err := service.RequestPasswordReset(createContext(), &authPb.PasswordResetRequest{Email: "notreal"}, &authPb.Response{})
+
+		assertError(err, nil, t)
+
+		if sent := service.passwordResetService.mailer.(*MemoryMailer).Sent(); len(sent) != 0 {
+			t.Errorf("wanted no emails but got %v", sent)
+		}
+	})
+
+	t.Run("email links to the reset page", func(t *testing.T) {
+		service := createService(false)
+		service.passwordResetService.url = "https://go-do.example/reset?source=email"
+
+		err := service.RequestPasswordReset(createContext(), &authPb.PasswordResetRequest{Email: fakeUser.Email}, &authPb.Response{})
+
+		assertError(err, nil, t)
+
+		link := sentToken(service, t)
+
+		if !strings.HasPrefix(link, "https://go-do.example/reset?source=email&token=") {
+			t.Errorf("wanted a link to the reset page with the token but got %v", link)
+		}
+	})
+
+	t.Run("password is reset and other sessions are revoked", func(t *testing.T) {
+		service := createService(false)
+		before := login(service, t)
+
+		err := service.RequestPasswordReset(createContext(), &authPb.PasswordResetRequest{Email: fakeUser.Email}, &authPb.Response{})
+
+		assertError(err, nil, t)
+
+		response := authPb.Token{}
+
+		err = service.ResetPassword(createContext(), &authPb.PasswordReset{Token: sentToken(service, t), NewPassword: "reset"}, &response)
+
+		assertError(err, nil, t)
+
+		if response.Token == "" || response.RefreshToken == "" {
+			t.Errorf("wanted new tokens but got %v", response)
+		}
+
+		if err := bcrypt.CompareHashAndPassword([]byte(fakeUser.Password), []byte("reset")); err != nil {
+			t.Errorf("wanted the password to be changed: %v", err)
+		}
+
+		err = service.Refresh(createContext(), &authPb.Token{RefreshToken: before.RefreshToken}, &authPb.Token{})
+
+		assertError(err, errSessionRevoked, t)
+	})
+
+	t.Run("token can only be used once", func(t *testing.T) {
+		service := createService(false)
+		login(service, t)
+
+		service.RequestPasswordReset(createContext(), &authPb.PasswordResetRequest{Email: fakeUser.Email}, &authPb.Response{})
+		token := sentToken(service, t)
+
+		err := service.ResetPassword(createContext(), &authPb.PasswordReset{Token: token, NewPassword: "reset"}, &authPb.Token{})
+
+		assertError(err, nil, t)
+
+		err = service.ResetPassword(createContext(), &authPb.PasswordReset{Token: token, NewPassword: "again"}, &authPb.Token{})
+
+		assertError(err, errInvalidResetToken, t)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		service := createService(false)
+		login(service, t)
+
+		service.RequestPasswordReset(createContext(), &authPb.PasswordResetRequest{Email: fakeUser.Email}, &authPb.Response{})
+		token := sentToken(service, t)
+
+		for _, reset := range service.repo.(*fakeRepo).tokens[purposePasswordReset] {
+			reset.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+		}
+
+		err := service.ResetPassword(createContext(), &authPb.PasswordReset{Token: token, NewPassword: "reset"}, &authPb.Token{})
+
+		assertError(err, errResetTokenExpired, t)
+	})
+
+	tests := []struct {
+		name        string
+		token       string
+		newPassword string
+		want        error
+	}{
+		{"wrong secret", "1.wrong", "reset", errInvalidResetToken},
+		{"unknown token", "2.secret", "reset", errInvalidResetToken},
+		{"not a reset token", "secret", "reset", errInvalidResetToken},
+		{"empty password", "1.secret", "", errEmptyPassword},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := createService(false)
+
+			service.RequestPasswordReset(createContext(), &authPb.PasswordResetRequest{Email: fakeUser.Email}, &authPb.Response{})
+
+			err := service.ResetPassword(createContext(), &authPb.PasswordReset{Token: tt.token, NewPassword: tt.newPassword}, &authPb.Token{})
+
+			assertError(err, tt.want, t)
+		})
+	}
+}
+
+func TestEmailVerification(t *testing.T) {
+
+	// Other tests log in as the fake user without verifying their email
+	defer func() { fakeUser.EmailVerified = false }()
+
+	t.Run("new users are sent a verification email", func(t *testing.T) {
+		service := createService(false)
+
+		response := authPb.Response{}
+
+		err := service.Create(createContext(), &fakeUserToCreate, &response)
+
+		assertError(err, nil, t)
+
+		if response.User.EmailVerified {
+			t.Errorf("wanted the new user to be unverified")
+		}
+
+		sent := service.verificationService.mailer.(*MemoryMailer).Sent()
+
+		if len(sent) != 1 || sent[0].To != fakeUserToCreate.Email {
+			t.Errorf("wanted 1 email to %v but got %v", fakeUserToCreate.Email, sent)
+		}
+	})
+
+	for _, email := range []string{"", "notanemail", "Will <will@email.com>", "will@email.com\r\nBcc: everyone@email.com"} {
+		t.Run("invalid email "+email, func(t *testing.T) {
+			service := createService(false)
+
+			err := service.Create(createContext(), &authPb.User{Email: email}, &authPb.Response{})
+
+			assertError(err, errInvalidEmail, t)
+		})
+	}
+
+	t.Run("login is refused until the email is verified", func(t *testing.T) {
+		service := createService(false)
+		login(service, t)
+		service.verificationService.required = true
+		fakeUser.EmailVerified = false
+
+		err := service.Auth(createContext(), &authPb.User{Email: fakeUser.Email, Password: "test"}, &authPb.Token{})
+
+		assertError(err, errEmailNotVerified, t)
+
+		err = service.ResendVerification(createContext(), &authPb.ResendVerificationRequest{Email: fakeUser.Email}, &authPb.Response{})
+
+		assertError(err, nil, t)
+
+		token := sentToken(service, t)
+
+		err = service.VerifyEmail(createContext(), &authPb.VerifyEmailRequest{Token: token}, &authPb.Response{})
+
+		assertError(err, nil, t)
+
+		if !fakeUser.EmailVerified {
+			t.Fatalf("wanted the email to be verified")
+		}
+
+		err = service.Auth(createContext(), &authPb.User{Email: fakeUser.Email, Password: "test"}, &authPb.Token{})
+
+		assertError(err, nil, t)
+
+		t.Run("token can only be used once", func(t *testing.T) {
+			err := service.VerifyEmail(createContext(), &authPb.VerifyEmailRequest{Token: token}, &authPb.Response{})
+
+			assertError(err, errInvalidVerificationToken, t)
+		})
+
+		t.Run("verified users aren't sent another email", func(t *testing.T) {
+			service := createService(false)
+
+			err := service.ResendVerification(createContext(), &authPb.ResendVerificationRequest{Email: fakeUser.Email}, &authPb.Response{})
+
+			assertError(err, nil, t)
+
+			if sent := service.verificationService.mailer.(*MemoryMailer).Sent(); len(sent) != 0 {
+				t.Errorf("wanted no emails but got %v", sent)
+			}
+		})
+	})
+
+	t.Run("resending is rate limited", func(t *testing.T) {
+		service := createService(false)
+		fakeUser.EmailVerified = false
+
+		err := service.ResendVerification(createContext(), &authPb.ResendVerificationRequest{Email: fakeUser.Email}, &authPb.Response{})
+
+		assertError(err, nil, t)
+
+		err = service.ResendVerification(createContext(), &authPb.ResendVerificationRequest{Email: fakeUser.Email}, &authPb.Response{})
+
+		assertError(err, errVerificationRateLimited, t)
+
+		if sent := service.verificationService.mailer.(*MemoryMailer).Sent(); len(sent) != 1 {
+			t.Errorf("wanted 1 email but got %v", sent)
+		}
+	})
+
+	t.Run("unknown email doesn't return an error", func(t *testing.T) {
+		service := createService(true)
+
+		err := service.ResendVerification(createContext(), &authPb.ResendVerificationRequest{Email: "notreal"}, &authPb.Response{})
+
+		assertError(err, nil, t)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		service := createService(false)
+		fakeUser.EmailVerified = false
+
+		service.ResendVerification(createContext(), &authPb.ResendVerificationRequest{Email: fakeUser.Email}, &authPb.Response{})
+		token := sentToken(service, t)
+
+		for _, stored := range service.repo.(*fakeRepo).tokens[purposeEmailVerification] {
+			stored.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+		}
+
+		err := service.VerifyEmail(createContext(), &authPb.VerifyEmailRequest{Token: token}, &authPb.Response{})
+
+		assertError(err, errVerificationTokenExpired, t)
+	})
+}
+
+func TestErrorCodes(t *testing.T) {
+
+	tests := []struct {
+		err  error
+		want int32
+	}{
+		{errInvalidCredentials, apierrors.CodeUnauthenticated},
+		{errSessionRevoked, apierrors.CodeUnauthenticated},
+		{errEmailNotVerified, apierrors.CodeForbidden},
+		{errUnknownUser, apierrors.CodeNotFound},
+		{errInvalidTimezone, apierrors.CodeValidation},
+		{errResetTokenExpired, apierrors.CodeValidation},
+		{errVerificationRateLimited, apierrors.CodeRateLimited},
+		{errFake, apierrors.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			assertCode(tt.err, tt.want, t)
+		})
+	}
+}
+
+// totpCodeAt generates the code an authenticator app would show for a secret at a time
+func totpCodeAt(secret string, at time.Time) string {
+	key, _ := totpEncoding.DecodeString(secret)
+
+	return totp(key, at, totpPeriod, totpDigits, sha1.New)
+}
+
+func TestTwoFactorAuthentication(t *testing.T) {
+
+	service := createService(false)
+	tokens := login(service, t)
+
+	// Turning on two factor authentication changes the shared fake user, so turn it off again for the other tests
+	defer func() { fakeUser.MfaEnabled = false }()
+
+	ctx := createTokenContext(tokens.Token)
+
+	enrollment := authPb.TOTPEnrollment{}
+	var recoveryCodes []string
+	var lastCode string
+
+	password := authPb.User{Email: fakeUser.Email, Password: "test"}
+
+	// logIn logs in with the password, returning the mfa token that has to be sent with a code
+	logIn := func(t *testing.T) string {
+		t.Helper()
+
+		res := authPb.Token{}
+
+		err := service.Auth(createContext(), &password, &res)
+
+		if err != nil || !res.MfaRequired || res.MfaToken == "" || res.Token != "" {
+			t.Fatalf("wanted an mfa token but not a token but got %+v %v", res, err)
+		}
+
+		return res.MfaToken
+	}
+
+	t.Run("enrolling returns a secret and an otpauth URI", func(t *testing.T) {
+		err := service.EnrollTOTP(ctx, &authPb.EnrollTOTPRequest{}, &enrollment)
+
+		assertError(err, nil, t)
+
+		if enrollment.Secret == "" || !strings.HasPrefix(enrollment.Uri, "otpauth://totp/Go-Do:fake@fake.com?") {
+			t.Fatalf("wanted a secret and otpauth URI but got %+v", enrollment)
+		}
+	})
+
+	t.Run("logging in doesn't need a code until it's confirmed", func(t *testing.T) {
+		res := authPb.Token{}
+
+		err := service.Auth(createContext(), &password, &res)
+
+		if err != nil || res.MfaRequired || res.Token == "" {
+			t.Errorf("wanted a token but got %+v %v", res, err)
+		}
+	})
+
+	t.Run("confirming needs a valid code", func(t *testing.T) {
+		err := service.ConfirmTOTP(ctx, &authPb.TOTPCode{Code: "12345"}, &authPb.RecoveryCodes{})
+
+		assertError(err, errInvalidMFACode, t)
+	})
+
+	t.Run("confirming returns recovery codes", func(t *testing.T) {
+		lastCode = totpCodeAt(enrollment.Secret, time.Now())
+		res := authPb.RecoveryCodes{}
+
+		err := service.ConfirmTOTP(ctx, &authPb.TOTPCode{Code: lastCode}, &res)
+
+		assertError(err, nil, t)
+
+		if len(res.Codes) != recoveryCodeCount {
+			t.Fatalf("wanted %v recovery codes but got %v", recoveryCodeCount, res.Codes)
+		}
+
+		recoveryCodes = res.Codes
+	})
+
+	t.Run("enrolling again once it's turned on is a conflict", func(t *testing.T) {
+		err := service.EnrollTOTP(ctx, &authPb.EnrollTOTPRequest{}, &authPb.TOTPEnrollment{})
+
+		assertCode(err, apierrors.CodeConflict, t)
+	})
+
+	t.Run("a code can't be used twice", func(t *testing.T) {
+		err := service.VerifyMFA(createContext(), &authPb.MFAVerification{MfaToken: logIn(t), Code: lastCode}, &authPb.Token{})
+
+		assertError(err, errInvalidMFACode, t)
+	})
+
+	t.Run("a wrong code is counted as a failed login and uses up the mfa token", func(t *testing.T) {
+		mfaToken := logIn(t)
+
+		err := service.VerifyMFA(createContext(), &authPb.MFAVerification{MfaToken: mfaToken, Code: "000000"}, &authPb.Token{})
+
+		assertError(err, errInvalidMFACode, t)
+
+		if attempts := service.repo.(*fakeRepo).loginAttempts[emailLoginKey(fakeUser.Email)]; attempts == nil || attempts.Failures != 2 {
+			t.Errorf("wanted 2 failures, with the reused code, but got %v", attempts)
+		}
+
+		err = service.VerifyMFA(createContext(), &authPb.MFAVerification{MfaToken: mfaToken, Code: totpCodeAt(enrollment.Secret, time.Now().Add(totpPeriod))}, &authPb.Token{})
+
+		assertError(err, errInvalidMFAToken, t)
+	})
+
+	t.Run("the right password doesn't forget failed codes", func(t *testing.T) {
+		logIn(t)
+
+		if attempts := service.repo.(*fakeRepo).loginAttempts[emailLoginKey(fakeUser.Email)]; attempts == nil || attempts.Failures != 2 {
+			t.Errorf("wanted 2 failures but got %v", attempts)
+		}
+
+		delete(service.repo.(*fakeRepo).loginAttempts, emailLoginKey(fakeUser.Email))
+	})
+
+	t.Run("a new code logs in", func(t *testing.T) {
+		lastCode = totpCodeAt(enrollment.Secret, time.Now().Add(totpPeriod))
+		res := authPb.Token{}
+
+		err := service.VerifyMFA(createContext(), &authPb.MFAVerification{MfaToken: logIn(t), Code: lastCode}, &res)
+
+		assertError(err, nil, t)
+
+		if res.Token == "" || res.RefreshToken == "" {
+			t.Errorf("wanted tokens but got %+v", res)
+		}
+	})
+
+	t.Run("a recovery code logs in once", func(t *testing.T) {
+		res := authPb.Token{}
+
+		err := service.VerifyMFA(createContext(), &authPb.MFAVerification{MfaToken: logIn(t), RecoveryCode: strings.ToUpper(recoveryCodes[0])}, &res)
+
+		assertError(err, nil, t)
+
+		if res.Token == "" {
+			t.Errorf("wanted a token but got %+v", res)
+		}
+
+		err = service.VerifyMFA(createContext(), &authPb.MFAVerification{MfaToken: logIn(t), RecoveryCode: recoveryCodes[0]}, &authPb.Token{})
+
+		assertError(err, errInvalidMFACode, t)
+
+		delete(service.repo.(*fakeRepo).loginAttempts, emailLoginKey(fakeUser.Email))
+	})
+
+	t.Run("new recovery codes replace the old ones", func(t *testing.T) {
+		// Every code that's valid now has been used, so pretend that the last one was used a while ago
+		service.repo.(*fakeRepo).totp[fakeUser.Id].LastStep = 0
+
+		res := authPb.RecoveryCodes{}
+
+		err := service.GenerateRecoveryCodes(ctx, &authPb.TOTPCode{Code: totpCodeAt(enrollment.Secret, time.Now())}, &res)
+
+		assertError(err, nil, t)
+
+		err = service.VerifyMFA(createContext(), &authPb.MFAVerification{MfaToken: logIn(t), RecoveryCode: recoveryCodes[1]}, &authPb.Token{})
+
+		assertError(err, errInvalidMFACode, t)
+
+		err = service.VerifyMFA(createContext(), &authPb.MFAVerification{MfaToken: logIn(t), RecoveryCode: res.Codes[0]}, &authPb.Token{})
+
+		assertError(err, nil, t)
+	})
+
+	t.Run("resetting the password still needs a code", func(t *testing.T) {
+		service.RequestPasswordReset(createContext(), &authPb.PasswordResetRequest{Email: fakeUser.Email}, &authPb.Response{})
+
+		res := authPb.Token{}
+
+		err := service.ResetPassword(createContext(), &authPb.PasswordReset{Token: sentToken(service, t), NewPassword: "test"}, &res)
+
+		assertError(err, nil, t)
+
+		if !res.MfaRequired || res.Token != "" {
+			t.Errorf("wanted an mfa token but not a token but got %+v", res)
+		}
+	})
 }
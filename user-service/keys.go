@@ -0,0 +1,319 @@
+package main
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/dgrijalva/jwt-go"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/user-service/verifier"
+	"golang.org/x/crypto/ed25519"
+)
+
+var errNoSigningKeys = errors.New("No JWT signing keys configured, set JWT_KEYS_FILE or JWT_SECRET")
+
+var errUnknownSigningKey = errors.New("Token was signed with a key that isn't known")
+
+var errUnexpectedSigningMethod = errors.New("Token was signed with a different algorithm to its key")
+
+// defaultKeyID is the id of the key set by JWT_SECRET. Tokens issued before keys had ids are checked against it
+const defaultKeyID = verifier.DefaultKeyID
+
+// signingKey is a key that tokens are signed and verified with. Keys that have been rotated out can be kept to verify
+// tokens that were signed with them, without being able to sign new ones
+type signingKey struct {
+	id     string
+	method jwt.SigningMethod
+	// private signs tokens, and is nil for keys that can only verify
+	private interface{}
+	// public verifies tokens. For HMAC keys it's the same secret as private
+	public interface{}
+}
+
+func newHMACKey(id string, secret []byte) *signingKey {
+	return &signingKey{id, jwt.SigningMethodHS256, secret, secret}
+}
+
+func newRSAKey(id string, private *rsa.PrivateKey, public *rsa.PublicKey) *signingKey {
+	if private != nil {
+		return &signingKey{id, jwt.SigningMethodRS256, private, &private.PublicKey}
+	}
+
+	return &signingKey{id, jwt.SigningMethodRS256, nil, public}
+}
+
+func newEdDSAKey(id string, private ed25519.PrivateKey, public ed25519.PublicKey) *signingKey {
+	if private != nil {
+		return &signingKey{id, verifier.SigningMethodEdDSA, private, private.Public().(ed25519.PublicKey)}
+	}
+
+	return &signingKey{id, verifier.SigningMethodEdDSA, nil, public}
+}
+
+// KeySet holds the keys tokens can be signed with. New tokens are signed with the active key and carry its id in the
+// kid header, so keys can be rotated by adding a new key, making it active, and removing the old key once the tokens
+// it signed have expired
+type KeySet struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+// NewKeySet creates a key set that signs with the key that has the active id
+func NewKeySet(activeID string, keys ...*signingKey) (*KeySet, error) {
+	keySet := &KeySet{keys: map[string]*signingKey{}}
+
+	for _, key := range keys {
+		if _, exists := keySet.keys[key.id]; exists {
+			return nil, fmt.Errorf("JWT key %q is configured more than once", key.id)
+		}
+
+		keySet.keys[key.id] = key
+	}
+
+	keySet.active = keySet.keys[activeID]
+
+	if keySet.active == nil {
+		return nil, fmt.Errorf("active JWT key %q isn't configured", activeID)
+	}
+
+	if keySet.active.private == nil {
+		return nil, fmt.Errorf("active JWT key %q has no private key to sign with", activeID)
+	}
+
+	return keySet, nil
+}
+
+// Sign signs the claims with the active key
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.active.method, claims)
+	token.Header["kid"] = k.active.id
+
+	return token.SignedString(k.active.private)
+}
+
+// VerificationKey finds the key a token was signed with. It's used as the jwt.Keyfunc when parsing tokens
+func (k *KeySet) VerificationKey(token *jwt.Token) (interface{}, error) {
+	id, _ := token.Header["kid"].(string)
+
+	if id == "" {
+		id = defaultKeyID
+	}
+
+	key, ok := k.keys[id]
+
+	if !ok {
+		return nil, errUnknownSigningKey
+	}
+
+	// Without this, a token could be signed with HMAC using a public key as the secret
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errUnexpectedSigningMethod
+	}
+
+	return key.public, nil
+}
+
+// JWKS gets the public keys in the set so that other services can verify tokens. HMAC keys are secret so aren't included
+func (k *KeySet) JWKS() *authPb.JWKS {
+	jwks := &authPb.JWKS{}
+
+	for _, key := range k.keys {
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, &authPb.JWK{
+				Kty: "RSA",
+				Kid: key.id,
+				Alg: key.method.Alg(),
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, &authPb.JWK{
+				Kty: "OKP",
+				Kid: key.id,
+				Alg: key.method.Alg(),
+				Use: "sig",
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+
+	// Keep the order the same between requests
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].Kid < jwks.Keys[j].Kid
+	})
+
+	return jwks
+}
+
+// jwksHandler serves the public keys over HTTP, for clients that can't call the Auth service
+func jwksHandler(keys *KeySet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(keys.JWKS())
+	}
+}
+
+// keysConfig is the format of the file given by JWT_KEYS_FILE, for example:
+//
+//	{
+//		"active": "2019-09",
+//		"keys": [
+//			{ "id": "2019-09", "algorithm": "RS256", "privateKeyFile": "/run/secrets/jwt-2019-09.pem" },
+//			{ "id": "2019-08", "algorithm": "EdDSA", "publicKey": "{base64 public key}" },
+//			{ "id": "default", "algorithm": "HS256", "secret": "{secret}" }
+//		]
+//	}
+//
+// RS256 keys are PEM encoded and EdDSA keys are base64 encoded, with private keys given as the 32 byte seed. A key
+// with only a public key can verify tokens but not sign them
+type keysConfig struct {
+	Active string      `json:"active"`
+	Keys   []keyConfig `json:"keys"`
+}
+
+type keyConfig struct {
+	ID             string `json:"id"`
+	Algorithm      string `json:"algorithm"`
+	Secret         string `json:"secret"`
+	PrivateKey     string `json:"privateKey"`
+	PrivateKeyFile string `json:"privateKeyFile"`
+	PublicKey      string `json:"publicKey"`
+	PublicKeyFile  string `json:"publicKeyFile"`
+}
+
+// LoadKeySet loads the signing keys from the file in JWT_KEYS_FILE, or uses JWT_SECRET as a single HS256 key
+func LoadKeySet() (*KeySet, error) {
+	if path := os.Getenv("JWT_KEYS_FILE"); path != "" {
+		data, err := ioutil.ReadFile(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return parseKeysConfig(data)
+	}
+
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		return NewKeySet(defaultKeyID, newHMACKey(defaultKeyID, []byte(secret)))
+	}
+
+	return nil, errNoSigningKeys
+}
+
+func parseKeysConfig(data []byte) (*KeySet, error) {
+	var config keysConfig
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid JWT keys config: %v", err)
+	}
+
+	var keys []*signingKey
+
+	for _, kc := range config.Keys {
+		key, err := kc.signingKey()
+
+		if err != nil {
+			return nil, fmt.Errorf("JWT key %q: %v", kc.ID, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(config.Active, keys...)
+}
+
+func (kc keyConfig) signingKey() (*signingKey, error) {
+	if kc.ID == "" {
+		return nil, errors.New("id is required")
+	}
+
+	private, err := valueOrFile(kc.PrivateKey, kc.PrivateKeyFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	public, err := valueOrFile(kc.PublicKey, kc.PublicKeyFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch kc.Algorithm {
+	case jwt.SigningMethodHS256.Alg():
+		if kc.Secret == "" {
+			return nil, errors.New("secret is required for HS256")
+		}
+
+		return newHMACKey(kc.ID, []byte(kc.Secret)), nil
+
+	case jwt.SigningMethodRS256.Alg():
+		if private != "" {
+			key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(private))
+
+			if err != nil {
+				return nil, err
+			}
+
+			return newRSAKey(kc.ID, key, nil), nil
+		}
+
+		if public != "" {
+			key, err := jwt.ParseRSAPublicKeyFromPEM([]byte(public))
+
+			if err != nil {
+				return nil, err
+			}
+
+			return newRSAKey(kc.ID, nil, key), nil
+		}
+
+	case verifier.SigningMethodEdDSA.Alg():
+		if private != "" {
+			seed, err := base64.StdEncoding.DecodeString(private)
+
+			if err != nil || len(seed) != ed25519.SeedSize {
+				return nil, errors.New("EdDSA private key must be a base64 encoded 32 byte seed")
+			}
+
+			return newEdDSAKey(kc.ID, ed25519.NewKeyFromSeed(seed), nil), nil
+		}
+
+		if public != "" {
+			key, err := base64.StdEncoding.DecodeString(public)
+
+			if err != nil || len(key) != ed25519.PublicKeySize {
+				return nil, errors.New("EdDSA public key must be a base64 encoded 32 byte key")
+			}
+
+			return newEdDSAKey(kc.ID, nil, ed25519.PublicKey(key)), nil
+		}
+
+	default:
+		return nil, fmt.Errorf("algorithm %q isn't supported, use HS256, RS256 or EdDSA", kc.Algorithm)
+	}
+
+	return nil, errors.New("a private or public key is required")
+}
+
+// valueOrFile gives the value if it's set, otherwise the contents of the file if that's set
+func valueOrFile(value, path string) (string, error) {
+	if value != "" || path == "" {
+		return value, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+
+	return string(data), err
+}
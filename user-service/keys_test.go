@@ -0,0 +1,258 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"golang.org/x/crypto/ed25519"
+)
+
+func createRSAKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+
+	if err != nil {
+		t.Fatalf("error generating RSA key: %v", err)
+	}
+
+	return key
+}
+
+func createEdDSAKey(t *testing.T) ed25519.PrivateKey {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+
+	if err != nil {
+		t.Fatalf("error generating EdDSA key: %v", err)
+	}
+
+	return key
+}
+
+func createTokenService(t *testing.T, activeID string, keys ...*signingKey) TokenService {
+	keySet, err := NewKeySet(activeID, keys...)
+
+	assertError(err, nil, t)
+
+	return TokenService{&fakeRepo{}, keySet, time.Hour, time.Hour}
+}
+
+// verificationError gets the error returned by the key set from the error returned when parsing a token
+func verificationError(err error) error {
+	if validationErr, ok := err.(*jwt.ValidationError); ok && validationErr.Inner != nil {
+		return validationErr.Inner
+	}
+
+	return err
+}
+
+func TestSignAndVerify(t *testing.T) {
+	tests := []struct {
+		name string
+		key  *signingKey
+	}{
+		{"HS256", newHMACKey("hmac", []byte("secret"))},
+		{"RS256", newRSAKey("rsa", createRSAKey(t), nil)},
+		{"EdDSA", newEdDSAKey("eddsa", createEdDSAKey(t), nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := createTokenService(t, tt.key.id, tt.key)
+
+			token, _, err := service.Encode(&authPb.User{Id: "123"}, "456", 2)
+
+			assertError(err, nil, t)
+
+			claims, err := service.Decode(token)
+
+			assertError(err, nil, t)
+
+			if claims.UserID != "123" || claims.SessionID != "456" || claims.TokenGeneration != 2 {
+				t.Errorf("claims didn't survive being signed: got %+v", claims)
+			}
+
+			parsed, _ := jwt.Parse(token, service.keys.VerificationKey)
+
+			if parsed.Header["kid"] != tt.key.id || parsed.Header["alg"] != tt.name {
+				t.Errorf("wanted kid %v and alg %v but got %v", tt.key.id, tt.name, parsed.Header)
+			}
+		})
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKey := newRSAKey("old", createRSAKey(t), nil)
+	newKey := newEdDSAKey("new", createEdDSAKey(t), nil)
+
+	before := createTokenService(t, "old", oldKey)
+
+	token, _, err := before.Encode(&authPb.User{Id: "123"}, "456", 0)
+
+	assertError(err, nil, t)
+
+	t.Run("token signed by the old key is accepted after rotating", func(t *testing.T) {
+		during := createTokenService(t, "new", oldKey, newKey)
+
+		_, err := during.Decode(token)
+
+		assertError(err, nil, t)
+
+		newToken, _, _ := during.Encode(&authPb.User{Id: "123"}, "456", 0)
+		parsed, _ := jwt.Parse(newToken, during.keys.VerificationKey)
+
+		if parsed.Header["kid"] != "new" {
+			t.Errorf("wanted new tokens to be signed with the new key but got %v", parsed.Header["kid"])
+		}
+	})
+
+	t.Run("token signed by the old key is rejected once the key is removed", func(t *testing.T) {
+		after := createTokenService(t, "new", newKey)
+
+		_, err := after.Decode(token)
+
+		assertError(verificationError(err), errUnknownSigningKey, t)
+	})
+}
+
+func TestAlgorithmMustMatchKey(t *testing.T) {
+	rsaKey := createRSAKey(t)
+	service := createTokenService(t, "rsa", newRSAKey("rsa", rsaKey, nil))
+
+	publicKey, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+
+	// A token signed with HMAC, using the public key that anyone can get as the secret
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, CustomClaims{UserID: "123"})
+	token.Header["kid"] = "rsa"
+	signed, _ := token.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}))
+
+	_, err := service.Decode(signed)
+
+	assertError(verificationError(err), errUnexpectedSigningMethod, t)
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey := createRSAKey(t)
+	edKey := createEdDSAKey(t)
+
+	service := createTokenService(t, "hmac",
+		newHMACKey("hmac", []byte("secret")),
+		newRSAKey("rsa", rsaKey, nil),
+		newEdDSAKey("eddsa", edKey, nil))
+
+	jwks := service.keys.JWKS()
+
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("wanted only the 2 public keys but got %v", jwks.Keys)
+	}
+
+	eddsa, rsaJWK := jwks.Keys[0], jwks.Keys[1]
+
+	if eddsa.Kid != "eddsa" || eddsa.Kty != "OKP" || eddsa.Crv != "Ed25519" ||
+		eddsa.X != base64.RawURLEncoding.EncodeToString(edKey.Public().(ed25519.PublicKey)) {
+		t.Errorf("EdDSA key is wrong: %v", eddsa)
+	}
+
+	if rsaJWK.Kid != "rsa" || rsaJWK.Kty != "RSA" || rsaJWK.Alg != "RS256" || rsaJWK.E != "AQAB" ||
+		rsaJWK.N != base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()) {
+		t.Errorf("RSA key is wrong: %v", rsaJWK)
+	}
+
+	t.Run("served over HTTP", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+
+		jwksHandler(service.keys).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+
+		var served authPb.JWKS
+
+		err := json.NewDecoder(recorder.Body).Decode(&served)
+
+		assertError(err, nil, t)
+
+		if len(served.Keys) != 2 || served.Keys[0].Kid != "eddsa" || served.Keys[1].N != rsaJWK.N {
+			t.Errorf("wanted the same keys over HTTP but got %v", served.Keys)
+		}
+	})
+
+	t.Run("from the Auth service", func(t *testing.T) {
+		handler := userHandler{&fakeRepo{}, service, PasswordResetService{}, EmailVerificationService{}, LoginThrottle{}, nil, MFAService{}, CompanyService{}, "", nil}
+
+		response := authPb.JWKS{}
+
+		err := handler.GetJWKS(createContext(), &authPb.JWKSRequest{}, &response)
+
+		assertError(err, nil, t)
+
+		if len(response.Keys) != 2 {
+			t.Errorf("wanted 2 keys but got %v", response.Keys)
+		}
+	})
+}
+
+func TestParseKeysConfig(t *testing.T) {
+	rsaKey := createRSAKey(t)
+	rsaPEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))
+
+	edKey := createEdDSAKey(t)
+	edSeed := base64.StdEncoding.EncodeToString(edKey.Seed())
+	edPublic := base64.StdEncoding.EncodeToString(edKey.Public().(ed25519.PublicKey))
+
+	config := func(active string, keys ...keyConfig) []byte {
+		data, _ := json.Marshal(keysConfig{active, keys})
+		return data
+	}
+
+	t.Run("loads each type of key", func(t *testing.T) {
+		keys, err := parseKeysConfig(config("rsa",
+			keyConfig{ID: "rsa", Algorithm: "RS256", PrivateKey: rsaPEM},
+			keyConfig{ID: "eddsa", Algorithm: "EdDSA", PrivateKey: edSeed},
+			keyConfig{ID: "eddsa-old", Algorithm: "EdDSA", PublicKey: edPublic},
+			keyConfig{ID: "hmac", Algorithm: "HS256", Secret: "secret"},
+		))
+
+		assertError(err, nil, t)
+
+		if keys.active.id != "rsa" || len(keys.keys) != 4 {
+			t.Errorf("wanted 4 keys with rsa active but got %v active from %v", keys.active.id, keys.keys)
+		}
+
+		if keys.keys["eddsa-old"].private != nil {
+			t.Errorf("wanted a key with only a public key to be verify only")
+		}
+	})
+
+	tests := []struct {
+		name   string
+		config []byte
+		want   string
+	}{
+		{"active key missing", config("missing", keyConfig{ID: "hmac", Algorithm: "HS256", Secret: "secret"}), "isn't configured"},
+		{"active key can't sign", config("eddsa", keyConfig{ID: "eddsa", Algorithm: "EdDSA", PublicKey: edPublic}), "no private key"},
+		{"unknown algorithm", config("x", keyConfig{ID: "x", Algorithm: "ES256", Secret: "secret"}), "isn't supported"},
+		{"no key", config("rsa", keyConfig{ID: "rsa", Algorithm: "RS256"}), "private or public key is required"},
+		{"bad EdDSA seed", config("eddsa", keyConfig{ID: "eddsa", Algorithm: "EdDSA", PrivateKey: "c2hvcnQ="}), "32 byte seed"},
+		{"duplicate id", config("hmac",
+			keyConfig{ID: "hmac", Algorithm: "HS256", Secret: "one"},
+			keyConfig{ID: "hmac", Algorithm: "HS256", Secret: "two"}), "more than once"},
+		{"not json", []byte("{"), "invalid JWT keys config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseKeysConfig(tt.config)
+
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("wanted an error containing %q but got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/micro/go-micro/metadata"
+	"golang.org/x/net/context"
+
+	"github.com/willdot/Go-Do/apierrors"
+)
+
+// defaultLoginLockout is how long logging in is locked for after too many failures
+const defaultLoginLockout = time.Minute * 15
+
+// LoginAttempts are the failed logins for an email or a client IP since the last time they were reset
+type LoginAttempts struct {
+	Key         string
+	Failures    int
+	LastFailure time.Time
+}
+
+// loginPolicy decides how long has to be waited before trying to log in again after failing
+type loginPolicy struct {
+	// freeAttempts is how many times logging in can fail before having to wait
+	freeAttempts int
+	// lockoutAttempts is how many times logging in can fail before it's locked for the lockout
+	lockoutAttempts int
+	// baseDelay is the wait after the first failure past the free attempts, which doubles with each failure after that
+	baseDelay time.Duration
+	lockout   time.Duration
+}
+
+// emailLoginPolicy is for the failures of a single account
+var emailLoginPolicy = loginPolicy{3, 10, time.Second, defaultLoginLockout}
+
+// ipLoginPolicy is for the failures from a single client IP. It allows more failures than for an account, as many
+// people can share an IP
+var ipLoginPolicy = loginPolicy{20, 100, time.Second, defaultLoginLockout}
+
+// wait is how long after the last failure the next try has to wait
+func (p loginPolicy) wait(failures int) time.Duration {
+	if failures < p.freeAttempts {
+		return 0
+	}
+
+	if failures >= p.lockoutAttempts {
+		return p.lockout
+	}
+
+	wait := p.baseDelay << uint(failures-p.freeAttempts)
+
+	if wait <= 0 || wait > p.lockout {
+		return p.lockout
+	}
+
+	return wait
+}
+
+// loginLimit is the policy that applies to the failures stored under a key
+type loginLimit struct {
+	key    string
+	policy loginPolicy
+}
+
+// LoginThrottle slows down guessing passwords. Failed logins are counted for each email and client IP, and once the
+// free attempts are used up each try has to wait twice as long as the last, until logging in is locked altogether
+type LoginThrottle struct {
+	repo  Repository
+	email loginPolicy
+	ip    loginPolicy
+}
+
+// limits are the limits that apply to logging in with an email from an IP. The IP isn't limited if it isn't known
+func (t LoginThrottle) limits(email, ip string) []loginLimit {
+	limits := []loginLimit{{emailLoginKey(email), t.email}}
+
+	if ip != "" {
+		limits = append(limits, loginLimit{"ip:" + ip, t.ip})
+	}
+
+	return limits
+}
+
+// emailLoginKey is the key the failures for an email are stored under. Emails are compared without case, so that
+// changing the case doesn't get around the limit
+func emailLoginKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// Check returns a rate limited error if logging in with the email or from the IP has to wait
+func (t LoginThrottle) Check(email, ip string) error {
+	now := time.Now()
+
+	for _, limit := range t.limits(email, ip) {
+		attempts, err := t.repo.GetLoginAttempts(limit.key)
+
+		if err != nil {
+			return err
+		}
+
+		retryAt := attempts.LastFailure.Add(limit.policy.wait(attempts.Failures))
+
+		if !now.Before(retryAt) {
+			continue
+		}
+
+		// Round up, so that trying again after the wait always works
+		wait := (retryAt.Sub(now) + time.Second - 1).Truncate(time.Second)
+
+		if attempts.Failures >= limit.policy.lockoutAttempts {
+			return apierrors.RateLimited("Logging in is locked after too many failed attempts, try again in %v or ask an admin to unlock the account", wait)
+		}
+
+		return apierrors.RateLimited("Too many failed attempts to log in, try again in %v", wait)
+	}
+
+	return nil
+}
+
+// Failed records a failed login with the email from the IP. Failures are forgotten once there hasn't been one for the
+// lockout
+func (t LoginThrottle) Failed(email, ip string) error {
+	now := time.Now()
+
+	for _, limit := range t.limits(email, ip) {
+		if _, err := t.repo.RecordLoginFailure(limit.key, now, limit.policy.lockout); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Reset forgets the failed logins for an email, after a successful login or when an admin unlocks the account. The
+// failures from IPs are kept, as one correct password doesn't mean the other guesses from there were innocent
+func (t LoginThrottle) Reset(email string) error {
+	return t.repo.ClearLoginAttempts(emailLoginKey(email))
+}
+
+// clientIP gets the IP that a request came from. The address that connected to the service is used, unless it's one of
+// the trusted proxies, such as the API gateway. Then the X-Forwarded-For header that it passes on as metadata is read
+// from the end, skipping the trusted proxies, as only the addresses added by them can be believed and the ones before
+// can be made up by the client. Returns an empty string if the IP isn't known
+func clientIP(ctx context.Context, trustedProxies []*net.IPNet) string {
+	meta, ok := metadata.FromContext(ctx)
+
+	if !ok {
+		return ""
+	}
+
+	nearest := hostIP(meta["Remote"])
+
+	if nearest == nil {
+		return ""
+	}
+
+	if !isTrustedProxy(nearest, trustedProxies) {
+		return nearest.String()
+	}
+
+	addresses := strings.Split(meta["X-Forwarded-For"], ",")
+
+	for i := len(addresses) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(addresses[i]))
+
+		// Anything that isn't an address wasn't added by a proxy, so the closest proxy is the best that's known
+		if ip == nil {
+			break
+		}
+
+		if !isTrustedProxy(ip, trustedProxies) {
+			return ip.String()
+		}
+
+		nearest = ip
+	}
+
+	return nearest.String()
+}
+
+// hostIP gets the IP from a host and port, or from an address without a port
+func hostIP(address string) net.IP {
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		address = host
+	}
+
+	return net.ParseIP(address)
+}
+
+func isTrustedProxy(ip net.IP, trustedProxies []*net.IPNet) bool {
+	for _, proxy := range trustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseTrustedProxies parses a comma separated list of the IPs and CIDR ranges of trusted proxies
+func parseTrustedProxies(list string) ([]*net.IPNet, error) {
+	var proxies []*net.IPNet
+
+	for _, proxy := range strings.Split(list, ",") {
+		proxy = strings.TrimSpace(proxy)
+
+		if proxy == "" {
+			continue
+		}
+
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+
+		if err != nil {
+			return nil, err
+		}
+
+		proxies = append(proxies, network)
+	}
+
+	return proxies, nil
+}
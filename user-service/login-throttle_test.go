@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/metadata"
+	"golang.org/x/net/context"
+
+	"github.com/willdot/Go-Do/apierrors"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/user-service/verifier"
+)
+
+func TestLoginPolicyWait(t *testing.T) {
+	policy := loginPolicy{3, 10, time.Second, time.Minute * 15}
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{2, 0},
+		{3, time.Second},
+		{4, time.Second * 2},
+		{9, time.Second * 64},
+		{10, time.Minute * 15},
+		{100, time.Minute * 15},
+	}
+
+	for _, tt := range tests {
+		if got := policy.wait(tt.failures); got != tt.want {
+			t.Errorf("wanted a wait of %v after %v failures but got %v", tt.want, tt.failures, got)
+		}
+	}
+
+	t.Run("backoff is capped at the lockout", func(t *testing.T) {
+		policy := loginPolicy{0, 100, time.Second, time.Minute}
+
+		if got := policy.wait(50); got != time.Minute {
+			t.Errorf("wanted a wait of a minute but got %v", got)
+		}
+	})
+}
+
+// createIPContext creates a context for a request through the API gateway, with the X-Forwarded-For header it passes on
+func createIPContext(forwardedFor string) context.Context {
+	return metadata.NewContext(context.Background(), metadata.Metadata{"Remote": fakeGateway + ":50000", "X-Forwarded-For": forwardedFor})
+}
+
+func TestLoginThrottle(t *testing.T) {
+
+	wrongPassword := authPb.User{Email: "fake@fake.com", Password: "wrong"}
+
+	t.Run("logging in has to wait after the free attempts", func(t *testing.T) {
+		service := createService(false)
+
+		for i := 0; i < emailLoginPolicy.freeAttempts; i++ {
+			err := service.Auth(createContext(), &wrongPassword, &authPb.Token{})
+
+			assertError(err, errInvalidCredentials, t)
+		}
+
+		err := service.Auth(createContext(), &authPb.User{Email: fakeUser.Email, Password: "test"}, &authPb.Token{})
+
+		assertCode(err, apierrors.CodeRateLimited, t)
+	})
+
+	t.Run("logging in is locked after too many failures", func(t *testing.T) {
+		service := createService(false)
+		repo := service.repo.(*fakeRepo)
+
+		repo.loginAttempts[emailLoginKey(fakeUser.Email)] = &LoginAttempts{emailLoginKey(fakeUser.Email), emailLoginPolicy.lockoutAttempts, time.Now()}
+
+		err := service.Auth(createContext(), &authPb.User{Email: fakeUser.Email, Password: "test"}, &authPb.Token{})
+
+		assertCode(err, apierrors.CodeRateLimited, t)
+
+		if !strings.Contains(err.Error(), "locked") {
+			t.Errorf("wanted a locked error but got %v", err)
+		}
+	})
+
+	t.Run("logging in works again after the lockout and forgets the failures", func(t *testing.T) {
+		service := createService(false)
+		repo := service.repo.(*fakeRepo)
+
+		key := emailLoginKey(fakeUser.Email)
+		repo.loginAttempts[key] = &LoginAttempts{key, emailLoginPolicy.lockoutAttempts, time.Now().Add(-emailLoginPolicy.lockout)}
+
+		login(service, t)
+
+		if _, ok := repo.loginAttempts[key]; ok {
+			t.Errorf("wanted the failures to be forgotten but got %v", repo.loginAttempts[key])
+		}
+	})
+
+	t.Run("the case of the email doesn't matter", func(t *testing.T) {
+		service := createService(false)
+
+		service.Auth(createContext(), &wrongPassword, &authPb.Token{})
+		service.Auth(createContext(), &authPb.User{Email: "FAKE@fake.com", Password: "wrong"}, &authPb.Token{})
+
+		attempts := service.repo.(*fakeRepo).loginAttempts[emailLoginKey(fakeUser.Email)]
+
+		if attempts == nil || attempts.Failures != 2 {
+			t.Errorf("wanted 2 failures but got %v", attempts)
+		}
+	})
+
+	t.Run("unknown emails fail like wrong passwords and are counted", func(t *testing.T) {
+		service := createService(false)
+
+		err := service.Auth(createContext(), &authPb.User{Email: "nobody@fake.com", Password: "wrong"}, &authPb.Token{})
+
+		assertError(err, errInvalidCredentials, t)
+
+		if attempts := service.repo.(*fakeRepo).loginAttempts[emailLoginKey("nobody@fake.com")]; attempts == nil || attempts.Failures != 1 {
+			t.Errorf("wanted 1 failure but got %v", attempts)
+		}
+	})
+
+	t.Run("failures from an IP are counted across emails", func(t *testing.T) {
+		service := createService(false)
+
+		service.Auth(createIPContext("192.0.2.7"), &wrongPassword, &authPb.Token{})
+		service.Auth(createIPContext("192.0.2.7"), &authPb.User{Email: "nobody@fake.com", Password: "wrong"}, &authPb.Token{})
+
+		if attempts := service.repo.(*fakeRepo).loginAttempts["ip:192.0.2.7"]; attempts == nil || attempts.Failures != 2 {
+			t.Errorf("wanted 2 failures but got %v", attempts)
+		}
+	})
+
+	t.Run("changing password is throttled too", func(t *testing.T) {
+		service := createService(false)
+		repo := service.repo.(*fakeRepo)
+
+		repo.loginAttempts[emailLoginKey(fakeUser.Email)] = &LoginAttempts{emailLoginKey(fakeUser.Email), emailLoginPolicy.lockoutAttempts, time.Now()}
+
+		err := service.ChangePassword(createContext(), &authPb.PasswordChange{Email: fakeUser.Email, OldPassword: "test", NewPassword: "password1"}, &authPb.Token{})
+
+		assertCode(err, apierrors.CodeRateLimited, t)
+	})
+}
+
+func TestUnlockUser(t *testing.T) {
+
+	lock := func(service userHandler) {
+		key := emailLoginKey("locked@fake.com")
+		service.repo.(*fakeRepo).loginAttempts[key] = &LoginAttempts{key, emailLoginPolicy.lockoutAttempts, time.Now()}
+	}
+
+	defer func() { fakeUser.Role = "" }()
+
+	t.Run("system admins can unlock users", func(t *testing.T) {
+		service := createService(false)
+		fakeUser.Role = verifier.RoleSystemAdmin
+		lock(service)
+
+		tokens := login(service, t)
+
+		err := service.UnlockUser(createTokenContext(tokens.Token), &authPb.UnlockUserRequest{Email: "locked@fake.com"}, &authPb.Response{})
+
+		assertError(err, nil, t)
+
+		if _, ok := service.repo.(*fakeRepo).loginAttempts[emailLoginKey("locked@fake.com")]; ok {
+			t.Errorf("wanted the user to be unlocked")
+		}
+	})
+
+	t.Run("company admins can unlock users in their company", func(t *testing.T) {
+		service := createService(false)
+		fakeUser.Role = verifier.RoleCompanyAdmin
+		lock(service)
+
+		repo := service.repo.(*fakeRepo)
+		repo.users = append(repo.users, &authPb.User{Id: "456", Email: "locked@fake.com", CompanyId: fakeUser.CompanyId})
+
+		tokens := login(service, t)
+
+		err := service.UnlockUser(createTokenContext(tokens.Token), &authPb.UnlockUserRequest{Email: "locked@fake.com"}, &authPb.Response{})
+
+		assertError(err, nil, t)
+	})
+
+	t.Run("company admins can't unlock users in other companies", func(t *testing.T) {
+		service := createService(false)
+		fakeUser.Role = verifier.RoleCompanyAdmin
+		lock(service)
+
+		repo := service.repo.(*fakeRepo)
+		repo.users = append(repo.users, &authPb.User{Id: "456", Email: "locked@fake.com", CompanyId: "other"})
+
+		tokens := login(service, t)
+
+		err := service.UnlockUser(createTokenContext(tokens.Token), &authPb.UnlockUserRequest{Email: "locked@fake.com"}, &authPb.Response{})
+
+		assertCode(err, apierrors.CodeForbidden, t)
+	})
+
+	t.Run("other users can't", func(t *testing.T) {
+		service := createService(false)
+		fakeUser.Role = verifier.RoleUser
+		lock(service)
+
+		tokens := login(service, t)
+
+		err := service.UnlockUser(createTokenContext(tokens.Token), &authPb.UnlockUserRequest{Email: "locked@fake.com"}, &authPb.Response{})
+
+		assertCode(err, apierrors.CodeForbidden, t)
+
+		if _, ok := service.repo.(*fakeRepo).loginAttempts[emailLoginKey("locked@fake.com")]; !ok {
+			t.Errorf("wanted the user to still be locked")
+		}
+	})
+
+	t.Run("a token is needed", func(t *testing.T) {
+		service := createService(false)
+
+		err := service.UnlockUser(createContext(), &authPb.UnlockUserRequest{Email: "locked@fake.com"}, &authPb.Response{})
+
+		assertCode(err, apierrors.CodeUnauthenticated, t)
+	})
+}
+
+func TestClientIP(t *testing.T) {
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"no metadata", context.Background(), ""},
+		{"one address", createIPContext("192.0.2.7"), "192.0.2.7"},
+		{"the address added by the gateway is used", createIPContext("10.0.0.1, 192.0.2.7"), "192.0.2.7"},
+		{"trusted proxies in front of the gateway are skipped", createIPContext("192.0.2.7, 10.0.0.10"), "192.0.2.7"},
+		{"not an address", createIPContext("unknown"), fakeGateway},
+		{"no header", createIPContext(""), fakeGateway},
+		{"IPv6", createIPContext("2001:db8::1"), "2001:db8::1"},
+		{"requests that aren't from a trusted proxy use the address they came from",
+			metadata.NewContext(context.Background(), metadata.Metadata{"Remote": "192.0.2.9:1234"}), "192.0.2.9"},
+		{"a forged header from a client that isn't a trusted proxy is ignored",
+			metadata.NewContext(context.Background(), metadata.Metadata{"Remote": "192.0.2.9:1234", "X-Forwarded-For": "203.0.113.1"}), "192.0.2.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clientIP(tt.ctx, fakeTrustedProxies); got != tt.want {
+				t.Errorf("wanted %q but got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+
+	proxies, err := parseTrustedProxies(" 10.0.0.10, 172.28.0.0/16,2001:db8::1 ,")
+
+	assertError(err, nil, t)
+
+	for _, ip := range []string{"10.0.0.10", "172.28.5.1", "2001:db8::1"} {
+		if !isTrustedProxy(net.ParseIP(ip), proxies) {
+			t.Errorf("wanted %v to be trusted", ip)
+		}
+	}
+
+	if isTrustedProxy(net.ParseIP("10.0.0.11"), proxies) {
+		t.Errorf("wanted only the addresses given to be trusted")
+	}
+
+	if _, err := parseTrustedProxies("not a proxy"); err == nil {
+		t.Errorf("wanted an error for an invalid proxy")
+	}
+}
+
+func TestLoginAuditLog(t *testing.T) {
+
+	service := createService(false)
+
+	out := &bytes.Buffer{}
+	service.auditLog = NewAuditLog(out)
+
+	service.Auth(createIPContext("192.0.2.7"), &authPb.User{Email: fakeUser.Email, Password: "wrong password"}, &authPb.Token{})
+	login(service, t)
+
+	if strings.Contains(out.String(), "wrong password") || strings.Contains(out.String(), `"test"`) {
+		t.Fatalf("wanted no passwords in the audit log but got %v", out.String())
+	}
+
+	var events []AuditEvent
+
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var event AuditEvent
+
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("wanted a JSON line but got %q: %v", line, err)
+		}
+
+		events = append(events, event)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("wanted 2 events but got %v", events)
+	}
+
+	if events[0].Outcome != outcomeWrongPassword || events[0].IP != "192.0.2.7" || events[0].Email != fakeUser.Email {
+		t.Errorf("wanted a wrong password from 192.0.2.7 but got %+v", events[0])
+	}
+
+	if events[1].Outcome != outcomeSuccess || events[1].UserID != fakeUser.Id || events[1].Time.IsZero() {
+		t.Errorf("wanted a successful login but got %+v", events[1])
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errNoMailer = errors.New("No mailer configured, set SMTP_ADDRESS or MAIL_DIR")
+
+var errInvalidEmailHeader = errors.New("Email address and subject can't contain line breaks")
+
+// defaultMailFrom is who emails are from if MAIL_FROM isn't set
+const defaultMailFrom = "go-do@localhost"
+
+// Email is an email to a user
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails to users
+type Mailer interface {
+	Send(email Email) error
+}
+
+// SMTPMailer sends emails through an SMTP server
+type SMTPMailer struct {
+	address string
+	from    string
+	auth    smtp.Auth
+}
+
+// Send sends an email
+func (m *SMTPMailer) Send(email Email) error {
+	message, err := formatEmail(m.from, email)
+
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(m.address, m.auth, m.from, []string{email.To}, message)
+}
+
+// FileMailer writes each email to a file in a directory instead of sending it, so that emails can be read when running
+// locally without a mail server
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// Send writes an email to a new file
+func (m *FileMailer) Send(email Email) error {
+	message, err := formatEmail(m.from, email)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), strings.Replace(email.To, "/", "_", -1))
+
+	return ioutil.WriteFile(filepath.Join(m.dir, name), message, 0600)
+}
+
+// MemoryMailer keeps emails in memory instead of sending them, for tests
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []Email
+}
+
+// Send keeps the email
+func (m *MemoryMailer) Send(email Email) error {
+	if _, err := formatEmail(defaultMailFrom, email); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sent = append(m.sent, email)
+
+	return nil
+}
+
+// Sent gets the emails that have been sent
+func (m *MemoryMailer) Sent() []Email {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Email(nil), m.sent...)
+}
+
+// formatEmail creates the message for an email, with its headers
+func formatEmail(from string, email Email) ([]byte, error) {
+	// Line breaks would let whoever controls the address or subject add their own headers
+	for _, header := range []string{from, email.To, email.Subject} {
+		if strings.ContainsAny(header, "\r\n") {
+			return nil, errInvalidEmailHeader
+		}
+	}
+
+	var message bytes.Buffer
+
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", email.To)
+	fmt.Fprintf(&message, "Subject: %s\r\n", email.Subject)
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.Replace(email.Body, "\n", "\r\n", -1))
+
+	return message.Bytes(), nil
+}
+
+// LoadMailer creates an SMTP mailer if SMTP_ADDRESS is set, otherwise a mailer that writes emails to MAIL_DIR
+func LoadMailer() (Mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+
+	if from == "" {
+		from = defaultMailFrom
+	}
+
+	if address := os.Getenv("SMTP_ADDRESS"); address != "" {
+		host, _, err := net.SplitHostPort(address)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid SMTP_ADDRESS: %v", err)
+		}
+
+		var auth smtp.Auth
+
+		if username := os.Getenv("SMTP_USERNAME"); username != "" {
+			auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+		}
+
+		return &SMTPMailer{address, from, auth}, nil
+	}
+
+	if dir := os.Getenv("MAIL_DIR"); dir != "" {
+		return &FileMailer{dir, from}, nil
+	}
+
+	return nil, errNoMailer
+}
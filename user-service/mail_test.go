@@ -0,0 +1,48 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mail")
+
+	assertError(err, nil, t)
+
+	mailer := FileMailer{filepath.Join(dir, "sent"), "go-do@example.com"}
+
+	err = mailer.Send(Email{To: "fake@fake.com", Subject: "Hello", Body: "first line\nsecond line"})
+
+	assertError(err, nil, t)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "sent", "*.eml"))
+
+	if len(files) != 1 {
+		t.Fatalf("wanted 1 email file but got %v", files)
+	}
+
+	data, _ := ioutil.ReadFile(files[0])
+	message := string(data)
+
+	for _, want := range []string{"From: go-do@example.com\r\n", "To: fake@fake.com\r\n", "Subject: Hello\r\n", "\r\n\r\nfirst line\r\nsecond line"} {
+		if !strings.Contains(message, want) {
+			t.Errorf("wanted the email to contain %q but got %q", want, message)
+		}
+	}
+}
+
+func TestEmailHeadersCantBeInjected(t *testing.T) {
+	emails := []Email{
+		{To: "fake@fake.com\r\nBcc: everyone@fake.com", Subject: "Hello"},
+		{To: "fake@fake.com", Subject: "Hello\nBcc: everyone@fake.com"},
+	}
+
+	for _, email := range emails {
+		err := (&MemoryMailer{}).Send(email)
+
+		assertError(err, errInvalidEmailHeader, t)
+	}
+}
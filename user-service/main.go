@@ -2,34 +2,149 @@ package main
 
 import (
 	"fmt"
+	"log"
+	"net/http"
+	"os"
 	"time"
 
-	authPb "github.com/willdot/go-do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/apierrors"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
 
 	"github.com/micro/go-micro"
 )
 
+const (
+	// defaultAccessTokenLifetime is how long an access token can be used for before it has to be refreshed
+	defaultAccessTokenLifetime = time.Minute * 15
+	// defaultRefreshTokenLifetime is how long a session can go without being refreshed before the user has to log in again
+	defaultRefreshTokenLifetime = time.Hour * 24 * 30
+	// defaultPasswordResetLifetime is how long a password reset token can be used for
+	defaultPasswordResetLifetime = time.Hour
+	// defaultVerificationLifetime is how long an email verification token can be used for
+	defaultVerificationLifetime = time.Hour * 24
+	// defaultVerificationResendInterval is how long a user has to wait between verification emails
+	defaultVerificationResendInterval = time.Minute
+	// defaultMFATokenLifetime is how long a user has to enter their two factor authentication code after logging in
+	defaultMFATokenLifetime = time.Minute * 5
+	// defaultCompanyInviteLifetime is how long an invite to join a company can be accepted for
+	defaultCompanyInviteLifetime = time.Hour * 24 * 7
+)
+
 func main() {
 
 	CassandraSession := Session
 	defer CassandraSession.Close()
 
-	tokenExpireTime := time.Now().Add(time.Hour * 72).Unix()
-
 	repo := &UserRepository{CassandraSession}
 
-	tokenService := TokenService{repo, tokenExpireTime}
+	keys, err := LoadKeySet()
+
+	if err != nil {
+		log.Fatalf("error loading JWT keys: %v", err)
+	}
+
+	tokenService := TokenService{
+		repo,
+		keys,
+		durationFromEnv("ACCESS_TOKEN_LIFETIME", defaultAccessTokenLifetime),
+		durationFromEnv("REFRESH_TOKEN_LIFETIME", defaultRefreshTokenLifetime),
+	}
+
+	mailer, err := LoadMailer()
+
+	if err != nil {
+		log.Fatalf("error loading mailer: %v", err)
+	}
+
+	passwordResetService := PasswordResetService{
+		repo,
+		mailer,
+		durationFromEnv("PASSWORD_RESET_LIFETIME", defaultPasswordResetLifetime),
+		os.Getenv("PASSWORD_RESET_URL"),
+	}
+
+	verificationService := EmailVerificationService{
+		repo,
+		mailer,
+		durationFromEnv("EMAIL_VERIFICATION_LIFETIME", defaultVerificationLifetime),
+		os.Getenv("EMAIL_VERIFICATION_URL"),
+		durationFromEnv("EMAIL_VERIFICATION_RESEND_INTERVAL", defaultVerificationResendInterval),
+		os.Getenv("REQUIRE_VERIFIED_EMAIL") == "true",
+	}
+
+	lockout := durationFromEnv("LOGIN_LOCKOUT_DURATION", defaultLoginLockout)
+
+	emailPolicy := emailLoginPolicy
+	emailPolicy.lockout = lockout
+
+	ipPolicy := ipLoginPolicy
+	ipPolicy.lockout = lockout
+
+	loginThrottle := LoginThrottle{repo, emailPolicy, ipPolicy}
 
+	// TRUSTED_PROXIES are the addresses of the API gateway and any proxies in front of it, whose X-Forwarded-For header
+	// is believed to find the IP of the client
+	trustedProxies, err := parseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+
+	if err != nil {
+		log.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	handler := &userHandler{
+		repo,
+		tokenService,
+		passwordResetService,
+		verificationService,
+		loginThrottle,
+		NewAuditLog(os.Stdout),
+		MFAService{repo, mfaIssuer, durationFromEnv("MFA_TOKEN_LIFETIME", defaultMFATokenLifetime)},
+		CompanyService{repo, mailer, durationFromEnv("COMPANY_INVITE_LIFETIME", defaultCompanyInviteLifetime)},
+		os.Getenv("SERVICE_TOKEN"),
+		trustedProxies,
+	}
+
+	// Roles are stored on users, so ADMIN_EMAILS is only needed to make the first system admins when the service starts
+	promoteAdmins(repo, parseEmails(os.Getenv("ADMIN_EMAILS")))
+
+	// Requests are authorised before they're validated, so that requests without a token don't learn anything
 	srv := micro.NewService(
 		micro.Name("go_do.auth"),
+		micro.WrapHandler(apierrors.HandlerWrapper, handler.AuthorisationWrapper, newValidator().HandlerWrapper),
 	)
 
 	srv.Init()
 
-	authPb.RegisterAuthHandler(srv.Server(), &userHandler{repo, tokenService})
+	authPb.RegisterAuthHandler(srv.Server(), handler)
+
+	// The public keys are also served over HTTP so that anything can verify tokens, not just other micro services
+	if jwksAddress := os.Getenv("JWKS_ADDRESS"); jwksAddress != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/.well-known/jwks.json", jwksHandler(keys))
+
+		go func() {
+			log.Println(http.ListenAndServe(jwksAddress, mux))
+		}()
+	}
 
 	// Run the server
 	if err := srv.Run(); err != nil {
 		fmt.Println(err)
 	}
 }
+
+// durationFromEnv reads a duration such as "15m" from an environment variable, using the default if it isn't set
+func durationFromEnv(name string, defaultDuration time.Duration) time.Duration {
+	value := os.Getenv(name)
+
+	if value == "" {
+		return defaultDuration
+	}
+
+	duration, err := time.ParseDuration(value)
+
+	if err != nil {
+		log.Fatalf("invalid %s: %v", name, err)
+	}
+
+	return duration
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"crypto/rand"
+	"strings"
+	"time"
+
+	"github.com/willdot/Go-Do/apierrors"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+)
+
+var errMFAAlreadyEnabled = apierrors.Conflict("Two factor authentication is already turned on")
+
+var errMFANotEnrolled = apierrors.Conflict("Enrol an authenticator app with EnrollTOTP first")
+
+var errMFANotEnabled = apierrors.Conflict("Two factor authentication isn't turned on")
+
+var errInvalidMFACode = apierrors.Unauthenticated("Two factor authentication code is not valid")
+
+var errInvalidMFAToken = apierrors.Unauthenticated("Two factor login token is not valid, log in again")
+
+var errMFATokenExpired = apierrors.Unauthenticated("Two factor login token has expired, log in again")
+
+const (
+	// recoveryCodeCount is how many recovery codes a user is given
+	recoveryCodeCount = 10
+	// recoveryCodeBytes is how random each recovery code is, which is 8 characters once encoded
+	recoveryCodeBytes = 5
+	// mfaIssuer is the name authenticator apps show the account under
+	mfaIssuer = "Go-Do"
+)
+
+// TOTPSettings is the authenticator app secret of a user. The secret is set when the user enrols, but isn't needed to
+// log in until they've confirmed it with a code
+type TOTPSettings struct {
+	Secret  string
+	Enabled bool
+	// LastStep is the step of the last code that was used, so that a code can't be used twice
+	LastStep int64
+}
+
+// MFAService handles two factor authentication with authenticator apps (TOTP) and recovery codes
+type MFAService struct {
+	repo   Repository
+	issuer string
+	// challengeLifetime is how long the mfa token from logging in can be used for
+	challengeLifetime time.Duration
+}
+
+func (s *MFAService) challenges() oneTimeTokens {
+	return oneTimeTokens{s.repo, purposeMFAChallenge, s.challengeLifetime, errInvalidMFAToken, errMFATokenExpired}
+}
+
+// Enroll creates a new secret for a user to add to their authenticator app, returning it and the otpauth URI for it.
+// Two factor authentication isn't turned on until the secret is confirmed
+func (s *MFAService) Enroll(user *authPb.User) (string, string, error) {
+
+	settings, err := s.repo.GetTOTP(user.Id)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if settings.Enabled {
+		return "", "", errMFAAlreadyEnabled
+	}
+
+	secret, err := newTOTPSecret()
+
+	if err != nil {
+		return "", "", err
+	}
+
+	err = s.repo.SetTOTPSecret(user.Id, secret)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return secret, totpURI(s.issuer, user.Email, secret), nil
+}
+
+// Confirm turns on two factor authentication once the user has shown that their authenticator app works by sending a
+// code from it, and returns their recovery codes
+func (s *MFAService) Confirm(user *authPb.User, code string) ([]string, error) {
+
+	settings, err := s.repo.GetTOTP(user.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.Enabled {
+		return nil, errMFAAlreadyEnabled
+	}
+
+	if settings.Secret == "" {
+		return nil, errMFANotEnrolled
+	}
+
+	step, ok := checkTOTP(settings.Secret, normaliseCode(code), time.Now())
+
+	if !ok {
+		return nil, errInvalidMFACode
+	}
+
+	err = s.repo.EnableTOTP(user.Id, step)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newRecoveryCodes(user.Id)
+}
+
+// RecoveryCodes replaces the recovery codes of a user, which needs a code from their authenticator app
+func (s *MFAService) RecoveryCodes(user *authPb.User, code string) ([]string, error) {
+
+	settings, err := s.repo.GetTOTP(user.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !settings.Enabled {
+		return nil, errMFANotEnabled
+	}
+
+	if err := s.useCode(user.Id, settings, code); err != nil {
+		return nil, err
+	}
+
+	return s.newRecoveryCodes(user.Id)
+}
+
+// Challenge creates the mfa token that has to be sent with a code to finish logging in
+func (s *MFAService) Challenge(userID string) (string, error) {
+	return s.challenges().issue(userID)
+}
+
+// UseChallenge checks an mfa token and uses it up, returning the id of the user it was issued to. Each token can only
+// be tried once, so a wrong code means logging in again
+func (s *MFAService) UseChallenge(token string) (string, error) {
+	return s.challenges().use(token)
+}
+
+// Verify checks the second factor of a user, which is either a code from their authenticator app or one of their
+// recovery codes. Either is used up, so that it can't be used again
+func (s *MFAService) Verify(userID, code, recoveryCode string) error {
+
+	if recoveryCode != "" {
+		used, err := s.repo.UseRecoveryCode(userID, hashTokenSecret(normaliseCode(recoveryCode)))
+
+		if err != nil {
+			return err
+		}
+
+		if !used {
+			return errInvalidMFACode
+		}
+
+		return nil
+	}
+
+	settings, err := s.repo.GetTOTP(userID)
+
+	if err != nil {
+		return err
+	}
+
+	if !settings.Enabled {
+		return errMFANotEnabled
+	}
+
+	return s.useCode(userID, settings, code)
+}
+
+// useCode checks a code from an authenticator app, and records that it has been used. Codes from before the last one
+// that was used are rejected too, as they'd have been seen by then
+func (s *MFAService) useCode(userID string, settings *TOTPSettings, code string) error {
+
+	step, ok := checkTOTP(settings.Secret, normaliseCode(code), time.Now())
+
+	if !ok || step <= settings.LastStep {
+		return errInvalidMFACode
+	}
+
+	// If the same code is sent twice at once, only one of them gets to use it
+	used, err := s.repo.UseTOTPStep(userID, step, settings.LastStep)
+
+	if err != nil {
+		return err
+	}
+
+	if !used {
+		return errInvalidMFACode
+	}
+
+	return nil
+}
+
+// newRecoveryCodes replaces the recovery codes of a user with new ones. Only hashes of the codes are stored, so they
+// can't be shown again
+func (s *MFAService) newRecoveryCodes(userID string) ([]string, error) {
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		random := make([]byte, recoveryCodeBytes)
+
+		if _, err := rand.Read(random); err != nil {
+			return nil, err
+		}
+
+		code := strings.ToLower(totpEncoding.EncodeToString(random))
+
+		codes[i] = code[:4] + "-" + code[4:]
+		hashes[i] = hashTokenSecret(code)
+	}
+
+	err := s.repo.ReplaceRecoveryCodes(userID, hashes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+var errOneTimeTokenNotFound = errors.New("Token not found")
+
+// OneTimeToken is a token emailed to a user, such as a password reset token. Only a hash of the token is stored, and it
+// can only be used once
+type OneTimeToken struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	ExpiresAt int64
+}
+
+// tokenPurpose is what a one time token is for. The tokens for each purpose are stored in the table named after it
+type tokenPurpose string
+
+const (
+	purposePasswordReset     tokenPurpose = "password_reset"
+	purposeEmailVerification tokenPurpose = "email_verification"
+	purposeMFAChallenge      tokenPurpose = "mfa_challenge"
+)
+
+// oneTimeTokens issues and uses the tokens for a purpose, returning the errors for that purpose when a token can't be used
+type oneTimeTokens struct {
+	repo       Repository
+	purpose    tokenPurpose
+	lifetime   time.Duration
+	errInvalid error
+	errExpired error
+}
+
+// issue creates a token for a user
+func (o oneTimeTokens) issue(userID string) (string, error) {
+
+	secret, hash, err := newTokenSecret()
+
+	if err != nil {
+		return "", err
+	}
+
+	token := OneTimeToken{
+		UserID:    userID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(o.lifetime).Unix(),
+	}
+
+	err = o.repo.CreateOneTimeToken(o.purpose, &token)
+
+	if err != nil {
+		return "", err
+	}
+
+	return joinToken(token.ID, secret), nil
+}
+
+// use checks a token and uses it up, returning the id of the user it was issued to
+func (o oneTimeTokens) use(token string) (string, error) {
+
+	id, secret, ok := splitToken(token)
+
+	if !ok {
+		return "", o.errInvalid
+	}
+
+	stored, err := o.repo.GetOneTimeToken(o.purpose, id)
+
+	if err == errOneTimeTokenNotFound {
+		return "", o.errInvalid
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if stored.TokenHash != hashTokenSecret(secret) {
+		return "", o.errInvalid
+	}
+
+	if stored.ExpiresAt < time.Now().Unix() {
+		return "", o.errExpired
+	}
+
+	// If the same token is used twice at once, only one of them gets to delete it
+	deleted, err := o.repo.DeleteOneTimeToken(o.purpose, id)
+
+	if err != nil {
+		return "", err
+	}
+
+	if !deleted {
+		return "", o.errInvalid
+	}
+
+	return stored.UserID, nil
+}
+
+// tokenLink adds a token to the page it's used on as the token query parameter. If there's no page, it's just the token
+func tokenLink(page, token string) (string, error) {
+	if page == "" {
+		return token, nil
+	}
+
+	link, err := url.Parse(page)
+
+	if err != nil {
+		return "", err
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String(), nil
+}
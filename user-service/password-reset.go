@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/willdot/Go-Do/apierrors"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+)
+
+var errInvalidResetToken = apierrors.Validation("Password reset token is not valid")
+
+var errResetTokenExpired = apierrors.Validation("Password reset token has expired, request a new one")
+
+// PasswordResetService emails users tokens that let them set a new password without knowing their old one
+type PasswordResetService struct {
+	repo   Repository
+	mailer Mailer
+	// lifetime is how long a reset token can be used for
+	lifetime time.Duration
+	// url is the page where users reset their password, which the token is added to as a query parameter. Without it
+	// the token is sent on its own
+	url string
+}
+
+func (s *PasswordResetService) tokens() oneTimeTokens {
+	return oneTimeTokens{s.repo, purposePasswordReset, s.lifetime, errInvalidResetToken, errResetTokenExpired}
+}
+
+// Send creates a reset token for a user and emails it to them
+func (s *PasswordResetService) Send(user *authPb.User) error {
+
+	token, err := s.tokens().issue(user.Id)
+
+	if err != nil {
+		return err
+	}
+
+	link, err := tokenLink(s.url, token)
+
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(Email{
+		To:      user.Email,
+		Subject: "Reset your Go-Do password",
+		Body: fmt.Sprintf("Someone asked to reset your Go-Do password. If it was you, reset it with:\n\n%s\n\n"+
+			"It can be used once in the next %v. If it wasn't you, you can ignore this email.\n", link, s.lifetime),
+	})
+}
+
+// Use checks a reset token and uses it up, returning the id of the user it was issued to
+func (s *PasswordResetService) Use(token string) (string, error) {
+	return s.tokens().use(token)
+}
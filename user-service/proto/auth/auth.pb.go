@@ -27,11 +27,18 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type User struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// company is the name the user gave for their company, and doesn't make them a member of one. That's companyId
 	Company              string   `protobuf:"bytes,3,opt,name=company,proto3" json:"company,omitempty"`
 	Email                string   `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
 	Password             string   `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
+	Timezone             string   `protobuf:"bytes,6,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	CarryOverDailyDo     bool     `protobuf:"varint,7,opt,name=carryOverDailyDo,proto3" json:"carryOverDailyDo,omitempty"`
+	EmailVerified        bool     `protobuf:"varint,8,opt,name=emailVerified,proto3" json:"emailVerified,omitempty"`
+	MfaEnabled           bool     `protobuf:"varint,9,opt,name=mfaEnabled,proto3" json:"mfaEnabled,omitempty"`
+	Role                 string   `protobuf:"bytes,10,opt,name=role,proto3" json:"role,omitempty"`
+	CompanyId            string   `protobuf:"bytes,11,opt,name=companyId,proto3" json:"companyId,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -97,6 +104,159 @@ func (m *User) GetPassword() string {
 	return ""
 }
 
+func (m *User) GetTimezone() string {
+	if m != nil {
+		return m.Timezone
+	}
+	return ""
+}
+
+func (m *User) GetCarryOverDailyDo() bool {
+	if m != nil {
+		return m.CarryOverDailyDo
+	}
+	return false
+}
+
+func (m *User) GetEmailVerified() bool {
+	if m != nil {
+		return m.EmailVerified
+	}
+	return false
+}
+
+func (m *User) GetMfaEnabled() bool {
+	if m != nil {
+		return m.MfaEnabled
+	}
+	return false
+}
+
+func (m *User) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *User) GetCompanyId() string {
+	if m != nil {
+		return m.CompanyId
+	}
+	return ""
+}
+
+// Company is a tenant that users can be members of. Members can only see the tasks in their company
+type Company struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	OwnerId              string   `protobuf:"bytes,3,opt,name=ownerId,proto3" json:"ownerId,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,4,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Company) Reset()         { *m = Company{} }
+func (m *Company) String() string { return proto.CompactTextString(m) }
+func (*Company) ProtoMessage()    {}
+func (*Company) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{1}
+}
+
+func (m *Company) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Company.Unmarshal(m, b)
+}
+func (m *Company) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Company.Marshal(b, m, deterministic)
+}
+func (m *Company) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Company.Merge(m, src)
+}
+func (m *Company) XXX_Size() int {
+	return xxx_messageInfo_Company.Size(m)
+}
+func (m *Company) XXX_DiscardUnknown() {
+	xxx_messageInfo_Company.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Company proto.InternalMessageInfo
+
+func (m *Company) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Company) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Company) GetOwnerId() string {
+	if m != nil {
+		return m.OwnerId
+	}
+	return ""
+}
+
+func (m *Company) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type UpdateUserRequest struct {
+	User                 *User    `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	UpdateMask           []string `protobuf:"bytes,2,rep,name=updateMask,proto3" json:"updateMask,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateUserRequest) Reset()         { *m = UpdateUserRequest{} }
+func (m *UpdateUserRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateUserRequest) ProtoMessage()    {}
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{2}
+}
+
+func (m *UpdateUserRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateUserRequest.Unmarshal(m, b)
+}
+func (m *UpdateUserRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateUserRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateUserRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateUserRequest.Merge(m, src)
+}
+func (m *UpdateUserRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateUserRequest.Size(m)
+}
+func (m *UpdateUserRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateUserRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateUserRequest proto.InternalMessageInfo
+
+func (m *UpdateUserRequest) GetUser() *User {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+func (m *UpdateUserRequest) GetUpdateMask() []string {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
 type Request struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -107,7 +267,7 @@ func (m *Request) Reset()         { *m = Request{} }
 func (m *Request) String() string { return proto.CompactTextString(m) }
 func (*Request) ProtoMessage()    {}
 func (*Request) Descriptor() ([]byte, []int) {
-	return fileDescriptor_82b5829f48cfb8e5, []int{1}
+	return fileDescriptor_82b5829f48cfb8e5, []int{3}
 }
 
 func (m *Request) XXX_Unmarshal(b []byte) error {
@@ -132,6 +292,7 @@ type Response struct {
 	User                 *User    `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
 	Users                []*User  `protobuf:"bytes,2,rep,name=users,proto3" json:"users,omitempty"`
 	Errors               []*Error `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	Company              *Company `protobuf:"bytes,4,opt,name=company,proto3" json:"company,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -141,7 +302,7 @@ func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_82b5829f48cfb8e5, []int{2}
+	return fileDescriptor_82b5829f48cfb8e5, []int{4}
 }
 
 func (m *Response) XXX_Unmarshal(b []byte) error {
@@ -183,11 +344,25 @@ func (m *Response) GetErrors() []*Error {
 	return nil
 }
 
+func (m *Response) GetCompany() *Company {
+	if m != nil {
+		return m.Company
+	}
+	return nil
+}
+
 type Token struct {
-	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
-	Valid                bool     `protobuf:"varint,2,opt,name=valid,proto3" json:"valid,omitempty"`
-	UserId               string   `protobuf:"bytes,3,opt,name=userId,proto3" json:"userId,omitempty"`
-	Errors               []*Error `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`
+	Token        string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Valid        bool     `protobuf:"varint,2,opt,name=valid,proto3" json:"valid,omitempty"`
+	UserId       string   `protobuf:"bytes,3,opt,name=userId,proto3" json:"userId,omitempty"`
+	Errors       []*Error `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`
+	RefreshToken string   `protobuf:"bytes,5,opt,name=refreshToken,proto3" json:"refreshToken,omitempty"`
+	ExpiresAt    int64    `protobuf:"varint,6,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
+	// mfaRequired is set instead of a token when the user has two factor authentication, and the mfaToken has to be
+	// sent to VerifyMFA with a code to get one
+	MfaRequired          bool     `protobuf:"varint,7,opt,name=mfaRequired,proto3" json:"mfaRequired,omitempty"`
+	MfaToken             string   `protobuf:"bytes,8,opt,name=mfaToken,proto3" json:"mfaToken,omitempty"`
+	CompanyId            string   `protobuf:"bytes,9,opt,name=companyId,proto3" json:"companyId,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -197,7 +372,7 @@ func (m *Token) Reset()         { *m = Token{} }
 func (m *Token) String() string { return proto.CompactTextString(m) }
 func (*Token) ProtoMessage()    {}
 func (*Token) Descriptor() ([]byte, []int) {
-	return fileDescriptor_82b5829f48cfb8e5, []int{3}
+	return fileDescriptor_82b5829f48cfb8e5, []int{5}
 }
 
 func (m *Token) XXX_Unmarshal(b []byte) error {
@@ -246,6 +421,41 @@ func (m *Token) GetErrors() []*Error {
 	return nil
 }
 
+func (m *Token) GetRefreshToken() string {
+	if m != nil {
+		return m.RefreshToken
+	}
+	return ""
+}
+
+func (m *Token) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *Token) GetMfaRequired() bool {
+	if m != nil {
+		return m.MfaRequired
+	}
+	return false
+}
+
+func (m *Token) GetMfaToken() string {
+	if m != nil {
+		return m.MfaToken
+	}
+	return ""
+}
+
+func (m *Token) GetCompanyId() string {
+	if m != nil {
+		return m.CompanyId
+	}
+	return ""
+}
+
 type PasswordChange struct {
 	Email                string   `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
 	OldPassword          string   `protobuf:"bytes,2,opt,name=oldPassword,proto3" json:"oldPassword,omitempty"`
@@ -259,7 +469,7 @@ func (m *PasswordChange) Reset()         { *m = PasswordChange{} }
 func (m *PasswordChange) String() string { return proto.CompactTextString(m) }
 func (*PasswordChange) ProtoMessage()    {}
 func (*PasswordChange) Descriptor() ([]byte, []int) {
-	return fileDescriptor_82b5829f48cfb8e5, []int{4}
+	return fileDescriptor_82b5829f48cfb8e5, []int{6}
 }
 
 func (m *PasswordChange) XXX_Unmarshal(b []byte) error {
@@ -301,142 +511,1144 @@ func (m *PasswordChange) GetNewPassword() string {
 	return ""
 }
 
-type Error struct {
-	Code                 int32    `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
-	Description          string   `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+type PasswordResetRequest struct {
+	Email                string   `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Error) Reset()         { *m = Error{} }
-func (m *Error) String() string { return proto.CompactTextString(m) }
-func (*Error) ProtoMessage()    {}
-func (*Error) Descriptor() ([]byte, []int) {
-	return fileDescriptor_82b5829f48cfb8e5, []int{5}
+func (m *PasswordResetRequest) Reset()         { *m = PasswordResetRequest{} }
+func (m *PasswordResetRequest) String() string { return proto.CompactTextString(m) }
+func (*PasswordResetRequest) ProtoMessage()    {}
+func (*PasswordResetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{7}
 }
 
-func (m *Error) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Error.Unmarshal(m, b)
+func (m *PasswordResetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PasswordResetRequest.Unmarshal(m, b)
 }
-func (m *Error) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Error.Marshal(b, m, deterministic)
+func (m *PasswordResetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PasswordResetRequest.Marshal(b, m, deterministic)
 }
-func (m *Error) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Error.Merge(m, src)
+func (m *PasswordResetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PasswordResetRequest.Merge(m, src)
 }
-func (m *Error) XXX_Size() int {
-	return xxx_messageInfo_Error.Size(m)
+func (m *PasswordResetRequest) XXX_Size() int {
+	return xxx_messageInfo_PasswordResetRequest.Size(m)
 }
-func (m *Error) XXX_DiscardUnknown() {
-	xxx_messageInfo_Error.DiscardUnknown(m)
+func (m *PasswordResetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PasswordResetRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Error proto.InternalMessageInfo
+var xxx_messageInfo_PasswordResetRequest proto.InternalMessageInfo
 
-func (m *Error) GetCode() int32 {
+func (m *PasswordResetRequest) GetEmail() string {
 	if m != nil {
-		return m.Code
+		return m.Email
 	}
-	return 0
+	return ""
 }
 
-func (m *Error) GetDescription() string {
+type PasswordReset struct {
+	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	NewPassword          string   `protobuf:"bytes,2,opt,name=newPassword,proto3" json:"newPassword,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PasswordReset) Reset()         { *m = PasswordReset{} }
+func (m *PasswordReset) String() string { return proto.CompactTextString(m) }
+func (*PasswordReset) ProtoMessage()    {}
+func (*PasswordReset) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{8}
+}
+
+func (m *PasswordReset) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PasswordReset.Unmarshal(m, b)
+}
+func (m *PasswordReset) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PasswordReset.Marshal(b, m, deterministic)
+}
+func (m *PasswordReset) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PasswordReset.Merge(m, src)
+}
+func (m *PasswordReset) XXX_Size() int {
+	return xxx_messageInfo_PasswordReset.Size(m)
+}
+func (m *PasswordReset) XXX_DiscardUnknown() {
+	xxx_messageInfo_PasswordReset.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PasswordReset proto.InternalMessageInfo
+
+func (m *PasswordReset) GetToken() string {
 	if m != nil {
-		return m.Description
+		return m.Token
 	}
 	return ""
 }
 
-func init() {
-	proto.RegisterType((*User)(nil), "auth.User")
-	proto.RegisterType((*Request)(nil), "auth.Request")
-	proto.RegisterType((*Response)(nil), "auth.Response")
-	proto.RegisterType((*Token)(nil), "auth.Token")
-	proto.RegisterType((*PasswordChange)(nil), "auth.PasswordChange")
-	proto.RegisterType((*Error)(nil), "auth.Error")
+func (m *PasswordReset) GetNewPassword() string {
+	if m != nil {
+		return m.NewPassword
+	}
+	return ""
 }
 
-func init() { proto.RegisterFile("proto/auth/auth.proto", fileDescriptor_82b5829f48cfb8e5) }
+type VerifyEmailRequest struct {
+	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
 
-var fileDescriptor_82b5829f48cfb8e5 = []byte{
-	// 417 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x53, 0xc1, 0x6e, 0xd3, 0x40,
-	0x10, 0x6d, 0x1c, 0xdb, 0x4d, 0x27, 0x6a, 0x0e, 0xa3, 0x82, 0x56, 0x3d, 0x20, 0xe3, 0x4a, 0x15,
-	0x08, 0xa9, 0x48, 0x45, 0x1c, 0x39, 0x54, 0x15, 0xaa, 0xb8, 0x21, 0x8b, 0x72, 0x5f, 0xb2, 0x23,
-	0x62, 0x70, 0xbc, 0xee, 0xee, 0xba, 0x15, 0x1f, 0xc5, 0x3f, 0xa2, 0x9d, 0x5d, 0x87, 0x25, 0xa2,
-	0xea, 0x25, 0x99, 0xf7, 0xe6, 0x65, 0xdf, 0xcc, 0x3c, 0x05, 0x9e, 0x0d, 0x46, 0x3b, 0xfd, 0x56,
-	0x8e, 0x6e, 0xc3, 0x1f, 0x17, 0x8c, 0x31, 0xf7, 0x75, 0x7d, 0x0f, 0xf9, 0xad, 0x25, 0x83, 0x2b,
-	0xc8, 0x5a, 0x25, 0x66, 0xd5, 0xec, 0xd5, 0x51, 0x93, 0xb5, 0x0a, 0x11, 0xf2, 0x5e, 0x6e, 0x49,
-	0x64, 0xcc, 0x70, 0x8d, 0x02, 0x0e, 0xd7, 0x7a, 0x3b, 0xc8, 0xfe, 0x97, 0x98, 0x33, 0x3d, 0x41,
-	0x3c, 0x81, 0x82, 0xb6, 0xb2, 0xed, 0x44, 0xce, 0x7c, 0x00, 0x78, 0x0a, 0x8b, 0x41, 0x5a, 0xfb,
-	0xa0, 0x8d, 0x12, 0x05, 0x37, 0x76, 0xb8, 0x3e, 0x82, 0xc3, 0x86, 0xee, 0x46, 0xb2, 0xae, 0xbe,
-	0x83, 0x45, 0x43, 0x76, 0xd0, 0xbd, 0x25, 0x7c, 0x01, 0xf9, 0x68, 0xc9, 0xf0, 0x20, 0xcb, 0x4b,
-	0xb8, 0xe0, 0x79, 0xfd, 0x80, 0x0d, 0xf3, 0x58, 0x41, 0xe1, 0xbf, 0xad, 0xc8, 0xaa, 0xf9, 0x9e,
-	0x20, 0x34, 0xf0, 0x0c, 0x4a, 0x32, 0x46, 0x1b, 0x2b, 0xe6, 0x2c, 0x59, 0x06, 0xc9, 0x47, 0xcf,
-	0x35, 0xb1, 0x55, 0x0f, 0x50, 0x7c, 0xd1, 0x3f, 0xa9, 0xf7, 0x83, 0x3b, 0x5f, 0xc4, 0xcd, 0x03,
-	0xf0, 0xec, 0xbd, 0xec, 0x5a, 0xc5, 0xdb, 0x2f, 0x9a, 0x00, 0xf0, 0x39, 0x94, 0xde, 0xe2, 0x93,
-	0x8a, 0xdb, 0x47, 0x94, 0x38, 0xe6, 0x8f, 0x3b, 0xfe, 0x80, 0xd5, 0xe7, 0xb8, 0xfb, 0xf5, 0x46,
-	0xf6, 0xdf, 0xe9, 0xef, 0xcd, 0x66, 0xe9, 0xcd, 0x2a, 0x58, 0xea, 0x4e, 0x4d, 0xd2, 0x78, 0xfe,
-	0x94, 0xf2, 0x8a, 0x9e, 0x1e, 0x76, 0x8a, 0x30, 0x4b, 0x4a, 0xd5, 0x1f, 0xa0, 0x60, 0x73, 0x1f,
-	0xe2, 0x5a, 0x2b, 0x62, 0x87, 0xa2, 0xe1, 0xda, 0xff, 0x5c, 0x91, 0x5d, 0x9b, 0x76, 0x70, 0xad,
-	0xee, 0x27, 0x83, 0x84, 0xba, 0xfc, 0x9d, 0x41, 0x7e, 0x35, 0xba, 0x0d, 0x9e, 0x43, 0x79, 0x6d,
-	0x48, 0x3a, 0xc2, 0xe4, 0xce, 0xa7, 0xab, 0x50, 0x4f, 0x91, 0xd5, 0x07, 0x78, 0x06, 0xf3, 0x1b,
-	0x72, 0x4f, 0x88, 0x5e, 0x43, 0x79, 0x43, 0xee, 0xaa, 0xeb, 0xf0, 0x78, 0xea, 0x71, 0xfc, 0xff,
-	0x91, 0xbe, 0x8c, 0xfe, 0xe9, 0x83, 0xf1, 0xa8, 0x9c, 0x5a, 0x7d, 0x80, 0x6f, 0xe0, 0xf8, 0xab,
-	0x0f, 0x45, 0x3a, 0x0a, 0x41, 0xa6, 0xfd, 0x7d, 0xf1, 0x39, 0x94, 0xb7, 0x83, 0x7a, 0x7a, 0x8f,
-	0xf7, 0xb0, 0x0a, 0xd9, 0xec, 0x6e, 0x7d, 0x12, 0x34, 0xff, 0x26, 0xb7, 0xf7, 0xfc, 0xb7, 0x92,
-	0xff, 0x4f, 0xef, 0xfe, 0x04, 0x00, 0x00, 0xff, 0xff, 0xb1, 0x00, 0xec, 0x01, 0x68, 0x03, 0x00,
-	0x00,
+func (m *VerifyEmailRequest) Reset()         { *m = VerifyEmailRequest{} }
+func (m *VerifyEmailRequest) String() string { return proto.CompactTextString(m) }
+func (*VerifyEmailRequest) ProtoMessage()    {}
+func (*VerifyEmailRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{9}
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ client.Option
-var _ server.Option
+func (m *VerifyEmailRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyEmailRequest.Unmarshal(m, b)
+}
+func (m *VerifyEmailRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyEmailRequest.Marshal(b, m, deterministic)
+}
+func (m *VerifyEmailRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyEmailRequest.Merge(m, src)
+}
+func (m *VerifyEmailRequest) XXX_Size() int {
+	return xxx_messageInfo_VerifyEmailRequest.Size(m)
+}
+func (m *VerifyEmailRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyEmailRequest.DiscardUnknown(m)
+}
 
-// Client API for Auth service
+var xxx_messageInfo_VerifyEmailRequest proto.InternalMessageInfo
 
-type AuthClient interface {
-	Create(ctx context.Context, in *User, opts ...client.CallOption) (*Response, error)
-	Get(ctx context.Context, in *User, opts ...client.CallOption) (*Response, error)
-	GetAll(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
-	Auth(ctx context.Context, in *User, opts ...client.CallOption) (*Token, error)
-	ValidateToken(ctx context.Context, in *Token, opts ...client.CallOption) (*Token, error)
-	Update(ctx context.Context, in *User, opts ...client.CallOption) (*Response, error)
-	ChangePassword(ctx context.Context, in *PasswordChange, opts ...client.CallOption) (*Token, error)
+func (m *VerifyEmailRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
 }
 
-type authClient struct {
-	c           client.Client
-	serviceName string
+type ResendVerificationRequest struct {
+	Email                string   `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func NewAuthClient(serviceName string, c client.Client) AuthClient {
-	if c == nil {
-		c = client.NewClient()
-	}
-	if len(serviceName) == 0 {
-		serviceName = "auth"
-	}
-	return &authClient{
-		c:           c,
-		serviceName: serviceName,
-	}
+func (m *ResendVerificationRequest) Reset()         { *m = ResendVerificationRequest{} }
+func (m *ResendVerificationRequest) String() string { return proto.CompactTextString(m) }
+func (*ResendVerificationRequest) ProtoMessage()    {}
+func (*ResendVerificationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{10}
 }
 
-func (c *authClient) Create(ctx context.Context, in *User, opts ...client.CallOption) (*Response, error) {
-	req := c.c.NewRequest(c.serviceName, "Auth.Create", in)
-	out := new(Response)
-	err := c.c.Call(ctx, req, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ResendVerificationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResendVerificationRequest.Unmarshal(m, b)
+}
+func (m *ResendVerificationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResendVerificationRequest.Marshal(b, m, deterministic)
+}
+func (m *ResendVerificationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResendVerificationRequest.Merge(m, src)
+}
+func (m *ResendVerificationRequest) XXX_Size() int {
+	return xxx_messageInfo_ResendVerificationRequest.Size(m)
+}
+func (m *ResendVerificationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResendVerificationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResendVerificationRequest proto.InternalMessageInfo
+
+func (m *ResendVerificationRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
 	}
-	return out, nil
+	return ""
 }
 
-func (c *authClient) Get(ctx context.Context, in *User, opts ...client.CallOption) (*Response, error) {
-	req := c.c.NewRequest(c.serviceName, "Auth.Get", in)
+type UnlockUserRequest struct {
+	Email                string   `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnlockUserRequest) Reset()         { *m = UnlockUserRequest{} }
+func (m *UnlockUserRequest) String() string { return proto.CompactTextString(m) }
+func (*UnlockUserRequest) ProtoMessage()    {}
+func (*UnlockUserRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{11}
+}
+
+func (m *UnlockUserRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnlockUserRequest.Unmarshal(m, b)
+}
+func (m *UnlockUserRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnlockUserRequest.Marshal(b, m, deterministic)
+}
+func (m *UnlockUserRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnlockUserRequest.Merge(m, src)
+}
+func (m *UnlockUserRequest) XXX_Size() int {
+	return xxx_messageInfo_UnlockUserRequest.Size(m)
+}
+func (m *UnlockUserRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnlockUserRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnlockUserRequest proto.InternalMessageInfo
+
+func (m *UnlockUserRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+type SetRoleRequest struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	Role                 string   `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetRoleRequest) Reset()         { *m = SetRoleRequest{} }
+func (m *SetRoleRequest) String() string { return proto.CompactTextString(m) }
+func (*SetRoleRequest) ProtoMessage()    {}
+func (*SetRoleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{12}
+}
+
+func (m *SetRoleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetRoleRequest.Unmarshal(m, b)
+}
+func (m *SetRoleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetRoleRequest.Marshal(b, m, deterministic)
+}
+func (m *SetRoleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetRoleRequest.Merge(m, src)
+}
+func (m *SetRoleRequest) XXX_Size() int {
+	return xxx_messageInfo_SetRoleRequest.Size(m)
+}
+func (m *SetRoleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetRoleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetRoleRequest proto.InternalMessageInfo
+
+func (m *SetRoleRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *SetRoleRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+type CreateCompanyRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateCompanyRequest) Reset()         { *m = CreateCompanyRequest{} }
+func (m *CreateCompanyRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateCompanyRequest) ProtoMessage()    {}
+func (*CreateCompanyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{13}
+}
+
+func (m *CreateCompanyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateCompanyRequest.Unmarshal(m, b)
+}
+func (m *CreateCompanyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateCompanyRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateCompanyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateCompanyRequest.Merge(m, src)
+}
+func (m *CreateCompanyRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateCompanyRequest.Size(m)
+}
+func (m *CreateCompanyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateCompanyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateCompanyRequest proto.InternalMessageInfo
+
+func (m *CreateCompanyRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// The requests about a company are for the company of the caller if they don't have a companyId
+type InviteMemberRequest struct {
+	CompanyId            string   `protobuf:"bytes,1,opt,name=companyId,proto3" json:"companyId,omitempty"`
+	Email                string   `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InviteMemberRequest) Reset()         { *m = InviteMemberRequest{} }
+func (m *InviteMemberRequest) String() string { return proto.CompactTextString(m) }
+func (*InviteMemberRequest) ProtoMessage()    {}
+func (*InviteMemberRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{14}
+}
+
+func (m *InviteMemberRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InviteMemberRequest.Unmarshal(m, b)
+}
+func (m *InviteMemberRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InviteMemberRequest.Marshal(b, m, deterministic)
+}
+func (m *InviteMemberRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InviteMemberRequest.Merge(m, src)
+}
+func (m *InviteMemberRequest) XXX_Size() int {
+	return xxx_messageInfo_InviteMemberRequest.Size(m)
+}
+func (m *InviteMemberRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InviteMemberRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InviteMemberRequest proto.InternalMessageInfo
+
+func (m *InviteMemberRequest) GetCompanyId() string {
+	if m != nil {
+		return m.CompanyId
+	}
+	return ""
+}
+
+func (m *InviteMemberRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+type AcceptInviteRequest struct {
+	CompanyId            string   `protobuf:"bytes,1,opt,name=companyId,proto3" json:"companyId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AcceptInviteRequest) Reset()         { *m = AcceptInviteRequest{} }
+func (m *AcceptInviteRequest) String() string { return proto.CompactTextString(m) }
+func (*AcceptInviteRequest) ProtoMessage()    {}
+func (*AcceptInviteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{15}
+}
+
+func (m *AcceptInviteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AcceptInviteRequest.Unmarshal(m, b)
+}
+func (m *AcceptInviteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AcceptInviteRequest.Marshal(b, m, deterministic)
+}
+func (m *AcceptInviteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AcceptInviteRequest.Merge(m, src)
+}
+func (m *AcceptInviteRequest) XXX_Size() int {
+	return xxx_messageInfo_AcceptInviteRequest.Size(m)
+}
+func (m *AcceptInviteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AcceptInviteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AcceptInviteRequest proto.InternalMessageInfo
+
+func (m *AcceptInviteRequest) GetCompanyId() string {
+	if m != nil {
+		return m.CompanyId
+	}
+	return ""
+}
+
+type RemoveMemberRequest struct {
+	CompanyId            string   `protobuf:"bytes,1,opt,name=companyId,proto3" json:"companyId,omitempty"`
+	UserId               string   `protobuf:"bytes,2,opt,name=userId,proto3" json:"userId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveMemberRequest) Reset()         { *m = RemoveMemberRequest{} }
+func (m *RemoveMemberRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveMemberRequest) ProtoMessage()    {}
+func (*RemoveMemberRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{16}
+}
+
+func (m *RemoveMemberRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveMemberRequest.Unmarshal(m, b)
+}
+func (m *RemoveMemberRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveMemberRequest.Marshal(b, m, deterministic)
+}
+func (m *RemoveMemberRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveMemberRequest.Merge(m, src)
+}
+func (m *RemoveMemberRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveMemberRequest.Size(m)
+}
+func (m *RemoveMemberRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveMemberRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveMemberRequest proto.InternalMessageInfo
+
+func (m *RemoveMemberRequest) GetCompanyId() string {
+	if m != nil {
+		return m.CompanyId
+	}
+	return ""
+}
+
+func (m *RemoveMemberRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+type ListMembersRequest struct {
+	CompanyId            string   `protobuf:"bytes,1,opt,name=companyId,proto3" json:"companyId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListMembersRequest) Reset()         { *m = ListMembersRequest{} }
+func (m *ListMembersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListMembersRequest) ProtoMessage()    {}
+func (*ListMembersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{17}
+}
+
+func (m *ListMembersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListMembersRequest.Unmarshal(m, b)
+}
+func (m *ListMembersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListMembersRequest.Marshal(b, m, deterministic)
+}
+func (m *ListMembersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListMembersRequest.Merge(m, src)
+}
+func (m *ListMembersRequest) XXX_Size() int {
+	return xxx_messageInfo_ListMembersRequest.Size(m)
+}
+func (m *ListMembersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListMembersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListMembersRequest proto.InternalMessageInfo
+
+func (m *ListMembersRequest) GetCompanyId() string {
+	if m != nil {
+		return m.CompanyId
+	}
+	return ""
+}
+
+type TransferOwnershipRequest struct {
+	CompanyId            string   `protobuf:"bytes,1,opt,name=companyId,proto3" json:"companyId,omitempty"`
+	UserId               string   `protobuf:"bytes,2,opt,name=userId,proto3" json:"userId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferOwnershipRequest) Reset()         { *m = TransferOwnershipRequest{} }
+func (m *TransferOwnershipRequest) String() string { return proto.CompactTextString(m) }
+func (*TransferOwnershipRequest) ProtoMessage()    {}
+func (*TransferOwnershipRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{18}
+}
+
+func (m *TransferOwnershipRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferOwnershipRequest.Unmarshal(m, b)
+}
+func (m *TransferOwnershipRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferOwnershipRequest.Marshal(b, m, deterministic)
+}
+func (m *TransferOwnershipRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferOwnershipRequest.Merge(m, src)
+}
+func (m *TransferOwnershipRequest) XXX_Size() int {
+	return xxx_messageInfo_TransferOwnershipRequest.Size(m)
+}
+func (m *TransferOwnershipRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferOwnershipRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferOwnershipRequest proto.InternalMessageInfo
+
+func (m *TransferOwnershipRequest) GetCompanyId() string {
+	if m != nil {
+		return m.CompanyId
+	}
+	return ""
+}
+
+func (m *TransferOwnershipRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+type EnrollTOTPRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EnrollTOTPRequest) Reset()         { *m = EnrollTOTPRequest{} }
+func (m *EnrollTOTPRequest) String() string { return proto.CompactTextString(m) }
+func (*EnrollTOTPRequest) ProtoMessage()    {}
+func (*EnrollTOTPRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{19}
+}
+
+func (m *EnrollTOTPRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnrollTOTPRequest.Unmarshal(m, b)
+}
+func (m *EnrollTOTPRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnrollTOTPRequest.Marshal(b, m, deterministic)
+}
+func (m *EnrollTOTPRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnrollTOTPRequest.Merge(m, src)
+}
+func (m *EnrollTOTPRequest) XXX_Size() int {
+	return xxx_messageInfo_EnrollTOTPRequest.Size(m)
+}
+func (m *EnrollTOTPRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnrollTOTPRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnrollTOTPRequest proto.InternalMessageInfo
+
+type TOTPEnrollment struct {
+	Secret               string   `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	Uri                  string   `protobuf:"bytes,2,opt,name=uri,proto3" json:"uri,omitempty"`
+	Errors               []*Error `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TOTPEnrollment) Reset()         { *m = TOTPEnrollment{} }
+func (m *TOTPEnrollment) String() string { return proto.CompactTextString(m) }
+func (*TOTPEnrollment) ProtoMessage()    {}
+func (*TOTPEnrollment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{20}
+}
+
+func (m *TOTPEnrollment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TOTPEnrollment.Unmarshal(m, b)
+}
+func (m *TOTPEnrollment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TOTPEnrollment.Marshal(b, m, deterministic)
+}
+func (m *TOTPEnrollment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TOTPEnrollment.Merge(m, src)
+}
+func (m *TOTPEnrollment) XXX_Size() int {
+	return xxx_messageInfo_TOTPEnrollment.Size(m)
+}
+func (m *TOTPEnrollment) XXX_DiscardUnknown() {
+	xxx_messageInfo_TOTPEnrollment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TOTPEnrollment proto.InternalMessageInfo
+
+func (m *TOTPEnrollment) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *TOTPEnrollment) GetUri() string {
+	if m != nil {
+		return m.Uri
+	}
+	return ""
+}
+
+func (m *TOTPEnrollment) GetErrors() []*Error {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+type TOTPCode struct {
+	Code                 string   `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TOTPCode) Reset()         { *m = TOTPCode{} }
+func (m *TOTPCode) String() string { return proto.CompactTextString(m) }
+func (*TOTPCode) ProtoMessage()    {}
+func (*TOTPCode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{21}
+}
+
+func (m *TOTPCode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TOTPCode.Unmarshal(m, b)
+}
+func (m *TOTPCode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TOTPCode.Marshal(b, m, deterministic)
+}
+func (m *TOTPCode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TOTPCode.Merge(m, src)
+}
+func (m *TOTPCode) XXX_Size() int {
+	return xxx_messageInfo_TOTPCode.Size(m)
+}
+func (m *TOTPCode) XXX_DiscardUnknown() {
+	xxx_messageInfo_TOTPCode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TOTPCode proto.InternalMessageInfo
+
+func (m *TOTPCode) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+type RecoveryCodes struct {
+	Codes                []string `protobuf:"bytes,1,rep,name=codes,proto3" json:"codes,omitempty"`
+	Errors               []*Error `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RecoveryCodes) Reset()         { *m = RecoveryCodes{} }
+func (m *RecoveryCodes) String() string { return proto.CompactTextString(m) }
+func (*RecoveryCodes) ProtoMessage()    {}
+func (*RecoveryCodes) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{22}
+}
+
+func (m *RecoveryCodes) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RecoveryCodes.Unmarshal(m, b)
+}
+func (m *RecoveryCodes) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RecoveryCodes.Marshal(b, m, deterministic)
+}
+func (m *RecoveryCodes) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RecoveryCodes.Merge(m, src)
+}
+func (m *RecoveryCodes) XXX_Size() int {
+	return xxx_messageInfo_RecoveryCodes.Size(m)
+}
+func (m *RecoveryCodes) XXX_DiscardUnknown() {
+	xxx_messageInfo_RecoveryCodes.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RecoveryCodes proto.InternalMessageInfo
+
+func (m *RecoveryCodes) GetCodes() []string {
+	if m != nil {
+		return m.Codes
+	}
+	return nil
+}
+
+func (m *RecoveryCodes) GetErrors() []*Error {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+// MFAVerification exchanges the mfaToken from logging in for a token, with either a code from an authenticator app or
+// a recovery code
+type MFAVerification struct {
+	MfaToken             string   `protobuf:"bytes,1,opt,name=mfaToken,proto3" json:"mfaToken,omitempty"`
+	Code                 string   `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	RecoveryCode         string   `protobuf:"bytes,3,opt,name=recoveryCode,proto3" json:"recoveryCode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MFAVerification) Reset()         { *m = MFAVerification{} }
+func (m *MFAVerification) String() string { return proto.CompactTextString(m) }
+func (*MFAVerification) ProtoMessage()    {}
+func (*MFAVerification) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{23}
+}
+
+func (m *MFAVerification) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MFAVerification.Unmarshal(m, b)
+}
+func (m *MFAVerification) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MFAVerification.Marshal(b, m, deterministic)
+}
+func (m *MFAVerification) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MFAVerification.Merge(m, src)
+}
+func (m *MFAVerification) XXX_Size() int {
+	return xxx_messageInfo_MFAVerification.Size(m)
+}
+func (m *MFAVerification) XXX_DiscardUnknown() {
+	xxx_messageInfo_MFAVerification.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MFAVerification proto.InternalMessageInfo
+
+func (m *MFAVerification) GetMfaToken() string {
+	if m != nil {
+		return m.MfaToken
+	}
+	return ""
+}
+
+func (m *MFAVerification) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *MFAVerification) GetRecoveryCode() string {
+	if m != nil {
+		return m.RecoveryCode
+	}
+	return ""
+}
+
+type JWKSRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JWKSRequest) Reset()         { *m = JWKSRequest{} }
+func (m *JWKSRequest) String() string { return proto.CompactTextString(m) }
+func (*JWKSRequest) ProtoMessage()    {}
+func (*JWKSRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{24}
+}
+
+func (m *JWKSRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JWKSRequest.Unmarshal(m, b)
+}
+func (m *JWKSRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JWKSRequest.Marshal(b, m, deterministic)
+}
+func (m *JWKSRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JWKSRequest.Merge(m, src)
+}
+func (m *JWKSRequest) XXX_Size() int {
+	return xxx_messageInfo_JWKSRequest.Size(m)
+}
+func (m *JWKSRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_JWKSRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JWKSRequest proto.InternalMessageInfo
+
+// JWK is a public key used to sign tokens, in the JSON Web Key format
+type JWK struct {
+	Kty                  string   `protobuf:"bytes,1,opt,name=kty,proto3" json:"kty,omitempty"`
+	Kid                  string   `protobuf:"bytes,2,opt,name=kid,proto3" json:"kid,omitempty"`
+	Alg                  string   `protobuf:"bytes,3,opt,name=alg,proto3" json:"alg,omitempty"`
+	Use                  string   `protobuf:"bytes,4,opt,name=use,proto3" json:"use,omitempty"`
+	N                    string   `protobuf:"bytes,5,opt,name=n,proto3" json:"n,omitempty"`
+	E                    string   `protobuf:"bytes,6,opt,name=e,proto3" json:"e,omitempty"`
+	Crv                  string   `protobuf:"bytes,7,opt,name=crv,proto3" json:"crv,omitempty"`
+	X                    string   `protobuf:"bytes,8,opt,name=x,proto3" json:"x,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JWK) Reset()         { *m = JWK{} }
+func (m *JWK) String() string { return proto.CompactTextString(m) }
+func (*JWK) ProtoMessage()    {}
+func (*JWK) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{25}
+}
+
+func (m *JWK) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JWK.Unmarshal(m, b)
+}
+func (m *JWK) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JWK.Marshal(b, m, deterministic)
+}
+func (m *JWK) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JWK.Merge(m, src)
+}
+func (m *JWK) XXX_Size() int {
+	return xxx_messageInfo_JWK.Size(m)
+}
+func (m *JWK) XXX_DiscardUnknown() {
+	xxx_messageInfo_JWK.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JWK proto.InternalMessageInfo
+
+func (m *JWK) GetKty() string {
+	if m != nil {
+		return m.Kty
+	}
+	return ""
+}
+
+func (m *JWK) GetKid() string {
+	if m != nil {
+		return m.Kid
+	}
+	return ""
+}
+
+func (m *JWK) GetAlg() string {
+	if m != nil {
+		return m.Alg
+	}
+	return ""
+}
+
+func (m *JWK) GetUse() string {
+	if m != nil {
+		return m.Use
+	}
+	return ""
+}
+
+func (m *JWK) GetN() string {
+	if m != nil {
+		return m.N
+	}
+	return ""
+}
+
+func (m *JWK) GetE() string {
+	if m != nil {
+		return m.E
+	}
+	return ""
+}
+
+func (m *JWK) GetCrv() string {
+	if m != nil {
+		return m.Crv
+	}
+	return ""
+}
+
+func (m *JWK) GetX() string {
+	if m != nil {
+		return m.X
+	}
+	return ""
+}
+
+type JWKS struct {
+	Keys                 []*JWK   `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JWKS) Reset()         { *m = JWKS{} }
+func (m *JWKS) String() string { return proto.CompactTextString(m) }
+func (*JWKS) ProtoMessage()    {}
+func (*JWKS) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{26}
+}
+
+func (m *JWKS) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JWKS.Unmarshal(m, b)
+}
+func (m *JWKS) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JWKS.Marshal(b, m, deterministic)
+}
+func (m *JWKS) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JWKS.Merge(m, src)
+}
+func (m *JWKS) XXX_Size() int {
+	return xxx_messageInfo_JWKS.Size(m)
+}
+func (m *JWKS) XXX_DiscardUnknown() {
+	xxx_messageInfo_JWKS.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JWKS proto.InternalMessageInfo
+
+func (m *JWKS) GetKeys() []*JWK {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+type Error struct {
+	Code                 int32    `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Description          string   `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Error) Reset()         { *m = Error{} }
+func (m *Error) String() string { return proto.CompactTextString(m) }
+func (*Error) ProtoMessage()    {}
+func (*Error) Descriptor() ([]byte, []int) {
+	return fileDescriptor_82b5829f48cfb8e5, []int{27}
+}
+
+func (m *Error) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Error.Unmarshal(m, b)
+}
+func (m *Error) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Error.Marshal(b, m, deterministic)
+}
+func (m *Error) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Error.Merge(m, src)
+}
+func (m *Error) XXX_Size() int {
+	return xxx_messageInfo_Error.Size(m)
+}
+func (m *Error) XXX_DiscardUnknown() {
+	xxx_messageInfo_Error.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Error proto.InternalMessageInfo
+
+func (m *Error) GetCode() int32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *Error) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*User)(nil), "auth.User")
+	proto.RegisterType((*Company)(nil), "auth.Company")
+	proto.RegisterType((*UpdateUserRequest)(nil), "auth.UpdateUserRequest")
+	proto.RegisterType((*Request)(nil), "auth.Request")
+	proto.RegisterType((*Response)(nil), "auth.Response")
+	proto.RegisterType((*Token)(nil), "auth.Token")
+	proto.RegisterType((*PasswordChange)(nil), "auth.PasswordChange")
+	proto.RegisterType((*PasswordResetRequest)(nil), "auth.PasswordResetRequest")
+	proto.RegisterType((*PasswordReset)(nil), "auth.PasswordReset")
+	proto.RegisterType((*VerifyEmailRequest)(nil), "auth.VerifyEmailRequest")
+	proto.RegisterType((*ResendVerificationRequest)(nil), "auth.ResendVerificationRequest")
+	proto.RegisterType((*UnlockUserRequest)(nil), "auth.UnlockUserRequest")
+	proto.RegisterType((*SetRoleRequest)(nil), "auth.SetRoleRequest")
+	proto.RegisterType((*CreateCompanyRequest)(nil), "auth.CreateCompanyRequest")
+	proto.RegisterType((*InviteMemberRequest)(nil), "auth.InviteMemberRequest")
+	proto.RegisterType((*AcceptInviteRequest)(nil), "auth.AcceptInviteRequest")
+	proto.RegisterType((*RemoveMemberRequest)(nil), "auth.RemoveMemberRequest")
+	proto.RegisterType((*ListMembersRequest)(nil), "auth.ListMembersRequest")
+	proto.RegisterType((*TransferOwnershipRequest)(nil), "auth.TransferOwnershipRequest")
+	proto.RegisterType((*EnrollTOTPRequest)(nil), "auth.EnrollTOTPRequest")
+	proto.RegisterType((*TOTPEnrollment)(nil), "auth.TOTPEnrollment")
+	proto.RegisterType((*TOTPCode)(nil), "auth.TOTPCode")
+	proto.RegisterType((*RecoveryCodes)(nil), "auth.RecoveryCodes")
+	proto.RegisterType((*MFAVerification)(nil), "auth.MFAVerification")
+	proto.RegisterType((*JWKSRequest)(nil), "auth.JWKSRequest")
+	proto.RegisterType((*JWK)(nil), "auth.JWK")
+	proto.RegisterType((*JWKS)(nil), "auth.JWKS")
+	proto.RegisterType((*Error)(nil), "auth.Error")
+}
+
+func init() { proto.RegisterFile("proto/auth/auth.proto", fileDescriptor_82b5829f48cfb8e5) }
+
+var fileDescriptor_82b5829f48cfb8e5 = []byte{
+	// 1316 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x57, 0xdd, 0x6e, 0xdb, 0xc6,
+	0x12, 0x36, 0xf5, 0x67, 0x69, 0x14, 0xe9, 0xc4, 0x6b, 0xe7, 0x1c, 0xc6, 0x38, 0xc7, 0x47, 0x65,
+	0x92, 0x36, 0x71, 0x8b, 0x04, 0xb1, 0x5b, 0x14, 0x41, 0x9b, 0x02, 0x82, 0xe3, 0x18, 0xb6, 0x63,
+	0x24, 0xa0, 0x9d, 0xe4, 0xb2, 0x60, 0xc4, 0x51, 0xcc, 0x9a, 0xe2, 0xaa, 0xbb, 0x2b, 0x25, 0xea,
+	0x1b, 0xf4, 0xa6, 0x0f, 0xd0, 0x97, 0xe8, 0x5d, 0x9f, 0xaf, 0xd8, 0x3f, 0x71, 0x29, 0xd1, 0x8e,
+	0x8b, 0xde, 0xd8, 0xbb, 0xf3, 0xcf, 0x99, 0x6f, 0x67, 0x46, 0x70, 0x6b, 0xcc, 0xa8, 0xa0, 0x8f,
+	0xa2, 0x89, 0x38, 0x57, 0x7f, 0x1e, 0xaa, 0x3b, 0xa9, 0xc9, 0x73, 0xf0, 0x67, 0x05, 0x6a, 0xaf,
+	0x39, 0x32, 0xd2, 0x85, 0x4a, 0x12, 0xfb, 0x5e, 0xcf, 0xbb, 0xdf, 0x0a, 0x2b, 0x49, 0x4c, 0x08,
+	0xd4, 0xb2, 0x68, 0x84, 0x7e, 0x45, 0x51, 0xd4, 0x99, 0xf8, 0xb0, 0x3a, 0xa0, 0xa3, 0x71, 0x94,
+	0xcd, 0xfc, 0xaa, 0x22, 0xdb, 0x2b, 0xd9, 0x80, 0x3a, 0x8e, 0xa2, 0x24, 0xf5, 0x6b, 0x8a, 0xae,
+	0x2f, 0x64, 0x13, 0x9a, 0xe3, 0x88, 0xf3, 0x0f, 0x94, 0xc5, 0x7e, 0x5d, 0x31, 0xe6, 0x77, 0xc9,
+	0x13, 0xc9, 0x08, 0x7f, 0xa1, 0x19, 0xfa, 0x0d, 0xcd, 0xb3, 0x77, 0xb2, 0x0d, 0x37, 0x07, 0x11,
+	0x63, 0xb3, 0x97, 0x53, 0x64, 0xcf, 0xa2, 0x24, 0x9d, 0x3d, 0xa3, 0xfe, 0x6a, 0xcf, 0xbb, 0xdf,
+	0x0c, 0x97, 0xe8, 0xe4, 0x2e, 0x74, 0x94, 0xb3, 0x37, 0xc8, 0x92, 0x61, 0x82, 0xb1, 0xdf, 0x54,
+	0x82, 0x45, 0x22, 0xd9, 0x02, 0x18, 0x0d, 0xa3, 0xfd, 0x2c, 0x7a, 0x97, 0x62, 0xec, 0xb7, 0x94,
+	0x88, 0x43, 0x91, 0x5f, 0xcb, 0x68, 0x8a, 0x3e, 0xe8, 0xaf, 0x95, 0x67, 0xf2, 0x5f, 0x68, 0x99,
+	0xcf, 0x3b, 0x8c, 0xfd, 0xb6, 0x62, 0xe4, 0x84, 0x00, 0x61, 0x75, 0xcf, 0x7c, 0xfc, 0x35, 0x53,
+	0x47, 0x3f, 0x64, 0xc8, 0x0e, 0x63, 0x9b, 0x3a, 0x73, 0x55, 0x6e, 0x18, 0x46, 0x02, 0xe3, 0xbe,
+	0x50, 0xe9, 0xab, 0x86, 0x39, 0x21, 0x38, 0x85, 0xb5, 0xd7, 0xe3, 0x38, 0x12, 0x28, 0x8b, 0x14,
+	0xe2, 0xcf, 0x13, 0xe4, 0x82, 0x6c, 0x41, 0x6d, 0xc2, 0x91, 0x29, 0x97, 0xed, 0x1d, 0x78, 0xa8,
+	0xaa, 0xaa, 0x04, 0x14, 0x5d, 0x7e, 0xed, 0x44, 0x29, 0x9d, 0x44, 0xfc, 0xc2, 0xaf, 0xf4, 0xaa,
+	0xf7, 0x5b, 0xa1, 0x43, 0x09, 0x5a, 0xb0, 0x6a, 0x4c, 0x05, 0xbf, 0x7b, 0xd0, 0x0c, 0x91, 0x8f,
+	0x69, 0xc6, 0xf1, 0x93, 0x76, 0x7b, 0x50, 0x97, 0xff, 0xb9, 0x32, 0x59, 0x14, 0xd0, 0x0c, 0x72,
+	0x07, 0x1a, 0xc8, 0x18, 0x65, 0xdc, 0xaf, 0x2a, 0x91, 0xb6, 0x16, 0xd9, 0x97, 0xb4, 0xd0, 0xb0,
+	0xc8, 0x17, 0x39, 0x8c, 0x6a, 0xca, 0x53, 0x47, 0x4b, 0x99, 0x7c, 0xce, 0x51, 0x15, 0xfc, 0x56,
+	0x81, 0xfa, 0x19, 0xbd, 0xc0, 0x4c, 0xe2, 0x4b, 0xc8, 0x83, 0xc9, 0x72, 0x5d, 0x58, 0xea, 0x34,
+	0x4a, 0x93, 0x58, 0x65, 0xba, 0x19, 0xea, 0x0b, 0xf9, 0x37, 0x34, 0x26, 0xdc, 0xc9, 0xb4, 0xb9,
+	0x39, 0xb1, 0xd5, 0x2e, 0x8f, 0x2d, 0x80, 0x1b, 0x0c, 0x87, 0x0c, 0xf9, 0xb9, 0x72, 0x6c, 0x60,
+	0x5b, 0xa0, 0xc9, 0x8a, 0xe1, 0xc7, 0x71, 0xc2, 0x90, 0xf7, 0x85, 0xc2, 0x6e, 0x35, 0xcc, 0x09,
+	0xa4, 0x07, 0xed, 0xd1, 0x30, 0x92, 0xf9, 0x4d, 0x18, 0xc6, 0x06, 0xb7, 0x2e, 0x49, 0x42, 0x7f,
+	0x34, 0x8c, 0xb4, 0xfd, 0xa6, 0x86, 0xbe, 0xbd, 0x17, 0x41, 0xd7, 0x5a, 0x04, 0xdd, 0x4f, 0xd0,
+	0x7d, 0x65, 0x1e, 0xd0, 0xde, 0x79, 0x94, 0xbd, 0xc7, 0xfc, 0xe1, 0x79, 0xee, 0xc3, 0xeb, 0x41,
+	0x9b, 0xa6, 0xb1, 0x15, 0x35, 0x40, 0x74, 0x49, 0x52, 0x22, 0xc3, 0x0f, 0x73, 0x09, 0x9d, 0x29,
+	0x97, 0x14, 0x7c, 0x05, 0x1b, 0xf6, 0x1c, 0x22, 0x47, 0x61, 0xc1, 0x57, 0xea, 0x31, 0x38, 0x80,
+	0x4e, 0x41, 0xfa, 0x92, 0x8a, 0x2d, 0xb8, 0xad, 0x2c, 0xbb, 0xdd, 0x06, 0xa2, 0x5e, 0xed, 0x6c,
+	0x5f, 0xda, 0x75, 0x9c, 0x2e, 0x5b, 0x0b, 0x1e, 0xc3, 0x6d, 0xe9, 0x2c, 0x8b, 0xf5, 0x3b, 0x1f,
+	0x44, 0x22, 0xa1, 0xd9, 0xd5, 0x71, 0x3e, 0x80, 0xb5, 0xd7, 0x59, 0x4a, 0x07, 0x17, 0xee, 0x7b,
+	0x2a, 0x17, 0xfd, 0x1e, 0xba, 0xa7, 0x28, 0x42, 0x9a, 0xa2, 0x95, 0xcb, 0x91, 0xe5, 0x15, 0x90,
+	0x65, 0xbb, 0x47, 0x25, 0xef, 0x1e, 0xc1, 0x36, 0x6c, 0xec, 0xa9, 0x57, 0x6c, 0x51, 0x6d, 0x6c,
+	0xd8, 0xe6, 0xe0, 0xe5, 0xcd, 0x21, 0x38, 0x84, 0xf5, 0xc3, 0x6c, 0x9a, 0x08, 0x3c, 0xc1, 0xd1,
+	0xbb, 0x3c, 0xac, 0x02, 0x16, 0xbc, 0x05, 0x2c, 0xe4, 0x41, 0x57, 0xdc, 0xa0, 0x77, 0x61, 0xbd,
+	0x3f, 0x18, 0xe0, 0x58, 0x68, 0x83, 0xd7, 0x32, 0x15, 0x1c, 0xc3, 0x7a, 0x88, 0x23, 0x3a, 0xfd,
+	0x5b, 0xfe, 0xf3, 0x64, 0x54, 0xdc, 0x64, 0x04, 0x3b, 0x40, 0x5e, 0x24, 0x5c, 0x68, 0x53, 0xfc,
+	0x7a, 0x01, 0xbc, 0x02, 0xff, 0x8c, 0x45, 0x19, 0x1f, 0x22, 0x7b, 0x29, 0xdb, 0x22, 0x3f, 0x4f,
+	0xc6, 0xff, 0x2c, 0x8a, 0x75, 0x58, 0xdb, 0xcf, 0x18, 0x4d, 0xd3, 0xb3, 0x97, 0x67, 0xaf, 0x6c,
+	0xb3, 0xfb, 0x11, 0xba, 0xf2, 0xaa, 0x19, 0x23, 0xcc, 0x54, 0x45, 0x39, 0x0e, 0x18, 0x0a, 0x5b,
+	0x51, 0x7d, 0x23, 0x37, 0xa1, 0x3a, 0x61, 0x89, 0xb1, 0x29, 0x8f, 0xd7, 0xea, 0x6c, 0xc1, 0x16,
+	0x34, 0xa5, 0x83, 0x3d, 0x1a, 0xa3, 0x2c, 0xf4, 0x80, 0xc6, 0xf3, 0x42, 0xcb, 0x73, 0x70, 0x04,
+	0x9d, 0x10, 0x07, 0x74, 0x8a, 0x6c, 0x26, 0x65, 0xb8, 0x2c, 0xa2, 0x64, 0x70, 0xdf, 0x53, 0x4d,
+	0x5a, 0x5f, 0x1c, 0x5f, 0x95, 0xcb, 0x7d, 0x21, 0xfc, 0xeb, 0xe4, 0x79, 0xdf, 0x45, 0x7e, 0xa1,
+	0xb1, 0x78, 0x0b, 0x8d, 0xc5, 0x86, 0x53, 0xc9, 0xc3, 0xd1, 0xcd, 0x2e, 0x0f, 0xc7, 0x74, 0x81,
+	0x02, 0x2d, 0xe8, 0x40, 0xfb, 0xe8, 0xed, 0xf1, 0xa9, 0x4d, 0xe1, 0xaf, 0x1e, 0x54, 0x8f, 0xde,
+	0x1e, 0xcb, 0x04, 0x5d, 0x88, 0x99, 0xf1, 0x22, 0x8f, 0x8a, 0x92, 0xd8, 0x32, 0xc8, 0xa3, 0xa4,
+	0x44, 0xe9, 0x7b, 0x63, 0x55, 0x1e, 0x55, 0x5a, 0x39, 0x9a, 0x25, 0x41, 0x1e, 0xc9, 0x0d, 0xf0,
+	0x6c, 0x93, 0xf5, 0x32, 0x79, 0xb3, 0xdb, 0x80, 0x87, 0x52, 0x7a, 0xc0, 0xa6, 0xaa, 0x83, 0xb6,
+	0x42, 0x79, 0x94, 0xfc, 0x8f, 0xa6, 0x65, 0x7a, 0x1f, 0x83, 0x7b, 0x50, 0x93, 0xa1, 0x91, 0xff,
+	0x41, 0xed, 0x02, 0x67, 0x3a, 0x87, 0xed, 0x9d, 0x96, 0x4e, 0xd6, 0xd1, 0xdb, 0xe3, 0x50, 0x91,
+	0x83, 0xa7, 0x50, 0x57, 0x99, 0x2b, 0x54, 0xa4, 0x6e, 0x52, 0xd0, 0x83, 0x76, 0x8c, 0x7c, 0xc0,
+	0x92, 0xb1, 0xcc, 0xa0, 0x6d, 0x48, 0x0e, 0x69, 0xe7, 0x8f, 0x36, 0xd4, 0xfa, 0x13, 0x71, 0x4e,
+	0x3e, 0x87, 0x86, 0x7e, 0xd1, 0xc4, 0x19, 0x7c, 0x9b, 0x5d, 0x7d, 0xb6, 0x33, 0x34, 0x58, 0x21,
+	0x77, 0xa0, 0x7a, 0x80, 0xe2, 0x13, 0x42, 0x0f, 0xa0, 0x71, 0x80, 0xa2, 0x9f, 0xa6, 0xa4, 0x63,
+	0x79, 0x2a, 0xc1, 0x25, 0xa2, 0x9f, 0x19, 0xff, 0xae, 0x41, 0x83, 0x08, 0x55, 0xda, 0x60, 0x85,
+	0x7c, 0x09, 0x9d, 0x37, 0x72, 0xf6, 0x45, 0x02, 0x75, 0xb5, 0x5d, 0xfe, 0xa2, 0xf0, 0x2e, 0x34,
+	0xf4, 0x4a, 0x41, 0xfe, 0x63, 0x2c, 0x2e, 0x2e, 0x18, 0x25, 0x41, 0x7c, 0x03, 0x5d, 0x3d, 0x71,
+	0xe6, 0x13, 0x64, 0x43, 0xcb, 0x14, 0xe7, 0xd1, 0xa2, 0xaf, 0x7b, 0x72, 0xd3, 0x50, 0xa3, 0xf3,
+	0xca, 0x90, 0xee, 0x42, 0xe3, 0x05, 0x7d, 0x4f, 0x27, 0xe2, 0x4a, 0xa9, 0x47, 0xb0, 0x16, 0xe2,
+	0x94, 0x5e, 0x60, 0x3f, 0x4d, 0x4f, 0x91, 0xf3, 0x84, 0x66, 0xfc, 0x4a, 0x85, 0x6d, 0x58, 0x3d,
+	0x40, 0xa1, 0x30, 0xb2, 0x36, 0x47, 0x85, 0x85, 0xf2, 0x26, 0xe4, 0xa4, 0x60, 0x85, 0x3c, 0x83,
+	0x0d, 0xc3, 0x28, 0xce, 0xb1, 0xcd, 0xe2, 0x67, 0xba, 0xa3, 0xb0, 0x24, 0x4d, 0xbb, 0xf2, 0x81,
+	0x73, 0x9c, 0xdb, 0x20, 0xeb, 0x25, 0xea, 0x8b, 0x61, 0x3e, 0x81, 0xb6, 0x33, 0xf2, 0x88, 0xaf,
+	0xb9, 0xcb, 0x53, 0xb0, 0xc4, 0xdf, 0x01, 0x90, 0xe5, 0x09, 0x48, 0xfe, 0x3f, 0x97, 0x2b, 0x9f,
+	0x8d, 0x25, 0x86, 0xbe, 0x05, 0xc8, 0xe7, 0xe2, 0x1c, 0x18, 0x8b, 0x93, 0xb2, 0x44, 0xf1, 0x31,
+	0xac, 0x9a, 0x29, 0x69, 0x11, 0x51, 0x1c, 0x9a, 0x25, 0x2a, 0x4f, 0x01, 0xf2, 0xde, 0x6c, 0x7d,
+	0x2d, 0x75, 0xeb, 0x4d, 0x63, 0xae, 0xd8, 0xb1, 0x83, 0x15, 0xf2, 0x35, 0xb4, 0xf7, 0x68, 0x36,
+	0x4c, 0xd8, 0x48, 0xe9, 0x77, 0x73, 0x31, 0xd9, 0xb0, 0x36, 0xd7, 0xad, 0x3f, 0xa7, 0xcf, 0x06,
+	0x2b, 0xe4, 0x07, 0xb8, 0x75, 0x80, 0x19, 0xb2, 0x48, 0x60, 0x81, 0x75, 0x5d, 0xfd, 0xc7, 0xd0,
+	0xd2, 0x15, 0x39, 0x79, 0xde, 0x27, 0xb7, 0xb4, 0xcc, 0x42, 0xff, 0x5d, 0xac, 0xeb, 0x53, 0xe8,
+	0x14, 0x56, 0x00, 0x8b, 0xa5, 0xb2, 0xbd, 0xa0, 0x24, 0x4d, 0xdf, 0xc1, 0x0d, 0x77, 0x2b, 0x20,
+	0xb7, 0xb5, 0x44, 0xc9, 0xa6, 0x50, 0xae, 0xec, 0xee, 0x01, 0x56, 0xb9, 0x64, 0x37, 0x28, 0x57,
+	0x76, 0xf7, 0x01, 0xab, 0x5c, 0xb2, 0x23, 0x94, 0x28, 0x3f, 0x81, 0xb6, 0x33, 0xff, 0x2d, 0x9a,
+	0x97, 0x57, 0x82, 0x12, 0xd5, 0x7d, 0x58, 0x5b, 0x5a, 0x03, 0xc8, 0x96, 0x49, 0xea, 0x25, 0xfb,
+	0xc1, 0xb2, 0x99, 0x77, 0x0d, 0xf5, 0x03, 0x77, 0xf7, 0xaf, 0x01, 0x00, 0xf8, 0x9f, 0x98, 0x0e,
+	0xf9, 0x0e, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ client.Option
+var _ server.Option
+
+// Client API for Auth service
+
+type AuthClient interface {
+	Create(ctx context.Context, in *User, opts ...client.CallOption) (*Response, error)
+	Get(ctx context.Context, in *User, opts ...client.CallOption) (*Response, error)
+	GetAll(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
+	Auth(ctx context.Context, in *User, opts ...client.CallOption) (*Token, error)
+	ValidateToken(ctx context.Context, in *Token, opts ...client.CallOption) (*Token, error)
+	Update(ctx context.Context, in *UpdateUserRequest, opts ...client.CallOption) (*Response, error)
+	ChangePassword(ctx context.Context, in *PasswordChange, opts ...client.CallOption) (*Token, error)
+	Refresh(ctx context.Context, in *Token, opts ...client.CallOption) (*Token, error)
+	Logout(ctx context.Context, in *Token, opts ...client.CallOption) (*Token, error)
+	RevokeAllSessions(ctx context.Context, in *Token, opts ...client.CallOption) (*Token, error)
+	GetJWKS(ctx context.Context, in *JWKSRequest, opts ...client.CallOption) (*JWKS, error)
+	RequestPasswordReset(ctx context.Context, in *PasswordResetRequest, opts ...client.CallOption) (*Response, error)
+	ResetPassword(ctx context.Context, in *PasswordReset, opts ...client.CallOption) (*Token, error)
+	VerifyEmail(ctx context.Context, in *VerifyEmailRequest, opts ...client.CallOption) (*Response, error)
+	ResendVerification(ctx context.Context, in *ResendVerificationRequest, opts ...client.CallOption) (*Response, error)
+	UnlockUser(ctx context.Context, in *UnlockUserRequest, opts ...client.CallOption) (*Response, error)
+	SetRole(ctx context.Context, in *SetRoleRequest, opts ...client.CallOption) (*Response, error)
+	EnrollTOTP(ctx context.Context, in *EnrollTOTPRequest, opts ...client.CallOption) (*TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, in *TOTPCode, opts ...client.CallOption) (*RecoveryCodes, error)
+	GenerateRecoveryCodes(ctx context.Context, in *TOTPCode, opts ...client.CallOption) (*RecoveryCodes, error)
+	VerifyMFA(ctx context.Context, in *MFAVerification, opts ...client.CallOption) (*Token, error)
+	CreateCompany(ctx context.Context, in *CreateCompanyRequest, opts ...client.CallOption) (*Response, error)
+	InviteMember(ctx context.Context, in *InviteMemberRequest, opts ...client.CallOption) (*Response, error)
+	AcceptInvite(ctx context.Context, in *AcceptInviteRequest, opts ...client.CallOption) (*Response, error)
+	RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...client.CallOption) (*Response, error)
+	ListMembers(ctx context.Context, in *ListMembersRequest, opts ...client.CallOption) (*Response, error)
+	TransferOwnership(ctx context.Context, in *TransferOwnershipRequest, opts ...client.CallOption) (*Response, error)
+}
+
+type authClient struct {
+	c           client.Client
+	serviceName string
+}
+
+func NewAuthClient(serviceName string, c client.Client) AuthClient {
+	if c == nil {
+		c = client.NewClient()
+	}
+	if len(serviceName) == 0 {
+		serviceName = "auth"
+	}
+	return &authClient{
+		c:           c,
+		serviceName: serviceName,
+	}
+}
+
+func (c *authClient) Create(ctx context.Context, in *User, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.Create", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) Get(ctx context.Context, in *User, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.Get", in)
 	out := new(Response)
 	err := c.c.Call(ctx, req, out, opts...)
 	if err != nil {
@@ -475,7 +1687,7 @@ func (c *authClient) ValidateToken(ctx context.Context, in *Token, opts ...clien
 	return out, nil
 }
 
-func (c *authClient) Update(ctx context.Context, in *User, opts ...client.CallOption) (*Response, error) {
+func (c *authClient) Update(ctx context.Context, in *UpdateUserRequest, opts ...client.CallOption) (*Response, error) {
 	req := c.c.NewRequest(c.serviceName, "Auth.Update", in)
 	out := new(Response)
 	err := c.c.Call(ctx, req, out, opts...)
@@ -495,6 +1707,206 @@ func (c *authClient) ChangePassword(ctx context.Context, in *PasswordChange, opt
 	return out, nil
 }
 
+func (c *authClient) Refresh(ctx context.Context, in *Token, opts ...client.CallOption) (*Token, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.Refresh", in)
+	out := new(Token)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) Logout(ctx context.Context, in *Token, opts ...client.CallOption) (*Token, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.Logout", in)
+	out := new(Token)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RevokeAllSessions(ctx context.Context, in *Token, opts ...client.CallOption) (*Token, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.RevokeAllSessions", in)
+	out := new(Token)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetJWKS(ctx context.Context, in *JWKSRequest, opts ...client.CallOption) (*JWKS, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.GetJWKS", in)
+	out := new(JWKS)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RequestPasswordReset(ctx context.Context, in *PasswordResetRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.RequestPasswordReset", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ResetPassword(ctx context.Context, in *PasswordReset, opts ...client.CallOption) (*Token, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.ResetPassword", in)
+	out := new(Token)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) VerifyEmail(ctx context.Context, in *VerifyEmailRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.VerifyEmail", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ResendVerification(ctx context.Context, in *ResendVerificationRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.ResendVerification", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) UnlockUser(ctx context.Context, in *UnlockUserRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.UnlockUser", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) SetRole(ctx context.Context, in *SetRoleRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.SetRole", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) EnrollTOTP(ctx context.Context, in *EnrollTOTPRequest, opts ...client.CallOption) (*TOTPEnrollment, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.EnrollTOTP", in)
+	out := new(TOTPEnrollment)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ConfirmTOTP(ctx context.Context, in *TOTPCode, opts ...client.CallOption) (*RecoveryCodes, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.ConfirmTOTP", in)
+	out := new(RecoveryCodes)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GenerateRecoveryCodes(ctx context.Context, in *TOTPCode, opts ...client.CallOption) (*RecoveryCodes, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.GenerateRecoveryCodes", in)
+	out := new(RecoveryCodes)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) VerifyMFA(ctx context.Context, in *MFAVerification, opts ...client.CallOption) (*Token, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.VerifyMFA", in)
+	out := new(Token)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) CreateCompany(ctx context.Context, in *CreateCompanyRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.CreateCompany", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) InviteMember(ctx context.Context, in *InviteMemberRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.InviteMember", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) AcceptInvite(ctx context.Context, in *AcceptInviteRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.AcceptInvite", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.RemoveMember", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ListMembers(ctx context.Context, in *ListMembersRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.ListMembers", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) TransferOwnership(ctx context.Context, in *TransferOwnershipRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.serviceName, "Auth.TransferOwnership", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Auth service
 
 type AuthHandler interface {
@@ -503,8 +1915,28 @@ type AuthHandler interface {
 	GetAll(context.Context, *Request, *Response) error
 	Auth(context.Context, *User, *Token) error
 	ValidateToken(context.Context, *Token, *Token) error
-	Update(context.Context, *User, *Response) error
+	Update(context.Context, *UpdateUserRequest, *Response) error
 	ChangePassword(context.Context, *PasswordChange, *Token) error
+	Refresh(context.Context, *Token, *Token) error
+	Logout(context.Context, *Token, *Token) error
+	RevokeAllSessions(context.Context, *Token, *Token) error
+	GetJWKS(context.Context, *JWKSRequest, *JWKS) error
+	RequestPasswordReset(context.Context, *PasswordResetRequest, *Response) error
+	ResetPassword(context.Context, *PasswordReset, *Token) error
+	VerifyEmail(context.Context, *VerifyEmailRequest, *Response) error
+	ResendVerification(context.Context, *ResendVerificationRequest, *Response) error
+	UnlockUser(context.Context, *UnlockUserRequest, *Response) error
+	SetRole(context.Context, *SetRoleRequest, *Response) error
+	EnrollTOTP(context.Context, *EnrollTOTPRequest, *TOTPEnrollment) error
+	ConfirmTOTP(context.Context, *TOTPCode, *RecoveryCodes) error
+	GenerateRecoveryCodes(context.Context, *TOTPCode, *RecoveryCodes) error
+	VerifyMFA(context.Context, *MFAVerification, *Token) error
+	CreateCompany(context.Context, *CreateCompanyRequest, *Response) error
+	InviteMember(context.Context, *InviteMemberRequest, *Response) error
+	AcceptInvite(context.Context, *AcceptInviteRequest, *Response) error
+	RemoveMember(context.Context, *RemoveMemberRequest, *Response) error
+	ListMembers(context.Context, *ListMembersRequest, *Response) error
+	TransferOwnership(context.Context, *TransferOwnershipRequest, *Response) error
 }
 
 func RegisterAuthHandler(s server.Server, hdlr AuthHandler, opts ...server.HandlerOption) {
@@ -535,10 +1967,90 @@ func (h *Auth) ValidateToken(ctx context.Context, in *Token, out *Token) error {
 	return h.AuthHandler.ValidateToken(ctx, in, out)
 }
 
-func (h *Auth) Update(ctx context.Context, in *User, out *Response) error {
+func (h *Auth) Update(ctx context.Context, in *UpdateUserRequest, out *Response) error {
 	return h.AuthHandler.Update(ctx, in, out)
 }
 
 func (h *Auth) ChangePassword(ctx context.Context, in *PasswordChange, out *Token) error {
 	return h.AuthHandler.ChangePassword(ctx, in, out)
 }
+
+func (h *Auth) Refresh(ctx context.Context, in *Token, out *Token) error {
+	return h.AuthHandler.Refresh(ctx, in, out)
+}
+
+func (h *Auth) Logout(ctx context.Context, in *Token, out *Token) error {
+	return h.AuthHandler.Logout(ctx, in, out)
+}
+
+func (h *Auth) RevokeAllSessions(ctx context.Context, in *Token, out *Token) error {
+	return h.AuthHandler.RevokeAllSessions(ctx, in, out)
+}
+
+func (h *Auth) GetJWKS(ctx context.Context, in *JWKSRequest, out *JWKS) error {
+	return h.AuthHandler.GetJWKS(ctx, in, out)
+}
+
+func (h *Auth) RequestPasswordReset(ctx context.Context, in *PasswordResetRequest, out *Response) error {
+	return h.AuthHandler.RequestPasswordReset(ctx, in, out)
+}
+
+func (h *Auth) ResetPassword(ctx context.Context, in *PasswordReset, out *Token) error {
+	return h.AuthHandler.ResetPassword(ctx, in, out)
+}
+
+func (h *Auth) VerifyEmail(ctx context.Context, in *VerifyEmailRequest, out *Response) error {
+	return h.AuthHandler.VerifyEmail(ctx, in, out)
+}
+
+func (h *Auth) ResendVerification(ctx context.Context, in *ResendVerificationRequest, out *Response) error {
+	return h.AuthHandler.ResendVerification(ctx, in, out)
+}
+
+func (h *Auth) UnlockUser(ctx context.Context, in *UnlockUserRequest, out *Response) error {
+	return h.AuthHandler.UnlockUser(ctx, in, out)
+}
+
+func (h *Auth) SetRole(ctx context.Context, in *SetRoleRequest, out *Response) error {
+	return h.AuthHandler.SetRole(ctx, in, out)
+}
+
+func (h *Auth) EnrollTOTP(ctx context.Context, in *EnrollTOTPRequest, out *TOTPEnrollment) error {
+	return h.AuthHandler.EnrollTOTP(ctx, in, out)
+}
+
+func (h *Auth) ConfirmTOTP(ctx context.Context, in *TOTPCode, out *RecoveryCodes) error {
+	return h.AuthHandler.ConfirmTOTP(ctx, in, out)
+}
+
+func (h *Auth) GenerateRecoveryCodes(ctx context.Context, in *TOTPCode, out *RecoveryCodes) error {
+	return h.AuthHandler.GenerateRecoveryCodes(ctx, in, out)
+}
+
+func (h *Auth) VerifyMFA(ctx context.Context, in *MFAVerification, out *Token) error {
+	return h.AuthHandler.VerifyMFA(ctx, in, out)
+}
+
+func (h *Auth) CreateCompany(ctx context.Context, in *CreateCompanyRequest, out *Response) error {
+	return h.AuthHandler.CreateCompany(ctx, in, out)
+}
+
+func (h *Auth) InviteMember(ctx context.Context, in *InviteMemberRequest, out *Response) error {
+	return h.AuthHandler.InviteMember(ctx, in, out)
+}
+
+func (h *Auth) AcceptInvite(ctx context.Context, in *AcceptInviteRequest, out *Response) error {
+	return h.AuthHandler.AcceptInvite(ctx, in, out)
+}
+
+func (h *Auth) RemoveMember(ctx context.Context, in *RemoveMemberRequest, out *Response) error {
+	return h.AuthHandler.RemoveMember(ctx, in, out)
+}
+
+func (h *Auth) ListMembers(ctx context.Context, in *ListMembersRequest, out *Response) error {
+	return h.AuthHandler.ListMembers(ctx, in, out)
+}
+
+func (h *Auth) TransferOwnership(ctx context.Context, in *TransferOwnershipRequest, out *Response) error {
+	return h.AuthHandler.TransferOwnership(ctx, in, out)
+}
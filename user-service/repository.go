@@ -3,21 +3,59 @@ package main
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gocql/gocql"
-	authPb "github.com/willdot/go-do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/apierrors"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/user-service/verifier"
 )
 
 var errUserAlreadyExists = "User with email '%s' already exists"
 
+var errLoginAttemptsContended = errors.New("Failed login couldn't be recorded as the attempts kept changing")
+
+// maxLoginFailureTries is how many times recording a failed login is tried when other failures are being recorded at
+// the same time
+const maxLoginFailureTries = 10
+
 // Repository ..
 type Repository interface {
 	GetAll() ([]*authPb.User, error)
+	GetByCompanyID(companyID string) ([]*authPb.User, error)
 	Get(id string) (*authPb.User, error)
 	Create(user *authPb.User) error
 	GetByEmail(email string) (*authPb.User, error)
 	Update(user *authPb.User) error
 	UpdatePassword(id, password string) error
+	SetRole(id, role string) error
+	GetTokenGeneration(id string) (int32, error)
+	CreateSession(session *LoginSession) error
+	GetSession(id string) (*LoginSession, error)
+	RotateSession(session *LoginSession, previousHash string) (bool, error)
+	RevokeSession(id string) error
+	RevokeSessions(userID string) error
+	CreateOneTimeToken(purpose tokenPurpose, token *OneTimeToken) error
+	GetOneTimeToken(purpose tokenPurpose, id string) (*OneTimeToken, error)
+	DeleteOneTimeToken(purpose tokenPurpose, id string) (bool, error)
+	SetEmailVerified(id string) error
+	ClaimVerificationEmail(userID string, interval time.Duration) (bool, error)
+	GetLoginAttempts(key string) (*LoginAttempts, error)
+	RecordLoginFailure(key string, at time.Time, ttl time.Duration) (*LoginAttempts, error)
+	ClearLoginAttempts(key string) error
+	GetTOTP(userID string) (*TOTPSettings, error)
+	SetTOTPSecret(userID, secret string) error
+	EnableTOTP(userID string, step int64) error
+	UseTOTPStep(userID string, step, lastStep int64) (bool, error)
+	ReplaceRecoveryCodes(userID string, hashes []string) error
+	UseRecoveryCode(userID, hash string) (bool, error)
+	CreateCompany(company *authPb.Company) error
+	GetCompany(id string) (*authPb.Company, error)
+	SetCompany(userID, companyID, role string) error
+	TransferOwnership(companyID, ownerID, newOwnerID string) (bool, error)
+	CreateInvite(invite *CompanyInvite, lifetime time.Duration) error
+	GetInvite(companyID, email string) (*CompanyInvite, error)
+	DeleteInvite(companyID, email string) error
 }
 
 // UserRepository is a datastore
@@ -27,31 +65,56 @@ type UserRepository struct {
 
 // GetAll will get all users from database
 func (repo *UserRepository) GetAll() ([]*authPb.User, error) {
+	return repo.scanUsers(repo.Session.Query("SELECT * FROM user"))
+}
+
+// GetByCompanyID will get the members of a company
+func (repo *UserRepository) GetByCompanyID(companyID string) ([]*authPb.User, error) {
+	return repo.scanUsers(repo.Session.Query("SELECT * FROM user WHERE companyId = ?", companyID))
+}
+
+func (repo *UserRepository) scanUsers(query *gocql.Query) ([]*authPb.User, error) {
 	var users []*authPb.User
 
 	m := map[string]interface{}{}
 
-	query := repo.Session.Query("SELECT * FROM user")
 	iterable := query.Iter()
 
 	for iterable.MapScan(m) {
-		users = append(users, &authPb.User{
-			Id:       m["id"].(gocql.UUID).String(),
-			Name:     m["name"].(string),
-			Email:    m["email"].(string),
-			Password: m["password"].(string),
-			Company:  m["company"].(string),
-		})
+		users = append(users, userFromMap(m))
 		m = map[string]interface{}{}
 	}
 
-	return users, nil
+	return users, iterable.Close()
+}
+
+// userFromMap creates a user from a row of the user table. Users created before roles were added are users
+func userFromMap(m map[string]interface{}) *authPb.User {
+	user := &authPb.User{
+		Id:               m["id"].(gocql.UUID).String(),
+		Name:             m["name"].(string),
+		Email:            m["email"].(string),
+		Password:         m["password"].(string),
+		Company:          m["company"].(string),
+		CompanyId:        m["companyid"].(string),
+		Timezone:         m["timezone"].(string),
+		CarryOverDailyDo: m["carryoverdailydo"].(bool),
+		EmailVerified:    m["emailverified"].(bool),
+		MfaEnabled:       m["mfaenabled"].(bool),
+		Role:             m["role"].(string),
+	}
+
+	if user.Role == "" {
+		user.Role = verifier.RoleUser
+	}
+
+	return user
 }
 
 // Get will get a single user
 func (repo *UserRepository) Get(id string) (*authPb.User, error) {
 	var found = false
-	var user authPb.User
+	var user *authPb.User
 	m := map[string]interface{}{}
 
 	query := repo.Session.Query("SELECT * FROM user WHERE id=? LIMIT 1", id)
@@ -59,27 +122,21 @@ func (repo *UserRepository) Get(id string) (*authPb.User, error) {
 
 	for iterable.MapScan(m) {
 		found = true
-		user = authPb.User{
-			Id:       m["id"].(gocql.UUID).String(),
-			Name:     m["name"].(string),
-			Email:    m["email"].(string),
-			Password: m["password"].(string),
-			Company:  m["company"].(string),
-		}
+		user = userFromMap(m)
 	}
 
 	if found {
-		return &user, nil
+		return user, nil
 	}
 
-	return nil, errors.New("User can't be found")
+	return nil, errUnknownUser
 }
 
 // GetByEmail will get a user by email
 func (repo *UserRepository) GetByEmail(email string) (*authPb.User, error) {
 
 	var found = false
-	var user authPb.User
+	var user *authPb.User
 	m := map[string]interface{}{}
 
 	query := repo.Session.Query("SELECT * FROM user WHERE email=? LIMIT 1", email)
@@ -87,20 +144,14 @@ func (repo *UserRepository) GetByEmail(email string) (*authPb.User, error) {
 
 	for iterable.MapScan(m) {
 		found = true
-		user = authPb.User{
-			Id:       m["id"].(gocql.UUID).String(),
-			Name:     m["name"].(string),
-			Email:    m["email"].(string),
-			Password: m["password"].(string),
-			Company:  m["company"].(string),
-		}
+		user = userFromMap(m)
 	}
 
 	if found {
-		return &user, nil
+		return user, nil
 	}
 
-	return nil, errors.New("User can't be found")
+	return nil, errUnknownUser
 }
 
 // Create will create a new user
@@ -117,30 +168,517 @@ func (repo *UserRepository) Create(user *authPb.User) error {
 	}
 
 	if found {
-		return fmt.Errorf(errUserAlreadyExists, user.Email)
+		return apierrors.Conflict(errUserAlreadyExists, user.Email)
 	}
 
 	gocqlUUID := gocql.TimeUUID()
 
 	err := repo.Session.Query(`
-	INSERT INTO user (id, name, email, password, company) VALUES (?,?,?,?,?)`,
-		gocqlUUID, user.Name, user.Email, user.Password, user.Company).Exec()
+	INSERT INTO user (id, name, email, password, company, companyId, timezone, carryOverDailyDo, emailVerified, mfaEnabled, role) VALUES (?,?,?,?,?,?,?,?,?,?,?)`,
+		gocqlUUID, user.Name, user.Email, user.Password, user.Company, "", user.Timezone, user.CarryOverDailyDo, user.EmailVerified, false, user.Role).Exec()
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	user.Id = gocqlUUID.String()
+
+	return nil
 }
 
 // Update will update a user
 func (repo *UserRepository) Update(user *authPb.User) error {
 
-	err := repo.Session.Query(`UPDATE user SET name = ?, company = ? where id = ?`, user.Name, user.Company, user.Id).Exec()
+	err := repo.Session.Query(`UPDATE user SET name = ?, company = ?, timezone = ?, carryOverDailyDo = ? where id = ?`,
+		user.Name, user.Company, user.Timezone, user.CarryOverDailyDo, user.Id).Exec()
 
 	return err
 }
 
-// UpdatePassword updates the users password
+// UpdatePassword updates the users password and moves them on to a new token generation, so that tokens issued
+// before the change can no longer be used
 func (repo *UserRepository) UpdatePassword(id, password string) error {
 
-	err := repo.Session.Query(`UPDATE user SET password = ? where id = ?`, password, id).Exec()
+	tokenGeneration, err := repo.GetTokenGeneration(id)
+
+	if err != nil {
+		return err
+	}
+
+	err = repo.Session.Query(`UPDATE user SET password = ?, tokenGeneration = ? where id = ?`, password, int(tokenGeneration+1), id).Exec()
+
+	return err
+}
+
+// SetRole changes the role of a user and moves them on to a new token generation, so that tokens with their old role
+// can no longer be used
+func (repo *UserRepository) SetRole(id, role string) error {
+
+	tokenGeneration, err := repo.GetTokenGeneration(id)
+
+	if err != nil {
+		return err
+	}
+
+	err = repo.Session.Query(`UPDATE user SET role = ?, tokenGeneration = ? where id = ?`, role, int(tokenGeneration+1), id).Exec()
 
 	return err
 }
+
+// GetTokenGeneration gets the generation of a users tokens. Users created before generations were added are on 0
+func (repo *UserRepository) GetTokenGeneration(id string) (int32, error) {
+	var tokenGeneration int
+
+	err := repo.Session.Query(`SELECT tokenGeneration FROM user WHERE id = ?`, id).Consistency(gocql.One).Scan(&tokenGeneration)
+
+	if err == gocql.ErrNotFound {
+		return 0, errUnknownUser
+	}
+
+	return int32(tokenGeneration), err
+}
+
+// CreateSession will create a new session
+func (repo *UserRepository) CreateSession(session *LoginSession) error {
+	gocqlUUID := gocql.TimeUUID()
+
+	err := repo.Session.Query(`
+	INSERT INTO session (id, userId, refreshTokenHash, expiresAt, revoked) VALUES (?,?,?,?,?)`,
+		gocqlUUID, session.UserID, session.RefreshTokenHash, time.Unix(session.ExpiresAt, 0), false).Exec()
+
+	if err != nil {
+		return err
+	}
+
+	session.ID = gocqlUUID.String()
+
+	return nil
+}
+
+// GetSession will get a single session
+func (repo *UserRepository) GetSession(id string) (*LoginSession, error) {
+	var session *LoginSession
+	m := map[string]interface{}{}
+
+	uuid, err := gocql.ParseUUID(id)
+
+	if err != nil {
+		return nil, errSessionNotFound
+	}
+
+	query := repo.Session.Query("SELECT * FROM session WHERE id=? LIMIT 1", uuid)
+	iterable := query.Consistency(gocql.One).Iter()
+
+	for iterable.MapScan(m) {
+		session = &LoginSession{
+			ID:               m["id"].(gocql.UUID).String(),
+			UserID:           m["userid"].(string),
+			RefreshTokenHash: m["refreshtokenhash"].(string),
+			ExpiresAt:        m["expiresat"].(time.Time).Unix(),
+			Revoked:          m["revoked"].(bool),
+		}
+	}
+
+	if err := iterable.Close(); err != nil {
+		return nil, err
+	}
+
+	if session == nil {
+		return nil, errSessionNotFound
+	}
+
+	return session, nil
+}
+
+// RotateSession replaces the refresh token hash and expiry of a session, but only if the hash stored is still the
+// previous one. Returns false if it wasn't, meaning the refresh token has already been used
+func (repo *UserRepository) RotateSession(session *LoginSession, previousHash string) (bool, error) {
+
+	return repo.Session.Query(`UPDATE session SET refreshTokenHash = ?, expiresAt = ? WHERE id = ? IF refreshTokenHash = ?`,
+		session.RefreshTokenHash, time.Unix(session.ExpiresAt, 0), session.ID, previousHash).MapScanCAS(map[string]interface{}{})
+}
+
+// RevokeSession revokes a session so that its tokens can no longer be used
+func (repo *UserRepository) RevokeSession(id string) error {
+
+	err := repo.Session.Query(`UPDATE session SET revoked = ? WHERE id = ?`, true, id).Exec()
+
+	return err
+}
+
+// RevokeSessions revokes all of a users sessions
+func (repo *UserRepository) RevokeSessions(userID string) error {
+	var ids []gocql.UUID
+	m := map[string]interface{}{}
+
+	iterable := repo.Session.Query("SELECT id FROM session WHERE userId = ?", userID).Iter()
+
+	for iterable.MapScan(m) {
+		ids = append(ids, m["id"].(gocql.UUID))
+		m = map[string]interface{}{}
+	}
+
+	if err := iterable.Close(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := repo.Session.Query(`UPDATE session SET revoked = ? WHERE id = ?`, true, id).Exec(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateOneTimeToken will create a new one time token. Cassandra removes it once it has expired
+func (repo *UserRepository) CreateOneTimeToken(purpose tokenPurpose, token *OneTimeToken) error {
+	gocqlUUID := gocql.TimeUUID()
+
+	ttl := token.ExpiresAt - time.Now().Unix()
+
+	if ttl < 1 {
+		ttl = 1
+	}
+
+	err := repo.Session.Query(fmt.Sprintf(`
+	INSERT INTO %s (id, userId, tokenHash, expiresAt) VALUES (?,?,?,?) USING TTL ?`, purpose),
+		gocqlUUID, token.UserID, token.TokenHash, time.Unix(token.ExpiresAt, 0), int(ttl)).Exec()
+
+	if err != nil {
+		return err
+	}
+
+	token.ID = gocqlUUID.String()
+
+	return nil
+}
+
+// GetOneTimeToken will get a single one time token
+func (repo *UserRepository) GetOneTimeToken(purpose tokenPurpose, id string) (*OneTimeToken, error) {
+	var token *OneTimeToken
+	m := map[string]interface{}{}
+
+	uuid, err := gocql.ParseUUID(id)
+
+	if err != nil {
+		return nil, errOneTimeTokenNotFound
+	}
+
+	query := repo.Session.Query(fmt.Sprintf("SELECT * FROM %s WHERE id=? LIMIT 1", purpose), uuid)
+	iterable := query.Consistency(gocql.One).Iter()
+
+	for iterable.MapScan(m) {
+		token = &OneTimeToken{
+			ID:        m["id"].(gocql.UUID).String(),
+			UserID:    m["userid"].(string),
+			TokenHash: m["tokenhash"].(string),
+			ExpiresAt: m["expiresat"].(time.Time).Unix(),
+		}
+	}
+
+	if err := iterable.Close(); err != nil {
+		return nil, err
+	}
+
+	if token == nil {
+		return nil, errOneTimeTokenNotFound
+	}
+
+	return token, nil
+}
+
+// DeleteOneTimeToken deletes a one time token once it has been used. Returns false if it had already been deleted,
+// meaning the token has already been used
+func (repo *UserRepository) DeleteOneTimeToken(purpose tokenPurpose, id string) (bool, error) {
+
+	return repo.Session.Query(fmt.Sprintf(`DELETE FROM %s WHERE id = ? IF EXISTS`, purpose), id).MapScanCAS(map[string]interface{}{})
+}
+
+// SetEmailVerified marks a users email as verified
+func (repo *UserRepository) SetEmailVerified(id string) error {
+
+	err := repo.Session.Query(`UPDATE user SET emailVerified = ? WHERE id = ?`, true, id).Exec()
+
+	return err
+}
+
+// ClaimVerificationEmail records that a verification email is being sent to a user, unless one was sent within the
+// interval. The record expires after the interval, so returns false if one still exists
+func (repo *UserRepository) ClaimVerificationEmail(userID string, interval time.Duration) (bool, error) {
+
+	ttl := int(interval / time.Second)
+
+	if ttl < 1 {
+		ttl = 1
+	}
+
+	return repo.Session.Query(`INSERT INTO verification_email (userId, sentAt) VALUES (?,?) IF NOT EXISTS USING TTL ?`,
+		userID, time.Now(), ttl).MapScanCAS(map[string]interface{}{})
+}
+
+// GetLoginAttempts gets the failed logins stored under a key. If there haven't been any, attempts with no failures are
+// returned
+func (repo *UserRepository) GetLoginAttempts(key string) (*LoginAttempts, error) {
+	attempts := &LoginAttempts{Key: key}
+	m := map[string]interface{}{}
+
+	iterable := repo.Session.Query("SELECT * FROM login_attempts WHERE id=? LIMIT 1", key).Iter()
+
+	for iterable.MapScan(m) {
+		attempts.Failures = m["failures"].(int)
+		attempts.LastFailure = m["lastfailure"].(time.Time)
+	}
+
+	if err := iterable.Close(); err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+// RecordLoginFailure adds a failed login to the attempts stored under a key. The count is only changed if nothing else
+// has changed it since it was read, so that failures at the same time aren't lost. Cassandra removes the attempts once
+// there hasn't been a failure for the ttl
+func (repo *UserRepository) RecordLoginFailure(key string, at time.Time, ttl time.Duration) (*LoginAttempts, error) {
+
+	seconds := int(ttl / time.Second)
+
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	for try := 0; try < maxLoginFailureTries; try++ {
+		attempts, err := repo.GetLoginAttempts(key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var applied bool
+
+		if attempts.Failures == 0 {
+			applied, err = repo.Session.Query(`INSERT INTO login_attempts (id, failures, lastFailure) VALUES (?,?,?) IF NOT EXISTS USING TTL ?`,
+				key, 1, at, seconds).MapScanCAS(map[string]interface{}{})
+		} else {
+			applied, err = repo.Session.Query(`UPDATE login_attempts USING TTL ? SET failures = ?, lastFailure = ? WHERE id = ? IF failures = ?`,
+				seconds, attempts.Failures+1, at, key, attempts.Failures).MapScanCAS(map[string]interface{}{})
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if applied {
+			return &LoginAttempts{key, attempts.Failures + 1, at}, nil
+		}
+	}
+
+	return nil, errLoginAttemptsContended
+}
+
+// ClearLoginAttempts forgets the failed logins stored under a key
+func (repo *UserRepository) ClearLoginAttempts(key string) error {
+
+	return repo.Session.Query(`DELETE FROM login_attempts WHERE id = ?`, key).Exec()
+}
+
+// GetTOTP gets the authenticator app secret of a user
+func (repo *UserRepository) GetTOTP(userID string) (*TOTPSettings, error) {
+	var settings *TOTPSettings
+	m := map[string]interface{}{}
+
+	id, err := gocql.ParseUUID(userID)
+
+	if err != nil {
+		return nil, errUnknownUser
+	}
+
+	iterable := repo.Session.Query("SELECT totpSecret, mfaEnabled, totpLastStep FROM user WHERE id=? LIMIT 1", id).Consistency(gocql.One).Iter()
+
+	for iterable.MapScan(m) {
+		settings = &TOTPSettings{
+			Secret:   m["totpsecret"].(string),
+			Enabled:  m["mfaenabled"].(bool),
+			LastStep: m["totplaststep"].(int64),
+		}
+	}
+
+	if err := iterable.Close(); err != nil {
+		return nil, err
+	}
+
+	if settings == nil {
+		return nil, errUnknownUser
+	}
+
+	return settings, nil
+}
+
+// SetTOTPSecret sets a new authenticator app secret for a user, which isn't used until it's enabled
+func (repo *UserRepository) SetTOTPSecret(userID, secret string) error {
+
+	return repo.Session.Query(`UPDATE user SET totpSecret = ?, mfaEnabled = ?, totpLastStep = ? WHERE id = ?`, secret, false, int64(0), userID).Exec()
+}
+
+// EnableTOTP turns on two factor authentication for a user, recording the step of the code that confirmed it
+func (repo *UserRepository) EnableTOTP(userID string, step int64) error {
+
+	return repo.Session.Query(`UPDATE user SET mfaEnabled = ?, totpLastStep = ? WHERE id = ?`, true, step, userID).Exec()
+}
+
+// UseTOTPStep records that the code for a step has been used, as long as the last step used is still the one that was
+// read. Returns false if another code was used first
+func (repo *UserRepository) UseTOTPStep(userID string, step, lastStep int64) (bool, error) {
+
+	return repo.Session.Query(`UPDATE user SET totpLastStep = ? WHERE id = ? IF totpLastStep = ?`, step, userID, lastStep).
+		MapScanCAS(map[string]interface{}{})
+}
+
+// ReplaceRecoveryCodes removes a users recovery codes and stores the hashes of new ones
+func (repo *UserRepository) ReplaceRecoveryCodes(userID string, hashes []string) error {
+
+	if err := repo.Session.Query(`DELETE FROM recovery_code WHERE userId = ?`, userID).Exec(); err != nil {
+		return err
+	}
+
+	for _, hash := range hashes {
+		if err := repo.Session.Query(`INSERT INTO recovery_code (userId, codeHash) VALUES (?,?)`, userID, hash).Exec(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UseRecoveryCode deletes a recovery code once it has been used. Returns false if the user doesn't have the code, or
+// it has already been used
+func (repo *UserRepository) UseRecoveryCode(userID, hash string) (bool, error) {
+
+	return repo.Session.Query(`DELETE FROM recovery_code WHERE userId = ? AND codeHash = ? IF EXISTS`, userID, hash).
+		MapScanCAS(map[string]interface{}{})
+}
+
+// CreateCompany will create a new company
+func (repo *UserRepository) CreateCompany(company *authPb.Company) error {
+	gocqlUUID := gocql.TimeUUID()
+
+	err := repo.Session.Query(`INSERT INTO company (id, name, ownerId, createdAt) VALUES (?,?,?,?)`,
+		gocqlUUID, company.Name, company.OwnerId, time.Unix(company.CreatedAt, 0)).Exec()
+
+	if err != nil {
+		return err
+	}
+
+	company.Id = gocqlUUID.String()
+
+	return nil
+}
+
+// GetCompany will get a single company
+func (repo *UserRepository) GetCompany(id string) (*authPb.Company, error) {
+	var company *authPb.Company
+	m := map[string]interface{}{}
+
+	uuid, err := gocql.ParseUUID(id)
+
+	if err != nil {
+		return nil, errCompanyNotFound
+	}
+
+	iterable := repo.Session.Query("SELECT * FROM company WHERE id=? LIMIT 1", uuid).Consistency(gocql.One).Iter()
+
+	for iterable.MapScan(m) {
+		company = &authPb.Company{
+			Id:        m["id"].(gocql.UUID).String(),
+			Name:      m["name"].(string),
+			OwnerId:   m["ownerid"].(string),
+			CreatedAt: m["createdat"].(time.Time).Unix(),
+		}
+	}
+
+	if err := iterable.Close(); err != nil {
+		return nil, err
+	}
+
+	if company == nil {
+		return nil, errCompanyNotFound
+	}
+
+	return company, nil
+}
+
+// SetCompany moves a user into a company, or out of one with an empty id, with their role in it. The user is moved on
+// to a new token generation, so that tokens for their old company can no longer be used
+func (repo *UserRepository) SetCompany(userID, companyID, role string) error {
+
+	tokenGeneration, err := repo.GetTokenGeneration(userID)
+
+	if err != nil {
+		return err
+	}
+
+	return repo.Session.Query(`UPDATE user SET companyId = ?, role = ?, tokenGeneration = ? where id = ?`,
+		companyID, role, int(tokenGeneration+1), userID).Exec()
+}
+
+// TransferOwnership changes the owner of a company, as long as the owner is still the one that was read. Returns
+// false if the owner was changed first
+func (repo *UserRepository) TransferOwnership(companyID, ownerID, newOwnerID string) (bool, error) {
+
+	uuid, err := gocql.ParseUUID(companyID)
+
+	if err != nil {
+		return false, errCompanyNotFound
+	}
+
+	return repo.Session.Query(`UPDATE company SET ownerId = ? WHERE id = ? IF ownerId = ?`, newOwnerID, uuid, ownerID).
+		MapScanCAS(map[string]interface{}{})
+}
+
+// CreateInvite will create an invite to a company, replacing any earlier invite for the email. Cassandra removes it
+// once it has expired
+func (repo *UserRepository) CreateInvite(invite *CompanyInvite, lifetime time.Duration) error {
+
+	ttl := int(lifetime.Seconds())
+
+	if ttl < 1 {
+		ttl = 1
+	}
+
+	return repo.Session.Query(`INSERT INTO company_invite (email, companyId, invitedBy, createdAt) VALUES (?,?,?,?) USING TTL ?`,
+		invite.Email, invite.CompanyID, invite.InvitedBy, time.Now(), ttl).Exec()
+}
+
+// GetInvite will get the invite for an email to a company
+func (repo *UserRepository) GetInvite(companyID, email string) (*CompanyInvite, error) {
+	var invite *CompanyInvite
+	m := map[string]interface{}{}
+
+	iterable := repo.Session.Query("SELECT * FROM company_invite WHERE email=? AND companyId=? LIMIT 1", email, companyID).
+		Consistency(gocql.One).Iter()
+
+	for iterable.MapScan(m) {
+		invite = &CompanyInvite{
+			CompanyID: m["companyid"].(string),
+			Email:     m["email"].(string),
+			InvitedBy: m["invitedby"].(string),
+		}
+	}
+
+	if err := iterable.Close(); err != nil {
+		return nil, err
+	}
+
+	if invite == nil {
+		return nil, errInviteNotFound
+	}
+
+	return invite, nil
+}
+
+// DeleteInvite deletes an invite once it has been accepted
+func (repo *UserRepository) DeleteInvite(companyID, email string) error {
+
+	return repo.Session.Query(`DELETE FROM company_invite WHERE email = ? AND companyId = ?`, email, companyID).Exec()
+}
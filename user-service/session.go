@@ -0,0 +1,79 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/willdot/Go-Do/apierrors"
+)
+
+var errInvalidRefreshToken = apierrors.Unauthenticated("Refresh token is not valid")
+
+var errSessionRevoked = apierrors.Unauthenticated("The session for this token has been revoked")
+
+var errSessionExpired = apierrors.Unauthenticated("The session for this refresh token has expired, log in again")
+
+var errSessionNotFound = apierrors.NotFound("Session not found")
+
+// tokenSecretLength is the number of random bytes in a refresh or password reset token
+const tokenSecretLength = 32
+
+// LoginSession is a login for a user. Access tokens carry the id of the session they were issued for, so revoking the
+// session stops its tokens being accepted. Only a hash of the sessions current refresh token is stored
+type LoginSession struct {
+	ID               string
+	UserID           string
+	RefreshTokenHash string
+	ExpiresAt        int64
+	Revoked          bool
+}
+
+// newTokenSecret creates the random part of a refresh or password reset token and the hash of it to store
+func newTokenSecret() (secret, hash string, err error) {
+	b := make([]byte, tokenSecretLength)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	secret = base64.RawURLEncoding.EncodeToString(b)
+
+	return secret, hashTokenSecret(secret), nil
+}
+
+// hashTokenSecret hashes the secret part of a token. The secret is random, so a fast hash is enough
+func hashTokenSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// joinToken joins the id of what a token is for and its secret into the token given to the client
+func joinToken(id, secret string) string {
+	return id + "." + secret
+}
+
+// splitToken gets the id and secret from a token made by joinToken
+func splitToken(token string) (id, secret string, ok bool) {
+	parts := strings.SplitN(token, ".", 2)
+
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+
+	return parts[0], parts[1], true
+}
+
+// splitRefreshToken gets the session id and secret from a refresh token
+func splitRefreshToken(token string) (sessionID, secret string, err error) {
+	sessionID, secret, ok := splitToken(token)
+
+	if !ok {
+		return "", "", errInvalidRefreshToken
+	}
+
+	return sessionID, secret, nil
+}
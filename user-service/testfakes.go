@@ -3,17 +3,33 @@ package main
 import (
 	"context"
 	"errors"
-	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strconv"
 	"time"
 
-	authPb "github.com/willdot/go-do/user-service/proto/auth"
+	"github.com/micro/go-micro/metadata"
+	"github.com/willdot/Go-Do/apierrors"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/user-service/verifier"
 )
 
 type fakeRepo struct {
 	// returnError is used as a flag to return a fake error
 	returnError bool
 	users       []*authPb.User
+	sessions    map[string]*LoginSession
+	generations map[string]int32
+	tokens      map[tokenPurpose]map[string]*OneTimeToken
+	// verificationEmails is when each user was last sent a verification email
+	verificationEmails map[string]time.Time
+	loginAttempts      map[string]*LoginAttempts
+	totp               map[string]*TOTPSettings
+	// recoveryCodes are the hashes of each users recovery codes
+	recoveryCodes map[string]map[string]bool
+	companies     map[string]*authPb.Company
+	// invites are the invites to each company by email
+	invites map[string]map[string]*CompanyInvite
 }
 
 var errFake = errors.New("This is a fake error message")
@@ -26,12 +42,36 @@ func (f *fakeRepo) GetAll() ([]*authPb.User, error) {
 	return f.users, nil
 }
 
+func (f *fakeRepo) GetByCompanyID(companyID string) ([]*authPb.User, error) {
+
+	if f.returnError {
+		return nil, errFake
+	}
+
+	var users []*authPb.User
+
+	for _, user := range f.users {
+		if user.CompanyId == companyID {
+			users = append(users, user)
+		}
+	}
+
+	return users, nil
+}
+
 func (f *fakeRepo) Get(id string) (*authPb.User, error) {
 
 	if f.returnError {
 		return nil, errFake
 	}
-	return &fakeUser, nil
+
+	for _, user := range f.users {
+		if user.Id == id {
+			return user, nil
+		}
+	}
+
+	return nil, errUnknownUser
 }
 
 func (f *fakeRepo) Create(user *authPb.User) error {
@@ -42,7 +82,7 @@ func (f *fakeRepo) Create(user *authPb.User) error {
 
 	for _, v := range f.users {
 		if v.Email == user.Email {
-			return fmt.Errorf(errUserAlreadyExists, user.Email)
+			return apierrors.Conflict(errUserAlreadyExists, user.Email)
 		}
 	}
 	return nil
@@ -54,8 +94,13 @@ func (f *fakeRepo) GetByEmail(email string) (*authPb.User, error) {
 		return nil, errFake
 	}
 
-	user := f.users[0]
-	return user, nil
+	for _, user := range f.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+
+	return nil, errUnknownUser
 }
 
 func (f *fakeRepo) Update(user *authPb.User) error {
@@ -71,14 +116,343 @@ func (f *fakeRepo) UpdatePassword(id, password string) error {
 	if f.returnError {
 		return errFake
 	}
+
+	f.generations[id]++
+	return nil
+}
+
+func (f *fakeRepo) SetRole(id, role string) error {
+
+	if f.returnError {
+		return errFake
+	}
+
+	user, err := f.Get(id)
+
+	if err != nil {
+		return err
+	}
+
+	user.Role = role
+	f.generations[id]++
+
 	return nil
 }
 
+func (f *fakeRepo) GetTokenGeneration(id string) (int32, error) {
+
+	if f.returnError {
+		return 0, errFake
+	}
+	return f.generations[id], nil
+}
+
+func (f *fakeRepo) CreateSession(session *LoginSession) error {
+
+	if f.returnError {
+		return errFake
+	}
+
+	session.ID = strconv.Itoa(len(f.sessions) + 1)
+
+	stored := *session
+	f.sessions[session.ID] = &stored
+
+	return nil
+}
+
+func (f *fakeRepo) GetSession(id string) (*LoginSession, error) {
+
+	if f.returnError {
+		return nil, errFake
+	}
+
+	session, ok := f.sessions[id]
+
+	if !ok {
+		return nil, errSessionNotFound
+	}
+
+	found := *session
+	return &found, nil
+}
+
+func (f *fakeRepo) RotateSession(session *LoginSession, previousHash string) (bool, error) {
+
+	if f.returnError {
+		return false, errFake
+	}
+
+	stored, ok := f.sessions[session.ID]
+
+	if !ok || stored.RefreshTokenHash != previousHash {
+		return false, nil
+	}
+
+	stored.RefreshTokenHash = session.RefreshTokenHash
+	stored.ExpiresAt = session.ExpiresAt
+
+	return true, nil
+}
+
+func (f *fakeRepo) RevokeSession(id string) error {
+
+	if f.returnError {
+		return errFake
+	}
+
+	if session, ok := f.sessions[id]; ok {
+		session.Revoked = true
+	}
+
+	return nil
+}
+
+func (f *fakeRepo) RevokeSessions(userID string) error {
+
+	if f.returnError {
+		return errFake
+	}
+
+	for _, session := range f.sessions {
+		if session.UserID == userID {
+			session.Revoked = true
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeRepo) CreateOneTimeToken(purpose tokenPurpose, token *OneTimeToken) error {
+
+	if f.returnError {
+		return errFake
+	}
+
+	if f.tokens[purpose] == nil {
+		f.tokens[purpose] = map[string]*OneTimeToken{}
+	}
+
+	token.ID = strconv.Itoa(len(f.tokens[purpose]) + 1)
+
+	stored := *token
+	f.tokens[purpose][token.ID] = &stored
+
+	return nil
+}
+
+func (f *fakeRepo) GetOneTimeToken(purpose tokenPurpose, id string) (*OneTimeToken, error) {
+
+	if f.returnError {
+		return nil, errFake
+	}
+
+	token, ok := f.tokens[purpose][id]
+
+	if !ok {
+		return nil, errOneTimeTokenNotFound
+	}
+
+	found := *token
+	return &found, nil
+}
+
+func (f *fakeRepo) DeleteOneTimeToken(purpose tokenPurpose, id string) (bool, error) {
+
+	if f.returnError {
+		return false, errFake
+	}
+
+	if _, ok := f.tokens[purpose][id]; !ok {
+		return false, nil
+	}
+
+	delete(f.tokens[purpose], id)
+
+	return true, nil
+}
+
+func (f *fakeRepo) SetEmailVerified(id string) error {
+
+	if f.returnError {
+		return errFake
+	}
+
+	for _, user := range f.users {
+		if user.Id == id {
+			user.EmailVerified = true
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeRepo) ClaimVerificationEmail(userID string, interval time.Duration) (bool, error) {
+
+	if f.returnError {
+		return false, errFake
+	}
+
+	if sentAt, ok := f.verificationEmails[userID]; ok && time.Since(sentAt) < interval {
+		return false, nil
+	}
+
+	f.verificationEmails[userID] = time.Now()
+
+	return true, nil
+}
+
+func (f *fakeRepo) GetLoginAttempts(key string) (*LoginAttempts, error) {
+
+	if f.returnError {
+		return nil, errFake
+	}
+
+	if attempts, ok := f.loginAttempts[key]; ok {
+		found := *attempts
+		return &found, nil
+	}
+
+	return &LoginAttempts{Key: key}, nil
+}
+
+func (f *fakeRepo) RecordLoginFailure(key string, at time.Time, ttl time.Duration) (*LoginAttempts, error) {
+
+	if f.returnError {
+		return nil, errFake
+	}
+
+	attempts, ok := f.loginAttempts[key]
+
+	if !ok || at.Sub(attempts.LastFailure) >= ttl {
+		attempts = &LoginAttempts{Key: key}
+		f.loginAttempts[key] = attempts
+	}
+
+	attempts.Failures++
+	attempts.LastFailure = at
+
+	found := *attempts
+	return &found, nil
+}
+
+func (f *fakeRepo) ClearLoginAttempts(key string) error {
+
+	if f.returnError {
+		return errFake
+	}
+
+	delete(f.loginAttempts, key)
+
+	return nil
+}
+
+func (f *fakeRepo) GetTOTP(userID string) (*TOTPSettings, error) {
+
+	if f.returnError {
+		return nil, errFake
+	}
+
+	if settings, ok := f.totp[userID]; ok {
+		found := *settings
+		return &found, nil
+	}
+
+	return &TOTPSettings{}, nil
+}
+
+func (f *fakeRepo) SetTOTPSecret(userID, secret string) error {
+
+	if f.returnError {
+		return errFake
+	}
+
+	f.totp[userID] = &TOTPSettings{Secret: secret}
+
+	return nil
+}
+
+func (f *fakeRepo) EnableTOTP(userID string, step int64) error {
+
+	if f.returnError {
+		return errFake
+	}
+
+	f.totp[userID].Enabled = true
+	f.totp[userID].LastStep = step
+
+	for _, user := range f.users {
+		if user.Id == userID {
+			user.MfaEnabled = true
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeRepo) UseTOTPStep(userID string, step, lastStep int64) (bool, error) {
+
+	if f.returnError {
+		return false, errFake
+	}
+
+	if f.totp[userID].LastStep != lastStep {
+		return false, nil
+	}
+
+	f.totp[userID].LastStep = step
+
+	return true, nil
+}
+
+func (f *fakeRepo) ReplaceRecoveryCodes(userID string, hashes []string) error {
+
+	if f.returnError {
+		return errFake
+	}
+
+	f.recoveryCodes[userID] = map[string]bool{}
+
+	for _, hash := range hashes {
+		f.recoveryCodes[userID][hash] = true
+	}
+
+	return nil
+}
+
+func (f *fakeRepo) UseRecoveryCode(userID, hash string) (bool, error) {
+
+	if f.returnError {
+		return false, errFake
+	}
+
+	if !f.recoveryCodes[userID][hash] {
+		return false, nil
+	}
+
+	delete(f.recoveryCodes[userID], hash)
+
+	return true, nil
+}
+
+var fakeSecret = []byte("fake secret")
+
+// fakeServiceToken is the service token the fake service shares with other services
+const fakeServiceToken = "fake service token"
+
+// fakeGateway is the address of the API gateway, which the fake service trusts to pass on the address of the client
+const fakeGateway = "10.0.0.10"
+
+var fakeTrustedProxies, _ = parseTrustedProxies(fakeGateway)
+
 var fakeUser = authPb.User{
+	Id:       "123",
 	Name:     "Fake",
 	Email:    "fake@fake.com",
 	Password: "$2a$10$cSOEkdxPPOrX8h/t3/Aw5e.vludnAzMGU38I3Cv0V/GAAwaqyJDaK",
 	Company:  "fake",
+	// CompanyId is the id of the fake company, which isn't in the fake repo
+	CompanyId: "fake",
 }
 
 var fakeUserToCreate = authPb.User{
@@ -95,17 +469,31 @@ func createService(returnError bool) userHandler {
 
 	users = append(users, &fakeUser)
 
-	fakeRepo := &fakeRepo{returnError, users}
+	fakeRepo := &fakeRepo{returnError, users, map[string]*LoginSession{}, map[string]int32{}, map[tokenPurpose]map[string]*OneTimeToken{}, map[string]time.Time{}, map[string]*LoginAttempts{}, map[string]*TOTPSettings{}, map[string]map[string]bool{}, map[string]*authPb.Company{}, map[string]map[string]*CompanyInvite{}}
 
-	expireTime := time.Now().Add(time.Hour * 1).Unix()
+	accessTokenLifetime := time.Hour
 
 	if returnError {
-		expireTime = time.Now().AddDate(-1, 0, 0).Unix()
+		accessTokenLifetime = -time.Hour * 24 * 365
 	}
 
-	tokenService := TokenService{fakeRepo, expireTime}
+	keys, _ := NewKeySet(defaultKeyID, newHMACKey(defaultKeyID, fakeSecret))
+
+	tokenService := TokenService{fakeRepo, keys, accessTokenLifetime, time.Hour * 24}
+
+	mailer := &MemoryMailer{}
+
+	passwordResetService := PasswordResetService{fakeRepo, mailer, time.Hour, ""}
+
+	verificationService := EmailVerificationService{fakeRepo, mailer, time.Hour, "", time.Minute, false}
 
-	service := userHandler{fakeRepo, tokenService}
+	loginThrottle := LoginThrottle{fakeRepo, emailLoginPolicy, ipLoginPolicy}
+
+	mfaService := MFAService{fakeRepo, mfaIssuer, time.Minute}
+
+	companyService := CompanyService{fakeRepo, mailer, time.Hour}
+
+	service := userHandler{fakeRepo, tokenService, passwordResetService, verificationService, loginThrottle, NewAuditLog(ioutil.Discard), mfaService, companyService, fakeServiceToken, fakeTrustedProxies}
 
 	return service
 }
@@ -121,3 +509,118 @@ func createContext() context.Context {
 
 	return ctx
 }
+
+// createCallerContext creates a context with the claims of a user, as the AuthorisationWrapper does once it has verified
+// their token
+func createCallerContext(userID string) context.Context {
+	return verifier.NewContext(context.Background(), &CustomClaims{UserID: userID, Role: verifier.RoleUser})
+}
+
+// createTokenContext creates a context with a token in the metadata, as it is for requests through the API gateway
+func createTokenContext(token string) context.Context {
+	return metadata.NewContext(context.Background(), metadata.Metadata{"Token": token})
+}
+
+func (f *fakeRepo) CreateCompany(company *authPb.Company) error {
+
+	if f.returnError {
+		return errFake
+	}
+
+	company.Id = strconv.Itoa(len(f.companies) + 100)
+	f.companies[company.Id] = company
+
+	return nil
+}
+
+func (f *fakeRepo) GetCompany(id string) (*authPb.Company, error) {
+
+	if f.returnError {
+		return nil, errFake
+	}
+
+	company, ok := f.companies[id]
+
+	if !ok {
+		return nil, errCompanyNotFound
+	}
+
+	return company, nil
+}
+
+func (f *fakeRepo) SetCompany(userID, companyID, role string) error {
+
+	if f.returnError {
+		return errFake
+	}
+
+	user, err := f.Get(userID)
+
+	if err != nil {
+		return err
+	}
+
+	user.CompanyId = companyID
+	user.Role = role
+	f.generations[userID]++
+
+	return nil
+}
+
+func (f *fakeRepo) TransferOwnership(companyID, ownerID, newOwnerID string) (bool, error) {
+
+	if f.returnError {
+		return false, errFake
+	}
+
+	company, ok := f.companies[companyID]
+
+	if !ok || company.OwnerId != ownerID {
+		return false, nil
+	}
+
+	company.OwnerId = newOwnerID
+
+	return true, nil
+}
+
+func (f *fakeRepo) CreateInvite(invite *CompanyInvite, lifetime time.Duration) error {
+
+	if f.returnError {
+		return errFake
+	}
+
+	if f.invites[invite.CompanyID] == nil {
+		f.invites[invite.CompanyID] = map[string]*CompanyInvite{}
+	}
+
+	f.invites[invite.CompanyID][invite.Email] = invite
+
+	return nil
+}
+
+func (f *fakeRepo) GetInvite(companyID, email string) (*CompanyInvite, error) {
+
+	if f.returnError {
+		return nil, errFake
+	}
+
+	invite, ok := f.invites[companyID][email]
+
+	if !ok {
+		return nil, errInviteNotFound
+	}
+
+	return invite, nil
+}
+
+func (f *fakeRepo) DeleteInvite(companyID, email string) error {
+
+	if f.returnError {
+		return errFake
+	}
+
+	delete(f.invites[companyID], email)
+
+	return nil
+}
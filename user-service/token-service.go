@@ -1,41 +1,39 @@
 package main
 
 import (
+	"time"
+
 	"github.com/dgrijalva/jwt-go"
-	authPb "github.com/willdot/go-do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/apierrors"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/user-service/verifier"
 )
 
-var (
-	key = []byte("mysupersecretkey")
-)
-
-// CustomClaims ..
-type CustomClaims struct {
-	User *authPb.User
-	jwt.StandardClaims
-}
+// CustomClaims are the claims in an access token. They're defined by the verifier so that other services read the
+// same claims the token service writes
+type CustomClaims = verifier.Claims
 
 // Authable ..
 type Authable interface {
 	Decode(token string) (*CustomClaims, error)
-	Encode(user *authPb.User) (string, error)
+	Encode(user *authPb.User, sessionID string, tokenGeneration int32) (string, int64, error)
 }
 
 // TokenService ..
 type TokenService struct {
-	repo       Repository
-	expireTime int64
+	repo                 Repository
+	keys                 *KeySet
+	accessTokenLifetime  time.Duration
+	refreshTokenLifetime time.Duration
 }
 
 // Decode a token
 func (s *TokenService) Decode(tokenString string) (*CustomClaims, error) {
 
-	tokenType, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return key, nil
-	})
+	tokenType, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, s.keys.VerificationKey)
 
 	if err != nil {
-		return nil, err
+		return nil, apierrors.Categorise(err, apierrors.CodeUnauthenticated)
 	}
 
 	if claims, ok := tokenType.Claims.(*CustomClaims); ok && tokenType.Valid {
@@ -45,19 +43,137 @@ func (s *TokenService) Decode(tokenString string) (*CustomClaims, error) {
 	return nil, err
 }
 
-// Encode a claim into a JWT
-func (s *TokenService) Encode(user *authPb.User) (string, error) {
-	expireToken := s.expireTime
+// Encode the claims for a user into a JWT for a session. The token expires after the access token lifetime, and the
+// time it expires is returned along with it
+func (s *TokenService) Encode(user *authPb.User, sessionID string, tokenGeneration int32) (string, int64, error) {
+	expiresAt := time.Now().Add(s.accessTokenLifetime).Unix()
 
 	claims := CustomClaims{
-		user,
-		jwt.StandardClaims{
-			ExpiresAt: expireToken,
+		UserID:          user.Id,
+		SessionID:       sessionID,
+		TokenGeneration: tokenGeneration,
+		Role:            roleOf(user),
+		CompanyID:       user.CompanyId,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expiresAt,
 			Issuer:    "go-do.user",
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := s.keys.Sign(claims)
+
+	return signed, expiresAt, err
+}
+
+// NewSession starts a new session for a user, returning an access token and a refresh token for it
+func (s *TokenService) NewSession(user *authPb.User) (*authPb.Token, error) {
+
+	secret, hash, err := newTokenSecret()
+
+	if err != nil {
+		return nil, err
+	}
+
+	session := LoginSession{
+		UserID:           user.Id,
+		RefreshTokenHash: hash,
+		ExpiresAt:        time.Now().Add(s.refreshTokenLifetime).Unix(),
+	}
+
+	err = s.repo.CreateSession(&session)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issue(user, &session, secret)
+}
+
+// Refresh swaps a refresh token for a new access token and refresh token. Each refresh token can only be used once, so
+// if one that has already been swapped is used again, it's assumed to have been stolen and the session is revoked
+func (s *TokenService) Refresh(token string) (*authPb.Token, error) {
+
+	sessionID, secret, err := splitRefreshToken(token)
+
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.repo.GetSession(sessionID)
+
+	if err == errSessionNotFound {
+		return nil, errInvalidRefreshToken
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Revoked {
+		return nil, errSessionRevoked
+	}
+
+	if session.ExpiresAt < time.Now().Unix() {
+		return nil, errSessionExpired
+	}
+
+	if session.RefreshTokenHash != hashTokenSecret(secret) {
+		if err := s.repo.RevokeSession(session.ID); err != nil {
+			return nil, err
+		}
+
+		return nil, errInvalidRefreshToken
+	}
+
+	user, err := s.repo.Get(session.UserID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	newSecret, newHash, err := newTokenSecret()
+
+	if err != nil {
+		return nil, err
+	}
+
+	previousHash := session.RefreshTokenHash
+	session.RefreshTokenHash = newHash
+	session.ExpiresAt = time.Now().Add(s.refreshTokenLifetime).Unix()
+
+	// Another request may have used the same refresh token at the same time, in which case only one of them wins
+	rotated, err := s.repo.RotateSession(session, previousHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !rotated {
+		return nil, errInvalidRefreshToken
+	}
+
+	return s.issue(user, session, newSecret)
+}
+
+// issue creates the tokens given to a client for a session
+func (s *TokenService) issue(user *authPb.User, session *LoginSession, secret string) (*authPb.Token, error) {
+
+	tokenGeneration, err := s.repo.GetTokenGeneration(user.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, expiresAt, err := s.Encode(user, session.ID, tokenGeneration)
+
+	if err != nil {
+		return nil, err
+	}
 
-	return token.SignedString(key)
+	return &authPb.Token{
+		Token:        accessToken,
+		UserId:       user.Id,
+		RefreshToken: joinToken(session.ID, secret),
+		ExpiresAt:    expiresAt,
+	}, nil
 }
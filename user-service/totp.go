@@ -0,0 +1,115 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// totpDigits is how many digits the codes have, which is what authenticator apps expect
+	totpDigits = 6
+	// totpPeriod is how long each code lasts for
+	totpPeriod = time.Second * 30
+	// totpSkew is how many periods either side of the current one are accepted, for clocks that are a little out
+	totpSkew = 1
+	// totpSecretBytes is the length of the secrets, which RFC 4226 recommends for SHA-1
+	totpSecretBytes = 20
+)
+
+// totpEncoding is how secrets are shown to users, which is base32 without padding as authenticator apps expect
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// hotp generates an HMAC based one time password for a counter (RFC 4226)
+func hotp(key []byte, counter uint64, digits int, h func() hash.Hash) string {
+	mac := hmac.New(h, key)
+	binary.Write(mac, binary.BigEndian, counter)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation takes 31 bits from the offset given by the last 4 bits of the hash
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+
+	for i := 0; i < digits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%modulo)
+}
+
+// totpStep is the number of periods since the Unix epoch, which is the counter for the code at a time
+func totpStep(t time.Time, period time.Duration) int64 {
+	return t.Unix() / int64(period/time.Second)
+}
+
+// totp generates a time based one time password (RFC 6238)
+func totp(key []byte, t time.Time, period time.Duration, digits int, h func() hash.Hash) string {
+	return hotp(key, uint64(totpStep(t, period)), digits, h)
+}
+
+// newTOTPSecret creates a random secret, encoded to be shown to the user
+func newTOTPSecret() (string, error) {
+	secret := make([]byte, totpSecretBytes)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// checkTOTP checks a code against a secret at a time, allowing for the skew. The step the code is for is returned, so
+// that a code that has been used can't be used again
+func checkTOTP(secret, code string, t time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(secret)
+
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	step := totpStep(t, totpPeriod)
+
+	for offset := int64(-totpSkew); offset <= totpSkew; offset++ {
+		expected := hotp(key, uint64(step+offset), totpDigits, sha1.New)
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step + offset, true
+		}
+	}
+
+	return 0, false
+}
+
+// totpURI is the otpauth URI for a secret, which authenticator apps can add the account from, usually as a QR code
+func totpURI(issuer, email, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(int(totpPeriod/time.Second)))
+
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + email,
+		RawQuery: query.Encode(),
+	}
+
+	return uri.String()
+}
+
+// normaliseCode removes the spaces and dashes that users might type in a code, such as "123 456"
+func normaliseCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTOTPVectors checks the codes against the test vectors in appendix B of RFC 6238
+func TestTOTPVectors(t *testing.T) {
+
+	keys := map[string][]byte{
+		"SHA1":   []byte("12345678901234567890"),
+		"SHA256": []byte("12345678901234567890123456789012"),
+		"SHA512": []byte("1234567890123456789012345678901234567890123456789012345678901234"),
+	}
+
+	hashes := map[string]func() hash.Hash{
+		"SHA1":   sha1.New,
+		"SHA256": sha256.New,
+		"SHA512": sha512.New,
+	}
+
+	tests := []struct {
+		time int64
+		mode string
+		want string
+	}{
+		{59, "SHA1", "94287082"},
+		{59, "SHA256", "46119246"},
+		{59, "SHA512", "90693936"},
+		{1111111109, "SHA1", "07081804"},
+		{1111111109, "SHA256", "68084774"},
+		{1111111109, "SHA512", "25091201"},
+		{1111111111, "SHA1", "14050471"},
+		{1111111111, "SHA256", "67062674"},
+		{1111111111, "SHA512", "99943326"},
+		{1234567890, "SHA1", "89005924"},
+		{1234567890, "SHA256", "91819424"},
+		{1234567890, "SHA512", "93441116"},
+		{2000000000, "SHA1", "69279037"},
+		{2000000000, "SHA256", "90698825"},
+		{2000000000, "SHA512", "38618901"},
+		{20000000000, "SHA1", "65353130"},
+		{20000000000, "SHA256", "77737706"},
+		{20000000000, "SHA512", "47863826"},
+	}
+
+	for _, tt := range tests {
+		got := totp(keys[tt.mode], time.Unix(tt.time, 0), totpPeriod, 8, hashes[tt.mode])
+
+		if got != tt.want {
+			t.Errorf("wanted %v for %v at %v but got %v", tt.want, tt.mode, tt.time, got)
+		}
+	}
+}
+
+func TestCheckTOTP(t *testing.T) {
+
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	key, _ := totpEncoding.DecodeString(secret)
+
+	now := time.Unix(1111111111, 0)
+	code := totp(key, now, totpPeriod, totpDigits, sha1.New)
+
+	t.Run("current code", func(t *testing.T) {
+		step, ok := checkTOTP(secret, code, now)
+
+		if !ok || step != totpStep(now, totpPeriod) {
+			t.Errorf("wanted the code to be valid for step %v but got %v %v", totpStep(now, totpPeriod), step, ok)
+		}
+	})
+
+	t.Run("code from the last period", func(t *testing.T) {
+		if _, ok := checkTOTP(secret, code, now.Add(totpPeriod)); !ok {
+			t.Errorf("wanted a code from the last period to be valid")
+		}
+	})
+
+	t.Run("code from too long ago", func(t *testing.T) {
+		if _, ok := checkTOTP(secret, code, now.Add(totpPeriod*2)); ok {
+			t.Errorf("wanted a code from 2 periods ago to be invalid")
+		}
+	})
+
+	t.Run("wrong code", func(t *testing.T) {
+		wrong := "000000"
+
+		if code == wrong {
+			wrong = "111111"
+		}
+
+		if _, ok := checkTOTP(secret, wrong, now); ok {
+			t.Errorf("wanted a wrong code to be invalid")
+		}
+	})
+
+	t.Run("secret that isn't base32", func(t *testing.T) {
+		if _, ok := checkTOTP("not base32!", code, now); ok {
+			t.Errorf("wanted an invalid secret to fail")
+		}
+	})
+}
+
+func TestTOTPURI(t *testing.T) {
+
+	uri := totpURI("Go-Do", "will@email.com", "JBSWY3DPEHPK3PXP")
+
+	if !strings.HasPrefix(uri, "otpauth://totp/Go-Do:will@email.com?") {
+		t.Errorf("wanted an otpauth URI for the account but got %v", uri)
+	}
+
+	for _, param := range []string{"secret=JBSWY3DPEHPK3PXP", "issuer=Go-Do", "digits=6", "period=30", "algorithm=SHA1"} {
+		if !strings.Contains(uri, param) {
+			t.Errorf("wanted %v in %v", param, uri)
+		}
+	}
+}
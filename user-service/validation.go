@@ -0,0 +1,65 @@
+package main
+
+import "github.com/willdot/Go-Do/validation"
+
+const (
+	minPasswordLength = 8
+	maxNameLength     = 100
+	maxCompanyLength  = 100
+	maxTimezoneLength = 64
+)
+
+// newValidator creates the validator with the rules for the requests to each endpoint, which run before the handlers
+func newValidator() *validation.Validator {
+	v := validation.New()
+
+	email := validation.Field("email", validation.Required(), validation.Email())
+	newPassword := validation.Field("newPassword", validation.Required(), validation.Password(minPasswordLength))
+
+	v.Register("Auth.Create",
+		validation.Field("name", validation.MaxLength(maxNameLength)),
+		validation.Field("company", validation.MaxLength(maxCompanyLength)),
+		email,
+		validation.Field("password", validation.Required(), validation.Password(minPasswordLength)),
+		validation.Field("timezone", validation.MaxLength(maxTimezoneLength)),
+	)
+
+	// Logging in doesn't check the password policy, so that users with passwords from before it can still log in
+	v.Register("Auth.Auth",
+		validation.Field("email", validation.Required()),
+		validation.Field("password", validation.Required()),
+	)
+
+	v.Register("Auth.Update",
+		validation.Field("user.id", validation.Required()),
+		validation.Field("user.name", validation.MaxLength(maxNameLength)),
+		validation.Field("user.company", validation.MaxLength(maxCompanyLength)),
+		validation.Field("user.timezone", validation.MaxLength(maxTimezoneLength)),
+	)
+
+	v.Register("Auth.ChangePassword",
+		validation.Field("email", validation.Required()),
+		validation.Field("oldPassword", validation.Required()),
+		newPassword,
+	)
+
+	v.Register("Auth.RequestPasswordReset", email)
+	v.Register("Auth.ResetPassword", validation.Field("token", validation.Required()), newPassword)
+	v.Register("Auth.VerifyEmail", validation.Field("token", validation.Required()))
+	v.Register("Auth.ResendVerification", email)
+	v.Register("Auth.UnlockUser", email)
+	v.Register("Auth.SetRole",
+		validation.Field("userId", validation.Required()),
+		validation.Field("role", validation.Required(), validation.OneOf(allRoles...)),
+	)
+	v.Register("Auth.ConfirmTOTP", validation.Field("code", validation.Required()))
+	v.Register("Auth.GenerateRecoveryCodes", validation.Field("code", validation.Required()))
+	v.Register("Auth.VerifyMFA", validation.Field("mfaToken", validation.Required()))
+
+	v.Register("Auth.CreateCompany", validation.Field("name", validation.Required(), validation.MaxLength(maxCompanyLength)))
+	v.Register("Auth.InviteMember", email)
+	v.Register("Auth.AcceptInvite", validation.Field("companyId", validation.Required()))
+	v.Register("Auth.TransferOwnership", validation.Field("userId", validation.Required()))
+
+	return v
+}
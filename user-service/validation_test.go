@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"github.com/willdot/Go-Do/validation"
+)
+
+func TestValidationRules(t *testing.T) {
+	v := newValidator()
+
+	tests := []struct {
+		name     string
+		endpoint string
+		req      interface{}
+		want     []int32
+	}{
+		{"valid user", "Auth.Create", &authPb.User{Email: "will@email.com", Password: "password1", Name: "Will"}, nil},
+		{"missing email and password", "Auth.Create", &authPb.User{}, []int32{validation.CodeRequired, validation.CodeRequired}},
+		{"malformed email", "Auth.Create", &authPb.User{Email: "will", Password: "password1"}, []int32{validation.CodeInvalidEmail}},
+		{"one character password", "Auth.Create", &authPb.User{Email: "will@email.com", Password: "a"}, []int32{validation.CodeWeakPassword}},
+		{"long name", "Auth.Create", &authPb.User{Email: "will@email.com", Password: "password1", Name: strings.Repeat("a", maxNameLength+1)}, []int32{validation.CodeTooLong}},
+		{"login with an old password", "Auth.Auth", &authPb.User{Email: "will@email.com", Password: "a"}, nil},
+		{"login without a password", "Auth.Auth", &authPb.User{Email: "will@email.com"}, []int32{validation.CodeRequired}},
+		{"update without a user", "Auth.Update", &authPb.UpdateUserRequest{}, []int32{validation.CodeRequired}},
+		{"weak new password", "Auth.ChangePassword", &authPb.PasswordChange{Email: "will@email.com", OldPassword: "a", NewPassword: "b"}, []int32{validation.CodeWeakPassword}},
+		{"weak reset password", "Auth.ResetPassword", &authPb.PasswordReset{Token: "token", NewPassword: "b"}, []int32{validation.CodeWeakPassword}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := v.Validate(tt.endpoint, tt.req)
+
+			if len(errs) != len(tt.want) {
+				t.Fatalf("wanted %v errors but got %v", len(tt.want), errs)
+			}
+
+			for i, err := range errs {
+				if err.Code != tt.want[i] {
+					t.Errorf("wanted code %v but got %v", tt.want[i], err)
+				}
+			}
+		})
+	}
+}
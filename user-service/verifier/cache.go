@@ -0,0 +1,49 @@
+package verifier
+
+import (
+	"sync"
+	"time"
+)
+
+// maxCachedTokens is how many tokens are cached before expired ones are cleared out
+const maxCachedTokens = 10000
+
+// revocationCache remembers which tokens the auth service has recently said are valid
+type revocationCache struct {
+	mu     sync.Mutex
+	tokens map[string]time.Time
+}
+
+func newRevocationCache() *revocationCache {
+	return &revocationCache{tokens: map[string]time.Time{}}
+}
+
+// valid reports if the token was found to be valid and that result hasn't expired
+func (c *revocationCache) valid(token string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires, ok := c.tokens[token]
+
+	return ok && now.Before(expires)
+}
+
+func (c *revocationCache) add(token string, expires, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.tokens) >= maxCachedTokens {
+		for cached, cachedExpires := range c.tokens {
+			if !now.Before(cachedExpires) {
+				delete(c.tokens, cached)
+			}
+		}
+	}
+
+	// If every cached token is still valid, start again rather than let the cache grow without limit
+	if len(c.tokens) >= maxCachedTokens {
+		c.tokens = map[string]time.Time{}
+	}
+
+	c.tokens[token] = expires
+}
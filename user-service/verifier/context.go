@@ -0,0 +1,28 @@
+package verifier
+
+import "context"
+
+type claimsKey struct{}
+
+// NewContext returns a context carrying the claims of a token that has been verified
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext gets the verified claims added by NewContext
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+
+	return claims, ok && claims != nil && claims.UserID != ""
+}
+
+// UserIDFromContext gets the id of the user whose claims were added by NewContext
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+
+	if !ok {
+		return "", false
+	}
+
+	return claims.UserID, true
+}
@@ -0,0 +1,17 @@
+package verifier
+
+import (
+	"context"
+
+	"github.com/micro/go-micro/metadata"
+)
+
+// ServiceTokenKey is the metadata other services send the SERVICE_TOKEN they share with the auth service in. It lets
+// them call the auth service for users other than the caller, such as getting a users timezone when there's no caller
+const ServiceTokenKey = "Service-Token"
+
+// NewServiceContext returns a context for calling the auth service as a service rather than as the caller. The callers
+// token isn't passed on, so the call is only ever authorised by the service token
+func NewServiceContext(ctx context.Context, serviceToken string) context.Context {
+	return metadata.NewContext(ctx, metadata.Metadata{ServiceTokenKey: serviceToken})
+}
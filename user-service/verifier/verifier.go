@@ -0,0 +1,266 @@
+// Package verifier checks access tokens issued by the auth service without calling it, so that other services don't
+// need a round trip to the auth service for every request
+package verifier
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"golang.org/x/crypto/ed25519"
+)
+
+var errUnknownSigningKey = errors.New("Token was signed with a key that isn't known")
+
+var errUnexpectedSigningMethod = errors.New("Token was signed with a different algorithm to its key")
+
+var errNoUserID = errors.New("Token doesn't have a user id")
+
+// DefaultKeyID is the id the auth service gives the key set by JWT_SECRET. Tokens without a kid header were signed by it
+const DefaultKeyID = "default"
+
+// minRefreshInterval stops tokens with made up key ids causing a call to the auth service every time
+const minRefreshInterval = time.Minute
+
+// The roles a user can have. Tokens issued before roles were added don't have one, and are for a RoleUser
+const (
+	// RoleUser can only see and change their own account
+	RoleUser = "user"
+	// RoleCompanyAdmin can manage the users in their company
+	RoleCompanyAdmin = "company-admin"
+	// RoleSystemAdmin can manage every user
+	RoleSystemAdmin = "system-admin"
+)
+
+// Claims are the claims in an access token
+type Claims struct {
+	UserID          string
+	SessionID       string
+	TokenGeneration int32
+	Role            string `json:",omitempty"`
+	// CompanyID is the company the user is a member of, which is the tenant their data is scoped to
+	CompanyID string `json:",omitempty"`
+	// User is only set in tokens issued before the claims were reduced to ids, which carried the whole user including
+	// their password hash. It's kept so that those tokens keep working until they expire
+	User *authPb.User `json:",omitempty"`
+	jwt.StandardClaims
+}
+
+// publicKey is a key that tokens can be verified with
+type publicKey struct {
+	method jwt.SigningMethod
+	key    interface{}
+}
+
+// Verifier checks the signature and expiry of access tokens using the public keys the auth service publishes. HS256
+// tokens can only be checked if the verifier is given the same secret as the auth service, as it isn't published.
+//
+// Checking a token locally can't tell if its session has been revoked since it was issued, so if revocationTTL is set
+// tokens are also checked with the auth service, and the result is cached for that long
+type Verifier struct {
+	authClient    authPb.AuthClient
+	hmacSecret    []byte
+	revocationTTL time.Duration
+
+	mu          sync.RWMutex
+	keys        map[string]publicKey
+	lastFetched time.Time
+
+	revocations *revocationCache
+}
+
+// New creates a verifier that gets keys from the auth service. hmacSecret can be nil if the auth service doesn't sign
+// tokens with JWT_SECRET, and a revocationTTL of 0 turns off revocation checks
+func New(authClient authPb.AuthClient, hmacSecret []byte, revocationTTL time.Duration) *Verifier {
+	keys := map[string]publicKey{}
+
+	if len(hmacSecret) > 0 {
+		keys[DefaultKeyID] = publicKey{jwt.SigningMethodHS256, hmacSecret}
+	}
+
+	return &Verifier{
+		authClient:    authClient,
+		hmacSecret:    hmacSecret,
+		revocationTTL: revocationTTL,
+		keys:          keys,
+		revocations:   newRevocationCache(),
+	}
+}
+
+// Verify checks a token and returns its claims. For tokens issued before the claims were reduced to ids, the user id is
+// taken from the user in the token
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	claims := &Claims{}
+
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return v.verificationKey(ctx, t)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.UserID == "" && claims.User != nil {
+		claims.UserID = claims.User.Id
+	}
+
+	if claims.UserID == "" {
+		return nil, errNoUserID
+	}
+
+	if claims.Role == "" {
+		claims.Role = RoleUser
+	}
+
+	if v.revocationTTL > 0 {
+		if err := v.checkRevocation(ctx, token, claims); err != nil {
+			return nil, err
+		}
+	}
+
+	return claims, nil
+}
+
+// checkRevocation asks the auth service if a token is still valid, unless it has said so within the last revocationTTL
+func (v *Verifier) checkRevocation(ctx context.Context, token string, claims *Claims) error {
+	now := time.Now()
+
+	if v.revocations.valid(token, now) {
+		return nil
+	}
+
+	_, err := v.authClient.ValidateToken(ctx, &authPb.Token{Token: token})
+
+	if err != nil {
+		return err
+	}
+
+	// Never cache a token for longer than it's valid for
+	expires := now.Add(v.revocationTTL)
+
+	if claims.ExpiresAt != 0 && time.Unix(claims.ExpiresAt, 0).Before(expires) {
+		expires = time.Unix(claims.ExpiresAt, 0)
+	}
+
+	v.revocations.add(token, expires, now)
+
+	return nil
+}
+
+// verificationKey finds the key a token was signed with, getting the keys from the auth service again if it has one the
+// verifier doesn't know about, as it may be a newly rotated key
+func (v *Verifier) verificationKey(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	id, _ := token.Header["kid"].(string)
+
+	if id == "" {
+		id = DefaultKeyID
+	}
+
+	key, ok := v.key(id)
+
+	if !ok && v.shouldRefresh() {
+		if err := v.refresh(ctx); err != nil {
+			return nil, err
+		}
+
+		key, ok = v.key(id)
+	}
+
+	if !ok {
+		return nil, errUnknownSigningKey
+	}
+
+	// Without this, a token could be signed with HMAC using a public key as the secret
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errUnexpectedSigningMethod
+	}
+
+	return key.key, nil
+}
+
+func (v *Verifier) key(id string) (publicKey, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	key, ok := v.keys[id]
+
+	return key, ok
+}
+
+func (v *Verifier) shouldRefresh() bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return time.Since(v.lastFetched) >= minRefreshInterval
+}
+
+// refresh replaces the keys with the ones the auth service currently publishes
+func (v *Verifier) refresh(ctx context.Context) error {
+	jwks, err := v.authClient.GetJWKS(ctx, &authPb.JWKSRequest{})
+
+	if err != nil {
+		return err
+	}
+
+	keys := map[string]publicKey{}
+
+	if len(v.hmacSecret) > 0 {
+		keys[DefaultKeyID] = publicKey{jwt.SigningMethodHS256, v.hmacSecret}
+	}
+
+	for _, jwk := range jwks.GetKeys() {
+		// Keys this version doesn't understand are skipped rather than failing, so that the auth service can start
+		// using new kinds of keys before every service has been updated
+		if key, ok := publicKeyFromJWK(jwk); ok {
+			keys[jwk.Kid] = key
+		}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.keys = keys
+	v.lastFetched = time.Now()
+
+	return nil
+}
+
+// publicKeyFromJWK converts a key published by the auth service
+func publicKeyFromJWK(jwk *authPb.JWK) (publicKey, bool) {
+	switch {
+	case jwk.Kty == "RSA" && jwk.Alg == jwt.SigningMethodRS256.Alg():
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+
+		if err != nil {
+			return publicKey{}, false
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+
+		if err != nil || len(e) == 0 {
+			return publicKey{}, false
+		}
+
+		return publicKey{jwt.SigningMethodRS256, &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}}, true
+
+	case jwk.Kty == "OKP" && jwk.Crv == "Ed25519":
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return publicKey{}, false
+		}
+
+		return publicKey{SigningMethodEdDSA, ed25519.PublicKey(x)}, true
+	}
+
+	return publicKey{}, false
+}
@@ -0,0 +1,410 @@
+package verifier
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/micro/go-micro/client"
+	authPb "github.com/willdot/Go-Do/user-service/proto/auth"
+	"golang.org/x/crypto/ed25519"
+)
+
+var errFake = errors.New("fake error")
+
+// simulatedRPCLatency is roughly how long a call to the auth service takes, including it reading the user from Cassandra
+const simulatedRPCLatency = time.Millisecond
+
+var hmacSecret = []byte("secret")
+
+type fakeAuthClient struct {
+	authPb.AuthClient
+	jwks          *authPb.JWKS
+	revoked       bool
+	latency       time.Duration
+	jwksCalls     int32
+	validateCalls int32
+}
+
+func (c *fakeAuthClient) GetJWKS(ctx context.Context, req *authPb.JWKSRequest, opts ...client.CallOption) (*authPb.JWKS, error) {
+	atomic.AddInt32(&c.jwksCalls, 1)
+
+	return c.jwks, nil
+}
+
+func (c *fakeAuthClient) ValidateToken(ctx context.Context, req *authPb.Token, opts ...client.CallOption) (*authPb.Token, error) {
+	atomic.AddInt32(&c.validateCalls, 1)
+
+	time.Sleep(c.latency)
+
+	if c.revoked {
+		return nil, errFake
+	}
+
+	return &authPb.Token{Valid: true}, nil
+}
+
+type testKeys struct {
+	rsa   *rsa.PrivateKey
+	eddsa ed25519.PrivateKey
+}
+
+func createKeys(t testing.TB) testKeys {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+
+	if err != nil {
+		t.Fatalf("error generating RSA key: %v", err)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+
+	if err != nil {
+		t.Fatalf("error generating EdDSA key: %v", err)
+	}
+
+	return testKeys{rsaKey, edKey}
+}
+
+// jwks publishes the keys the same way the auth service does
+func (k testKeys) jwks() *authPb.JWKS {
+	return &authPb.JWKS{Keys: []*authPb.JWK{
+		{
+			Kty: "OKP",
+			Kid: "eddsa",
+			Alg: "EdDSA",
+			Use: "sig",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(k.eddsa.Public().(ed25519.PublicKey)),
+		},
+		{
+			Kty: "RSA",
+			Kid: "rsa",
+			Alg: "RS256",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(k.rsa.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.rsa.E)).Bytes()),
+		},
+	}}
+}
+
+func createToken(t testing.TB, method jwt.SigningMethod, kid string, key interface{}, claims Claims) string {
+	token := jwt.NewWithClaims(method, claims)
+
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+
+	if err != nil {
+		t.Fatalf("error signing token: %v", err)
+	}
+
+	return signed
+}
+
+func validClaims() Claims {
+	return Claims{UserID: "123", SessionID: "456", StandardClaims: jwt.StandardClaims{
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}}
+}
+
+func assertError(got, want error, t *testing.T) {
+	t.Helper()
+
+	// jwt-go wraps the error from the key func
+	if validationErr, ok := got.(*jwt.ValidationError); ok && validationErr.Inner != nil {
+		got = validationErr.Inner
+	}
+
+	if got != want {
+		t.Errorf("got error '%v' but want error '%v'", got, want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	keys := createKeys(t)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"HS256 without a kid", createToken(t, jwt.SigningMethodHS256, "", hmacSecret, validClaims())},
+		{"HS256 with the default kid", createToken(t, jwt.SigningMethodHS256, DefaultKeyID, hmacSecret, validClaims())},
+		{"RS256", createToken(t, jwt.SigningMethodRS256, "rsa", keys.rsa, validClaims())},
+		{"EdDSA", createToken(t, SigningMethodEdDSA, "eddsa", keys.eddsa, validClaims())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New(&fakeAuthClient{jwks: keys.jwks()}, hmacSecret, 0)
+
+			claims, err := v.Verify(context.Background(), tt.token)
+
+			assertError(err, nil, t)
+
+			if claims.UserID != "123" || claims.SessionID != "456" {
+				t.Errorf("wanted the claims from the token but got %+v", claims)
+			}
+		})
+	}
+
+	t.Run("expired token", func(t *testing.T) {
+		claims := validClaims()
+		claims.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+
+		_, err := New(&fakeAuthClient{}, hmacSecret, 0).Verify(context.Background(), createToken(t, jwt.SigningMethodHS256, "", hmacSecret, claims))
+
+		if validationErr, ok := err.(*jwt.ValidationError); !ok || validationErr.Errors&jwt.ValidationErrorExpired == 0 {
+			t.Errorf("wanted an expired error but got %v", err)
+		}
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		token := createToken(t, jwt.SigningMethodHS256, "", []byte("not the secret"), validClaims())
+
+		_, err := New(&fakeAuthClient{}, hmacSecret, 0).Verify(context.Background(), token)
+
+		assertError(err, jwt.ErrSignatureInvalid, t)
+	})
+
+	t.Run("HS256 without the secret", func(t *testing.T) {
+		token := createToken(t, jwt.SigningMethodHS256, "", hmacSecret, validClaims())
+
+		_, err := New(&fakeAuthClient{jwks: keys.jwks()}, nil, 0).Verify(context.Background(), token)
+
+		assertError(err, errUnknownSigningKey, t)
+	})
+
+	t.Run("token signed with HMAC using a public key", func(t *testing.T) {
+		publicKey, _ := x509.MarshalPKIXPublicKey(&keys.rsa.PublicKey)
+		secret := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})
+
+		token := createToken(t, jwt.SigningMethodHS256, "rsa", secret, validClaims())
+
+		_, err := New(&fakeAuthClient{jwks: keys.jwks()}, nil, 0).Verify(context.Background(), token)
+
+		assertError(err, errUnexpectedSigningMethod, t)
+	})
+
+	t.Run("legacy token with the whole user", func(t *testing.T) {
+		claims := validClaims()
+		claims.UserID = ""
+		claims.User = &authPb.User{Id: "789"}
+
+		got, err := New(&fakeAuthClient{}, hmacSecret, 0).Verify(context.Background(), createToken(t, jwt.SigningMethodHS256, "", hmacSecret, claims))
+
+		assertError(err, nil, t)
+
+		if got.UserID != "789" {
+			t.Errorf("wanted the user id from the user but got %v", got.UserID)
+		}
+	})
+
+	t.Run("role", func(t *testing.T) {
+		claims := validClaims()
+		claims.Role = RoleCompanyAdmin
+
+		got, err := New(&fakeAuthClient{}, hmacSecret, 0).Verify(context.Background(), createToken(t, jwt.SigningMethodHS256, "", hmacSecret, claims))
+
+		assertError(err, nil, t)
+
+		if got.Role != RoleCompanyAdmin {
+			t.Errorf("wanted the role from the token but got %v", got.Role)
+		}
+	})
+
+	t.Run("token from before roles is for a user", func(t *testing.T) {
+		got, err := New(&fakeAuthClient{}, hmacSecret, 0).Verify(context.Background(), createToken(t, jwt.SigningMethodHS256, "", hmacSecret, validClaims()))
+
+		assertError(err, nil, t)
+
+		if got.Role != RoleUser {
+			t.Errorf("wanted the user role but got %v", got.Role)
+		}
+	})
+
+	t.Run("token without a user", func(t *testing.T) {
+		claims := validClaims()
+		claims.UserID = ""
+
+		_, err := New(&fakeAuthClient{}, hmacSecret, 0).Verify(context.Background(), createToken(t, jwt.SigningMethodHS256, "", hmacSecret, claims))
+
+		assertError(err, errNoUserID, t)
+	})
+}
+
+func TestKeyRefresh(t *testing.T) {
+	keys := createKeys(t)
+	authClient := &fakeAuthClient{jwks: &authPb.JWKS{}}
+	v := New(authClient, nil, 0)
+
+	token := createToken(t, jwt.SigningMethodRS256, "rsa", keys.rsa, validClaims())
+
+	_, err := v.Verify(context.Background(), token)
+
+	assertError(err, errUnknownSigningKey, t)
+
+	t.Run("unknown keys don't fetch the keys again straight away", func(t *testing.T) {
+		authClient.jwks = keys.jwks()
+
+		_, err := v.Verify(context.Background(), token)
+
+		assertError(err, errUnknownSigningKey, t)
+
+		if authClient.jwksCalls != 1 {
+			t.Errorf("wanted the keys to be fetched once but they were fetched %v times", authClient.jwksCalls)
+		}
+	})
+
+	t.Run("new keys are fetched once the refresh interval has passed", func(t *testing.T) {
+		v.lastFetched = time.Now().Add(-minRefreshInterval)
+
+		_, err := v.Verify(context.Background(), token)
+
+		assertError(err, nil, t)
+
+		_, err = v.Verify(context.Background(), createToken(t, SigningMethodEdDSA, "eddsa", keys.eddsa, validClaims()))
+
+		assertError(err, nil, t)
+
+		if authClient.jwksCalls != 2 {
+			t.Errorf("wanted known keys to be used without fetching them but they were fetched %v times", authClient.jwksCalls)
+		}
+	})
+}
+
+func TestRevocationCheck(t *testing.T) {
+	token := createToken(t, jwt.SigningMethodHS256, "", hmacSecret, validClaims())
+
+	t.Run("result is cached", func(t *testing.T) {
+		authClient := &fakeAuthClient{}
+		v := New(authClient, hmacSecret, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			_, err := v.Verify(context.Background(), token)
+
+			assertError(err, nil, t)
+		}
+
+		if authClient.validateCalls != 1 {
+			t.Errorf("wanted the auth service to be asked once but it was asked %v times", authClient.validateCalls)
+		}
+	})
+
+	t.Run("checked again once the cache expires", func(t *testing.T) {
+		authClient := &fakeAuthClient{}
+		v := New(authClient, hmacSecret, time.Minute)
+
+		_, err := v.Verify(context.Background(), token)
+
+		assertError(err, nil, t)
+
+		authClient.revoked = true
+		v.revocations.tokens[token] = time.Now().Add(-time.Second)
+
+		_, err = v.Verify(context.Background(), token)
+
+		assertError(err, errFake, t)
+	})
+
+	t.Run("revoked tokens are rejected", func(t *testing.T) {
+		v := New(&fakeAuthClient{revoked: true}, hmacSecret, time.Minute)
+
+		_, err := v.Verify(context.Background(), token)
+
+		assertError(err, errFake, t)
+	})
+
+	t.Run("not checked when turned off", func(t *testing.T) {
+		authClient := &fakeAuthClient{revoked: true}
+
+		_, err := New(authClient, hmacSecret, 0).Verify(context.Background(), token)
+
+		assertError(err, nil, t)
+
+		if authClient.validateCalls != 0 {
+			t.Errorf("wanted the auth service not to be asked but it was asked %v times", authClient.validateCalls)
+		}
+	})
+
+	t.Run("not cached for longer than the token is valid", func(t *testing.T) {
+		claims := validClaims()
+		claims.ExpiresAt = time.Now().Add(time.Second * 10).Unix()
+
+		v := New(&fakeAuthClient{}, hmacSecret, time.Hour)
+		shortToken := createToken(t, jwt.SigningMethodHS256, "", hmacSecret, claims)
+
+		_, err := v.Verify(context.Background(), shortToken)
+
+		assertError(err, nil, t)
+
+		if expires := v.revocations.tokens[shortToken]; expires.After(time.Unix(claims.ExpiresAt, 0)) {
+			t.Errorf("wanted the result to be cached until the token expires at the latest but it's cached until %v", expires)
+		}
+	})
+}
+
+func TestContext(t *testing.T) {
+	if _, ok := UserIDFromContext(context.Background()); ok {
+		t.Errorf("wanted no user id in an empty context")
+	}
+
+	ctx := NewContext(context.Background(), &Claims{UserID: "123", Role: RoleSystemAdmin})
+
+	userID, ok := UserIDFromContext(ctx)
+
+	if !ok || userID != "123" {
+		t.Errorf("wanted user id 123 but got %v", userID)
+	}
+
+	if claims, ok := ClaimsFromContext(ctx); !ok || claims.Role != RoleSystemAdmin {
+		t.Errorf("wanted the claims but got %+v", claims)
+	}
+}
+
+// BenchmarkAuthenticate compares authenticating a request by asking the auth service, as the task service did for every
+// request, with verifying the token locally. The auth service is faked with simulatedRPCLatency
+func BenchmarkAuthenticate(b *testing.B) {
+	keys := createKeys(b)
+	token := createToken(b, SigningMethodEdDSA, "eddsa", keys.eddsa, validClaims())
+
+	b.Run("ValidateToken RPC", func(b *testing.B) {
+		authClient := &fakeAuthClient{latency: simulatedRPCLatency}
+
+		for i := 0; i < b.N; i++ {
+			// Once in the wrapper and again in the handler
+			authClient.ValidateToken(context.Background(), &authPb.Token{Token: token})
+			authClient.ValidateToken(context.Background(), &authPb.Token{Token: token})
+		}
+	})
+
+	b.Run("local", func(b *testing.B) {
+		v := New(&fakeAuthClient{jwks: keys.jwks(), latency: simulatedRPCLatency}, nil, 0)
+
+		for i := 0; i < b.N; i++ {
+			if _, err := v.Verify(context.Background(), token); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("local with cached revocation check", func(b *testing.B) {
+		v := New(&fakeAuthClient{jwks: keys.jwks(), latency: simulatedRPCLatency}, nil, time.Minute)
+
+		for i := 0; i < b.N; i++ {
+			if _, err := v.Verify(context.Background(), token); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
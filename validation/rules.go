@@ -0,0 +1,141 @@
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxEmailLength is the longest an email address can be
+const maxEmailLength = 254
+
+// maxPasswordBytes is the longest password bcrypt can hash, as it ignores anything after it
+const maxPasswordBytes = 72
+
+// Required checks that a field has been set. Strings that are only spaces count as not being set
+func Required() Rule {
+	return func(field string, value reflect.Value) *Error {
+		if !value.IsValid() {
+			return newError(CodeRequired, field, "is required")
+		}
+
+		switch value.Kind() {
+		case reflect.String:
+			if strings.TrimSpace(value.String()) == "" {
+				return newError(CodeRequired, field, "is required")
+			}
+		case reflect.Slice, reflect.Map, reflect.Ptr:
+			if value.IsNil() || (value.Kind() != reflect.Ptr && value.Len() == 0) {
+				return newError(CodeRequired, field, "is required")
+			}
+		default:
+			if value.Interface() == reflect.Zero(value.Type()).Interface() {
+				return newError(CodeRequired, field, "is required")
+			}
+		}
+
+		return nil
+	}
+}
+
+// MaxLength checks that a string has at most the given number of characters
+func MaxLength(max int) Rule {
+	return func(field string, value reflect.Value) *Error {
+		if value.Kind() == reflect.String && utf8.RuneCountInString(value.String()) > max {
+			return newError(CodeTooLong, field, "must be at most %d characters", max)
+		}
+
+		return nil
+	}
+}
+
+// MaxItems checks that a repeated field has at most the given number of items
+func MaxItems(max int) Rule {
+	return func(field string, value reflect.Value) *Error {
+		if value.Kind() == reflect.Slice && value.Len() > max {
+			return newError(CodeTooMany, field, "must have at most %d items", max)
+		}
+
+		return nil
+	}
+}
+
+// Each checks every item of a repeated field against the rules
+func Each(rules ...Rule) Rule {
+	return func(field string, value reflect.Value) *Error {
+		if value.Kind() != reflect.Slice {
+			return nil
+		}
+
+		for i := 0; i < value.Len(); i++ {
+			for _, rule := range rules {
+				if err := rule(fmt.Sprintf("%s[%d]", field, i), value.Index(i)); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// Email checks that a string is a plain email address such as will@email.com, without a display name. Empty strings are
+// allowed, so use Required as well if it has to be set
+func Email() Rule {
+	return func(field string, value reflect.Value) *Error {
+		if value.Kind() != reflect.String || value.String() == "" {
+			return nil
+		}
+
+		email := value.String()
+		address, err := mail.ParseAddress(email)
+
+		if err != nil || address.Address != email || len(email) > maxEmailLength {
+			return newError(CodeInvalidEmail, field, "must be an email address such as will@email.com")
+		}
+
+		return nil
+	}
+}
+
+// Password checks that a string is at least minLength characters and has both letters and numbers in it. Empty strings
+// are allowed, so use Required as well if it has to be set
+func Password(minLength int) Rule {
+	return func(field string, value reflect.Value) *Error {
+		if value.Kind() != reflect.String || value.String() == "" {
+			return nil
+		}
+
+		password := value.String()
+
+		hasLetter := strings.IndexFunc(password, unicode.IsLetter) >= 0
+		hasNumber := strings.IndexFunc(password, unicode.IsDigit) >= 0
+
+		if utf8.RuneCountInString(password) < minLength || len(password) > maxPasswordBytes || !hasLetter || !hasNumber {
+			return newError(CodeWeakPassword, field, "must be %d to %d characters long and contain both letters and numbers", minLength, maxPasswordBytes)
+		}
+
+		return nil
+	}
+}
+
+// OneOf checks that a string is one of the allowed values. Empty strings are allowed, so use Required as well if it has
+// to be set
+func OneOf(allowed ...string) Rule {
+	return func(field string, value reflect.Value) *Error {
+		if value.Kind() != reflect.String || value.String() == "" {
+			return nil
+		}
+
+		for _, v := range allowed {
+			if value.String() == v {
+				return nil
+			}
+		}
+
+		return newError(CodeNotAllowed, field, "must be one of %s", strings.Join(allowed, ", "))
+	}
+}
@@ -0,0 +1,109 @@
+// Package validation checks requests before they reach the handlers, against rules declared for each field of the
+// request sent to an endpoint
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Error codes for the rules, sent in the code of the errors in a response
+const (
+	CodeRequired int32 = 1001 + iota
+	CodeTooLong
+	CodeTooMany
+	CodeInvalidEmail
+	CodeWeakPassword
+	CodeNotAllowed
+)
+
+// Error is a field of a request that isn't valid
+type Error struct {
+	Code        int32
+	Field       string
+	Description string
+}
+
+func (e *Error) Error() string {
+	return e.Description
+}
+
+// Rule checks the value of a field, returning an error if it isn't valid. The value is invalid (the zero reflect.Value)
+// when the field is inside a message that wasn't sent
+type Rule func(field string, value reflect.Value) *Error
+
+// FieldRules are the rules for one field of a request
+type FieldRules struct {
+	// Name is the name of the field in the proto, with the fields of nested messages separated by dots, such as
+	// user.email
+	Name  string
+	Rules []Rule
+}
+
+// Field declares the rules for a field
+func Field(name string, rules ...Rule) FieldRules {
+	return FieldRules{name, rules}
+}
+
+// Validator checks requests against the rules registered for the endpoint they're sent to. Rules are registered per
+// endpoint rather than per message, as some messages are sent to more than one endpoint, such as the user that's sent
+// to both create a user and log in
+type Validator struct {
+	endpoints map[string][]FieldRules
+}
+
+// New creates a validator with no rules
+func New() *Validator {
+	return &Validator{endpoints: map[string][]FieldRules{}}
+}
+
+// Register sets the rules for the request to an endpoint, such as "TaskService.Create"
+func (v *Validator) Register(endpoint string, fields ...FieldRules) {
+	v.endpoints[endpoint] = fields
+}
+
+// Validate checks a request, returning an error for each field that breaks a rule. Only the first rule a field breaks
+// is reported
+func (v *Validator) Validate(endpoint string, req interface{}) []*Error {
+	var errs []*Error
+
+	for _, field := range v.endpoints[endpoint] {
+		value := fieldValue(reflect.ValueOf(req), field.Name)
+
+		for _, rule := range field.Rules {
+			if err := rule(field.Name, value); err != nil {
+				errs = append(errs, err)
+				break
+			}
+		}
+	}
+
+	return errs
+}
+
+// fieldValue finds a field in a message by its proto name. The Go name of a proto field is its name with the first
+// letter in upper case
+func fieldValue(message reflect.Value, name string) reflect.Value {
+	for _, part := range strings.Split(name, ".") {
+		for message.Kind() == reflect.Ptr {
+			if message.IsNil() {
+				return reflect.Value{}
+			}
+
+			message = message.Elem()
+		}
+
+		if message.Kind() != reflect.Struct {
+			return reflect.Value{}
+		}
+
+		message = message.FieldByName(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return message
+}
+
+func newError(code int32, field, format string, args ...interface{}) *Error {
+	return &Error{code, field, field + " " + fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,184 @@
+package validation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/micro/go-micro/errors"
+	"github.com/micro/go-micro/server"
+)
+
+type protoError struct {
+	Code        int32
+	Description string
+}
+
+type nested struct {
+	Email string
+}
+
+type request struct {
+	Title    string
+	Tags     []string
+	Priority int32
+	Password string
+	User     *nested
+}
+
+type response struct {
+	Errors []*protoError
+}
+
+func codes(errs []*Error) []int32 {
+	var got []int32
+
+	for _, err := range errs {
+		got = append(got, err.Code)
+	}
+
+	return got
+}
+
+func assertCodes(got []*Error, want []int32, t *testing.T) {
+	t.Helper()
+
+	gotCodes := codes(got)
+
+	if len(gotCodes) != len(want) {
+		t.Fatalf("wanted codes %v but got %v", want, gotCodes)
+	}
+
+	for i := range want {
+		if gotCodes[i] != want[i] {
+			t.Errorf("wanted codes %v but got %v", want, gotCodes)
+		}
+	}
+}
+
+func TestRules(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		rules []FieldRules
+		req   *request
+		want  []int32
+	}{
+		{"required string", []FieldRules{Field("title", Required())}, &request{Title: "title"}, nil},
+		{"missing string", []FieldRules{Field("title", Required())}, &request{}, []int32{CodeRequired}},
+		{"only spaces", []FieldRules{Field("title", Required())}, &request{Title: "  "}, []int32{CodeRequired}},
+		{"missing repeated", []FieldRules{Field("tags", Required())}, &request{}, []int32{CodeRequired}},
+		{"missing number", []FieldRules{Field("priority", Required())}, &request{}, []int32{CodeRequired}},
+		{"missing nested message", []FieldRules{Field("user.email", Required())}, &request{}, []int32{CodeRequired}},
+		{"nested field", []FieldRules{Field("user.email", Required(), Email())}, &request{User: &nested{"will@email.com"}}, nil},
+		{"too long", []FieldRules{Field("title", MaxLength(3))}, &request{Title: "four"}, []int32{CodeTooLong}},
+		{"length is in characters", []FieldRules{Field("title", MaxLength(3))}, &request{Title: "äöü"}, nil},
+		{"too many", []FieldRules{Field("tags", MaxItems(1))}, &request{Tags: []string{"a", "b"}}, []int32{CodeTooMany}},
+		{"each item", []FieldRules{Field("tags", Each(MaxLength(1)))}, &request{Tags: []string{"a", "bb"}}, []int32{CodeTooLong}},
+		{"email", []FieldRules{Field("user.email", Email())}, &request{User: &nested{"not an email"}}, []int32{CodeInvalidEmail}},
+		{"email with a name", []FieldRules{Field("user.email", Email())}, &request{User: &nested{"Will <will@email.com>"}}, []int32{CodeInvalidEmail}},
+		{"empty email is left to required", []FieldRules{Field("user.email", Email())}, &request{User: &nested{}}, nil},
+		{"password", []FieldRules{Field("password", Password(8))}, &request{Password: "password1"}, nil},
+		{"short password", []FieldRules{Field("password", Password(8))}, &request{Password: "pass1"}, []int32{CodeWeakPassword}},
+		{"password without numbers", []FieldRules{Field("password", Password(8))}, &request{Password: "password"}, []int32{CodeWeakPassword}},
+		{"password without letters", []FieldRules{Field("password", Password(8))}, &request{Password: "12345678"}, []int32{CodeWeakPassword}},
+		{"password bcrypt would cut short", []FieldRules{Field("password", Password(8))}, &request{Password: "a1" + strings.Repeat("x", 71)}, []int32{CodeWeakPassword}},
+		{"one of", []FieldRules{Field("title", OneOf("a", "b"))}, &request{Title: "b"}, nil},
+		{"not one of", []FieldRules{Field("title", OneOf("a", "b"))}, &request{Title: "c"}, []int32{CodeNotAllowed}},
+		{"only the first broken rule is reported", []FieldRules{Field("title", Required(), MaxLength(3))}, &request{}, []int32{CodeRequired}},
+		{"every field is reported", []FieldRules{Field("title", Required()), Field("password", Required())}, &request{}, []int32{CodeRequired, CodeRequired}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New()
+			v.Register("Test.Endpoint", tt.rules...)
+
+			assertCodes(v.Validate("Test.Endpoint", tt.req), tt.want, t)
+		})
+	}
+
+	t.Run("description names the field", func(t *testing.T) {
+		v := New()
+		v.Register("Test.Endpoint", Field("tags", Each(MaxLength(1))))
+
+		errs := v.Validate("Test.Endpoint", &request{Tags: []string{"a", "bb"}})
+
+		if len(errs) != 1 || errs[0].Field != "tags[1]" || errs[0].Description != "tags[1] must be at most 1 characters" {
+			t.Errorf("wanted an error for tags[1] but got %v", errs)
+		}
+	})
+
+	t.Run("endpoints without rules are valid", func(t *testing.T) {
+		assertCodes(New().Validate("Test.Other", &request{}), nil, t)
+	})
+}
+
+type fakeRequest struct {
+	server.Request
+	endpoint string
+	body     interface{}
+}
+
+func (r *fakeRequest) Service() string {
+	return "go_do.test"
+}
+
+func (r *fakeRequest) Endpoint() string {
+	return r.endpoint
+}
+
+func (r *fakeRequest) Body() interface{} {
+	return r.body
+}
+
+func TestHandlerWrapper(t *testing.T) {
+	v := New()
+	v.Register("Test.Endpoint", Field("title", Required()))
+
+	called := false
+
+	wrapped := v.HandlerWrapper(func(ctx context.Context, req server.Request, resp interface{}) error {
+		called = true
+		return nil
+	})
+
+	t.Run("valid requests reach the handler", func(t *testing.T) {
+		called = false
+
+		err := wrapped(context.Background(), &fakeRequest{endpoint: "Test.Endpoint", body: &request{Title: "title"}}, &response{})
+
+		if err != nil || !called {
+			t.Errorf("wanted the handler to be called without an error but got %v", err)
+		}
+	})
+
+	t.Run("errors are put in the response", func(t *testing.T) {
+		called = false
+		resp := response{}
+
+		err := wrapped(context.Background(), &fakeRequest{endpoint: "Test.Endpoint", body: &request{}}, &resp)
+
+		microErr, ok := err.(*errors.Error)
+
+		if !ok || microErr.Code != 400 || called {
+			t.Errorf("wanted the handler not to be called and a bad request error but got %v", err)
+		}
+
+		if len(resp.Errors) != 1 || resp.Errors[0].Code != CodeRequired || resp.Errors[0].Description != "title is required" {
+			t.Errorf("wanted a required error in the response but got %v", resp.Errors)
+		}
+	})
+
+	t.Run("responses without errors get a bad request error", func(t *testing.T) {
+		called = false
+
+		err := wrapped(context.Background(), &fakeRequest{endpoint: "Test.Endpoint", body: &request{}}, &struct{}{})
+
+		microErr, ok := err.(*errors.Error)
+
+		if !ok || microErr.Code != 400 || microErr.Detail != "title is required" || called {
+			t.Errorf("wanted a bad request error but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package validation
+
+import (
+	"context"
+	"strings"
+
+	"github.com/micro/go-micro/errors"
+	"github.com/micro/go-micro/server"
+
+	"github.com/willdot/Go-Do/apierrors"
+)
+
+// HandlerWrapper checks requests before they reach the handler. If a request isn't valid the handler isn't called, the
+// errors are put in the errors field of the response and a bad request error is returned
+func (v *Validator) HandlerWrapper(fn server.HandlerFunc) server.HandlerFunc {
+	return func(ctx context.Context, req server.Request, resp interface{}) error {
+		errs := v.Validate(req.Endpoint(), req.Body())
+
+		if len(errs) == 0 {
+			return fn(ctx, req, resp)
+		}
+
+		apiErrs := make([]*apierrors.Error, len(errs))
+		descriptions := make([]string, len(errs))
+
+		for i, err := range errs {
+			apiErrs[i] = &apierrors.Error{Status: apierrors.CodeValidation, Code: err.Code, Description: err.Description}
+			descriptions[i] = err.Description
+		}
+
+		apierrors.SetErrors(resp, apiErrs...)
+
+		return errors.BadRequest(req.Service(), "%s", strings.Join(descriptions, ", "))
+	}
+}